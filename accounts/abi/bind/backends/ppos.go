@@ -0,0 +1,389 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
+	"github.com/AlayaNetwork/Alaya-Go/consensus"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/evidence"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/misc"
+	"github.com/AlayaNetwork/Alaya-Go/core"
+	"github.com/AlayaNetwork/Alaya-Go/core/rawdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/state"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/core/vm"
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/crypto/bls"
+	"github.com/AlayaNetwork/Alaya-Go/eth/filters"
+	"github.com/AlayaNetwork/Alaya-Go/event"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/params"
+	"github.com/AlayaNetwork/Alaya-Go/x/gov"
+	"github.com/AlayaNetwork/Alaya-Go/x/handler"
+	"github.com/AlayaNetwork/Alaya-Go/x/plugin"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
+	"github.com/AlayaNetwork/Alaya-Go/x/xutil"
+)
+
+// pposGenesisNonce is the vrf seed of the simulated genesis block.
+var pposGenesisNonce = hexutil.MustDecode("0x024c6378c176ef6c717cd37a74c612c9abd615d13873ff6651e3d352b31cb0b2e1")
+
+var (
+	pposOnce    sync.Once
+	pposReactor *core.BlockChainReactor
+)
+
+// PPOSConfig is the configuration of a simulated backend running the PPOS
+// plugins.
+type PPOSConfig struct {
+	// EconomicModel configures the epoch and round lengths, staking thresholds
+	// and the rest of the economic parameters. The unit test net model is used
+	// if it is nil, the block interval and the number of blocks produced by a
+	// validator in a row are taken from its NodeBlockTimeWindow and PerRoundBlocks.
+	EconomicModel *xcom.EconomicModel
+
+	// NodeKeys are the keys of the genesis validators, a single validator is
+	// generated if it is empty.
+	NodeKeys []*ecdsa.PrivateKey
+
+	// GenesisVersion is the active version of the genesis block, defaults to
+	// params.GenesisVersion.
+	GenesisVersion uint32
+}
+
+// pposState holds the block production state of a PPOS simulated backend.
+type pposState struct {
+	keys      []*ecdsa.PrivateKey
+	timeShift time.Duration
+}
+
+// NewPPOSSimulatedBackend creates a new binding backend whose blocks are produced
+// like the ones of a cbft validator: the staking, slashing, restricting, reward
+// and governance plugins run at the beginning and the end of every block on top
+// of an in-memory snapshotdb, and WASM contracts are executed.
+//
+// The reactor, the plugins and the snapshotdb are process wide singletons, so
+// only one PPOS backend may be used at a time and a test binary using it must
+// not import blocks into any other blockchain, including the plain simulated
+// backend, after it has been created.
+func NewPPOSSimulatedBackend(alloc core.GenesisAlloc, gasLimit uint64, config *PPOSConfig) (*SimulatedBackend, error) {
+	if config == nil {
+		config = new(PPOSConfig)
+	}
+
+	// The plugins open the snapshotdb as soon as they are created, so select
+	// the in-memory backend first. Closing it drops the data of the previous
+	// backend, the plugins keep referring to the same snapshotdb instance.
+	if err := snapshotdb.SetDBBackend(snapshotdb.BackendMemory); err != nil {
+		return nil, err
+	}
+	if err := snapshotdb.Instance().Close(); err != nil {
+		return nil, err
+	}
+
+	// The economic model must be in place before the plugins are set up, and
+	// the default govern parameters of the genesis are derived from it.
+	ec := config.EconomicModel
+	if ec == nil {
+		ec = xcom.GetEc(xcom.DefaultUnitTestNet)
+	}
+	xcom.ResetEconomicDefaultConfig(ec)
+	if err := xcom.CheckEconomicModel(); err != nil {
+		return nil, fmt.Errorf("invalid economic model: %v", err)
+	}
+	if err := initPPOS(); err != nil {
+		return nil, err
+	}
+
+	keys := config.NodeKeys
+	if len(keys) == 0 {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		keys = []*ecdsa.PrivateKey{key}
+	}
+	initialNodes := make([]params.CbftNode, len(keys))
+	for i, key := range keys {
+		var blsKey bls.SecretKey
+		blsKey.SetByCSPRNG()
+		initialNodes[i].Node = *discover.NewNode(discover.PubkeyID(&key.PublicKey), nil, 0, 0)
+		initialNodes[i].BlsPubKey = *blsKey.GetPublicKey()
+	}
+
+	chainConfig := *params.AllEthashProtocolChanges
+	chainConfig.Cbft = &params.CbftConfig{
+		Period:        ec.Common.NodeBlockTimeWindow * 1000,
+		Amount:        uint32(ec.Common.PerRoundBlocks),
+		InitialNodes:  initialNodes,
+		ValidatorMode: common.PPOS_VALIDATOR_MODE,
+	}
+	chainConfig.GenesisVersion = params.GenesisVersion
+	if config.GenesisVersion != 0 {
+		chainConfig.GenesisVersion = config.GenesisVersion
+	}
+
+	database := rawdb.NewMemoryDatabase()
+	genesis := core.Genesis{
+		Config:        &chainConfig,
+		EconomicModel: ec,
+		Nonce:         pposGenesisNonce,
+		Timestamp:     uint64(time.Now().UnixNano() / 1e6),
+		GasLimit:      gasLimit,
+		Alloc:         alloc,
+	}
+	if _, err := genesis.Commit(database, snapshotdb.Instance()); err != nil {
+		return nil, err
+	}
+	blockchain, err := core.NewBlockChain(database, nil, genesis.Config, consensus.NewFaker(), vm.Config{WasmType: vm.Wagon}, nil)
+	if err != nil {
+		return nil, err
+	}
+	snapshotdb.SetDBBlockChain(blockchain)
+
+	backend := &SimulatedBackend{
+		database:   database,
+		blockchain: blockchain,
+		config:     genesis.Config,
+		vmConfig:   vm.Config{WasmType: vm.Wagon},
+		ppos:       &pposState{keys: keys},
+		events:     filters.NewEventSystem(new(event.TypeMux), &filterBackend{database, blockchain}, false),
+	}
+	backend.rollback()
+	return backend, nil
+}
+
+// initPPOS starts the blockchain reactor in PPOS mode and registers the plugins
+// the same way a cbft node does.
+func initPPOS() (err error) {
+	pposOnce.Do(func() {
+		if err = bls.Init(int(bls.BLS12_381)); err != nil {
+			return
+		}
+		pposReactor = core.NewBlockChainReactor(new(event.TypeMux), params.AllEthashProtocolChanges.ChainID)
+		pposReactor.Start(common.PPOS_VALIDATOR_MODE)
+		pposReactor.SetVRFhandler(handler.NewVrfHandler(pposGenesisNonce))
+		pposReactor.SetPluginEventMux()
+
+		pposReactor.RegisterPlugin(xcom.SlashingRule, plugin.SlashInstance())
+		plugin.SlashInstance().SetDecodeEvidenceFun(evidence.NewEvidence)
+		pposReactor.RegisterPlugin(xcom.StakingRule, plugin.StakingInstance())
+		pposReactor.RegisterPlugin(xcom.RestrictingRule, plugin.RestrictingInstance())
+		pposReactor.RegisterPlugin(xcom.RewardRule, plugin.RewardMgrInstance())
+		plugin.GovPluginInstance().SetChainID(pposReactor.GetChainID())
+		pposReactor.RegisterPlugin(xcom.GovernanceRule, plugin.GovPluginInstance())
+
+		pposReactor.SetBeginRule([]int{xcom.StakingRule, xcom.SlashingRule, xcom.CollectDeclareVersionRule, xcom.GovernanceRule})
+		pposReactor.SetEndRule([]int{xcom.CollectDeclareVersionRule, xcom.RestrictingRule, xcom.RewardRule, xcom.GovernanceRule, xcom.StakingRule})
+		gov.RegisterGovernParamVerifiers()
+	})
+	if err == nil && pposReactor == nil {
+		err = errors.New("the PPOS plugins failed to initialize")
+	}
+	return err
+}
+
+// AddNodeKey registers the key of a node staked on the simulated chain, the
+// node produces its share of blocks once it is elected as a validator. Blocks
+// of validators without a known key are produced by the first genesis node.
+func (b *SimulatedBackend) AddNodeKey(key *ecdsa.PrivateKey) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ppos == nil {
+		return errors.New("not a PPOS simulated backend")
+	}
+	b.ppos.keys = append(b.ppos.keys, key)
+	return nil
+}
+
+// FastForward imports the pending transactions and n-1 empty blocks on top of
+// them.
+func (b *SimulatedBackend) FastForward(n uint64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ppos == nil {
+		return errors.New("not a PPOS simulated backend")
+	}
+	for i := uint64(0); i < n; i++ {
+		if err := b.commitPPOS(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FastForwardEpochs imports blocks until the end of the n-th epoch from the
+// current block, the pending transactions are included in the first one.
+func (b *SimulatedBackend) FastForwardEpochs(n uint64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ppos == nil {
+		return errors.New("not a PPOS simulated backend")
+	}
+	epoch := xutil.CalcBlocksEachEpoch()
+	target := (b.blockchain.CurrentBlock().NumberU64()/epoch + n) * epoch
+	for b.blockchain.CurrentBlock().NumberU64() < target {
+		if err := b.commitPPOS(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// producer returns the key of the validator producing the given block, the
+// validators take turns every PerRoundBlocks blocks like they do in cbft.
+func (b *SimulatedBackend) producer(number uint64) *ecdsa.PrivateKey {
+	validators, err := plugin.StakingInstance().GetValidator(number)
+	if err != nil || validators.Len() == 0 {
+		return b.ppos.keys[0]
+	}
+	index := int((number-validators.ValidBlockNumber)/xcom.BlocksWillCreate()) % validators.Len()
+	node, err := validators.FindNodeByIndex(index)
+	if err != nil {
+		return b.ppos.keys[0]
+	}
+	for _, key := range b.ppos.keys {
+		if discover.PubkeyID(&key.PublicKey) == node.NodeID {
+			return key
+		}
+	}
+	return b.ppos.keys[0]
+}
+
+// buildPPOS executes the transactions in a new block on top of the current
+// one. The end blockers only run for blocks about to be imported, the pending
+// block just reflects the effect of its transactions.
+func (b *SimulatedBackend) buildPPOS(txs []*types.Transaction, final bool) (*types.Block, types.Receipts, *state.StateDB, error) {
+	parent := b.blockchain.CurrentBlock()
+	statedb, err := b.blockchain.StateAt(parent.Root())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	number := new(big.Int).Add(parent.Number(), common.Big1)
+	interval := int64(xcom.Interval()) * 1000
+	if interval == 0 {
+		interval = 1000
+	}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     number,
+		GasLimit:   core.CalcGasLimit(parent, parent.GasLimit()),
+		Time:       big.NewInt(parent.Time().Int64() + interval + int64(b.ppos.timeShift/time.Millisecond)),
+		Extra:      make([]byte, 32+consensus.ExtraSeal),
+	}
+
+	key := b.producer(number.Uint64())
+	pposReactor.SetPrivateKey(key)
+	plugin.RewardMgrInstance().SetCurrentNodeID(pposReactor.NodeId)
+
+	if err := pposReactor.BeginBlocker(header, statedb); err != nil {
+		return nil, nil, nil, err
+	}
+	if gov.Gte0180VersionState(statedb) {
+		header.BaseFee = misc.CalcBaseFee(parent.Header())
+	}
+	pposReactor.SetWorkerCoinBase(header, pposReactor.NodeId)
+
+	var (
+		receipts = make(types.Receipts, 0, len(txs))
+		usedGas  = new(uint64)
+		gp       = new(core.GasPool).AddGas(header.GasLimit)
+	)
+	for i, tx := range txs {
+		statedb.Prepare(tx.Hash(), common.Hash{}, i)
+		receipt, _, err := core.ApplyTransaction(b.config, b.blockchain, gp, statedb, header, tx, usedGas, b.vmConfig)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("could not apply tx %d [%v]: %v", i, tx.Hash().Hex(), err)
+		}
+		receipts = append(receipts, receipt)
+	}
+	header.GasUsed = *usedGas
+
+	if final {
+		if err := pposReactor.EndBlocker(header, statedb); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	header.Root = statedb.IntermediateRoot(true)
+	if final {
+		sign, err := crypto.Sign(header.SealHash().Bytes(), key)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		copy(header.Extra[32:], sign)
+	}
+	return types.NewBlock(header, txs, receipts), receipts, statedb, nil
+}
+
+// pendingPPOS rebuilds the pending block with the given transactions.
+func (b *SimulatedBackend) pendingPPOS(txs []*types.Transaction) error {
+	block, _, statedb, err := b.buildPPOS(txs, false)
+	if err != nil {
+		return err
+	}
+	b.pendingBlock = block
+	b.pendingState = statedb
+	return nil
+}
+
+// commitPPOS imports the pending transactions as a new block, the way the
+// miner and the reactor do when a cbft block is confirmed.
+func (b *SimulatedBackend) commitPPOS() error {
+	block, receipts, statedb, err := b.buildPPOS(b.pendingBlock.Transactions(), true)
+	if err != nil {
+		return err
+	}
+	if err := pposReactor.Flush(block.Header()); err != nil {
+		return err
+	}
+
+	var logs []*types.Log
+	for i, receipt := range receipts {
+		receipt.BlockHash = block.Hash()
+		receipt.BlockNumber = block.Number()
+		receipt.TransactionIndex = uint(i)
+		for _, log := range receipt.Logs {
+			log.BlockHash = block.Hash()
+		}
+		logs = append(logs, receipt.Logs...)
+	}
+	if _, err := b.blockchain.WriteBlockWithState(block, receipts, statedb); err != nil {
+		return err
+	}
+	if err := pposReactor.OnCommit(block); err != nil {
+		return err
+	}
+	b.blockchain.PostChainEvents([]interface{}{
+		core.ChainEvent{Block: block, Hash: block.Hash(), Logs: logs},
+		core.ChainHeadEvent{Block: block},
+	}, logs)
+
+	b.ppos.timeShift = 0
+	return b.pendingPPOS(nil)
+}
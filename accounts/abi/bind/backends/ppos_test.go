@@ -0,0 +1,252 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"hash/fnv"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	ethereum "github.com/AlayaNetwork/Alaya-Go"
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
+	"github.com/AlayaNetwork/Alaya-Go/common/vm"
+	"github.com/AlayaNetwork/Alaya-Go/core"
+	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/params"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+	"github.com/AlayaNetwork/Alaya-Go/x/gov"
+	"github.com/AlayaNetwork/Alaya-Go/x/restricting"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
+	"github.com/AlayaNetwork/Alaya-Go/x/xutil"
+)
+
+func TestMain(m *testing.M) {
+	// The backend opens the snapshotdb before it switches to the in-memory
+	// backend, keep those files out of the source tree.
+	dir, err := ioutil.TempDir("", "ppos-backend")
+	if err != nil {
+		panic(err)
+	}
+	snapshotdb.SetDBPathWithNode(dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func pposData(t *testing.T, fnType uint16, args ...interface{}) []byte {
+	fn, err := rlp.EncodeToBytes(fnType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := [][]byte{fn}
+	for _, arg := range args {
+		b, err := rlp.EncodeToBytes(arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		params = append(params, b)
+	}
+	data, err := rlp.EncodeToBytes(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func wasmCallData(t *testing.T, funcName string, args ...interface{}) []byte {
+	hash := fnv.New64()
+	hash.Write([]byte(funcName))
+	data, err := rlp.EncodeToBytes(append([]interface{}{hash.Sum64()}, args...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func sendTx(t *testing.T, sim *SimulatedBackend, key *ecdsa.PrivateKey, to *common.Address, data []byte) common.Hash {
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	nonce, err := sim.PendingNonceAt(context.Background(), from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tx *types.Transaction
+	if to == nil {
+		tx = types.NewContractCreation(nonce, new(big.Int), 5000000, big.NewInt(1), data)
+	} else {
+		tx = types.NewTransaction(nonce, *to, new(big.Int), 5000000, big.NewInt(1), data)
+	}
+	tx, err = types.SignTx(tx, types.LatestSignerForChainID(sim.config.ChainID), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sim.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatal(err)
+	}
+	return tx.Hash()
+}
+
+func queryPPOS(t *testing.T, sim *SimulatedBackend, to common.Address, data []byte, ret interface{}) {
+	out, err := sim.CallContract(context.Background(), ethereum.CallMsg{To: &to, Data: data}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		Code uint32
+		Ret  json.RawMessage
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		t.Fatalf("invalid result %s: %v", out, err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("query failed: %s", out)
+	}
+	if err := json.Unmarshal(result.Ret, ret); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPPOSSimulatedBackend(t *testing.T) {
+	nodeKey, _ := crypto.GenerateKey()
+	nodeID := discover.PubkeyID(&nodeKey.PublicKey)
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	balance := new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.ATP))
+
+	sim, err := NewPPOSSimulatedBackend(core.GenesisAlloc{addr: {Balance: balance}}, 100000000, &PPOSConfig{NodeKeys: []*ecdsa.PrivateKey{nodeKey}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close()
+
+	// Lock some funds for a new account until the end of the second epoch.
+	account := common.Address{0x01}
+	amount := new(big.Int).Mul(big.NewInt(10), big.NewInt(params.ATP))
+	plans := []restricting.RestrictingPlan{{Epoch: 2, Amount: amount}}
+	txHash := sendTx(t, sim, key, &vm.RestrictingContractAddr, pposData(t, 4000, account, plans))
+	sim.Commit()
+	receipt, _ := sim.TransactionReceipt(context.Background(), txHash)
+	if receipt == nil || receipt.Status != types.ReceiptStatusSuccessful || len(receipt.Logs) != 1 {
+		t.Fatalf("restricting plan failed: %v", receipt)
+	}
+	var info struct {
+		Balance *hexutil.Big `json:"balance"`
+	}
+	queryPPOS(t, sim, vm.RestrictingContractAddr, pposData(t, 4100, account), &info)
+	if info.Balance.ToInt().Cmp(amount) != 0 {
+		t.Fatalf("restricting balance mismatch: have %v, want %v", info.Balance, amount)
+	}
+
+	var verifiers []struct {
+		NodeId discover.NodeID
+	}
+	queryPPOS(t, sim, vm.StakingContractAddr, pposData(t, 1100), &verifiers)
+	if len(verifiers) != 1 || verifiers[0].NodeId != nodeID {
+		t.Fatalf("verifier list mismatch: %v", verifiers)
+	}
+
+	// The plan is released at the end of the second epoch.
+	epoch := xutil.CalcBlocksEachEpoch()
+	if err := sim.FastForwardEpochs(1); err != nil {
+		t.Fatal(err)
+	}
+	if have := sim.blockchain.CurrentBlock().NumberU64(); have != epoch {
+		t.Fatalf("block number mismatch: have %d, want %d", have, epoch)
+	}
+	if have, _ := sim.BalanceAt(context.Background(), account, nil); have.Sign() != 0 {
+		t.Fatalf("funds released early: %v", have)
+	}
+	if err := sim.FastForwardEpochs(1); err != nil {
+		t.Fatal(err)
+	}
+	if have := sim.blockchain.CurrentBlock().NumberU64(); have != 2*epoch {
+		t.Fatalf("block number mismatch: have %d, want %d", have, 2*epoch)
+	}
+	if have, _ := sim.BalanceAt(context.Background(), account, nil); have.Cmp(amount) != 0 {
+		t.Fatalf("released balance mismatch: have %v, want %v", have, amount)
+	}
+	queryPPOS(t, sim, vm.StakingContractAddr, pposData(t, 1100), &verifiers)
+	if len(verifiers) != 1 || verifiers[0].NodeId != nodeID {
+		t.Fatalf("verifier list mismatch: %v", verifiers)
+	}
+
+	// Deploy a WASM contract and call it.
+	code, err := ioutil.ReadFile("../../../../core/vm/testdata/contract_hello.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deploy, _ := rlp.EncodeToBytes([][]byte{code, wasmCallData(t, "init")})
+	txHash = sendTx(t, sim, key, nil, append([]byte{0x00, 0x61, 0x73, 0x6d}, deploy...))
+	sim.Commit()
+	receipt, _ = sim.TransactionReceipt(context.Background(), txHash)
+	if receipt == nil || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("deployment failed: %v", receipt)
+	}
+	message := []interface{}{[]interface{}{"head"}, "body", "end"}
+	sendTx(t, sim, key, &receipt.ContractAddress, wasmCallData(t, "add_message", message))
+	sim.Commit()
+
+	var size uint64
+	out, err := sim.CallContract(context.Background(), ethereum.CallMsg{To: &receipt.ContractAddress, Data: wasmCallData(t, "get_vector_size")}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rlp.DecodeBytes(out, &size); err != nil || size != 1 {
+		t.Fatalf("vector size mismatch: have %d, want 1 (%v)", size, err)
+	}
+}
+
+func TestPPOSSimulatedBackendEconomicModel(t *testing.T) {
+	maxValidators := func(sim *SimulatedBackend) uint64 {
+		head := sim.blockchain.CurrentBlock()
+		have, err := gov.GovernMaxValidators(head.NumberU64(), head.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return have
+	}
+
+	sim, err := NewPPOSSimulatedBackend(core.GenesisAlloc{}, 100000000, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := xcom.GetEc(xcom.DefaultUnitTestNet).Staking.MaxValidators
+	if have := maxValidators(sim); have != want {
+		t.Fatalf("max validators mismatch: have %d, want %d", have, want)
+	}
+	sim.Close()
+
+	// The genesis govern parameters of a later backend follow its own model.
+	ec := *xcom.GetEc(xcom.DefaultUnitTestNet)
+	ec.Staking.MaxValidators = want + 1
+	sim, err = NewPPOSSimulatedBackend(core.GenesisAlloc{}, 100000000, &PPOSConfig{EconomicModel: &ec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close()
+	if have := maxValidators(sim); have != want+1 {
+		t.Fatalf("max validators mismatch: have %d, want %d", have, want+1)
+	}
+}
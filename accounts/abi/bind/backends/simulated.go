@@ -63,7 +63,9 @@ type SimulatedBackend struct {
 
 	events *filters.EventSystem // Event system for filtering log events live
 
-	config *params.ChainConfig
+	config   *params.ChainConfig
+	vmConfig vm.Config
+	ppos     *pposState // Block production state if the PPOS plugins are enabled
 }
 
 // NewSimulatedBackend creates a new binding backend using a simulated blockchain
@@ -86,12 +88,24 @@ func NewSimulatedBackend(alloc core.GenesisAlloc, gasLimit uint64) *SimulatedBac
 	return backend
 }
 
+// Close terminates the underlying blockchain's update loop.
+func (b *SimulatedBackend) Close() error {
+	b.blockchain.Stop()
+	return nil
+}
+
 // Commit imports all the pending transactions as a single block and starts a
 // fresh new state.
 func (b *SimulatedBackend) Commit() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	if b.ppos != nil {
+		if err := b.commitPPOS(); err != nil {
+			panic(err) // This cannot happen unless the simulator is wrong, fail in that case
+		}
+		return
+	}
 	if _, err := b.blockchain.InsertChain([]*types.Block{b.pendingBlock}); err != nil {
 		panic(err) // This cannot happen unless the simulator is wrong, fail in that case
 	}
@@ -107,6 +121,13 @@ func (b *SimulatedBackend) Rollback() {
 }
 
 func (b *SimulatedBackend) rollback() {
+	if b.ppos != nil {
+		b.ppos.timeShift = 0
+		if err := b.pendingPPOS(nil); err != nil {
+			panic(err)
+		}
+		return
+	}
 	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), consensus.NewFaker(), b.database, 1, func(int, *core.BlockGen) {})
 	statedb, _ := b.blockchain.State()
 
@@ -243,7 +264,9 @@ func (b *SimulatedBackend) EstimateGas(ctx context.Context, call ethereum.CallMs
 	}
 	cap = hi
 
-	// Create a helper to check if a gas allowance results in an executable transaction
+	// Create a helper to check if a gas allowance results in an executable transaction,
+	// remembering the business error a system contract rejected it with
+	var bizErr *common.BizError
 	executable := func(gas uint64) bool {
 		call.Gas = gas
 
@@ -252,6 +275,10 @@ func (b *SimulatedBackend) EstimateGas(ctx context.Context, call ethereum.CallMs
 		b.pendingState.RevertToSnapshot(snapshot)
 
 		if err != nil || res.Failed() {
+			bizErr = nil
+			if err == nil {
+				errors.As(res.Err, &bizErr)
+			}
 			return false
 		}
 		return true
@@ -268,6 +295,9 @@ func (b *SimulatedBackend) EstimateGas(ctx context.Context, call ethereum.CallMs
 	// Reject the transaction as invalid if it still fails at the highest allowance
 	if hi == cap {
 		if !executable(hi) {
+			if bizErr != nil {
+				return 0, bizErr
+			}
 			return 0, errGasEstimationFailed
 		}
 	}
@@ -296,18 +326,19 @@ func (b *SimulatedBackend) callContract(ctx context.Context, call ethereum.CallM
 	evmContext := core.NewEVMContext(msg, block.Header(), b.blockchain)
 	// Create a new environment which holds all relevant information
 	// about the transaction and calling mechanisms.
-	vmenv := vm.NewEVM(evmContext, snapshotdb.Instance(), statedb, b.config, vm.Config{})
+	vmenv := vm.NewEVM(evmContext, snapshotdb.Instance(), statedb, b.config, b.vmConfig)
 	gaspool := new(core.GasPool).AddGas(math.MaxUint64)
 	return core.NewStateTransition(vmenv, msg, gaspool).TransitionDb()
 }
 
 // SendTransaction updates the pending block to include the given transaction.
-// It panics if the transaction is invalid.
+// It panics if the transaction is invalid, a PPOS backend returns an error if
+// the transaction cannot be applied to the pending block.
 func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	sender, err := types.Sender(types.NewEIP155Signer(b.config.ChainID), tx)
+	sender, err := types.Sender(types.LatestSignerForChainID(b.config.ChainID), tx)
 	if err != nil {
 		panic(fmt.Errorf("invalid transaction: %v", err))
 	}
@@ -315,6 +346,17 @@ func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transa
 	if tx.Nonce() != nonce {
 		panic(fmt.Errorf("invalid transaction nonce: got %d, want %d", tx.Nonce(), nonce))
 	}
+	if b.ppos != nil {
+		txs := append(types.Transactions{}, b.pendingBlock.Transactions()...)
+		if err := b.pendingPPOS(append(txs, tx)); err != nil {
+			// Restore the snapshotdb view of the pending block
+			if err := b.pendingPPOS(txs); err != nil {
+				panic(err)
+			}
+			return err
+		}
+		return nil
+	}
 
 	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), consensus.NewFaker(), b.database, 1, func(number int, block *core.BlockGen) {
 		for _, tx := range b.pendingBlock.Transactions() {
@@ -401,6 +443,10 @@ func (b *SimulatedBackend) SubscribeFilterLogs(ctx context.Context, query ethere
 func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	if b.ppos != nil {
+		b.ppos.timeShift += adjustment
+		return b.pendingPPOS(b.pendingBlock.Transactions())
+	}
 	blocks, _ := core.GenerateChain(b.config, b.blockchain.CurrentBlock(), nil, b.database, 1, func(number int, block *core.BlockGen) {
 		for _, tx := range b.pendingBlock.Transactions() {
 			block.AddTx(tx)
@@ -425,9 +471,14 @@ func (m callmsg) Nonce() uint64        { return 0 }
 func (m callmsg) CheckNonce() bool     { return false }
 func (m callmsg) To() *common.Address  { return m.CallMsg.To }
 func (m callmsg) GasPrice() *big.Int   { return m.CallMsg.GasPrice }
+func (m callmsg) GasFeeCap() *big.Int  { return m.CallMsg.GasFeeCap }
+func (m callmsg) GasTipCap() *big.Int  { return m.CallMsg.GasTipCap }
 func (m callmsg) Gas() uint64          { return m.CallMsg.Gas }
 func (m callmsg) Value() *big.Int      { return m.CallMsg.Value }
 func (m callmsg) Data() []byte         { return m.CallMsg.Data }
+func (m callmsg) AccessList() types.AccessList {
+	return m.CallMsg.AccessList
+}
 
 // filterBackend implements filters.Backend to support filtering for logs without
 // taking bloom-bits acceleration structures into account.
@@ -0,0 +1,151 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"fmt"
+
+	"github.com/AlayaNetwork/Alaya-Go/accounts/abi/wasm"
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/event"
+)
+
+// WasmBoundContract is the base wrapper object that reflects a WASM contract
+// on the Alaya network. Calls and events are encoded with the RLP based WASM
+// ABI instead of the Solidity one, transaction handling is shared with the
+// BoundContract.
+type WasmBoundContract struct {
+	bound *BoundContract // Generic wrapper for the raw calls, transactions and logs
+	abi   wasm.ABI       // Reflect based ABI to access the correct WASM methods
+}
+
+// NewWasmBoundContract creates a low level contract interface through which
+// calls and transactions may be made through.
+func NewWasmBoundContract(address common.Address, abi wasm.ABI, caller ContractCaller, transactor ContractTransactor, filterer ContractFilterer) *WasmBoundContract {
+	return &WasmBoundContract{
+		bound: &BoundContract{
+			address:    address,
+			caller:     caller,
+			transactor: transactor,
+			filterer:   filterer,
+		},
+		abi: abi,
+	}
+}
+
+// DeployWasmContract deploys a WASM contract onto the Alaya blockchain and
+// binds the deployment address with a Go wrapper. The params are passed to
+// the init method of the contract.
+func DeployWasmContract(opts *TransactOpts, abi wasm.ABI, code []byte, backend ContractBackend, params ...interface{}) (common.Address, *types.Transaction, *WasmBoundContract, error) {
+	c := NewWasmBoundContract(common.Address{}, abi, backend, backend, backend)
+
+	input, err := abi.PackDeploy(code, params...)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	tx, err := c.bound.transact(opts, nil, input)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	c.bound.address = crypto.CreateAddress(opts.From, tx.Nonce())
+	return c.bound.address, tx, c, nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result, which must be a pointer to the Go type of the
+// method's return value.
+func (c *WasmBoundContract) Call(opts *CallOpts, result interface{}, method string, params ...interface{}) error {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(CallOpts)
+	}
+	input, err := c.abi.Pack(method, params...)
+	if err != nil {
+		return err
+	}
+	output, err := c.bound.call(opts, input)
+	if err != nil {
+		return err
+	}
+	return c.abi.Unpack(result, method, output)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (c *WasmBoundContract) Transact(opts *TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	if method == wasm.InitMethod {
+		return nil, fmt.Errorf("method '%s' can only be called on deployment", method)
+	}
+	input, err := c.abi.Pack(method, params...)
+	if err != nil {
+		return nil, err
+	}
+	return c.bound.transact(opts, &c.bound.address, input)
+}
+
+// RawTransact initiates a transaction with the given raw calldata as the input.
+func (c *WasmBoundContract) RawTransact(opts *TransactOpts, calldata []byte) (*types.Transaction, error) {
+	return c.bound.RawTransact(opts, calldata)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract.
+func (c *WasmBoundContract) Transfer(opts *TransactOpts) (*types.Transaction, error) {
+	return c.bound.Transfer(opts)
+}
+
+// FilterLogs filters contract logs for past blocks, returning the necessary
+// channels to construct a strongly typed bound iterator on top of them. The
+// query holds the accepted values of the indexed inputs in order.
+func (c *WasmBoundContract) FilterLogs(opts *FilterOpts, name string, query ...[]interface{}) (chan types.Log, event.Subscription, error) {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(FilterOpts)
+	}
+	topics, err := c.eventTopics(name, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.bound.filterLogs(opts, topics)
+}
+
+// WatchLogs filters subscribes to contract logs for future blocks, returning a
+// subscription object that can be used to tear down the watcher.
+func (c *WasmBoundContract) WatchLogs(opts *WatchOpts, name string, query ...[]interface{}) (chan types.Log, event.Subscription, error) {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(WatchOpts)
+	}
+	topics, err := c.eventTopics(name, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.bound.watchLogs(opts, topics)
+}
+
+func (c *WasmBoundContract) eventTopics(name string, query [][]interface{}) ([][]common.Hash, error) {
+	event, ok := c.abi.Events[name]
+	if !ok {
+		return nil, fmt.Errorf("event '%s' not found", name)
+	}
+	return event.MakeTopics(query...)
+}
+
+// UnpackLog unpacks a retrieved log into the provided output structure.
+func (c *WasmBoundContract) UnpackLog(out interface{}, event string, log types.Log) error {
+	return c.abi.UnpackLog(out, event, log)
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/accounts/abi/bind"
+	"github.com/AlayaNetwork/Alaya-Go/accounts/abi/bind/backends"
+	"github.com/AlayaNetwork/Alaya-Go/accounts/abi/wasm"
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/core"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/params"
+)
+
+// helloABI is the ABI of core/vm/testdata/contract_hello.cpp.
+const helloABI = `[
+	{"name":"message","type":"struct","baseclass":[],"fields":[{"name":"head","type":"string"}]},
+	{"name":"my_message","type":"struct","baseclass":["message"],"fields":[{"name":"body","type":"string"},{"name":"end","type":"string"}]},
+	{"name":"init","type":"Action","constant":false,"input":[],"output":"void"},
+	{"name":"add_message","type":"Action","constant":false,"input":[{"name":"one_message","type":"my_message"}],"output":"my_message[]"},
+	{"name":"get_message","type":"Action","constant":true,"input":[{"name":"name","type":"string"}],"output":"my_message[]"},
+	{"name":"get_vector_size","type":"Action","constant":true,"input":[],"output":"uint64"}
+]`
+
+type helloMessage struct {
+	Message struct{ Head string }
+	Body    string
+	End     string
+}
+
+func TestWasmBoundContract(t *testing.T) {
+	nodeKey, _ := crypto.GenerateKey()
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	balance := new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.ATP))
+
+	sim, err := backends.NewPPOSSimulatedBackend(core.GenesisAlloc{addr: {Balance: balance}}, 100000000, &backends.PPOSConfig{NodeKeys: []*ecdsa.PrivateKey{nodeKey}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close()
+
+	parsed, err := wasm.JSON(strings.NewReader(helloABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := ioutil.ReadFile("../../../core/vm/testdata/contract_hello.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := types.NewEIP155Signer(params.AllEthashProtocolChanges.ChainID)
+	opts := &bind.TransactOpts{
+		From: addr,
+		Signer: func(_ types.Signer, _ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return types.SignTx(tx, signer, key)
+		},
+	}
+	address, tx, contract, err := bind.DeployWasmContract(opts, parsed, code, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if receipt, _ := sim.TransactionReceipt(context.Background(), tx.Hash()); receipt == nil || receipt.Status != types.ReceiptStatusSuccessful || receipt.ContractAddress != address {
+		t.Fatalf("deployment failed: %v", receipt)
+	}
+	if _, err := contract.Transact(opts, "init"); err == nil {
+		t.Fatal("expected error for calling init")
+	}
+
+	var msg helloMessage
+	msg.Message.Head, msg.Body, msg.End = "head", "body", "end"
+	if _, err := contract.Transact(opts, "add_message", msg); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	var size uint64
+	if err := contract.Call(nil, &size, "get_vector_size"); err != nil {
+		t.Fatal(err)
+	}
+	if size != 1 {
+		t.Fatalf("vector size mismatch: have %d, want 1", size)
+	}
+	var messages []helloMessage
+	if err := contract.Call(nil, &messages, "get_message", "any"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(messages, []helloMessage{msg}) {
+		t.Fatalf("messages mismatch: have %+v, want %+v", messages, msg)
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/AlayaNetwork/Alaya-Go/accounts/abi/wasm"
+)
+
+// BindWasm generates a Go wrapper around a WASM contract ABI, as emitted by
+// the PlatON contract development kit. The bytecodes are the hex encoded
+// contract codes, the init method of the contract parametrizes the deployment.
+// Only Go bindings are supported.
+func BindWasm(types []string, abis []string, bytecodes []string, pkg string, lang Lang, aliases map[string]string) (string, error) {
+	if lang != LangGo {
+		return "", errors.New("only Go bindings are supported for WASM contracts")
+	}
+	var (
+		// contracts is the map of each individual contract requested binding
+		contracts = make(map[string]*tmplWasmContract)
+
+		// structs is the map of all redeclared structs shared by passed contracts.
+		structs = make(map[string]*tmplStruct)
+	)
+	for i := 0; i < len(types); i++ {
+		// Parse the actual ABI to generate the binding for
+		wasmABI, err := wasm.JSON(strings.NewReader(abis[i]))
+		if err != nil {
+			return "", err
+		}
+		// Strip any whitespace from the JSON ABI
+		strippedABI := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, abis[i])
+
+		for _, def := range wasmABI.Structs {
+			if err := checkWasmStruct(def); err != nil {
+				return "", err
+			}
+			bindStructTypeWasm(def, structs)
+		}
+		var (
+			calls     = make(map[string]*tmplWasmMethod)
+			transacts = make(map[string]*tmplWasmMethod)
+			events    = make(map[string]*tmplWasmEvent)
+			init      = wasm.Method{Name: wasm.InitMethod}
+
+			// identifiers are used to detect duplicated identifiers of functions
+			// and events once normalized.
+			methodIdentifiers = make(map[string]bool)
+			eventIdentifiers  = make(map[string]bool)
+		)
+		for _, original := range wasmABI.Methods {
+			for _, input := range original.Inputs {
+				if err := checkWasmType(input.Type); err != nil {
+					return "", fmt.Errorf("method %s: %v", original.Name, err)
+				}
+			}
+			if original.Output != nil {
+				if err := checkWasmType(*original.Output); err != nil {
+					return "", fmt.Errorf("method %s: %v", original.Name, err)
+				}
+			}
+			normalized := original
+			normalized.Inputs = normalizeWasmArgs(original.Inputs)
+			if original.Name == wasm.InitMethod {
+				init = normalized
+				continue
+			}
+			// Ensure there is no duplicated identifier
+			normalized.Name = methodNormalizer[lang](alias(aliases, original.Name))
+			if methodIdentifiers[normalized.Name] {
+				return "", fmt.Errorf("duplicated identifier \"%s\"(normalized \"%s\"), use --alias for renaming", original.Name, normalized.Name)
+			}
+			methodIdentifiers[normalized.Name] = true
+
+			method := &tmplWasmMethod{Original: original, Normalized: normalized}
+			if original.Output != nil {
+				method.Output = bindTypeWasm(*original.Output, structs)
+			}
+			if original.Constant {
+				calls[original.Name] = method
+			} else {
+				transacts[original.Name] = method
+			}
+		}
+		for _, original := range wasmABI.Events {
+			// Skip anonymous events as they don't support explicit filtering
+			if original.Anonymous {
+				continue
+			}
+			for _, input := range original.Inputs {
+				if err := checkWasmType(input.Type); err != nil {
+					return "", fmt.Errorf("event %s: %v", original.Name, err)
+				}
+			}
+			normalized := original
+			normalized.Name = methodNormalizer[lang](alias(aliases, original.Name))
+			if eventIdentifiers[normalized.Name] {
+				return "", fmt.Errorf("duplicated identifier \"%s\"(normalized \"%s\"), use --alias for renaming", original.Name, normalized.Name)
+			}
+			eventIdentifiers[normalized.Name] = true
+			normalized.Inputs = normalizeWasmArgs(original.Inputs)
+
+			events[original.Name] = &tmplWasmEvent{Original: original, Normalized: normalized}
+		}
+		contracts[types[i]] = &tmplWasmContract{
+			Type:      capitalise(types[i]),
+			InputABI:  strings.Replace(strippedABI, "\"", "\\\"", -1),
+			InputBin:  strings.TrimPrefix(strings.TrimSpace(bytecodes[i]), "0x"),
+			Init:      init,
+			Calls:     calls,
+			Transacts: transacts,
+			Events:    events,
+		}
+	}
+	// Generate the contract template data content and render it
+	data := &tmplWasmData{
+		Package:   pkg,
+		Contracts: contracts,
+		Structs:   structs,
+	}
+	buffer := new(bytes.Buffer)
+
+	funcs := map[string]interface{}{
+		"bindtype": func(kind wasm.Type) string {
+			return bindTypeWasm(kind, structs)
+		},
+		"bindtopictype": func(kind wasm.Type) string {
+			return bindTopicTypeWasm(kind, structs)
+		},
+		"capitalise":   capitalise,
+		"decapitalise": decapitalise,
+	}
+	tmpl := template.Must(template.New("").Funcs(funcs).Parse(tmplSourceWasmGo))
+	if err := tmpl.Execute(buffer, data); err != nil {
+		return "", err
+	}
+	// Pass the code through gofmt to clean it up
+	code, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("%v\n%s", err, buffer)
+	}
+	return string(code), nil
+}
+
+// normalizeWasmArgs names the anonymous arguments, the originals are kept as
+// is since the names are used as Go parameters.
+func normalizeWasmArgs(args []wasm.Argument) []wasm.Argument {
+	normalized := make([]wasm.Argument, len(args))
+	copy(normalized, args)
+	for i, arg := range normalized {
+		if arg.Name == "" {
+			normalized[i].Name = fmt.Sprintf("arg%d", i)
+		}
+	}
+	return normalized
+}
+
+// checkWasmType ensures the type can be represented in Go, which requires map
+// keys to be comparable.
+func checkWasmType(kind wasm.Type) error {
+	switch kind.T {
+	case wasm.MapTy:
+		switch kind.Key.T {
+		case wasm.SliceTy, wasm.MapTy, wasm.BigUintTy:
+			return fmt.Errorf("unsupported map key type %s", kind.Key)
+		}
+		if err := checkWasmType(*kind.Key); err != nil {
+			return err
+		}
+		return checkWasmType(*kind.Elem)
+	case wasm.PairTy:
+		if err := checkWasmType(*kind.Key); err != nil {
+			return err
+		}
+		return checkWasmType(*kind.Elem)
+	case wasm.SliceTy, wasm.ArrayTy:
+		return checkWasmType(*kind.Elem)
+	}
+	return nil
+}
+
+func checkWasmStruct(def *wasm.Struct) error {
+	for _, field := range def.Fields {
+		if err := checkWasmType(field.Type); err != nil {
+			return fmt.Errorf("struct %s: %v", def.Name, err)
+		}
+	}
+	return nil
+}
+
+// bindTypeWasm converts WASM ABI types to Go ones. Unsigned integers wider than
+// 64 bits are mapped to big integers, 20 and 32 byte hashes to the common types.
+func bindTypeWasm(kind wasm.Type, structs map[string]*tmplStruct) string {
+	switch kind.T {
+	case wasm.BoolTy:
+		return "bool"
+	case wasm.UintTy:
+		return fmt.Sprintf("uint%d", kind.Size)
+	case wasm.IntTy:
+		return fmt.Sprintf("int%d", kind.Size)
+	case wasm.BigUintTy:
+		return "*big.Int"
+	case wasm.StringTy:
+		return "string"
+	case wasm.FixedHashTy:
+		switch kind.Size {
+		case 20:
+			return "common.Address"
+		case 32:
+			return "common.Hash"
+		}
+		return fmt.Sprintf("[%d]byte", kind.Size)
+	case wasm.SliceTy:
+		return "[]" + bindTypeWasm(*kind.Elem, structs)
+	case wasm.ArrayTy:
+		return fmt.Sprintf("[%d]", kind.Size) + bindTypeWasm(*kind.Elem, structs)
+	case wasm.MapTy:
+		return fmt.Sprintf("map[%s]%s", bindTypeWasm(*kind.Key, structs), bindTypeWasm(*kind.Elem, structs))
+	case wasm.PairTy:
+		return fmt.Sprintf("struct{ First %s; Second %s }", bindTypeWasm(*kind.Key, structs), bindTypeWasm(*kind.Elem, structs))
+	case wasm.StructTy:
+		return bindStructTypeWasm(kind.Struct, structs)
+	}
+	return kind.String()
+}
+
+// bindTopicTypeWasm converts a WASM topic type to a Go one. Values that may be
+// hashed into the topic are returned as the raw hash.
+func bindTopicTypeWasm(kind wasm.Type, structs map[string]*tmplStruct) string {
+	if kind.HashedTopic() {
+		return "common.Hash"
+	}
+	return bindTypeWasm(kind, structs)
+}
+
+// bindStructTypeWasm converts a WASM struct to a Go one and records the mapping
+// in the given map. Base classes become leading fields named after them.
+func bindStructTypeWasm(def *wasm.Struct, structs map[string]*tmplStruct) string {
+	name := capitalise(def.Name)
+	if _, exist := structs[name]; exist {
+		return name
+	}
+	s := &tmplStruct{Name: name}
+	structs[name] = s
+
+	for _, base := range def.Bases {
+		s.Fields = append(s.Fields, &tmplField{Type: bindStructTypeWasm(base, structs), Name: capitalise(base.Name)})
+	}
+	for _, field := range def.Fields {
+		s.Fields = append(s.Fields, &tmplField{Type: bindTypeWasm(field.Type, structs), Name: capitalise(field.Name)})
+	}
+	return name
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/accounts/abi/bind"
+)
+
+func TestBindWasm(t *testing.T) {
+	abi := strings.TrimSuffix(helloABI, "]") + `,
+		{"name":"transfer","type":"Event","anonymous":false,"topic":2,"input":[{"name":"from","type":"FixedHash<20>"},{"name":"memo","type":"string"},{"name":"amount","type":"int64"}]}
+	]`
+	code, err := bind.BindWasm([]string{"hello"}, []string{abi}, []string{"0061736d"}, "bindtest", bind.LangGo, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"type MyMessage struct {\n\tMessage Message\n\tBody    string\n\tEnd     string\n}",
+		"func DeployHello(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *Hello, error)",
+		"func (_Hello *HelloCaller) GetMessage(opts *bind.CallOpts, name string) ([]MyMessage, error)",
+		"func (_Hello *HelloCaller) GetVectorSize(opts *bind.CallOpts) (uint64, error)",
+		"func (_Hello *HelloTransactor) AddMessage(opts *bind.TransactOpts, one_message MyMessage) (*types.Transaction, error)",
+		"func (_Hello *HelloFilterer) FilterTransfer(opts *bind.FilterOpts, from []common.Address, memo []string) (*HelloTransferIterator, error)",
+		"\tFrom   common.Address\n\tMemo   common.Hash\n\tAmount int64\n",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("binding is missing %q", want)
+		}
+	}
+	if strings.Contains(code, "Init(") {
+		t.Error("init method must only be bound for deployment")
+	}
+
+	if _, err := bind.BindWasm([]string{"hello"}, []string{abi}, []string{""}, "bindtest", bind.LangJava, nil); err == nil {
+		t.Error("expected error for java bindings")
+	}
+	invalid := `[{"name":"f","type":"Action","input":[{"name":"m","type":"map<uint8[],string>"}],"output":"void"}]`
+	if _, err := bind.BindWasm([]string{"invalid"}, []string{invalid}, []string{""}, "bindtest", bind.LangGo, nil); err == nil {
+		t.Error("expected error for incomparable map key")
+	}
+}
@@ -0,0 +1,403 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package wasm implements the ABI of WASM contracts built with the PlatON
+// contract development kit.
+//
+// Calls are encoded as the RLP list [fnv64(name), args...], return values and
+// event data as plain RLP, following the serialization rules of the kit.
+package wasm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+)
+
+// InitMethod is the name of the method invoked with the deployment arguments.
+// It can't be called once the contract exists.
+const InitMethod = "init"
+
+// MaxTopics is the number of topics a contract event may carry in total.
+const MaxTopics = 4
+
+// magic prefixes the deployment payload of WASM contracts, see vm.WasmInterp.
+var magic = []byte{0x00, 0x61, 0x73, 0x6d}
+
+// Argument holds the name and type of a method or event argument.
+type Argument struct {
+	Name string
+	Type Type
+}
+
+// Method represents a callable action of the contract.
+type Method struct {
+	Name     string
+	Inputs   []Argument
+	Output   *Type // nil if the method returns nothing
+	Constant bool
+}
+
+// ID returns the hash of the method name which selects the method in calls.
+func (method Method) ID() uint64 {
+	hash := fnv.New64()
+	hash.Write([]byte(method.Name))
+	return hash.Sum64()
+}
+
+// String returns the C++ like declaration of the method, such as
+// "uint64 get_vector_size(string name) const".
+func (method Method) String() string {
+	output := "void"
+	if method.Output != nil {
+		output = method.Output.String()
+	}
+	decl := fmt.Sprintf("%s %s(%s)", output, method.Name, joinArgs(method.Inputs))
+	if method.Constant {
+		decl += " const"
+	}
+	return decl
+}
+
+// Event is an event potentially emitted by the contract. The first Topics
+// inputs are indexed, the remaining ones are stored in the log data.
+type Event struct {
+	Name      string
+	Anonymous bool
+	Topics    int
+	Inputs    []Argument
+}
+
+// ID returns the topic identifying the event, which is the encoded name.
+func (event Event) ID() common.Hash {
+	return topicHash(rlpString(event.Name))
+}
+
+// String returns the declaration of the event, such as
+// "event transfer(string topic1, uint32 arg1)".
+func (event Event) String() string {
+	return fmt.Sprintf("event %s(%s)", event.Name, joinArgs(event.Inputs))
+}
+
+// MakeTopics converts the filter query of the indexed inputs into the topics
+// to filter logs by, including the event ID unless the event is anonymous.
+// A nil or empty query matches any value.
+func (event Event) MakeTopics(query ...[]interface{}) ([][]common.Hash, error) {
+	if len(query) > event.Topics {
+		return nil, fmt.Errorf("too many topics: have %d, want at most %d", len(query), event.Topics)
+	}
+	var topics [][]common.Hash
+	if !event.Anonymous {
+		topics = append(topics, []common.Hash{event.ID()})
+	}
+	for i, rule := range query {
+		var hashes []common.Hash
+		for _, v := range rule {
+			topic, err := Topic(event.Inputs[i].Type, v)
+			if err != nil {
+				return nil, fmt.Errorf("topic %s: %v", event.Inputs[i].Name, err)
+			}
+			hashes = append(hashes, topic)
+		}
+		topics = append(topics, hashes)
+	}
+	return topics, nil
+}
+
+func joinArgs(args []Argument) string {
+	decl := make([]string, len(args))
+	for i, arg := range args {
+		decl[i] = strings.TrimSpace(arg.Type.String() + " " + arg.Name)
+	}
+	return strings.Join(decl, ", ")
+}
+
+// Topic returns the log topic of the indexed value v of type t. Values whose
+// encoding is longer than a hash are replaced by its Keccak256 hash.
+func Topic(t Type, v interface{}) (common.Hash, error) {
+	enc, err := t.encode(reflect.ValueOf(v))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	b, err := rlp.EncodeToBytes(enc)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return topicHash(b), nil
+}
+
+func topicHash(b []byte) common.Hash {
+	if len(b) > common.HashLength {
+		return crypto.Keccak256Hash(b)
+	}
+	return common.BytesToHash(b)
+}
+
+func rlpString(s string) []byte {
+	b, _ := rlp.EncodeToBytes(s)
+	return b
+}
+
+// ABI holds information about a WASM contract's context and available
+// invokable methods.
+type ABI struct {
+	Methods map[string]Method
+	Events  map[string]Event
+	Structs map[string]*Struct
+}
+
+// JSON returns a parsed ABI interface and error if it failed.
+func JSON(reader io.Reader) (ABI, error) {
+	dec := json.NewDecoder(reader)
+
+	var abi ABI
+	if err := dec.Decode(&abi); err != nil {
+		return ABI{}, err
+	}
+	return abi, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (abi *ABI) UnmarshalJSON(data []byte) error {
+	type field struct {
+		Name string
+		Type string
+	}
+	var entries []struct {
+		Type      string
+		Name      string
+		Input     []field
+		Output    string
+		Constant  bool
+		Anonymous bool
+		Topic     int
+		Baseclass []string
+		Fields    []field
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	abi.Methods = make(map[string]Method)
+	abi.Events = make(map[string]Event)
+	abi.Structs = make(map[string]*Struct)
+
+	// Register the structs first, their definitions may reference each other
+	for _, entry := range entries {
+		if strings.ToLower(entry.Type) == "struct" {
+			if _, ok := abi.Structs[entry.Name]; ok {
+				return fmt.Errorf("duplicated struct %s", entry.Name)
+			}
+			abi.Structs[entry.Name] = &Struct{Name: entry.Name}
+		}
+	}
+	args := func(fields []field) ([]Argument, error) {
+		list := make([]Argument, len(fields))
+		for i, field := range fields {
+			typ, err := NewType(field.Type, abi.Structs)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = Argument{Name: field.Name, Type: typ}
+		}
+		return list, nil
+	}
+	for _, entry := range entries {
+		switch strings.ToLower(entry.Type) {
+		case "struct":
+			def := abi.Structs[entry.Name]
+			for _, base := range entry.Baseclass {
+				b, ok := abi.Structs[base]
+				if !ok {
+					return fmt.Errorf("struct %s: unknown base class %s", entry.Name, base)
+				}
+				def.Bases = append(def.Bases, b)
+			}
+			fields, err := args(entry.Fields)
+			if err != nil {
+				return fmt.Errorf("struct %s: %v", entry.Name, err)
+			}
+			def.Fields = fields
+
+		case "action":
+			if _, ok := abi.Methods[entry.Name]; ok {
+				return fmt.Errorf("duplicated method %s", entry.Name)
+			}
+			inputs, err := args(entry.Input)
+			if err != nil {
+				return fmt.Errorf("method %s: %v", entry.Name, err)
+			}
+			method := Method{Name: entry.Name, Inputs: inputs, Constant: entry.Constant}
+			if output := strings.TrimSpace(entry.Output); output != "" && output != "void" {
+				typ, err := NewType(output, abi.Structs)
+				if err != nil {
+					return fmt.Errorf("method %s: %v", entry.Name, err)
+				}
+				method.Output = &typ
+			}
+			abi.Methods[entry.Name] = method
+
+		case "event":
+			if _, ok := abi.Events[entry.Name]; ok {
+				return fmt.Errorf("duplicated event %s", entry.Name)
+			}
+			inputs, err := args(entry.Input)
+			if err != nil {
+				return fmt.Errorf("event %s: %v", entry.Name, err)
+			}
+			event := Event{Name: entry.Name, Anonymous: entry.Anonymous, Topics: entry.Topic, Inputs: inputs}
+			limit := MaxTopics
+			if !event.Anonymous {
+				limit--
+			}
+			if event.Topics < 0 || event.Topics > limit || event.Topics > len(inputs) {
+				return fmt.Errorf("event %s: invalid topic count %d", entry.Name, event.Topics)
+			}
+			abi.Events[entry.Name] = event
+
+		default:
+			return fmt.Errorf("unknown ABI entry type %q", entry.Type)
+		}
+	}
+	return nil
+}
+
+// Pack encodes the call of the method name with the given arguments.
+func (abi ABI) Pack(name string, args ...interface{}) ([]byte, error) {
+	method, ok := abi.Methods[name]
+	if !ok {
+		if name != InitMethod {
+			return nil, fmt.Errorf("method '%s' not found", name)
+		}
+		// Contracts without explicit init still accept the bare call
+		method = Method{Name: InitMethod}
+	}
+	if len(args) != len(method.Inputs) {
+		return nil, fmt.Errorf("argument count mismatch: %d for %d", len(args), len(method.Inputs))
+	}
+	list := make([]interface{}, 0, len(args)+1)
+	list = append(list, method.ID())
+	for i, input := range method.Inputs {
+		enc, err := input.Type.encode(reflect.ValueOf(args[i]))
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %v", input.Name, err)
+		}
+		list = append(list, enc)
+	}
+	return rlp.EncodeToBytes(list)
+}
+
+// PackDeploy returns the payload of the transaction deploying the contract
+// code, initialized with the given init arguments.
+func (abi ABI) PackDeploy(code []byte, args ...interface{}) ([]byte, error) {
+	init, err := abi.Pack(InitMethod, args...)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := rlp.EncodeToBytes([][]byte{code, init})
+	if err != nil {
+		return nil, err
+	}
+	return append(common.CopyBytes(magic), payload...), nil
+}
+
+// Unpack decodes the return value of the method name into v, which must be
+// a pointer to a value of the Go type matching the output.
+func (abi ABI) Unpack(v interface{}, name string, data []byte) error {
+	method, ok := abi.Methods[name]
+	if !ok {
+		return fmt.Errorf("method '%s' not found", name)
+	}
+	if method.Output == nil {
+		if len(data) != 0 {
+			return fmt.Errorf("method '%s' returns nothing", name)
+		}
+		return nil
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("method '%s' returned no data", name)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cannot unpack into %v", rv.Type())
+	}
+	return method.Output.decode(data, rv.Elem())
+}
+
+// UnpackLog unpacks a retrieved log of the event name into out, which must be
+// a pointer to a struct whose leading fields match the event inputs in order.
+// Indexed inputs which may be hashed are returned as the raw topic hash.
+func (abi ABI) UnpackLog(out interface{}, name string, log types.Log) error {
+	event, ok := abi.Events[name]
+	if !ok {
+		return fmt.Errorf("event '%s' not found", name)
+	}
+	topics := log.Topics
+	if !event.Anonymous {
+		if len(topics) == 0 || topics[0] != event.ID() {
+			return fmt.Errorf("event signature mismatch")
+		}
+		topics = topics[1:]
+	}
+	if len(topics) != event.Topics {
+		return fmt.Errorf("topic count mismatch: have %d, want %d", len(topics), event.Topics)
+	}
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct || rv.Elem().NumField() < len(event.Inputs) {
+		return fmt.Errorf("cannot unpack event into %T", out)
+	}
+	rv = rv.Elem()
+
+	for i, topic := range topics {
+		input, field := event.Inputs[i], rv.Field(i)
+		if input.Type.HashedTopic() {
+			if field.Type() != reflect.TypeOf(common.Hash{}) {
+				return fmt.Errorf("topic %s: cannot unpack into %v", input.Name, field.Type())
+			}
+			field.Set(reflect.ValueOf(topic))
+			continue
+		}
+		// Short encodings are left padded with zeros, which no RLP item starts with
+		if err := input.Type.decode(bytes.TrimLeft(topic[:], "\x00"), field); err != nil {
+			return fmt.Errorf("topic %s: %v", input.Name, err)
+		}
+	}
+	if len(event.Inputs) == event.Topics {
+		return nil
+	}
+	items, err := splitList(log.Data)
+	if err != nil {
+		return err
+	}
+	if len(items) != len(event.Inputs)-event.Topics {
+		return fmt.Errorf("event data count mismatch: have %d, want %d", len(items), len(event.Inputs)-event.Topics)
+	}
+	for i, item := range items {
+		input := event.Inputs[event.Topics+i]
+		if err := input.Type.decode(item, rv.Field(event.Topics+i)); err != nil {
+			return fmt.Errorf("argument %s: %v", input.Name, err)
+		}
+	}
+	return nil
+}
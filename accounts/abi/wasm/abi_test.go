@@ -0,0 +1,266 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasm
+
+import (
+	"bytes"
+	"hash/fnv"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+)
+
+const testABI = `[
+	{"name":"message","type":"struct","baseclass":[],"fields":[{"name":"head","type":"string"}]},
+	{"name":"my_message","type":"struct","baseclass":["message"],"fields":[{"name":"body","type":"string"},{"name":"end","type":"string"}]},
+	{"name":"init","type":"Action","constant":false,"input":[{"name":"owner","type":"FixedHash<20>"}],"output":"void"},
+	{"name":"add_message","type":"Action","constant":false,"input":[{"name":"one_message","type":"my_message"}],"output":"my_message[]"},
+	{"name":"get_vector_size","type":"Action","constant":true,"input":[],"output":"uint64"},
+	{"name":"set_values","type":"Action","constant":false,"input":[{"name":"delta","type":"int32"},{"name":"balances","type":"map<string,uint128>"},{"name":"entry","type":"pair<uint8,list<bool>>"},{"name":"data","type":"uint8[]"}],"output":"void"},
+	{"name":"transfer","type":"Event","anonymous":false,"topic":2,"input":[{"name":"from","type":"FixedHash<20>"},{"name":"memo","type":"string"},{"name":"amount","type":"uint64"},{"name":"delta","type":"int16"}]}
+]`
+
+type message struct {
+	Head string
+}
+
+type myMessage struct {
+	Message message
+	Body    string
+	End     string
+}
+
+func fnvHash(name string) uint64 {
+	hash := fnv.New64()
+	hash.Write([]byte(name))
+	return hash.Sum64()
+}
+
+func TestJSON(t *testing.T) {
+	abi, err := JSON(strings.NewReader(testABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(abi.Methods) != 4 || len(abi.Events) != 1 || len(abi.Structs) != 2 {
+		t.Fatalf("entry count mismatch: %d methods, %d events, %d structs", len(abi.Methods), len(abi.Events), len(abi.Structs))
+	}
+	def := abi.Structs["my_message"]
+	if len(def.Bases) != 1 || def.Bases[0] != abi.Structs["message"] || len(def.Fields) != 2 {
+		t.Fatalf("struct mismatch: %+v", def)
+	}
+	if have, want := abi.Methods["add_message"].String(), "my_message[] add_message(my_message one_message)"; have != want {
+		t.Errorf("method declaration mismatch: have %q, want %q", have, want)
+	}
+	if have, want := abi.Methods["get_vector_size"].String(), "uint64 get_vector_size() const"; have != want {
+		t.Errorf("method declaration mismatch: have %q, want %q", have, want)
+	}
+	if abi.Methods["init"].Output != nil {
+		t.Errorf("void output parsed as %v", abi.Methods["init"].Output)
+	}
+	entry := abi.Methods["set_values"].Inputs[2].Type
+	if entry.T != PairTy || entry.Key.T != UintTy || entry.Elem.T != SliceTy || entry.Elem.Elem.T != BoolTy {
+		t.Errorf("pair type mismatch: %+v", entry)
+	}
+	for _, invalid := range []string{
+		`[{"name":"f","type":"Action","input":[{"name":"a","type":"unknown"}]}]`,
+		`[{"name":"f","type":"Action","input":[{"name":"a","type":"map<string>"}]}]`,
+		`[{"name":"s","type":"struct","baseclass":["missing"]}]`,
+		`[{"name":"e","type":"Event","topic":4,"input":[{"name":"a","type":"uint8"},{"name":"b","type":"uint8"},{"name":"c","type":"uint8"},{"name":"d","type":"uint8"}]}]`,
+		`[{"name":"f","type":"Action"},{"name":"f","type":"Action"}]`,
+	} {
+		if _, err := JSON(strings.NewReader(invalid)); err == nil {
+			t.Errorf("expected error for %s", invalid)
+		}
+	}
+}
+
+func TestPack(t *testing.T) {
+	abi, _ := JSON(strings.NewReader(testABI))
+
+	// Structs encode their base classes as nested lists
+	input, err := abi.Pack("add_message", myMessage{message{"head"}, "body", "end"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := rlp.EncodeToBytes([]interface{}{fnvHash("add_message"), []interface{}{[]interface{}{"head"}, "body", "end"}})
+	if !bytes.Equal(input, want) {
+		t.Errorf("struct encoding mismatch: have %x, want %x", input, want)
+	}
+	// Signed integers are zigzag encoded, maps are sorted by key
+	balances := map[string]*big.Int{"bob": big.NewInt(2), "alice": big.NewInt(1)}
+	entry := struct {
+		First  uint8
+		Second []bool
+	}{7, []bool{true, false}}
+	input, err = abi.Pack("set_values", int32(-3), balances, entry, []byte{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ = rlp.EncodeToBytes([]interface{}{
+		fnvHash("set_values"),
+		uint64(5),
+		[]interface{}{[]interface{}{"alice", big.NewInt(1)}, []interface{}{"bob", big.NewInt(2)}},
+		[]interface{}{uint8(7), []bool{true, false}},
+		[]byte{1, 2},
+	})
+	if !bytes.Equal(input, want) {
+		t.Errorf("encoding mismatch: have %x, want %x", input, want)
+	}
+	// Invalid arguments are rejected
+	for _, args := range [][]interface{}{
+		{int32(-3), balances, entry},
+		{int64(1 << 40), balances, entry, []byte{}},
+		{int32(1), map[string]*big.Int{"a": big.NewInt(-1)}, entry, []byte{}},
+		{uint32(1), balances, entry, []byte{}},
+		{int32(1), balances, nil, []byte{}},
+	} {
+		if _, err := abi.Pack("set_values", args...); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+	if _, err := abi.Pack("missing"); err == nil {
+		t.Error("expected error for unknown method")
+	}
+}
+
+func TestPackDeploy(t *testing.T) {
+	abi, _ := JSON(strings.NewReader(testABI))
+
+	owner := common.Address{0x01}
+	payload, err := abi.PackDeploy([]byte{0x00, 0x61, 0x73, 0x6d, 0x01}, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(payload, magic) {
+		t.Fatalf("missing interpreter prefix: %x", payload)
+	}
+	var deploy [][]byte
+	if err := rlp.DecodeBytes(payload[len(magic):], &deploy); err != nil {
+		t.Fatal(err)
+	}
+	init, _ := rlp.EncodeToBytes([]interface{}{fnvHash("init"), owner})
+	if len(deploy) != 2 || !bytes.Equal(deploy[1], init) {
+		t.Fatalf("deployment mismatch: %x", deploy)
+	}
+	// Contracts without explicit init accept no arguments
+	empty, _ := JSON(strings.NewReader(`[]`))
+	if _, err := empty.PackDeploy(nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := empty.PackDeploy(nil, owner); err == nil {
+		t.Fatal("expected error for unexpected init argument")
+	}
+}
+
+func TestUnpack(t *testing.T) {
+	abi, _ := JSON(strings.NewReader(testABI))
+
+	output, _ := rlp.EncodeToBytes([]interface{}{
+		[]interface{}{[]interface{}{"a"}, "b", "c"},
+		[]interface{}{[]interface{}{"d"}, "e", "f"},
+	})
+	var messages []myMessage
+	if err := abi.Unpack(&messages, "add_message", output); err != nil {
+		t.Fatal(err)
+	}
+	want := []myMessage{{message{"a"}, "b", "c"}, {message{"d"}, "e", "f"}}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("output mismatch: have %+v, want %+v", messages, want)
+	}
+	var size uint64
+	if err := abi.Unpack(&size, "get_vector_size", []byte{0x80}); err != nil || size != 0 {
+		t.Errorf("size mismatch: have %d (%v)", size, err)
+	}
+	if err := abi.Unpack(&size, "add_message", output); err == nil {
+		t.Error("expected error for mismatched result type")
+	}
+	if err := abi.Unpack(&size, "get_vector_size", []byte{0x80, 0x80}); err == nil {
+		t.Error("expected error for trailing data")
+	}
+	if err := abi.Unpack(nil, "init", nil); err != nil {
+		t.Errorf("void output: %v", err)
+	}
+}
+
+func TestEventTopics(t *testing.T) {
+	abi, _ := JSON(strings.NewReader(testABI))
+	event := abi.Events["transfer"]
+
+	name, _ := rlp.EncodeToBytes("transfer")
+	if event.ID() != common.BytesToHash(name) {
+		t.Fatalf("event ID mismatch: %x", event.ID())
+	}
+	from := common.Address{0xaa}
+	memo := strings.Repeat("m", 40)
+	topics, err := event.MakeTopics([]interface{}{from}, []interface{}{memo, "short"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromEnc, _ := rlp.EncodeToBytes(from)
+	memoEnc, _ := rlp.EncodeToBytes(memo)
+	shortEnc, _ := rlp.EncodeToBytes("short")
+	want := [][]common.Hash{
+		{event.ID()},
+		{common.BytesToHash(fromEnc)},
+		{crypto.Keccak256Hash(memoEnc), common.BytesToHash(shortEnc)},
+	}
+	if !reflect.DeepEqual(topics, want) {
+		t.Fatalf("topics mismatch: have %x, want %x", topics, want)
+	}
+	if _, err := event.MakeTopics(nil, nil, nil); err == nil {
+		t.Fatal("expected error for too many topics")
+	}
+
+	// Unpack a log as emitted by the contract
+	data, _ := rlp.EncodeToBytes([]interface{}{uint64(100), zigzag(-7)})
+	log := types.Log{Topics: []common.Hash{want[0][0], want[1][0], want[2][0]}, Data: data}
+
+	var out struct {
+		From   common.Address
+		Memo   common.Hash
+		Amount uint64
+		Delta  int16
+		Raw    types.Log
+	}
+	if err := abi.UnpackLog(&out, "transfer", log); err != nil {
+		t.Fatal(err)
+	}
+	if out.From != from || out.Memo != want[2][0] || out.Amount != 100 || out.Delta != -7 {
+		t.Fatalf("event mismatch: %+v", out)
+	}
+	log.Topics[0] = common.Hash{}
+	if err := abi.UnpackLog(&out, "transfer", log); err == nil {
+		t.Fatal("expected error for mismatched event ID")
+	}
+}
+
+func TestZigzag(t *testing.T) {
+	for _, x := range []int64{0, -1, 1, -2, 63, -64, 1<<63 - 1, -1 << 63} {
+		if have := unzigzag(zigzag(x)); have != x {
+			t.Errorf("roundtrip mismatch: have %d, want %d", have, x)
+		}
+	}
+	if zigzag(-1) != 1 || zigzag(1) != 2 || zigzag(-64) != 127 {
+		t.Error("unexpected zigzag encoding")
+	}
+}
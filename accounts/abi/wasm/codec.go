@@ -0,0 +1,440 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+)
+
+var bigT = reflect.TypeOf((*big.Int)(nil))
+
+// indirect dereferences pointers and interfaces down to the underlying value,
+// stopping at big integers which are handled by reference.
+func indirect(v reflect.Value) (reflect.Value, error) {
+	for v.IsValid() && v.Type() != bigT && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return v, errors.New("nil value")
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return v, errors.New("nil value")
+	}
+	return v, nil
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uint64
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isByteSeq(v reflect.Type) bool {
+	return (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Elem().Kind() == reflect.Uint8
+}
+
+func typeErr(t Type, v reflect.Type) error {
+	return fmt.Errorf("cannot use %v as type %s", v, t)
+}
+
+// zigzag maps signed integers to unsigned ones the way the contract
+// development kit serializes them, so that small magnitudes stay short.
+func zigzag(x int64) uint64 {
+	return uint64((x << 1) ^ (x >> 63))
+}
+
+func unzigzag(x uint64) int64 {
+	return int64(x>>1) ^ -int64(x&1)
+}
+
+// encode converts the Go value v of type t into a value the rlp package
+// serializes the same way as the contract.
+func (t Type) encode(v reflect.Value) (interface{}, error) {
+	v, err := indirect(v)
+	if err != nil {
+		return nil, err
+	}
+	switch t.T {
+	case BoolTy:
+		if v.Kind() != reflect.Bool {
+			return nil, typeErr(t, v.Type())
+		}
+		return v.Bool(), nil
+
+	case UintTy:
+		if !isUint(v.Kind()) {
+			return nil, typeErr(t, v.Type())
+		}
+		if t.Size < 64 && v.Uint()>>uint(t.Size) != 0 {
+			return nil, fmt.Errorf("value %d overflows %s", v.Uint(), t)
+		}
+		return v.Uint(), nil
+
+	case IntTy:
+		if !isInt(v.Kind()) {
+			return nil, typeErr(t, v.Type())
+		}
+		if x := v.Int(); t.Size < 64 && (x < -1<<uint(t.Size-1) || x >= 1<<uint(t.Size-1)) {
+			return nil, fmt.Errorf("value %d overflows %s", x, t)
+		}
+		return zigzag(v.Int()), nil
+
+	case BigUintTy:
+		if v.Type() != bigT {
+			return nil, typeErr(t, v.Type())
+		}
+		x := v.Interface().(*big.Int)
+		if x.Sign() < 0 || x.BitLen() > t.Size {
+			return nil, fmt.Errorf("value %v overflows %s", x, t)
+		}
+		return x, nil
+
+	case StringTy:
+		if v.Kind() != reflect.String {
+			return nil, typeErr(t, v.Type())
+		}
+		return v.String(), nil
+
+	case FixedHashTy:
+		if v.Kind() != reflect.Array || !isByteSeq(v.Type()) || v.Len() != t.Size {
+			return nil, typeErr(t, v.Type())
+		}
+		return toBytes(v), nil
+
+	case SliceTy, ArrayTy:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil, typeErr(t, v.Type())
+		}
+		if t.T == ArrayTy && v.Len() != t.Size {
+			return nil, fmt.Errorf("array length mismatch: have %d, want %d", v.Len(), t.Size)
+		}
+		if t.IsBytes() {
+			if !isByteSeq(v.Type()) {
+				return nil, typeErr(t, v.Type())
+			}
+			return toBytes(v), nil
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			if list[i], err = t.Elem.encode(v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+
+	case MapTy:
+		if v.Kind() != reflect.Map {
+			return nil, typeErr(t, v.Type())
+		}
+		// Maps are ordered by key on the contract side
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return lessKey(keys[i], keys[j]) })
+
+		list := make([]interface{}, len(keys))
+		for i, key := range keys {
+			k, err := t.Key.encode(key)
+			if err != nil {
+				return nil, err
+			}
+			e, err := t.Elem.encode(v.MapIndex(key))
+			if err != nil {
+				return nil, err
+			}
+			list[i] = []interface{}{k, e}
+		}
+		return list, nil
+
+	case PairTy:
+		if v.Kind() != reflect.Struct || v.NumField() != 2 {
+			return nil, typeErr(t, v.Type())
+		}
+		k, err := t.Key.encode(v.Field(0))
+		if err != nil {
+			return nil, err
+		}
+		e, err := t.Elem.encode(v.Field(1))
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{k, e}, nil
+
+	case StructTy:
+		def := t.Struct
+		if v.Kind() != reflect.Struct || v.NumField() != len(def.Bases)+len(def.Fields) {
+			return nil, typeErr(t, v.Type())
+		}
+		list := make([]interface{}, v.NumField())
+		for i := range list {
+			if list[i], err = def.fieldType(i).encode(v.Field(i)); err != nil {
+				return nil, fmt.Errorf("field %s.%s: %v", def.Name, def.fieldName(i), err)
+			}
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// decode parses the single RLP item in data as type t into the settable v.
+func (t Type) decode(data []byte, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr && v.Type() != bigT {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	switch t.T {
+	case BoolTy:
+		if v.Kind() != reflect.Bool {
+			return typeErr(t, v.Type())
+		}
+		var b bool
+		if err := rlp.DecodeBytes(data, &b); err != nil {
+			return err
+		}
+		v.SetBool(b)
+
+	case UintTy, IntTy:
+		var x uint64
+		if err := rlp.DecodeBytes(data, &x); err != nil {
+			return err
+		}
+		if t.T == UintTy {
+			if !isUint(v.Kind()) {
+				return typeErr(t, v.Type())
+			}
+			if (t.Size < 64 && x>>uint(t.Size) != 0) || v.OverflowUint(x) {
+				return fmt.Errorf("value %d overflows %v", x, v.Type())
+			}
+			v.SetUint(x)
+		} else {
+			if !isInt(v.Kind()) {
+				return typeErr(t, v.Type())
+			}
+			n := unzigzag(x)
+			if v.OverflowInt(n) {
+				return fmt.Errorf("value %d overflows %v", n, v.Type())
+			}
+			v.SetInt(n)
+		}
+
+	case BigUintTy:
+		if v.Type() != bigT {
+			return typeErr(t, v.Type())
+		}
+		x := new(big.Int)
+		if err := rlp.DecodeBytes(data, x); err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(x))
+
+	case StringTy:
+		if v.Kind() != reflect.String {
+			return typeErr(t, v.Type())
+		}
+		var s string
+		if err := rlp.DecodeBytes(data, &s); err != nil {
+			return err
+		}
+		v.SetString(s)
+
+	case FixedHashTy:
+		if v.Kind() != reflect.Array || !isByteSeq(v.Type()) || v.Len() != t.Size {
+			return typeErr(t, v.Type())
+		}
+		return decodeBytes(data, v)
+
+	case SliceTy, ArrayTy:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return typeErr(t, v.Type())
+		}
+		if t.IsBytes() {
+			if !isByteSeq(v.Type()) || (t.T == ArrayTy && (v.Kind() != reflect.Array || v.Len() != t.Size)) {
+				return typeErr(t, v.Type())
+			}
+			return decodeBytes(data, v)
+		}
+		items, err := splitList(data)
+		if err != nil {
+			return err
+		}
+		if v.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(v.Type(), len(items), len(items)))
+		} else if v.Len() != len(items) {
+			return fmt.Errorf("array length mismatch: have %d, want %d", len(items), v.Len())
+		}
+		for i, item := range items {
+			if err := t.Elem.decode(item, v.Index(i)); err != nil {
+				return err
+			}
+		}
+
+	case MapTy:
+		if v.Kind() != reflect.Map {
+			return typeErr(t, v.Type())
+		}
+		items, err := splitList(data)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.MakeMapWithSize(v.Type(), len(items)))
+		for _, item := range items {
+			kv, err := splitList(item)
+			if err != nil {
+				return err
+			}
+			if len(kv) != 2 {
+				return fmt.Errorf("invalid map entry with %d elements", len(kv))
+			}
+			key, elem := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+			if err := t.Key.decode(kv[0], key); err != nil {
+				return err
+			}
+			if err := t.Elem.decode(kv[1], elem); err != nil {
+				return err
+			}
+			v.SetMapIndex(key, elem)
+		}
+
+	case PairTy:
+		if v.Kind() != reflect.Struct || v.NumField() != 2 {
+			return typeErr(t, v.Type())
+		}
+		items, err := splitList(data)
+		if err != nil {
+			return err
+		}
+		if len(items) != 2 {
+			return fmt.Errorf("invalid pair with %d elements", len(items))
+		}
+		if err := t.Key.decode(items[0], v.Field(0)); err != nil {
+			return err
+		}
+		return t.Elem.decode(items[1], v.Field(1))
+
+	case StructTy:
+		def := t.Struct
+		if v.Kind() != reflect.Struct || v.NumField() != len(def.Bases)+len(def.Fields) {
+			return typeErr(t, v.Type())
+		}
+		items, err := splitList(data)
+		if err != nil {
+			return err
+		}
+		if len(items) != v.NumField() {
+			return fmt.Errorf("struct %s field count mismatch: have %d, want %d", def.Name, len(items), v.NumField())
+		}
+		for i, item := range items {
+			if !v.Field(i).CanSet() {
+				return fmt.Errorf("field %s.%s is not settable", def.Name, def.fieldName(i))
+			}
+			if err := def.fieldType(i).decode(item, v.Field(i)); err != nil {
+				return fmt.Errorf("field %s.%s: %v", def.Name, def.fieldName(i), err)
+			}
+		}
+
+	default:
+		return fmt.Errorf("unsupported type %s", t)
+	}
+	return nil
+}
+
+// fieldType returns the type of the i'th encoded element of the struct, where
+// the base classes precede the declared fields.
+func (s *Struct) fieldType(i int) Type {
+	if i < len(s.Bases) {
+		return Type{T: StructTy, Struct: s.Bases[i], stringKind: s.Bases[i].Name}
+	}
+	return s.Fields[i-len(s.Bases)].Type
+}
+
+func (s *Struct) fieldName(i int) string {
+	if i < len(s.Bases) {
+		return s.Bases[i].Name
+	}
+	return s.Fields[i-len(s.Bases)].Name
+}
+
+func toBytes(v reflect.Value) []byte {
+	b := make([]byte, v.Len())
+	reflect.Copy(reflect.ValueOf(b), v)
+	return b
+}
+
+func decodeBytes(data []byte, v reflect.Value) error {
+	var b []byte
+	if err := rlp.DecodeBytes(data, &b); err != nil {
+		return err
+	}
+	if v.Kind() == reflect.Slice {
+		v.SetBytes(b)
+		return nil
+	}
+	if len(b) != v.Len() {
+		return fmt.Errorf("byte array length mismatch: have %d, want %d", len(b), v.Len())
+	}
+	reflect.Copy(v, reflect.ValueOf(b))
+	return nil
+}
+
+// splitList returns the raw encoding of every element of the RLP list in data.
+func splitList(data []byte) ([][]byte, error) {
+	content, rest, err := rlp.SplitList(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, rlp.ErrMoreThanOneValue
+	}
+	var items [][]byte
+	for len(content) > 0 {
+		_, _, tail, err := rlp.Split(content)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, content[:len(content)-len(tail)])
+		content = tail
+	}
+	return items, nil
+}
+
+// lessKey orders map keys the way the contract side sorts them.
+func lessKey(a, b reflect.Value) bool {
+	switch {
+	case isUint(a.Kind()):
+		return a.Uint() < b.Uint()
+	case isInt(a.Kind()):
+		return a.Int() < b.Int()
+	case a.Kind() == reflect.String:
+		return a.String() < b.String()
+	case a.Kind() == reflect.Bool:
+		return !a.Bool() && b.Bool()
+	case isByteSeq(a.Type()):
+		return bytes.Compare(toBytes(a), toBytes(b)) < 0
+	}
+	ea, _ := rlp.EncodeToBytes(a.Interface())
+	eb, _ := rlp.EncodeToBytes(b.Interface())
+	return bytes.Compare(ea, eb) < 0
+}
@@ -0,0 +1,198 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Type enumerator
+const (
+	BoolTy byte = iota
+	UintTy
+	IntTy
+	BigUintTy
+	StringTy
+	FixedHashTy
+	SliceTy
+	ArrayTy
+	MapTy
+	PairTy
+	StructTy
+)
+
+// Type is the reflection of a type declared in a WASM contract ABI.
+type Type struct {
+	T      byte    // Our own type checking
+	Size   int     // Bit size of integers, byte size of hashes, length of arrays
+	Key    *Type   // Key type of maps, first type of pairs
+	Elem   *Type   // Element type of slices and arrays, value type of maps, second type of pairs
+	Struct *Struct // Definition of user-defined structs
+
+	stringKind string // holds the unparsed string for deriving signatures
+}
+
+// Struct is the definition of a user-defined struct (or class) serialized by
+// the contract. Base classes are encoded as nested lists in front of the fields.
+type Struct struct {
+	Name   string
+	Bases  []*Struct
+	Fields []Argument
+}
+
+// NewType creates a new reflection type of abi type given in t. The structs
+// map resolves user-defined struct names, it may be nil if none are used.
+func NewType(t string, structs map[string]*Struct) (Type, error) {
+	t = strings.TrimSpace(t)
+	typ := Type{stringKind: t}
+
+	// Slices and arrays are suffixed with their optional length
+	if strings.HasSuffix(t, "]") {
+		i := strings.LastIndex(t, "[")
+		if i <= 0 {
+			return Type{}, fmt.Errorf("invalid type %q", t)
+		}
+		elem, err := NewType(t[:i], structs)
+		if err != nil {
+			return Type{}, err
+		}
+		typ.Elem = &elem
+		if size := t[i+1 : len(t)-1]; size == "" {
+			typ.T = SliceTy
+		} else {
+			n, err := strconv.Atoi(size)
+			if err != nil || n <= 0 {
+				return Type{}, fmt.Errorf("invalid array length in type %q", t)
+			}
+			typ.T, typ.Size = ArrayTy, n
+		}
+		return typ, nil
+	}
+	// Templates carry their parameters between angle brackets
+	if i := strings.Index(t, "<"); i > 0 && strings.HasSuffix(t, ">") {
+		params := splitParams(t[i+1 : len(t)-1])
+		switch name := t[:i]; {
+		case name == "FixedHash" && len(params) == 1:
+			n, err := strconv.Atoi(params[0])
+			if err != nil || n <= 0 {
+				return Type{}, fmt.Errorf("invalid hash size in type %q", t)
+			}
+			typ.T, typ.Size = FixedHashTy, n
+			return typ, nil
+
+		case (name == "list" || name == "set") && len(params) == 1:
+			elem, err := NewType(params[0], structs)
+			if err != nil {
+				return Type{}, err
+			}
+			typ.T, typ.Elem = SliceTy, &elem
+			return typ, nil
+
+		case (name == "map" || name == "pair") && len(params) == 2:
+			key, err := NewType(params[0], structs)
+			if err != nil {
+				return Type{}, err
+			}
+			elem, err := NewType(params[1], structs)
+			if err != nil {
+				return Type{}, err
+			}
+			typ.T, typ.Key, typ.Elem = MapTy, &key, &elem
+			if name == "pair" {
+				typ.T = PairTy
+			}
+			return typ, nil
+		}
+		return Type{}, fmt.Errorf("unsupported template type %q", t)
+	}
+	switch t {
+	case "bool":
+		typ.T = BoolTy
+	case "uint8", "uint16", "uint32", "uint64":
+		typ.T = UintTy
+		typ.Size, _ = strconv.Atoi(t[4:])
+	case "int8", "int16", "int32", "int64":
+		typ.T = IntTy
+		typ.Size, _ = strconv.Atoi(t[3:])
+	case "uint128", "uint256":
+		typ.T = BigUintTy
+		typ.Size, _ = strconv.Atoi(t[4:])
+	case "string":
+		typ.T = StringTy
+	case "Address":
+		typ.T, typ.Size = FixedHashTy, 20
+	default:
+		def, ok := structs[t]
+		if !ok {
+			return Type{}, fmt.Errorf("unsupported arg type: %s", t)
+		}
+		typ.T, typ.Struct = StructTy, def
+	}
+	return typ, nil
+}
+
+// splitParams splits the comma separated template parameters, leaving the
+// commas of nested templates intact.
+func splitParams(s string) []string {
+	var (
+		params []string
+		depth  int
+		start  int
+	)
+	for i, c := range s {
+		switch c {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				params = append(params, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(params, strings.TrimSpace(s[start:]))
+}
+
+// String implements Stringer.
+func (t Type) String() string {
+	return t.stringKind
+}
+
+// IsBytes reports whether the type is a sequence of bytes, which is encoded
+// as a single RLP string instead of a list.
+func (t Type) IsBytes() bool {
+	return (t.T == SliceTy || t.T == ArrayTy) && t.Elem.T == UintTy && t.Elem.Size == 8
+}
+
+// HashedTopic reports whether an indexed event argument of this type may be
+// replaced by the Keccak256 hash of its encoding, in which case the original
+// value cannot be recovered from the log topic.
+func (t Type) HashedTopic() bool {
+	switch t.T {
+	case BoolTy, UintTy, IntTy:
+		return false
+	case BigUintTy:
+		return t.Size > 128
+	case FixedHashTy:
+		return t.Size >= 32
+	}
+	return true
+}
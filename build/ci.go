@@ -75,6 +75,7 @@ var (
 		executablePath("abigen"),
 		executablePath("ctool"),
 		executablePath("bootnode"),
+		executablePath("devp2p"),
 		executablePath("alaya"),
 		executablePath("rlpdump"),
 		executablePath("wnode"),
@@ -94,6 +95,10 @@ var (
 			BinaryName:  "bootnode",
 			Description: "Alaya bootnode.",
 		},
+		{
+			BinaryName:  "devp2p",
+			Description: "Tool for building and publishing DNS node lists.",
+		},
 		{
 			BinaryName:  "alaya",
 			Description: "Alaya CLI client.",
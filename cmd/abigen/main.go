@@ -17,6 +17,8 @@
 package main
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -41,6 +43,9 @@ var (
 
 	app *cli.App
 
+	// wasmMagic is the header of binary WASM modules
+	wasmMagic = []byte("\x00asm")
+
 	// Flags needed by abigen
 	abiFlag = cli.StringFlag{
 		Name:  "abi",
@@ -88,6 +93,10 @@ var (
 		Name:  "alias",
 		Usage: "Comma separated aliases for function and event renaming, e.g. foo=bar",
 	}
+	wasmFlag = cli.BoolFlag{
+		Name:  "wasm",
+		Usage: "Bind a WASM contract ABI (--abi), the --bin file may be the raw .wasm code",
+	}
 )
 
 func init() {
@@ -104,6 +113,7 @@ func init() {
 		outFlag,
 		langFlag,
 		aliasFlag,
+		wasmFlag,
 	}
 	app.Action = utils.MigrateFlags(abigen)
 	cli.CommandHelpTemplate = flags.OriginCommandHelpTemplate
@@ -114,6 +124,9 @@ func abigen(c *cli.Context) error {
 	if c.GlobalString(pkgFlag.Name) == "" {
 		utils.Fatalf("No destination package specified (--pkg)")
 	}
+	if c.GlobalBool(wasmFlag.Name) && c.GlobalString(abiFlag.Name) == "" {
+		utils.Fatalf("WASM bindings can only be generated from an ABI (--abi)")
+	}
 	var lang bind.Lang
 	switch c.GlobalString(langFlag.Name) {
 	case "go":
@@ -157,6 +170,9 @@ func abigen(c *cli.Context) error {
 			if bin, err = ioutil.ReadFile(binFile); err != nil {
 				utils.Fatalf("Failed to read input bytecode: %v", err)
 			}
+			if c.GlobalBool(wasmFlag.Name) && bytes.HasPrefix(bin, wasmMagic) {
+				bin = []byte(hex.EncodeToString(bin))
+			}
 			if strings.Contains(string(bin), "//") {
 				utils.Fatalf("Contract has additional library references, please use other mode(e.g. --combined-json) to catch library infos")
 			}
@@ -225,7 +241,15 @@ func abigen(c *cli.Context) error {
 		}
 	}
 	// Generate the contract binding
-	code, err := bind.Bind(types, abis, bins, sigs, c.GlobalString(pkgFlag.Name), lang, libs, aliases)
+	var (
+		code string
+		err  error
+	)
+	if c.GlobalBool(wasmFlag.Name) {
+		code, err = bind.BindWasm(types, abis, bins, c.GlobalString(pkgFlag.Name), lang, aliases)
+	} else {
+		code, err = bind.Bind(types, abis, bins, sigs, c.GlobalString(pkgFlag.Name), lang, libs, aliases)
+	}
 	if err != nil {
 		utils.Fatalf("Failed to generate ABI binding: %v", err)
 	}
@@ -54,6 +54,7 @@ var (
 		ArgsUsage: "<genesisPath>",
 		Flags: []cli.Flag{
 			utils.DataDirFlag,
+			utils.DBSnapshotBackendFlag,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
@@ -155,6 +156,22 @@ The first argument must be the directory containing the blockchain to download f
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
 Remove blockchain and state databases`,
+	}
+	migrateSnapshotDBCommand = cli.Command{
+		Action:    utils.MigrateFlags(migrateSnapshotDB),
+		Name:      "migrate-snapshotdb",
+		Usage:     "Convert the snapshotdb to another storage backend",
+		ArgsUsage: "<fromBackend> <toBackend>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The migrate-snapshotdb command copies the snapshotdb of the data directory from
+one storage backend to another, e.g. from leveldb to chaindb. The node must be
+stopped and the destination must be empty. Start the node with the same value of
+--db.snapshot_backend as the destination after.`,
 	}
 	dumpCommand = cli.Command{
 		Action:    utils.MigrateFlags(dump),
@@ -197,6 +214,10 @@ func initGenesis(ctx *cli.Context) error {
 		}
 		var sdb snapshotdb.DB
 		if name == "chaindata" {
+			snapshotdb.SetDBChainDatabase(chaindb)
+			if err := snapshotdb.SetDBBackend(ctx.GlobalString(utils.DBSnapshotBackendFlag.Name)); err != nil {
+				utils.Fatalf("%v", err)
+			}
 			sdb, err = snapshotdb.Open(stack.ResolvePath(snapshotdb.DBPath), 0, 0, true)
 			if err != nil {
 				utils.Fatalf("Failed to open snapshotdb: %v", err)
@@ -505,6 +526,56 @@ func removeDB(ctx *cli.Context) error {
 	return nil
 }
 
+func migrateSnapshotDB(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("This command requires two arguments: <fromBackend> <toBackend>, available backends: %v", snapshotdb.Backends())
+	}
+	from, to := ctx.Args().Get(0), ctx.Args().Get(1)
+	if from == to {
+		utils.Fatalf("The backends must be different")
+	}
+	if from == snapshotdb.BackendMemory || to == snapshotdb.BackendMemory {
+		utils.Fatalf("The memory backend is not persistent")
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	if from == snapshotdb.BackendChainDB || to == snapshotdb.BackendChainDB {
+		chainDb := utils.MakeChainDatabase(ctx, stack)
+		defer chainDb.Close()
+		snapshotdb.SetDBChainDatabase(chainDb)
+	}
+	path := stack.ResolvePath(snapshotdb.DBPath)
+	cache := ctx.GlobalInt(utils.CacheFlag.Name)
+
+	src, err := snapshotdb.OpenBackend(from, path, cache, 256)
+	if err != nil {
+		utils.Fatalf("Failed to open snapshotdb backend %s: %v", from, err)
+	}
+	defer src.Close()
+	dst, err := snapshotdb.OpenBackend(to, path, cache, 256)
+	if err != nil {
+		utils.Fatalf("Failed to open snapshotdb backend %s: %v", to, err)
+	}
+	defer dst.Close()
+
+	itr := dst.NewIterator(nil, nil)
+	empty := !itr.Next()
+	itr.Release()
+	if !empty {
+		utils.Fatalf("The snapshotdb backend %s is not empty", to)
+	}
+
+	start := time.Now()
+	count, err := snapshotdb.MigrateBackend(src, dst)
+	if err != nil {
+		utils.Fatalf("Failed to migrate snapshotdb: %v", err)
+	}
+	log.Info("Migrated snapshotdb", "from", from, "to", to, "keys", count, "elapsed", common.PrettyDuration(time.Since(start)))
+	fmt.Printf("The snapshotdb is migrated, start the node with --%s %s, the data of the %s backend can be removed\n",
+		utils.DBSnapshotBackendFlag.Name, to, from)
+	return nil
+}
+
 func dump(ctx *cli.Context) error {
 	stack := makeFullNode(ctx)
 	chain, chainDb := utils.MakeChain(ctx, stack)
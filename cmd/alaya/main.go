@@ -81,6 +81,7 @@ var (
 		utils.SyncModeFlag,
 		utils.LightServFlag,
 		utils.LightPeersFlag,
+		utils.LightVerifyQCFlag,
 		utils.LightKDFFlag,
 		utils.CacheFlag,
 		utils.CacheDatabaseFlag,
@@ -97,10 +98,12 @@ var (
 		//utils.MinerLegacyExtraDataFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
+		utils.DNSDiscoveryFlag,
 		//	utils.DiscoveryV5Flag,
 		utils.NetrestrictFlag,
 		utils.NodeKeyFileFlag,
 		utils.NodeKeyHexFlag,
+		utils.DeveloperFlag,
 		utils.DeveloperPeriodFlag,
 		utils.AlayaNetFlag,
 		utils.NetworkIdFlag,
@@ -141,6 +144,8 @@ var (
 		utils.MetricsInfluxDBUsernameFlag,
 		utils.MetricsInfluxDBPasswordFlag,
 		utils.MetricsInfluxDBHostTagFlag,
+		utils.MonitorValidatorFlag,
+		utils.MonitorWebhookFlag,
 	}
 
 	//mpcFlags = []cli.Flag{
@@ -160,6 +165,7 @@ var (
 		utils.CbftMaxPingLatency,
 		utils.CbftBlsPriKeyFileFlag,
 		utils.CbftBlacklistDeadlineFlag,
+		utils.CbftEvidenceReporterFlag,
 	}
 
 	dbFlags = []cli.Flag{
@@ -168,11 +174,14 @@ var (
 		utils.DBGCTimeoutFlag,
 		utils.DBGCMptFlag,
 		utils.DBGCBlockFlag,
+		utils.DBSnapshotArchiveFlag,
+		utils.DBSnapshotBackendFlag,
 	}
 
 	vmFlags = []cli.Flag{
 		utils.VMWasmType,
 		utils.VmTimeoutDuration,
+		utils.VMParallelCheckFlag,
 	}
 )
 
@@ -190,6 +199,9 @@ func init() {
 		exportPreimagesCommand,
 		copydbCommand,
 		removedbCommand,
+		migrateSnapshotDBCommand,
+		snapshotdbCommand,
+		walCommand,
 		dumpCommand,
 		// See accountcmd.go:
 		accountCommand,
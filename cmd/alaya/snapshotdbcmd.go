@@ -0,0 +1,254 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of Alaya-Go.
+//
+// Alaya-Go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alaya-Go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Alaya-Go. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/AlayaNetwork/Alaya-Go/cmd/utils"
+	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
+	"github.com/AlayaNetwork/Alaya-Go/node"
+)
+
+var (
+	snapshotdbCommand = cli.Command{
+		Name:     "snapshotdb",
+		Usage:    "Manage the snapshotdb of the PPOS data",
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The snapshotdb stores the PPOS data (staking, delegation, slashing, governance
+and restricting), it is not part of the state trie. The subcommands operate on
+the snapshotdb of the data directory, the node must be stopped.`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "export",
+				Usage:     "Export the snapshotdb base DB into a file",
+				ArgsUsage: "<dumpfile>",
+				Action:    utils.MigrateFlags(exportSnapshotDB),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.DBSnapshotBackendFlag,
+				},
+				Description: `
+    alaya snapshotdb export <dumpfile>
+
+Export the base DB of the snapshotdb, the PPOS data committed at the base block,
+into a versioned and checksummed file. The file also records the hash of the
+base block and the ppos hash stored in its state. If the file ends with .gz,
+the output will be gzipped.`,
+			},
+			{
+				Name:      "import",
+				Usage:     "Import the snapshotdb base DB from a file",
+				ArgsUsage: "<dumpfile>",
+				Action:    utils.MigrateFlags(importSnapshotDB),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.DBSnapshotBackendFlag,
+				},
+				Description: `
+    alaya snapshotdb import <dumpfile>
+
+Replace the snapshotdb with the base DB exported by 'alaya snapshotdb export'.
+The block of the export must be in the local chain with its state, and the ppos
+hash of the export must match the one committed in the state. The base DB is
+staged in a temporary database first, the snapshotdb is only replaced if the KV
+hash of the staged base DB matches the checksum of the file. The ppos hash only
+covers the writes of the export block and the checksum is part of the file, so
+they detect corrupted files but not forged ones, import files of trusted sources.`,
+			},
+			{
+				Name:   "verify",
+				Usage:  "Check the snapshotdb against the chain",
+				Action: utils.MigrateFlags(verifySnapshotDB),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.DBSnapshotBackendFlag,
+				},
+				Description: `
+    alaya snapshotdb verify
+
+Walk the journal blocks between the base num and the highest block of the
+snapshotdb, check they are continuous and match the headers of the chain, and
+compare their kv hashes with the ppos hashes committed in the state. The kv
+hashes of the journal blocks written by a node run with --db.snapshot_archive
+are also recomputed from the writes they record. The first divergent block is
+reported. The kv hash of the base DB is printed, it can be
+compared with the nodes at the same base num. The snapshotdb is not changed.`,
+			},
+			{
+				Name:      "repair",
+				Usage:     "Rewind the snapshotdb to a trusted block",
+				ArgsUsage: "[<blockNum>]",
+				Action:    utils.MigrateFlags(repairSnapshotDB),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.DBSnapshotBackendFlag,
+				},
+				Description: `
+    alaya snapshotdb repair [<blockNum>]
+
+Rewind the snapshotdb to the trusted block, the block before the first
+divergent block reported by verify if it is not given. The journal blocks after
+are removed, and the PPOS transactions of the blocks after are replayed from the
+chain when the node starts. The state of the trusted block must be available.
+The base DB is only rewound below the base num by a node run with
+--db.snapshot_archive, back to the first archived block. Otherwise import an
+export of the base DB with 'alaya snapshotdb import' instead.`,
+			},
+		},
+	}
+)
+
+// openSnapshotDB opens the snapshotdb of the node, the backend is set up by utils.MakeChain.
+func openSnapshotDB(ctx *cli.Context, stack *node.Node) snapshotdb.DB {
+	db, err := snapshotdb.Open(stack.ResolvePath(snapshotdb.DBPath), ctx.GlobalInt(utils.CacheFlag.Name), 256, false)
+	if err != nil {
+		utils.Fatalf("Failed to open snapshotdb: %v", err)
+	}
+	return db
+}
+
+func exportSnapshotDB(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+	db := openSnapshotDB(ctx, stack)
+	defer db.Close()
+
+	start := time.Now()
+	if err := utils.ExportSnapshotDB(chain, db, ctx.Args().First()); err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	fmt.Printf("Export done in %v\n", time.Since(start))
+	return nil
+}
+
+func importSnapshotDB(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+	db := openSnapshotDB(ctx, stack)
+	defer db.Close()
+
+	start := time.Now()
+	if err := utils.ImportSnapshotDB(chain, db, ctx.Args().First(), stack.ResolvePath(snapshotdb.DBPath+"_import")); err != nil {
+		utils.Fatalf("Import error: %v\n", err)
+	}
+	fmt.Printf("Import done in %v\n", time.Since(start))
+	return nil
+}
+
+// openSnapshotDBBackend opens the storage of the snapshotdb without recovering
+// it, the backend is set up by utils.MakeChain.
+func openSnapshotDBBackend(ctx *cli.Context, stack *node.Node) snapshotdb.Backend {
+	db, err := snapshotdb.OpenBackend(ctx.GlobalString(utils.DBSnapshotBackendFlag.Name), stack.ResolvePath(snapshotdb.DBPath), ctx.GlobalInt(utils.CacheFlag.Name), 256)
+	if err != nil {
+		utils.Fatalf("Failed to open snapshotdb: %v", err)
+	}
+	return db
+}
+
+func printVerifyReport(report *snapshotdb.VerifyReport) {
+	fmt.Printf("Base:     %d (%d keys, kv hash %x)\n", report.BaseNum, report.BaseKeys, report.BaseHash)
+	fmt.Printf("Highest:  %d (%x)\n", report.HighestNum, report.HighestHash)
+	fmt.Printf("Journals: %d consistent\n", report.Journals)
+	for _, warning := range report.Warnings {
+		fmt.Printf("Warning:  %s\n", warning)
+	}
+	if !report.Consistent() {
+		fmt.Printf("Divergent block %d: %s\n", report.Divergent, report.Reason)
+	}
+}
+
+func verifySnapshotDB(ctx *cli.Context) error {
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+	db := openSnapshotDBBackend(ctx, stack)
+	defer db.Close()
+
+	report, err := utils.VerifySnapshotDB(chain, db)
+	if err != nil {
+		utils.Fatalf("Verify error: %v", err)
+	}
+	printVerifyReport(report)
+	if !report.Consistent() {
+		utils.Fatalf("The snapshotdb is not consistent with the chain, run 'alaya snapshotdb repair' to rewind it")
+	}
+	fmt.Println("The snapshotdb is consistent with the chain")
+	return nil
+}
+
+func repairSnapshotDB(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		utils.Fatalf("This command accepts at most one argument.")
+	}
+	stack := makeFullNode(ctx)
+	chain, chainDb := utils.MakeChain(ctx, stack)
+	defer chainDb.Close()
+	db := openSnapshotDBBackend(ctx, stack)
+	defer db.Close()
+
+	report, err := utils.VerifySnapshotDB(chain, db)
+	if err != nil {
+		utils.Fatalf("Verify error: %v", err)
+	}
+	printVerifyReport(report)
+
+	var number uint64
+	if len(ctx.Args()) == 1 {
+		if number, err = strconv.ParseUint(ctx.Args().First(), 10, 64); err != nil {
+			utils.Fatalf("Invalid block number: %v", err)
+		}
+	} else if report.Consistent() {
+		fmt.Println("The snapshotdb is consistent with the chain, nothing to repair")
+		return nil
+	} else {
+		number = report.Divergent - 1
+	}
+	header := chain.GetHeaderByNumber(number)
+	if header == nil {
+		utils.Fatalf("The trusted block %d is not in the chain", number)
+	}
+	if _, err := chain.StateAt(header.Root); err != nil {
+		utils.Fatalf("The state of the trusted block %d is not available, the blocks after can't be replayed: %v", number, err)
+	}
+	removed, err := snapshotdb.Rewind(db, number, header.Hash())
+	if err != nil {
+		if number < report.BaseNum {
+			utils.Fatalf("Repair error: %v, import an export of the base DB with 'alaya snapshotdb import' instead", err)
+		}
+		utils.Fatalf("Repair error: %v", err)
+	}
+	fmt.Printf("The snapshotdb is rewound to block %d, %d journal blocks removed, the blocks after are replayed when the node starts\n", number, removed)
+	return nil
+}
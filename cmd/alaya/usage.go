@@ -77,12 +77,14 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.IdentityFlag,
 			utils.LightServFlag,
 			utils.LightPeersFlag,
+			utils.LightVerifyQCFlag,
 			utils.LightKDFFlag,
 		},
 	},
 	{
 		Name: "DEVELOPER CHAIN",
 		Flags: []cli.Flag{
+			utils.DeveloperFlag,
 			utils.DeveloperPeriodFlag,
 		},
 	},
@@ -162,6 +164,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MaxPendingPeersFlag,
 			utils.NATFlag,
 			utils.NoDiscoverFlag,
+			utils.DNSDiscoveryFlag,
 			//	utils.DiscoveryV5Flag,
 			utils.NetrestrictFlag,
 			utils.NodeKeyFileFlag,
@@ -222,6 +225,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.CbftMaxPingLatency,
 			utils.CbftBlsPriKeyFileFlag,
 			utils.CbftBlacklistDeadlineFlag,
+			utils.CbftEvidenceReporterFlag,
 		},
 	},
 	{
@@ -232,6 +236,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.DBGCTimeoutFlag,
 			utils.DBGCMptFlag,
 			utils.DBGCBlockFlag,
+			utils.DBSnapshotArchiveFlag,
+			utils.DBSnapshotBackendFlag,
 		},
 	},
 	{
@@ -239,6 +245,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.VMWasmType,
 			utils.VmTimeoutDuration,
+			utils.VMParallelCheckFlag,
 		},
 	},
 	{
@@ -0,0 +1,265 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of Alaya-Go.
+//
+// Alaya-Go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alaya-Go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Alaya-Go. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/AlayaNetwork/Alaya-Go/cmd/utils"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/wal"
+)
+
+var (
+	walDirFlag = cli.StringFlag{
+		Name:  "wal.dir",
+		Usage: "Directory of the consensus WAL (default = inside the datadir)",
+	}
+	walEpochFlag = cli.Uint64Flag{
+		Name:  "epoch",
+		Usage: "Only show the entries of the epoch",
+	}
+	walViewFlag = cli.Uint64Flag{
+		Name:  "view",
+		Usage: "Only show the entries of the view number",
+	}
+	walReplayFlag = cli.BoolFlag{
+		Name:  "replay",
+		Usage: "Only show the entries replayed when the node starts",
+	}
+
+	walCommand = cli.Command{
+		Name:     "wal",
+		Usage:    "Inspect and repair the consensus WAL",
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The consensus WAL records the consensus state and the messages sent by the
+local validator, they are replayed when the node starts so that it never signs
+conflicting messages. The WAL consists of the journal files wal.<fileID> and of
+the wal_meta database. The subcommands operate on the WAL of the data
+directory, the node must be stopped.`,
+		Subcommands: []cli.Command{
+			{
+				Name:   "list",
+				Usage:  "List the journal files and the WAL database",
+				Action: utils.MigrateFlags(listWal),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					walDirFlag,
+				},
+				Description: `
+    alaya wal list
+
+List the journal files with their size, the chain state and the confirmed view
+change saved in the WAL database. The journal is replayed from the position of
+the confirmed view change.`,
+			},
+			{
+				Name:   "meta",
+				Usage:  "Print the WAL database as JSON",
+				Action: utils.MigrateFlags(printWalMeta),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					walDirFlag,
+				},
+				Description: `
+    alaya wal meta
+
+Print the chain state, the confirmed view change and the viewChangeQCs saved in
+the WAL database as JSON.`,
+			},
+			{
+				Name:   "dump",
+				Usage:  "Print the journal entries as JSON",
+				Action: utils.MigrateFlags(dumpWal),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					walDirFlag,
+					walEpochFlag,
+					walViewFlag,
+					walReplayFlag,
+				},
+				Description: `
+    alaya wal dump [--epoch <epoch>] [--view <view>] [--replay]
+
+Decode the journal entries (ConfirmedViewChange, SendViewChange,
+SendPrepareBlock and SendPrepareVote) and print one JSON object per line, with
+the journal file and the offset of the entry. Blocks are printed as a summary.
+The checksum of every entry is verified, the dump stops at the first corrupted
+entry.`,
+			},
+			{
+				Name:   "verify",
+				Usage:  "Verify the checksums of the journal",
+				Action: utils.MigrateFlags(verifyWal),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					walDirFlag,
+				},
+				Description: `
+    alaya wal verify
+
+Verify the checksum of every journal entry and check it can be decoded. The
+first corrupted entry is reported, it can be removed with 'alaya wal truncate'.`,
+			},
+			{
+				Name:      "truncate",
+				Usage:     "Discard the journal entries from a position",
+				ArgsUsage: "<fileID> <seq>",
+				Action:    utils.MigrateFlags(truncateWal),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					walDirFlag,
+				},
+				Description: `
+    alaya wal truncate <fileID> <seq>
+
+Discard the journal entry at the offset <seq> of the journal file <fileID> and
+all the entries after it, the following journal files are removed. The position
+must be the start of an entry as printed by 'alaya wal dump' or 'alaya wal
+verify'. If the node replays the journal from a discarded entry, the replay
+position is moved back to the truncation point. The messages of the discarded
+entries are not replayed when the node starts, so the node may sign messages
+conflicting with them, only discard entries of views the node won't take part
+in again.`,
+			},
+		},
+	}
+)
+
+// walDir returns the directory of the consensus WAL.
+func walDir(ctx *cli.Context) string {
+	if dir := ctx.String(walDirFlag.Name); dir != "" {
+		return dir
+	}
+	cfg := defaultNodeConfig()
+	utils.SetNodeConfig(ctx, &cfg)
+	return cfg.ResolvePath("wal")
+}
+
+func readWalMeta(dir string) *wal.Meta {
+	meta, err := wal.ReadMeta(dir)
+	if err != nil {
+		utils.Fatalf("Failed to read the WAL database: %v", err)
+	}
+	return meta
+}
+
+func listWal(ctx *cli.Context) error {
+	dir := walDir(ctx)
+	files, err := wal.ListJournalFiles(dir)
+	if err != nil {
+		utils.Fatalf("Failed to list the journal files: %v", err)
+	}
+	fmt.Printf("Directory: %s\n", dir)
+	for _, f := range files {
+		fmt.Printf("Journal:   %s (fileID %d, %d bytes)\n", f.Name, f.FileID, f.Size)
+	}
+	meta := readWalMeta(dir)
+	if cs := meta.ChainState; cs != nil && cs.ValidChainState() {
+		fmt.Printf("Commit:    %d (%x)\n", cs.Commit.Block.NumberU64(), cs.Commit.Block.Hash())
+		fmt.Printf("Lock:      %d (%x)\n", cs.Lock.Block.NumberU64(), cs.Lock.Block.Hash())
+		for _, qc := range cs.QC {
+			fmt.Printf("QC:        %d (%x)\n", qc.Block.NumberU64(), qc.Block.Hash())
+		}
+	}
+	if vc := meta.ViewChange; vc != nil {
+		fmt.Printf("View:      epoch %d, view %d, replay from fileID %d seq %d\n", vc.Epoch, vc.ViewNumber, vc.FileID, vc.Seq)
+	}
+	fmt.Printf("ViewChangeQCs: %d\n", len(meta.ViewChangeQCs))
+	return nil
+}
+
+func printWalMeta(ctx *cli.Context) error {
+	data, err := json.MarshalIndent(readWalMeta(walDir(ctx)), "", "  ")
+	if err != nil {
+		utils.Fatalf("Failed to encode the WAL database: %v", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func dumpWal(ctx *cli.Context) error {
+	dir := walDir(ctx)
+	var fileID, seq uint64
+	if ctx.Bool(walReplayFlag.Name) {
+		if vc := readWalMeta(dir).ViewChange; vc != nil {
+			fileID, seq = uint64(vc.FileID), vc.Seq
+		}
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	err := wal.IterateJournal(dir, uint32(fileID), seq, func(entry *wal.JournalEntry) error {
+		epoch, view := entry.View()
+		if ctx.IsSet(walEpochFlag.Name) && epoch != ctx.Uint64(walEpochFlag.Name) {
+			return nil
+		}
+		if ctx.IsSet(walViewFlag.Name) && view != ctx.Uint64(walViewFlag.Name) {
+			return nil
+		}
+		return encoder.Encode(entry)
+	})
+	if err != nil {
+		utils.Fatalf("Dump error: %v", err)
+	}
+	return nil
+}
+
+func verifyWal(ctx *cli.Context) error {
+	counts := make(map[uint16]int)
+	err := wal.IterateJournal(walDir(ctx), 0, 0, func(entry *wal.JournalEntry) error {
+		counts[entry.Type]++
+		return nil
+	})
+	for msgType := uint16(1); msgType <= 4; msgType++ {
+		fmt.Printf("%-20s %d\n", wal.WalMessageName(msgType)+":", counts[msgType])
+	}
+	if jerr, ok := err.(*wal.JournalError); ok {
+		utils.Fatalf("%v, run 'alaya wal truncate %d %d' to discard it and the entries after", jerr, jerr.FileID, jerr.Seq)
+	} else if err != nil {
+		utils.Fatalf("Verify error: %v", err)
+	}
+	fmt.Println("The journal is valid")
+	return nil
+}
+
+func truncateWal(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("This command requires two arguments.")
+	}
+	fileID, err := strconv.ParseUint(ctx.Args().Get(0), 10, 32)
+	if err != nil {
+		utils.Fatalf("Invalid fileID: %v", err)
+	}
+	seq, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid seq: %v", err)
+	}
+	dir := walDir(ctx)
+	if err := wal.TruncateJournal(dir, uint32(fileID), seq); err != nil {
+		utils.Fatalf("Truncate error: %v", err)
+	}
+	fmt.Printf("The journal is truncated at fileID %d seq %d\n", fileID, seq)
+	if meta, err := wal.ReadMeta(dir); err == nil && meta.ViewChange != nil {
+		vc := meta.ViewChange
+		fmt.Printf("The journal is replayed from fileID %d seq %d\n", vc.FileID, vc.Seq)
+	}
+	return nil
+}
@@ -36,6 +36,9 @@ var (
 			getGovernParamValueCmd,
 			getAccuVerifiersCountCmd,
 			listGovernParamCmd,
+			draftProposalCmd,
+			submitProposalCmd,
+			tallyProgressCmd,
 		},
 	}
 	getProposalCmd = cli.Command{
@@ -0,0 +1,747 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of Alaya-Go.
+//
+// Alaya-Go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alaya-Go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Alaya-Go. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
+	"github.com/AlayaNetwork/Alaya-Go/ethclient"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/rpc"
+	"github.com/AlayaNetwork/Alaya-Go/x/gov"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
+	"github.com/AlayaNetwork/Alaya-Go/x/xutil"
+)
+
+var (
+	draftProposalCmd = cli.Command{
+		Name:   "draftProposal",
+		Usage:  "validate a proposal against the chain and show its voting schedule,parameter:type,verifier,pipID,...",
+		Before: netCheck,
+		Action: draftProposal,
+		Flags:  proposalDraftFlags,
+	}
+	submitProposalCmd = cli.Command{
+		Name:   "submitProposal",
+		Usage:  "validate a proposal and submit it through an unlocked account of the node,parameter:from,type,verifier,pipID,...",
+		Before: netCheck,
+		Action: submitProposal,
+		Flags:  append([]cli.Flag{fromFlag}, proposalDraftFlags...),
+	}
+	tallyProgressCmd = cli.Command{
+		Name:   "tallyProgress",
+		Usage:  "show the live tally of a proposal and predict its result,parameter:proposalID",
+		Before: netCheck,
+		Action: tallyProgress,
+		Flags:  []cli.Flag{rpcUrlFlag, addressHRPFlag, proposalIDFlag, blockHashFlag},
+	}
+
+	proposalDraftFlags = []cli.Flag{rpcUrlFlag, addressHRPFlag, proposalTypeFlag, verifierFlag, pipIDFlag,
+		newVersionFlag, endVotingRoundsFlag, moduleFlag, nameFlag, newValueFlag, tobeCanceledFlag, jsonFlag}
+
+	proposalTypeFlag = cli.StringFlag{
+		Name:  "type",
+		Usage: "proposal type: text, version, param or cancel",
+	}
+	verifierFlag = cli.StringFlag{
+		Name:  "verifier",
+		Usage: "node id of the proposer",
+	}
+	pipIDFlag = cli.StringFlag{
+		Name:  "pipID",
+		Usage: "PIP id of the proposal",
+	}
+	newVersionFlag = cli.StringFlag{
+		Name:  "newVersion",
+		Usage: "new version of a version proposal, eg: 0.15.0",
+	}
+	endVotingRoundsFlag = cli.Uint64Flag{
+		Name:  "endVotingRounds",
+		Usage: "voting consensus rounds of a version or cancel proposal",
+	}
+	newValueFlag = cli.StringFlag{
+		Name:  "newValue",
+		Usage: "new value of a param proposal",
+	}
+	tobeCanceledFlag = cli.StringFlag{
+		Name:  "tobeCanceled",
+		Usage: "proposalID to be canceled",
+	}
+	fromFlag = cli.StringFlag{
+		Name:  "from",
+		Usage: "staking address of the proposer, must be unlocked in the node",
+	}
+)
+
+// proposalDraft is a proposal that has not been submitted yet.
+type proposalDraft struct {
+	Type            gov.ProposalType
+	Verifier        discover.NodeID
+	PIPID           string
+	NewVersion      uint32
+	EndVotingRounds uint64
+	Module          string
+	Name            string
+	NewValue        string
+	TobeCanceled    common.Hash
+}
+
+// proposalInfo holds the fields of any proposal type returned by listProposal.
+type proposalInfo struct {
+	ProposalID      common.Hash
+	ProposalType    gov.ProposalType
+	PIPID           string
+	SubmitBlock     uint64
+	EndVotingRounds uint64
+	EndVotingBlock  uint64
+	Proposer        discover.NodeID
+	NewVersion      uint32
+	ActiveBlock     uint64
+	TobeCanceled    common.Hash
+	Module          string
+	Name            string
+	NewValue        string
+
+	Status gov.ProposalStatus `json:"-"`
+}
+
+// govChainState is the part of the chain the proposal checks depend on.
+type govChainState struct {
+	blockNumber   uint64
+	blockHash     common.Hash
+	activeVersion uint32
+	proposals     []*proposalInfo
+	params        []*gov.GovernParam
+}
+
+// draftSchedule is the outcome of a valid draft.
+type draftSchedule struct {
+	SubmitBlock    uint64
+	EndVotingBlock uint64
+	ActiveBlock    uint64
+	Param          *gov.GovernParam
+}
+
+// tallyPrediction is the result a proposal would get if voting ended now.
+type tallyPrediction struct {
+	VoteRate            uint64
+	SupportRate         uint64
+	RequiredVoteRate    uint64
+	RequiredSupportRate uint64
+	Pass                bool
+	// MoreYeas is how many more yeas are needed to pass, 0 if passing already.
+	MoreYeas uint64
+}
+
+func parseProposalType(s string) (gov.ProposalType, error) {
+	switch strings.ToLower(s) {
+	case "text":
+		return gov.Text, nil
+	case "version":
+		return gov.Version, nil
+	case "param":
+		return gov.Param, nil
+	case "cancel":
+		return gov.Cancel, nil
+	}
+	return 0, fmt.Errorf("unknown proposal type %q", s)
+}
+
+func proposalTypeName(t gov.ProposalType) string {
+	switch t {
+	case gov.Text:
+		return "text"
+	case gov.Version:
+		return "version"
+	case gov.Param:
+		return "param"
+	case gov.Cancel:
+		return "cancel"
+	}
+	return "unknown"
+}
+
+func proposalStatusName(s gov.ProposalStatus) string {
+	switch s {
+	case gov.Voting:
+		return "voting"
+	case gov.Pass:
+		return "pass"
+	case gov.Failed:
+		return "failed"
+	case gov.PreActive:
+		return "pre-active"
+	case gov.Active:
+		return "active"
+	case gov.Canceled:
+		return "canceled"
+	}
+	return "unknown"
+}
+
+// parseVersion parses a version like 0.15.0 into the program version format.
+func parseVersion(s string) (uint32, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid version %q, expect major.minor.patch", s)
+	}
+	var version uint32
+	for _, p := range parts {
+		var n uint32
+		if _, err := fmt.Sscanf(p, "%d", &n); err != nil || n > 255 {
+			return 0, fmt.Errorf("invalid version %q", s)
+		}
+		version = version<<8 | n
+	}
+	return version, nil
+}
+
+func draftFromContext(c *cli.Context) (*proposalDraft, error) {
+	typ, err := parseProposalType(c.String(proposalTypeFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := discover.HexID(c.String(verifierFlag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid verifier: %v", err)
+	}
+	d := &proposalDraft{
+		Type:            typ,
+		Verifier:        verifier,
+		PIPID:           c.String(pipIDFlag.Name),
+		EndVotingRounds: c.Uint64(endVotingRoundsFlag.Name),
+		Module:          c.String(moduleFlag.Name),
+		Name:            c.String(nameFlag.Name),
+		NewValue:        c.String(newValueFlag.Name),
+	}
+	if typ == gov.Version {
+		if d.NewVersion, err = parseVersion(c.String(newVersionFlag.Name)); err != nil {
+			return nil, err
+		}
+	}
+	if typ == gov.Cancel {
+		tobeCanceled := c.String(tobeCanceledFlag.Name)
+		if tobeCanceled == "" {
+			return nil, errors.New("tobeCanceled not set")
+		}
+		d.TobeCanceled = common.HexToHash(tobeCanceled)
+	}
+	return d, nil
+}
+
+// callParams returns the gov contract function and its parameters for submitting the draft.
+func (d *proposalDraft) callParams() (uint16, []interface{}) {
+	switch d.Type {
+	case gov.Version:
+		return 2001, []interface{}{d.Verifier, d.PIPID, d.NewVersion, d.EndVotingRounds}
+	case gov.Param:
+		return 2002, []interface{}{d.Verifier, d.PIPID, d.Module, d.Name, d.NewValue}
+	case gov.Cancel:
+		return 2005, []interface{}{d.Verifier, d.PIPID, d.EndVotingRounds, d.TobeCanceled}
+	default:
+		return 2000, []interface{}{d.Verifier, d.PIPID}
+	}
+}
+
+func (st *govChainState) findProposal(id common.Hash) *proposalInfo {
+	for _, p := range st.proposals {
+		if p.ProposalID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (st *govChainState) findParam(module, name string) *gov.GovernParam {
+	for _, p := range st.params {
+		if p.ParamItem.Module == module && p.ParamItem.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func (st *govChainState) paramValueByName(name string) (string, bool) {
+	for _, p := range st.params {
+		if strings.EqualFold(p.ParamItem.Name, name) {
+			return p.ParamValue.Value, true
+		}
+	}
+	return "", false
+}
+
+func (st *govChainState) votingProposal(types ...gov.ProposalType) *proposalInfo {
+	for _, p := range st.proposals {
+		if p.Status != gov.Voting {
+			continue
+		}
+		for _, t := range types {
+			if p.ProposalType == t {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+func (st *govChainState) hasPreActive() bool {
+	for _, p := range st.proposals {
+		if p.Status == gov.PreActive {
+			return true
+		}
+	}
+	return false
+}
+
+// validate runs the checks the gov contract performs when the draft is
+// submitted in the next block, and returns the resulting voting schedule.
+func (d *proposalDraft) validate(st *govChainState) (*draftSchedule, error) {
+	if d.Verifier == discover.ZeroNodeID {
+		return nil, gov.ProposerEmpty
+	}
+	if d.PIPID == "" {
+		return nil, gov.PIPIDEmpty
+	}
+	for _, p := range st.proposals {
+		if p.PIPID == d.PIPID && p.Status != gov.Failed && p.Status != gov.Canceled {
+			return nil, gov.PIPIDExist
+		}
+	}
+
+	sch := &draftSchedule{SubmitBlock: st.blockNumber + 1}
+	switch d.Type {
+	case gov.Text:
+		sch.EndVotingBlock = xutil.CalEndVotingBlock(sch.SubmitBlock, xutil.EstimateConsensusRoundsForGov(xcom.TextProposalVote_DurationSeconds()))
+	case gov.Version:
+		if d.EndVotingRounds == 0 {
+			return nil, gov.EndVotingRoundsTooSmall
+		}
+		if d.EndVotingRounds > xutil.EstimateConsensusRoundsForGov(xcom.VersionProposalVote_DurationSeconds()) {
+			return nil, gov.EndVotingRoundsTooLarge
+		}
+		if d.NewVersion>>8 <= st.activeVersion>>8 {
+			return nil, gov.NewVersionError
+		}
+		if err := st.checkNoVotingUpgrade(); err != nil {
+			return nil, err
+		}
+		sch.EndVotingBlock = xutil.CalEndVotingBlock(sch.SubmitBlock, d.EndVotingRounds)
+		sch.ActiveBlock = xutil.CalActiveBlock(sch.EndVotingBlock)
+	case gov.Param:
+		param := st.findParam(d.Module, d.Name)
+		if param == nil {
+			return nil, gov.UnsupportedGovernParam
+		}
+		if param.ParamValue.Value == d.NewValue {
+			return nil, gov.ParamProposalIsSameValue
+		}
+		if err := st.checkParamRange(param, d.NewValue); err != nil {
+			return nil, err
+		}
+		if err := st.checkNoVotingUpgrade(); err != nil {
+			return nil, err
+		}
+		sch.Param = param
+		sch.EndVotingBlock = xutil.EstimateEndVotingBlockForParaProposal(sch.SubmitBlock, xcom.ParamProposalVote_DurationSeconds())
+		sch.ActiveBlock = sch.EndVotingBlock + 1
+	case gov.Cancel:
+		if d.EndVotingRounds == 0 {
+			return nil, gov.EndVotingRoundsTooSmall
+		}
+		sch.EndVotingBlock = xutil.CalEndVotingBlock(sch.SubmitBlock, d.EndVotingRounds)
+		if st.votingProposal(gov.Cancel) != nil {
+			return nil, gov.VotingCancelProposalExist
+		}
+		tobeCanceled := st.findProposal(d.TobeCanceled)
+		if tobeCanceled == nil {
+			return nil, gov.TobeCanceledProposalNotFound
+		}
+		if tobeCanceled.ProposalType != gov.Version && tobeCanceled.ProposalType != gov.Param {
+			return nil, gov.TobeCanceledProposalTypeError
+		}
+		if tobeCanceled.Status != gov.Voting {
+			return nil, gov.TobeCanceledProposalNotAtVoting
+		}
+		if sch.EndVotingBlock >= tobeCanceled.EndVotingBlock {
+			return nil, gov.EndVotingRoundsTooLarge
+		}
+	default:
+		return nil, gov.ProposalTypeError
+	}
+	return sch, nil
+}
+
+func (st *govChainState) checkNoVotingUpgrade() error {
+	if exist := st.votingProposal(gov.Version, gov.Param); exist != nil {
+		if exist.ProposalType == gov.Version {
+			return gov.VotingVersionProposalExist
+		}
+		return gov.VotingParamProposalExist
+	}
+	if st.hasPreActive() {
+		return gov.PreActiveVersionProposalExist
+	}
+	return nil
+}
+
+// checkParamRange checks value against the range in the parameter's
+// description, eg "range: [1, UnStakeFreezeDuration)". A bound may be the
+// name of another parameter, which is resolved to its current value.
+func (st *govChainState) checkParamRange(param *gov.GovernParam, value string) error {
+	desc := param.ParamItem.Desc
+	idx := strings.LastIndex(desc, "range:")
+	if idx < 0 {
+		return nil
+	}
+	rng := strings.TrimSpace(desc[idx+len("range:"):])
+	if len(rng) < 2 {
+		return nil
+	}
+	lowerOpen, upperOpen := rng[0] == '(', rng[len(rng)-1] == ')'
+	bounds := strings.Split(rng[1:len(rng)-1], ",")
+	if len(bounds) != 2 {
+		return nil
+	}
+	v, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return fmt.Errorf("the value of %s must be an integer", param.ParamItem.Name)
+	}
+	lower, err := st.resolveBound(bounds[0])
+	if err != nil {
+		return err
+	}
+	upper, err := st.resolveBound(bounds[1])
+	if err != nil {
+		return err
+	}
+	if cmp := v.Cmp(lower); cmp < 0 || (lowerOpen && cmp == 0) {
+		return fmt.Errorf("the value of %s is out of %s", param.ParamItem.Name, rng)
+	}
+	if cmp := v.Cmp(upper); cmp > 0 || (upperOpen && cmp == 0) {
+		return fmt.Errorf("the value of %s is out of %s", param.ParamItem.Name, rng)
+	}
+	return nil
+}
+
+func (st *govChainState) resolveBound(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	if n, ok := new(big.Int).SetString(s, 10); ok {
+		return n, nil
+	}
+	value, ok := st.paramValueByName(s)
+	if !ok {
+		return nil, fmt.Errorf("unknown range bound %q", s)
+	}
+	n, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid value %q of %s", value, s)
+	}
+	return n, nil
+}
+
+// predictTally applies the tally rules of the gov plugin to the current votes.
+func predictTally(typ gov.ProposalType, accuVerifiers, yeas, nays, abstentions uint64) *tallyPrediction {
+	p := new(tallyPrediction)
+	if typ == gov.Version {
+		// all votes of a version proposal count as yeas.
+		p.RequiredSupportRate = xcom.VersionProposal_SupportRate()
+		if accuVerifiers > 0 {
+			p.SupportRate = yeas * gov.RateCoefficient / accuVerifiers
+		}
+		p.Pass = accuVerifiers > 0 && p.SupportRate >= p.RequiredSupportRate
+		for more := uint64(1); !p.Pass && yeas+more <= accuVerifiers; more++ {
+			if (yeas+more)*gov.RateCoefficient/accuVerifiers >= p.RequiredSupportRate {
+				p.MoreYeas = more
+				break
+			}
+		}
+		return p
+	}
+
+	switch typ {
+	case gov.Text:
+		p.RequiredVoteRate, p.RequiredSupportRate = xcom.TextProposal_VoteRate(), xcom.TextProposal_SupportRate()
+	case gov.Cancel:
+		p.RequiredVoteRate, p.RequiredSupportRate = xcom.CancelProposal_VoteRate(), xcom.CancelProposal_SupportRate()
+	case gov.Param:
+		p.RequiredVoteRate, p.RequiredSupportRate = xcom.ParamProposal_VoteRate(), xcom.ParamProposal_SupportRate()
+	}
+	passes := func(y uint64) (bool, uint64, uint64) {
+		total := y + nays + abstentions
+		if total == 0 || accuVerifiers == 0 {
+			return false, 0, 0
+		}
+		voteRate := total * gov.RateCoefficient / accuVerifiers
+		supportRate := y * gov.RateCoefficient / total
+		return voteRate > p.RequiredVoteRate && supportRate >= p.RequiredSupportRate, voteRate, supportRate
+	}
+	p.Pass, p.VoteRate, p.SupportRate = passes(yeas)
+	voted := yeas + nays + abstentions
+	for more := uint64(1); !p.Pass && voted+more <= accuVerifiers; more++ {
+		if pass, _, _ := passes(yeas + more); pass {
+			p.MoreYeas = more
+			break
+		}
+	}
+	return p
+}
+
+func formatRate(rate uint64) string {
+	return fmt.Sprintf("%d.%02d%%", rate/100, rate%100)
+}
+
+// govResult decodes the Ret of a ppos call result into out.
+func govResult(client *ethclient.Client, funcType uint16, out interface{}, params ...interface{}) error {
+	res, err := CallPPosContract(client, funcType, params...)
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Code uint32
+		Ret  json.RawMessage
+	}
+	if err := json.Unmarshal(res, &result); err != nil {
+		return err
+	}
+	if result.Code != common.NoErr.Code {
+		var msg string
+		json.Unmarshal(result.Ret, &msg)
+		return &common.BizError{Code: result.Code, Msg: msg}
+	}
+	return json.Unmarshal(result.Ret, out)
+}
+
+// loadEconomicModel loads the economic model of the node, which holds the
+// voting durations and rates, falling back to the Alaya defaults if the
+// debug api is not available.
+func loadEconomicModel(client *rpc.Client) {
+	var config string
+	if err := client.Call(&config, "debug_economicConfig"); err == nil && config != "" {
+		ec := new(xcom.EconomicModel)
+		if err := json.Unmarshal([]byte(config), ec); err == nil {
+			xcom.ResetEconomicDefaultConfig(ec)
+			return
+		}
+	}
+	fmt.Println("economic config not available from the node, using the default config of Alaya")
+	xcom.GetEc(xcom.DefaultAlayaNet)
+}
+
+func loadGovChainState(client *ethclient.Client) (*govChainState, error) {
+	header, err := client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	st := &govChainState{blockNumber: header.Number.Uint64(), blockHash: header.Hash()}
+	if err := govResult(client, 2103, &st.activeVersion); err != nil {
+		return nil, err
+	}
+	if err := govResult(client, 2106, &st.params, ""); err != nil {
+		return nil, err
+	}
+	if err := govResult(client, 2102, &st.proposals); err != nil {
+		if bizErr, ok := err.(*common.BizError); !ok || bizErr.Code != common.NotFound.Code {
+			return nil, err
+		}
+	}
+	for _, p := range st.proposals {
+		var tally gov.TallyResult
+		if err := govResult(client, 2101, &tally, p.ProposalID); err != nil {
+			if _, ok := err.(*common.BizError); !ok {
+				return nil, err
+			}
+			// there is no tally result until the voting ends
+			p.Status = gov.Voting
+			continue
+		}
+		p.Status = tally.Status
+	}
+	return st, nil
+}
+
+func dialGov(c *cli.Context) (*rpc.Client, *ethclient.Client, error) {
+	url := c.String(rpcUrlFlag.Name)
+	if url == "" {
+		return nil, nil, errors.New("rpc url not set")
+	}
+	rpcClient, err := rpc.Dial(url)
+	if err != nil {
+		return nil, nil, err
+	}
+	loadEconomicModel(rpcClient)
+	return rpcClient, ethclient.NewClient(rpcClient), nil
+}
+
+func validateDraft(c *cli.Context) (*rpc.Client, *proposalDraft, error) {
+	d, err := draftFromContext(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	rpcClient, client, err := dialGov(c)
+	if err != nil {
+		return nil, nil, err
+	}
+	st, err := loadGovChainState(client)
+	if err != nil {
+		return nil, nil, err
+	}
+	sch, err := d.validate(st)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s proposal: %v", proposalTypeName(d.Type), err)
+	}
+
+	fmt.Printf("%s proposal %s is valid at block %d\n", proposalTypeName(d.Type), d.PIPID, st.blockNumber)
+	fmt.Printf("  submit block:      %d\n", sch.SubmitBlock)
+	fmt.Printf("  end voting block:  %d\n", sch.EndVotingBlock)
+	if sch.ActiveBlock != 0 {
+		fmt.Printf("  active block:      %d\n", sch.ActiveBlock)
+	}
+	switch d.Type {
+	case gov.Version:
+		fmt.Printf("  version change:    %s -> %s\n", xutil.ProgramVersion2Str(st.activeVersion), xutil.ProgramVersion2Str(d.NewVersion))
+	case gov.Param:
+		fmt.Printf("  param change:      %s.%s %s -> %s\n", d.Module, d.Name, sch.Param.ParamValue.Value, d.NewValue)
+	case gov.Cancel:
+		fmt.Printf("  to be canceled:    %s\n", d.TobeCanceled.Hex())
+	}
+	return rpcClient, d, nil
+}
+
+func draftProposal(c *cli.Context) error {
+	_, d, err := validateDraft(c)
+	if err != nil {
+		return err
+	}
+	if c.Bool(jsonFlag.Name) {
+		funcType, params := d.callParams()
+		res, err := BuildPPosContract(funcType, params...)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(res))
+	}
+	return nil
+}
+
+func submitProposal(c *cli.Context) error {
+	fromString := c.String(fromFlag.Name)
+	if fromString == "" {
+		return errors.New("from not set")
+	}
+	from, err := common.Bech32ToAddress(fromString)
+	if err != nil {
+		return err
+	}
+	rpcClient, d, err := validateDraft(c)
+	if err != nil {
+		return err
+	}
+	funcType, params := d.callParams()
+	data, to := EncodePPOS(funcType, params...)
+	args := map[string]interface{}{
+		"from": from,
+		"to":   to,
+		"data": hexutil.Bytes(data),
+	}
+	var txHash common.Hash
+	if err := rpcClient.Call(&txHash, "platon_sendTransaction", args); err != nil {
+		return err
+	}
+	fmt.Printf("proposal submitted, tx hash: %s\n", txHash.Hex())
+	return nil
+}
+
+func tallyProgress(c *cli.Context) error {
+	proposalIDstring := c.String(proposalIDFlag.Name)
+	if proposalIDstring == "" {
+		return errors.New("proposalID not set")
+	}
+	proposalID := common.HexToHash(proposalIDstring)
+
+	_, client, err := dialGov(c)
+	if err != nil {
+		return err
+	}
+	st, err := loadGovChainState(client)
+	if err != nil {
+		return err
+	}
+	p := st.findProposal(proposalID)
+	if p == nil {
+		return gov.ProposalNotFound
+	}
+	blockHash := st.blockHash
+	if hash := c.String(blockHashFlag.Name); hash != "" {
+		blockHash = common.HexToHash(hash)
+	}
+	var counts []uint64
+	if err := govResult(client, 2105, &counts, proposalID, blockHash); err != nil {
+		return err
+	}
+	if len(counts) != 4 {
+		return fmt.Errorf("unexpected verifier count result: %v", counts)
+	}
+	accuVerifiers, yeas, nays, abstentions := counts[0], counts[1], counts[2], counts[3]
+
+	fmt.Printf("%s proposal %s (%s)\n", proposalTypeName(p.ProposalType), p.PIPID, proposalID.Hex())
+	fmt.Printf("  status:            %s\n", proposalStatusName(p.Status))
+	fmt.Printf("  end voting block:  %d (current %d)\n", p.EndVotingBlock, st.blockNumber)
+	fmt.Printf("  verifiers:         %d\n", accuVerifiers)
+	fmt.Printf("  yeas:              %d\n", yeas)
+	if p.ProposalType != gov.Version {
+		fmt.Printf("  nays:              %d\n", nays)
+		fmt.Printf("  abstentions:       %d\n", abstentions)
+	}
+
+	pred := predictTally(p.ProposalType, accuVerifiers, yeas, nays, abstentions)
+	if p.ProposalType != gov.Version {
+		fmt.Printf("  vote rate:         %s (must exceed %s)\n", formatRate(pred.VoteRate), formatRate(pred.RequiredVoteRate))
+	}
+	fmt.Printf("  support rate:      %s (required %s)\n", formatRate(pred.SupportRate), formatRate(pred.RequiredSupportRate))
+
+	switch {
+	case pred.Pass:
+		fmt.Println("  prediction:        pass")
+	case pred.MoreYeas > 0:
+		fmt.Printf("  prediction:        fail, needs %d more yeas\n", pred.MoreYeas)
+	default:
+		fmt.Println("  prediction:        fail, can not pass with the remaining verifiers")
+	}
+
+	switch p.ProposalType {
+	case gov.Version:
+		fmt.Printf("  version change:    %s -> %s at block %d\n", xutil.ProgramVersion2Str(st.activeVersion), xutil.ProgramVersion2Str(p.NewVersion), p.ActiveBlock)
+	case gov.Param:
+		old := ""
+		if param := st.findParam(p.Module, p.Name); param != nil {
+			old = param.ParamValue.Value
+		}
+		fmt.Printf("  param change:      %s.%s %s -> %s at block %d\n", p.Module, p.Name, old, p.NewValue, p.EndVotingBlock+1)
+	case gov.Cancel:
+		fmt.Printf("  to be canceled:    %s\n", p.TobeCanceled.Hex())
+	}
+	return nil
+}
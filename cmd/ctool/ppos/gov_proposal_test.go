@@ -0,0 +1,156 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of Alaya-Go.
+//
+// Alaya-Go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alaya-Go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Alaya-Go. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/x/gov"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
+	"github.com/AlayaNetwork/Alaya-Go/x/xutil"
+)
+
+var testVerifier = discover.MustHexID("0x362003c50ed3a523cdede37a001803b8f0fed27cb402b3d6127a1a96661ec202318f68f4c76d9b0bfbabfd551a178d4335eaeaa9b7981a4df30dfc8c0bfe3384")
+
+func newTestGovChainState() *govChainState {
+	xcom.GetEc(xcom.DefaultUnitTestNet)
+	return &govChainState{
+		blockNumber:   100,
+		activeVersion: 0<<16 | 14<<8 | 0,
+		params: []*gov.GovernParam{
+			{
+				ParamItem:  &gov.ParamItem{Module: gov.ModuleSlashing, Name: gov.KeyMaxEvidenceAge, Desc: "quantity of epoch, range: (0, UnStakeFreezeDuration)"},
+				ParamValue: &gov.ParamValue{Value: "7"},
+			},
+			{
+				ParamItem:  &gov.ParamItem{Module: gov.ModuleStaking, Name: gov.KeyUnStakeFreezeDuration, Desc: "quantity of epoch, range: (MaxEvidenceAge, 336]"},
+				ParamValue: &gov.ParamValue{Value: "28"},
+			},
+		},
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	version, err := parseVersion("0.15.1")
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(0<<16|15<<8|1), version)
+	assert.Equal(t, "0.15.1", xutil.ProgramVersion2Str(version))
+
+	_, err = parseVersion("0.15")
+	assert.NotNil(t, err)
+	_, err = parseVersion("0.256.0")
+	assert.NotNil(t, err)
+}
+
+func TestProposalDraft_Validate(t *testing.T) {
+	st := newTestGovChainState()
+
+	text := &proposalDraft{Type: gov.Text, Verifier: testVerifier, PIPID: "1"}
+	sch, err := text.validate(st)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(101), sch.SubmitBlock)
+	assert.Equal(t, xutil.CalEndVotingBlock(101, xutil.EstimateConsensusRoundsForGov(xcom.TextProposalVote_DurationSeconds())), sch.EndVotingBlock)
+
+	_, err = (&proposalDraft{Type: gov.Text, Verifier: testVerifier}).validate(st)
+	assert.Equal(t, gov.PIPIDEmpty, err)
+
+	version := &proposalDraft{Type: gov.Version, Verifier: testVerifier, PIPID: "2", NewVersion: 0<<16 | 14<<8 | 1, EndVotingRounds: 1}
+	_, err = version.validate(st)
+	assert.Equal(t, gov.NewVersionError, err)
+	version.NewVersion = 0<<16 | 15<<8 | 0
+	version.EndVotingRounds = 0
+	_, err = version.validate(st)
+	assert.Equal(t, gov.EndVotingRoundsTooSmall, err)
+	version.EndVotingRounds = 1
+	sch, err = version.validate(st)
+	assert.Nil(t, err)
+	assert.Equal(t, xutil.CalActiveBlock(sch.EndVotingBlock), sch.ActiveBlock)
+
+	param := &proposalDraft{Type: gov.Param, Verifier: testVerifier, PIPID: "3", Module: gov.ModuleSlashing, Name: gov.KeyMaxEvidenceAge, NewValue: "7"}
+	_, err = param.validate(st)
+	assert.Equal(t, gov.ParamProposalIsSameValue, err)
+	param.NewValue = "28"
+	_, err = param.validate(st)
+	assert.NotNil(t, err, "the upper bound is open")
+	param.NewValue = "10"
+	sch, err = param.validate(st)
+	assert.Nil(t, err)
+	assert.Equal(t, sch.EndVotingBlock+1, sch.ActiveBlock)
+	param.Name = "unknown"
+	_, err = param.validate(st)
+	assert.Equal(t, gov.UnsupportedGovernParam, err)
+
+	st.proposals = []*proposalInfo{
+		{ProposalID: common.HexToHash("0x01"), ProposalType: gov.Version, PIPID: "2", EndVotingBlock: 101, Status: gov.Voting},
+		{ProposalID: common.HexToHash("0x02"), ProposalType: gov.Text, PIPID: "4", Status: gov.Failed},
+	}
+	_, err = version.validate(st)
+	assert.Equal(t, gov.PIPIDExist, err)
+	version.PIPID = "5"
+	_, err = version.validate(st)
+	assert.Equal(t, gov.VotingVersionProposalExist, err)
+	_, err = (&proposalDraft{Type: gov.Text, Verifier: testVerifier, PIPID: "4"}).validate(st)
+	assert.Nil(t, err, "the PIPID of a failed proposal can be reused")
+
+	cancel := &proposalDraft{Type: gov.Cancel, Verifier: testVerifier, PIPID: "6", EndVotingRounds: 1, TobeCanceled: common.HexToHash("0x02")}
+	_, err = cancel.validate(st)
+	assert.Equal(t, gov.TobeCanceledProposalTypeError, err)
+	cancel.TobeCanceled = common.HexToHash("0x03")
+	_, err = cancel.validate(st)
+	assert.Equal(t, gov.TobeCanceledProposalNotFound, err)
+	cancel.TobeCanceled = common.HexToHash("0x01")
+	_, err = cancel.validate(st)
+	assert.Equal(t, gov.EndVotingRoundsTooLarge, err)
+	st.proposals[0].EndVotingBlock = 1000
+	_, err = cancel.validate(st)
+	assert.Nil(t, err)
+}
+
+func TestPredictTally(t *testing.T) {
+	xcom.GetEc(xcom.DefaultUnitTestNet)
+
+	pred := predictTally(gov.Version, 10, 10, 0, 0)
+	assert.True(t, pred.Pass)
+	assert.Equal(t, uint64(0), pred.MoreYeas)
+
+	pred = predictTally(gov.Version, 10, 0, 0, 0)
+	assert.False(t, pred.Pass)
+	assert.True(t, pred.MoreYeas > 0)
+	assert.True(t, predictTally(gov.Version, 10, pred.MoreYeas, 0, 0).Pass)
+
+	pred = predictTally(gov.Text, 10, 10, 0, 0)
+	assert.True(t, pred.Pass)
+	assert.Equal(t, uint64(10000), pred.VoteRate)
+	assert.Equal(t, uint64(10000), pred.SupportRate)
+
+	pred = predictTally(gov.Param, 10, 0, 0, 0)
+	assert.False(t, pred.Pass)
+	assert.True(t, predictTally(gov.Param, 10, pred.MoreYeas, 0, 0).Pass)
+
+	pred = predictTally(gov.Cancel, 10, 0, 10, 0)
+	assert.False(t, pred.Pass)
+	assert.Equal(t, uint64(0), pred.MoreYeas, "no verifier left to vote")
+}
+
+func TestFormatRate(t *testing.T) {
+	assert.Equal(t, "66.67%", formatRate(6667))
+	assert.Equal(t, "0.05%", formatRate(5))
+}
@@ -17,9 +17,16 @@
 package ppos
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
 	"gopkg.in/urfave/cli.v1"
 
+	"github.com/AlayaNetwork/Alaya-Go/common"
 	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/rpc"
+	"github.com/AlayaNetwork/Alaya-Go/x/reward"
 )
 
 var (
@@ -28,6 +35,7 @@ var (
 		Usage: "use for reward",
 		Subcommands: []cli.Command{
 			getDelegateRewardCmd,
+			projectDelegateRewardCmd,
 		},
 	}
 	getDelegateRewardCmd = cli.Command{
@@ -37,21 +45,75 @@ var (
 		Action: getDelegateReward,
 		Flags:  []cli.Flag{rpcUrlFlag, addressHRPFlag, nodeList, jsonFlag},
 	}
+	projectDelegateRewardCmd = cli.Command{
+		Name:   "projectDelegateReward",
+		Usage:  "project the reward of the nodes the account delegates to and the income of the account in the next epochs,parameter:address,epochs,nodeList(can empty)",
+		Before: netCheck,
+		Action: projectDelegateReward,
+		Flags:  []cli.Flag{rpcUrlFlag, addressHRPFlag, addFlag, epochsFlag, nodeList},
+	}
 	nodeList = cli.StringSliceFlag{
 		Name:  "nodeList",
 		Usage: "node list,may empty",
 	}
+	epochsFlag = cli.Uint64Flag{
+		Name:  "epochs",
+		Usage: "the number of the epochs to project",
+		Value: 1,
+	}
 )
 
 func getDelegateReward(c *cli.Context) error {
+	idlist, err := parseNodeList(c)
+	if err != nil {
+		return err
+	}
+	return query(c, 5100, idlist)
+}
+
+func projectDelegateReward(c *cli.Context) error {
+	url := c.String(rpcUrlFlag.Name)
+	if url == "" {
+		return errors.New("rpc url not set")
+	}
+	addstring := c.String(addFlag.Name)
+	if addstring == "" {
+		return errors.New("The Del's account address is not set")
+	}
+	add, err := common.Bech32ToAddress(addstring)
+	if err != nil {
+		return err
+	}
+	idlist, err := parseNodeList(c)
+	if err != nil {
+		return err
+	}
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	var res []*reward.NodeDelegateRewardProjection
+	if err := client.Call(&res, "debug_projectDelegateReward", add, idlist, c.Uint64(epochsFlag.Name), "latest"); err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+func parseNodeList(c *cli.Context) ([]discover.NodeID, error) {
 	nodeIDlist := c.StringSlice(nodeList.Name)
 	idlist := make([]discover.NodeID, 0)
 	for _, node := range nodeIDlist {
 		nodeid, err := discover.HexID(node)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		idlist = append(idlist, nodeid)
 	}
-	return query(c, 5100, idlist)
+	return idlist, nil
 }
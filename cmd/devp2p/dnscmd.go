@@ -0,0 +1,413 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of Alaya-Go.
+//
+// Alaya-Go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alaya-Go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Alaya-Go. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/AlayaNetwork/Alaya-Go/accounts/keystore"
+	"github.com/AlayaNetwork/Alaya-Go/console"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/dnsdisc"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/enr"
+)
+
+var (
+	dnsCommand = cli.Command{
+		Name:  "dns",
+		Usage: "DNS Discovery Commands",
+		Subcommands: []cli.Command{
+			dnsSyncCommand,
+			dnsSignCommand,
+			dnsTXTCommand,
+			dnsZonefileCommand,
+		},
+	}
+	dnsSyncCommand = cli.Command{
+		Name:      "sync",
+		Usage:     "Download a DNS discovery tree",
+		ArgsUsage: "<url> [ <directory> ]",
+		Action:    dnsSync,
+		Flags:     []cli.Flag{dnsTimeoutFlag},
+	}
+	dnsSignCommand = cli.Command{
+		Name:      "sign",
+		Usage:     "Sign a DNS discovery tree",
+		ArgsUsage: "<tree-directory> <key-file>",
+		Description: `
+Signs the tree in the given directory with the key in the given keystore file.
+The sequence number of the tree is incremented unless --seq is given.`,
+		Action: dnsSign,
+		Flags:  []cli.Flag{dnsDomainFlag, dnsSeqFlag, passphraseFlag},
+	}
+	dnsTXTCommand = cli.Command{
+		Name:      "to-txt",
+		Usage:     "Create DNS TXT records for a discovery tree",
+		ArgsUsage: "<tree-directory> [ <output-file> ]",
+		Action:    dnsToTXT,
+	}
+	dnsZonefileCommand = cli.Command{
+		Name:      "to-zonefile",
+		Usage:     "Create a DNS zone file for a discovery tree",
+		ArgsUsage: "<tree-directory> [ <output-file> ]",
+		Action:    dnsToZonefile,
+		Flags:     []cli.Flag{dnsTTLFlag},
+	}
+)
+
+var (
+	dnsTimeoutFlag = cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "Timeout for DNS lookups",
+	}
+	dnsDomainFlag = cli.StringFlag{
+		Name:  "domain",
+		Usage: "Domain name of the tree",
+	}
+	dnsSeqFlag = cli.UintFlag{
+		Name:  "seq",
+		Usage: "New sequence number of the tree",
+	}
+	dnsTTLFlag = cli.UintFlag{
+		Name:  "ttl",
+		Usage: "TTL of the DNS records in seconds",
+		Value: 300,
+	}
+	passphraseFlag = cli.StringFlag{
+		Name:  "passwordfile",
+		Usage: "the file that contains the passphrase for the key file",
+	}
+)
+
+const (
+	treeMetaFile  = "enrtree-info.json"
+	treeNodesFile = "nodes.json"
+)
+
+// dnsSync performs dnsSyncCommand.
+func dnsSync(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("need tree URL as argument")
+	}
+	var (
+		url    = ctx.Args().Get(0)
+		outdir = ctx.Args().Get(1)
+	)
+	domain, _, err := dnsdisc.ParseURL(url)
+	if err != nil {
+		return err
+	}
+	if outdir == "" {
+		outdir = domain
+	}
+
+	client := dnsdisc.NewClient(dnsdisc.Config{Timeout: ctx.Duration(dnsTimeoutFlag.Name)})
+	t, err := client.SyncTree(url)
+	if err != nil {
+		return err
+	}
+	def := treeToDefinition(url, t)
+	def.Meta.LastModified = time.Now()
+	return writeTreeDefinition(outdir, def)
+}
+
+// dnsSign performs dnsSignCommand.
+func dnsSign(ctx *cli.Context) error {
+	if ctx.NArg() < 2 {
+		return fmt.Errorf("need tree definition directory and key file as arguments")
+	}
+	var (
+		defdir  = ctx.Args().Get(0)
+		keyfile = ctx.Args().Get(1)
+	)
+	def, err := loadTreeDefinition(defdir)
+	if err != nil {
+		return err
+	}
+	domain := ctx.String(dnsDomainFlag.Name)
+	if domain == "" {
+		if def.Meta.URL == "" {
+			return fmt.Errorf("need --%s to sign a new tree", dnsDomainFlag.Name)
+		}
+		if domain, _, err = dnsdisc.ParseURL(def.Meta.URL); err != nil {
+			return fmt.Errorf("invalid tree URL in %s: %v", treeMetaFile, err)
+		}
+	}
+	if ctx.IsSet(dnsSeqFlag.Name) {
+		def.Meta.Seq = ctx.Uint(dnsSeqFlag.Name)
+	} else {
+		def.Meta.Seq++
+	}
+	t, err := dnsdisc.MakeTree(def.Meta.Seq, def.Nodes, def.Meta.Links)
+	if err != nil {
+		return err
+	}
+	key, err := loadSigningKey(ctx, keyfile)
+	if err != nil {
+		return err
+	}
+	url, err := t.Sign(key, domain)
+	if err != nil {
+		return fmt.Errorf("can't sign: %v", err)
+	}
+
+	def = treeToDefinition(url, t)
+	def.Meta.LastModified = time.Now()
+	return writeTreeMetadata(defdir, def)
+}
+
+// dnsToTXT performs dnsTXTCommand.
+func dnsToTXT(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("need tree definition directory as argument")
+	}
+	domain, t, err := loadTreeDefinitionForExport(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	return writeOutput(ctx.Args().Get(1), func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(t.ToTXT(domain))
+	})
+}
+
+// dnsToZonefile performs dnsZonefileCommand.
+func dnsToZonefile(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		return fmt.Errorf("need tree definition directory as argument")
+	}
+	domain, t, err := loadTreeDefinitionForExport(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	return writeOutput(ctx.Args().Get(1), func(w io.Writer) error {
+		return writeZonefile(w, domain, ctx.Uint(dnsTTLFlag.Name), t.ToTXT(domain))
+	})
+}
+
+// loadSigningKey loads a private key from the given keystore file.
+func loadSigningKey(ctx *cli.Context, keyfile string) (*ecdsa.PrivateKey, error) {
+	keyjson, err := ioutil.ReadFile(keyfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the keyfile at '%s': %v", keyfile, err)
+	}
+	var passphrase string
+	if file := ctx.String(passphraseFlag.Name); file != "" {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase file '%s': %v", file, err)
+		}
+		passphrase = strings.TrimRight(string(content), "\r\n")
+	} else if passphrase, err = console.Stdin.PromptPassword("Key passphrase: "); err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %v", err)
+	}
+	key, err := keystore.DecryptKey(keyjson, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting key: %v", err)
+	}
+	return key.PrivateKey, nil
+}
+
+// writeZonefile writes the TXT records of a tree in DNS zone file format, with
+// the root record first.
+func writeZonefile(w io.Writer, domain string, ttl uint, records map[string]string) error {
+	names := make([]string, 0, len(records))
+	for name := range records {
+		if name != domain {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	names = append([]string{domain}, names...)
+	for _, name := range names {
+		if _, err := fmt.Fprintf(w, "%s.\t%d\tIN\tTXT\t%s\n", name, ttl, quoteTXT(records[name])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// quoteTXT splits a TXT record value into quoted strings of at most 255
+// bytes, which is the limit of a single DNS character-string.
+func quoteTXT(value string) string {
+	var parts []string
+	for len(value) > 255 {
+		parts = append(parts, `"`+value[:255]+`"`)
+		value = value[255:]
+	}
+	parts = append(parts, `"`+value+`"`)
+	return strings.Join(parts, " ")
+}
+
+// writeOutput runs fn on the given file, or on stdout if file is empty or "-".
+func writeOutput(file string, fn func(io.Writer) error) error {
+	if file == "" || file == "-" {
+		return fn(os.Stdout)
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := fn(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// dnsDefinition is the on-disk form of a DNS discovery tree.
+type dnsDefinition struct {
+	Meta  dnsMetaJSON
+	Nodes []*enr.Record
+}
+
+// dnsMetaJSON is the content of the tree metadata file.
+type dnsMetaJSON struct {
+	URL          string    `json:"url,omitempty"`
+	Seq          uint      `json:"seq"`
+	Sig          string    `json:"signature,omitempty"`
+	Links        []string  `json:"links"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+func treeToDefinition(url string, t *dnsdisc.Tree) *dnsDefinition {
+	meta := dnsMetaJSON{
+		URL:   url,
+		Seq:   t.Seq(),
+		Sig:   t.Signature(),
+		Links: t.Links(),
+	}
+	if meta.Links == nil {
+		meta.Links = []string{}
+	}
+	return &dnsDefinition{Meta: meta, Nodes: t.Records()}
+}
+
+// loadTreeDefinition loads a directory in 'definition' format.
+func loadTreeDefinition(directory string) (*dnsDefinition, error) {
+	metaFile, nodesFile := treeDefinitionFiles(directory)
+	var def dnsDefinition
+	if err := readJSON(metaFile, &def.Meta); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if def.Meta.Links == nil {
+		def.Meta.Links = []string{}
+	}
+	// Check link syntax.
+	for _, link := range def.Meta.Links {
+		if _, _, err := dnsdisc.ParseURL(link); err != nil {
+			return nil, fmt.Errorf("invalid link %q: %v", link, err)
+		}
+	}
+	// Check/convert nodes.
+	var texts []string
+	if err := readJSON(nodesFile, &texts); err != nil {
+		return nil, err
+	}
+	for i, text := range texts {
+		r, err := dnsdisc.DecodeRecord(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node %d in %s: %v", i, treeNodesFile, err)
+		}
+		def.Nodes = append(def.Nodes, r)
+	}
+	return &def, nil
+}
+
+// loadTreeDefinitionForExport loads a DNS tree and ensures it is signed.
+func loadTreeDefinitionForExport(dir string) (domain string, t *dnsdisc.Tree, err error) {
+	def, err := loadTreeDefinition(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	if def.Meta.URL == "" {
+		return "", nil, fmt.Errorf("missing tree URL in %s, tree is not signed", treeMetaFile)
+	}
+	domain, pubkey, err := dnsdisc.ParseURL(def.Meta.URL)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid tree URL in %s: %v", treeMetaFile, err)
+	}
+	if t, err = dnsdisc.MakeTree(def.Meta.Seq, def.Nodes, def.Meta.Links); err != nil {
+		return "", nil, err
+	}
+	if err := t.SetSignature(pubkey, def.Meta.Sig); err != nil {
+		return "", nil, fmt.Errorf("tree signature doesn't match content, sign it again: %v", err)
+	}
+	return domain, t, nil
+}
+
+// writeTreeDefinition writes a DNS node tree definition to the given directory.
+func writeTreeDefinition(directory string, def *dnsDefinition) error {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return err
+	}
+	_, nodesFile := treeDefinitionFiles(directory)
+	texts := make([]string, len(def.Nodes))
+	for i, r := range def.Nodes {
+		text, err := dnsdisc.EncodeRecord(r)
+		if err != nil {
+			return err
+		}
+		texts[i] = text
+	}
+	if err := writeJSON(nodesFile, texts); err != nil {
+		return err
+	}
+	return writeTreeMetadata(directory, def)
+}
+
+// writeTreeMetadata writes the metadata file of a tree definition.
+func writeTreeMetadata(directory string, def *dnsDefinition) error {
+	metaFile, _ := treeDefinitionFiles(directory)
+	return writeJSON(metaFile, def.Meta)
+}
+
+func treeDefinitionFiles(directory string) (string, string) {
+	meta := filepath.Join(directory, treeMetaFile)
+	nodes := filepath.Join(directory, treeNodesFile)
+	return meta, nodes
+}
+
+func readJSON(file string, v interface{}) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		return fmt.Errorf("can't parse %s: %v", file, err)
+	}
+	return nil
+}
+
+func writeJSON(file string, v interface{}) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, append(content, '\n'), 0644)
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of Alaya-Go.
+//
+// Alaya-Go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alaya-Go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Alaya-Go. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/dnsdisc"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/enr"
+)
+
+func TestTreeDefinitionExport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dnstree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var records []*enr.Record
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		var r enr.Record
+		r.Set(enr.IP(net.IP{10, 0, 0, byte(i + 1)}))
+		r.Set(enr.TCP(16789))
+		if err := enr.SignV4(&r, key); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, &r)
+	}
+	key, _ := crypto.GenerateKey()
+	tree, err := dnsdisc.MakeTree(1, records, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url, err := tree.Sign(key, "nodes.example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Write the tree and load it back for export.
+	if err := writeTreeDefinition(dir, treeToDefinition(url, tree)); err != nil {
+		t.Fatal(err)
+	}
+	domain, loaded, err := loadTreeDefinitionForExport(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if domain != "nodes.example.org" {
+		t.Errorf("wrong domain %q", domain)
+	}
+	if !reflect.DeepEqual(loaded.ToTXT(domain), tree.ToTXT(domain)) {
+		t.Error("exported records don't match original tree")
+	}
+
+	// Exporting must fail once the nodes changed after signing.
+	def, err := loadTreeDefinition(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	def.Nodes = def.Nodes[1:]
+	if err := writeTreeDefinition(dir, def); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := loadTreeDefinitionForExport(dir); err == nil {
+		t.Fatal("no error for modified tree")
+	}
+}
+
+func TestWriteZonefile(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	records := map[string]string{
+		"b.n": "enrtree-branch:",
+		"n":   "enrtree-root:v1",
+		"a.n": long,
+	}
+	var buf bytes.Buffer
+	if err := writeZonefile(&buf, "n", 60, records); err != nil {
+		t.Fatal(err)
+	}
+	want := "n.\t60\tIN\tTXT\t\"enrtree-root:v1\"\n" +
+		"a.n.\t60\tIN\tTXT\t\"" + long[:255] + "\" \"" + long[255:] + "\"\n" +
+		"b.n.\t60\tIN\tTXT\t\"enrtree-branch:\"\n"
+	if buf.String() != want {
+		t.Errorf("wrong zone file:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
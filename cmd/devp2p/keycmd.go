@@ -0,0 +1,97 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of Alaya-Go.
+//
+// Alaya-Go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// Alaya-Go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with Alaya-Go. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"net"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/dnsdisc"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/enr"
+)
+
+var (
+	keyCommand = cli.Command{
+		Name:  "key",
+		Usage: "Operations on node keys",
+		Subcommands: []cli.Command{
+			keyToRecordCommand,
+		},
+	}
+	keyToRecordCommand = cli.Command{
+		Name:      "to-enr",
+		Usage:     "Creates a signed node record from a node key",
+		ArgsUsage: "<nodekey-file>",
+		Description: `
+Prints the node record of the node using the given node key and endpoint,
+in the "enr:" text form which is stored in the nodes.json file of a DNS tree.`,
+		Action: keyToRecord,
+		Flags: []cli.Flag{
+			ipFlag,
+			tcpFlag,
+			udpFlag,
+		},
+	}
+)
+
+var (
+	ipFlag = cli.StringFlag{
+		Name:  "ip",
+		Usage: "IP address of the node",
+	}
+	tcpFlag = cli.IntFlag{
+		Name:  "tcp",
+		Usage: "TCP listening port of the node",
+		Value: 16789,
+	}
+	udpFlag = cli.IntFlag{
+		Name:  "udp",
+		Usage: "UDP discovery port of the node (default: same as TCP)",
+	}
+)
+
+func keyToRecord(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("need node key file as argument")
+	}
+	key, err := crypto.LoadECDSA(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("can't load node key: %v", err)
+	}
+	ip := net.ParseIP(ctx.String(ipFlag.Name))
+	if ip == nil {
+		return fmt.Errorf("invalid or missing --%s", ipFlag.Name)
+	}
+	var r enr.Record
+	r.Set(enr.IP(ip))
+	r.Set(enr.TCP(ctx.Int(tcpFlag.Name)))
+	if ctx.IsSet(udpFlag.Name) {
+		r.Set(enr.UDP(ctx.Int(udpFlag.Name)))
+	}
+	if err := enr.SignV4(&r, key); err != nil {
+		return err
+	}
+	text, err := dnsdisc.EncodeRecord(&r)
+	if err != nil {
+		return err
+	}
+	fmt.Println(text)
+	return nil
+}
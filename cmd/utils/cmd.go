@@ -28,8 +28,10 @@ import (
 	"syscall"
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
+	cvm "github.com/AlayaNetwork/Alaya-Go/common/vm"
 	"github.com/AlayaNetwork/Alaya-Go/core"
 	"github.com/AlayaNetwork/Alaya-Go/core/rawdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
 	"github.com/AlayaNetwork/Alaya-Go/crypto"
 	"github.com/AlayaNetwork/Alaya-Go/ethdb"
@@ -37,6 +39,7 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/log"
 	"github.com/AlayaNetwork/Alaya-Go/node"
 	"github.com/AlayaNetwork/Alaya-Go/rlp"
+	"github.com/AlayaNetwork/Alaya-Go/x/staking"
 )
 
 const (
@@ -312,3 +315,121 @@ func ExportPreimages(db ethdb.Database, fn string) error {
 	log.Info("Exported preimages", "file", fn)
 	return nil
 }
+
+// pposHashAt returns the ppos k-v hash stored in the state of the header, see
+// BlockChainReactor.EndBlocker.
+func pposHashAt(chain *core.BlockChain, header *types.Header) (common.Hash, error) {
+	statedb, err := chain.StateAt(header.Root)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(statedb.GetState(cvm.StakingContractAddr, staking.GetPPOSHASHKey())), nil
+}
+
+// ExportSnapshotDB exports the base DB of the snapshotdb into the specified
+// file, truncating any data already present in the file.
+func ExportSnapshotDB(chain *core.BlockChain, db snapshotdb.DB, fn string) error {
+	log.Info("Exporting snapshotdb", "file", fn)
+
+	base, err := db.BaseNum()
+	if err != nil {
+		return err
+	}
+	header := chain.GetHeaderByNumber(base.Uint64())
+	if header == nil {
+		return fmt.Errorf("the header of the snapshotdb base block %d is not found", base)
+	}
+	pposHash, err := pposHashAt(chain, header)
+	if err != nil {
+		return fmt.Errorf("the state of the snapshotdb base block %d is not available: %v", base, err)
+	}
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	count, err := snapshotdb.ExportBaseDB(db, writer, &snapshotdb.ExportHeader{
+		Number:   header.Number.Uint64(),
+		Hash:     header.Hash(),
+		PPOSHash: pposHash,
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("Exported snapshotdb", "file", fn, "number", header.Number, "hash", header.Hash(), "pposHash", pposHash, "keys", count)
+	return nil
+}
+
+// ImportSnapshotDB replaces the snapshotdb with the base DB exported into the
+// specified file. The block of the export must be in the local chain with its
+// state, the ppos hash of the export must match the one in the state. The base
+// DB is staged in a temporary database in tmpdir, which is removed after.
+func ImportSnapshotDB(chain *core.BlockChain, db snapshotdb.DB, fn string, tmpdir string) error {
+	log.Info("Importing snapshotdb", "file", fn)
+
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+	}
+	if err := os.RemoveAll(tmpdir); err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpdir)
+	tmp, err := snapshotdb.OpenBackend(snapshotdb.BackendLevelDB, tmpdir, 16, 16)
+	if err != nil {
+		return err
+	}
+	defer tmp.Close()
+
+	header, err := snapshotdb.ImportBaseDB(db, tmp, reader, func(exported *snapshotdb.ExportHeader) error {
+		header := chain.GetHeaderByNumber(exported.Number)
+		if header == nil || header.Hash() != exported.Hash {
+			return fmt.Errorf("the block %d %x of the export is not in the local chain", exported.Number, exported.Hash)
+		}
+		pposHash, err := pposHashAt(chain, header)
+		if err != nil {
+			return fmt.Errorf("the state of the export block %d is not available to check the ppos hash: %v", exported.Number, err)
+		}
+		if pposHash != exported.PPOSHash {
+			return fmt.Errorf("the ppos hash of the export %x doesn't match the local state %x", exported.PPOSHash, pposHash)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("Imported snapshotdb", "file", fn, "number", header.Number, "hash", header.Hash)
+	return nil
+}
+
+// snapshotDBChain verifies the snapshotdb against the ppos hashes in the state.
+type snapshotDBChain struct {
+	*core.BlockChain
+}
+
+func (c snapshotDBChain) PPOSHash(header *types.Header) (common.Hash, error) {
+	return pposHashAt(c.BlockChain, header)
+}
+
+// VerifySnapshotDB checks the snapshotdb stored in db against the chain.
+func VerifySnapshotDB(chain *core.BlockChain, db snapshotdb.Backend) (*snapshotdb.VerifyReport, error) {
+	log.Info("Verifying snapshotdb")
+	return snapshotdb.Verify(db, snapshotDBChain{chain})
+}
@@ -53,6 +53,7 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/node"
 	"github.com/AlayaNetwork/Alaya-Go/p2p"
 	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/dnsdisc"
 	"github.com/AlayaNetwork/Alaya-Go/p2p/nat"
 	"github.com/AlayaNetwork/Alaya-Go/p2p/netutil"
 	"github.com/AlayaNetwork/Alaya-Go/params"
@@ -140,6 +141,10 @@ var (
 		Name:  "addressHRP",
 		Usage: "set the address hrp,if not set,use default address hrp",
 	}
+	DeveloperFlag = cli.BoolFlag{
+		Name:  "dev",
+		Usage: "Ephemeral single node PPOS chain with a pre-funded developer account, mining enabled",
+	}
 	DeveloperPeriodFlag = cli.IntFlag{
 		Name:  "dev.period",
 		Usage: "Block period to use in developer mode (0 = mine only if transaction pending)",
@@ -161,7 +166,7 @@ var (
 	}
 	LightServFlag = cli.IntFlag{
 		Name:  "lightserv",
-		Usage: "Maximum percentage of time allowed for serving LES requests (0-90)",
+		Usage: "Maximum percentage of time allowed for serving LES requests (0-90), requires --db.snapshot_archive",
 		Value: 0,
 	}
 	LightPeersFlag = cli.IntFlag{
@@ -169,6 +174,10 @@ var (
 		Usage: "Maximum number of LES client peers",
 		Value: eth.DefaultConfig.LightPeers,
 	}
+	LightVerifyQCFlag = cli.BoolFlag{
+		Name:  "lightverifyqc",
+		Usage: "Verify the QuorumCerts and validator switches of the synced headers in light sync mode",
+	}
 	LightKDFFlag = cli.BoolFlag{
 		Name:  "lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
@@ -423,6 +432,10 @@ var (
 		Name:  "nodiscover",
 		Usage: "Disables the peer discovery mechanism (manual peer addition)",
 	}
+	DNSDiscoveryFlag = cli.StringFlag{
+		Name:  "discovery.dns",
+		Usage: "Comma separated enrtree:// URLs of DNS node lists used as a source of peers",
+	}
 	/*
 		DiscoveryV5Flag = cli.BoolFlag{
 			Name:  "v5disc",
@@ -503,6 +516,14 @@ var (
 		Usage: "InfluxDB `host` tag attached to all measurements",
 		Value: "localhost",
 	}
+	MonitorValidatorFlag = cli.BoolFlag{
+		Name:  "monitor.validator",
+		Usage: "Track the block production of the local validator and alert when it's at risk of the zero-production slashing",
+	}
+	MonitorWebhookFlag = cli.StringFlag{
+		Name:  "monitor.webhook",
+		Usage: "URL the validator alerts are posted to as JSON",
+	}
 
 	// mpc compute
 	//MPCIceFileFlag = cli.StringFlag{
@@ -563,6 +584,11 @@ var (
 		Value: "60",
 	}
 
+	CbftEvidenceReporterFlag = cli.StringFlag{
+		Name:  "cbft.evidence.reporter",
+		Usage: "Account reporting the duplicate signatures to the slashing contract (must be unlocked)",
+	}
+
 	DBNoGCFlag = cli.BoolFlag{
 		Name:  "db.nogc",
 		Usage: "Disables database garbage collection",
@@ -586,6 +612,15 @@ var (
 		Usage: "Number of cache block states, default 10",
 		Value: eth.DefaultConfig.DBGCBlock,
 	}
+	DBSnapshotArchiveFlag = cli.BoolFlag{
+		Name:  "db.snapshot_archive",
+		Usage: "Retains the history of snapshotdb so PPOS data can be queried at any block, and records the writes of the journal blocks for 'snapshotdb verify'",
+	}
+	DBSnapshotBackendFlag = cli.StringFlag{
+		Name:  "db.snapshot_backend",
+		Usage: "Storage backend of snapshotdb (leveldb, bolt, chaindb, memory)",
+		Value: eth.DefaultConfig.DBSnapshotBackend,
+	}
 
 	VMWasmType = cli.StringFlag{
 		Name:   "vm.wasm_type",
@@ -600,6 +635,10 @@ var (
 		EnvVar: "",
 		Value:  eth.DefaultConfig.VmTimeoutDuration,
 	}
+	VMParallelCheckFlag = cli.Uint64Flag{
+		Name:  "vm.parallel_check",
+		Usage: "Cross-check the parallel execution of every N blocks against the serial execution, 0 = disabled",
+	}
 )
 
 // MakeDataDir retrieves the currently requested data directory, terminating
@@ -660,6 +699,8 @@ func setBootstrapNodes(ctx *cli.Context, cfg *p2p.Config) {
 		}
 	case ctx.GlobalBool(AlayaNetFlag.Name):
 		urls = params.AlayanetBootnodes
+	case ctx.GlobalBool(DeveloperFlag.Name):
+		urls = nil
 	case cfg.BootstrapNodes != nil:
 		return // already set, don't apply defaults.
 	}
@@ -915,7 +956,24 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 		}
 		cfg.NetRestrict = list
 	}
+	setDNSDiscovery(ctx, cfg)
+}
 
+// setDNSDiscovery configures the DNS node lists used as a source of peers.
+func setDNSDiscovery(ctx *cli.Context, cfg *p2p.Config) {
+	if !ctx.GlobalIsSet(DNSDiscoveryFlag.Name) {
+		return
+	}
+	cfg.DNSDiscovery = nil
+	for _, url := range strings.Split(ctx.GlobalString(DNSDiscoveryFlag.Name), ",") {
+		if url = strings.TrimSpace(url); url == "" {
+			continue
+		}
+		if _, _, err := dnsdisc.ParseURL(url); err != nil {
+			Fatalf("Option %q: invalid URL %q: %v", DNSDiscoveryFlag.Name, url, err)
+		}
+		cfg.DNSDiscovery = append(cfg.DNSDiscovery, url)
+	}
 }
 
 // SetNodeConfig applies node-related command line flags to the config.
@@ -931,6 +989,21 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 		cfg.DataDir = ctx.GlobalString(DataDirFlag.Name)
 	case ctx.GlobalBool(AlayaNetFlag.Name):
 		cfg.DataDir = filepath.Join(node.DefaultDataDir(), "alayanet")
+	case ctx.GlobalBool(DeveloperFlag.Name):
+		dir, err := ioutil.TempDir("", "alaya-dev")
+		if err != nil {
+			Fatalf("Failed to create developer data directory: %v", err)
+		}
+		log.Info("Using ephemeral developer data directory", "datadir", dir)
+		cfg.DataDir = dir
+	}
+	if ctx.GlobalBool(DeveloperFlag.Name) {
+		// The developer chain is validated by this node alone, pin its node
+		// key so that the genesis can name it.
+		cfg.P2P.PrivateKey = cfg.NodeKey()
+		cfg.P2P.NoDiscovery = true
+		cfg.P2P.DNSDiscovery = nil
+		cfg.P2P.ListenAddr = ""
 	}
 
 	if ctx.GlobalIsSet(KeyStoreDirFlag.Name) {
@@ -1090,7 +1163,7 @@ func checkExclusive(ctx *cli.Context, args ...interface{}) {
 // SetEthConfig applies eth-related command line flags to the config.
 func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	// Avoid conflicting network flags
-	checkExclusive(ctx, AlayaNetFlag)
+	checkExclusive(ctx, AlayaNetFlag, DeveloperFlag)
 	checkExclusive(ctx, LightServFlag, SyncModeFlag, "light")
 
 	setGPO(ctx, &cfg.GPO)
@@ -1102,6 +1175,20 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(CbftWalDisabledFlag.Name) {
 		cfg.CbftConfig.WalMode = false
 	}
+	if ctx.GlobalIsSet(CbftEvidenceReporterFlag.Name) {
+		ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+		account, err := MakeAddress(ks, ctx.GlobalString(CbftEvidenceReporterFlag.Name))
+		if err != nil {
+			Fatalf("Invalid evidence reporter: %v", err)
+		}
+		cfg.EvidenceReporter = account.Address
+	}
+	if ctx.GlobalIsSet(MonitorValidatorFlag.Name) {
+		cfg.ValidatorMonitor = ctx.GlobalBool(MonitorValidatorFlag.Name)
+	}
+	if ctx.GlobalIsSet(MonitorWebhookFlag.Name) {
+		cfg.ValidatorMonitorWebhook = ctx.GlobalString(MonitorWebhookFlag.Name)
+	}
 	if ctx.GlobalIsSet(SyncModeFlag.Name) {
 		cfg.SyncMode = *GlobalTextMarshaler(ctx, SyncModeFlag.Name).(*downloader.SyncMode)
 	}
@@ -1111,6 +1198,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(LightPeersFlag.Name) {
 		cfg.LightPeers = ctx.GlobalInt(LightPeersFlag.Name)
 	}
+	if ctx.GlobalIsSet(LightVerifyQCFlag.Name) {
+		cfg.LightVerifyQC = ctx.GlobalBool(LightVerifyQCFlag.Name)
+	}
 	if ctx.GlobalIsSet(NetworkIdFlag.Name) {
 		cfg.NetworkId = ctx.GlobalUint64(NetworkIdFlag.Name)
 	}
@@ -1156,6 +1246,34 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 			cfg.NetworkId = 1
 		}
 		cfg.Genesis = core.DefaultAlayaGenesisBlock()
+	case ctx.GlobalBool(DeveloperFlag.Name):
+		if !ctx.GlobalIsSet(NetworkIdFlag.Name) {
+			cfg.NetworkId = 1337
+		}
+		// Create a new developer account or reuse the existing one
+		var (
+			developer  accounts.Account
+			passphrase string
+			err        error
+		)
+		if list := MakePasswordList(ctx); len(list) > 0 {
+			passphrase = list[0]
+		}
+		ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+		if accs := ks.Accounts(); len(accs) > 0 {
+			developer = accs[0]
+		} else {
+			developer, err = ks.NewAccount(passphrase)
+			if err != nil {
+				Fatalf("Failed to create developer account: %v", err)
+			}
+		}
+		if err := ks.Unlock(developer, passphrase); err != nil {
+			Fatalf("Failed to unlock developer account: %v", err)
+		}
+		log.Info("Using developer account", "address", developer.Address)
+
+		cfg.Genesis = core.DeveloperGenesisBlock(uint64(ctx.GlobalInt(DeveloperPeriodFlag.Name)), cfg.CbftConfig.NodeID, *cfg.CbftConfig.BlsPriKey.GetPublicKey(), developer.Address)
 	}
 	if ctx.GlobalIsSet(DBNoGCFlag.Name) {
 		cfg.DBDisabledGC = ctx.GlobalBool(DBNoGCFlag.Name)
@@ -1175,6 +1293,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 			cfg.DBGCBlock = b
 		}
 	}
+	if ctx.GlobalIsSet(DBSnapshotArchiveFlag.Name) {
+		cfg.DBSnapshotArchive = ctx.GlobalBool(DBSnapshotArchiveFlag.Name)
+	}
+	if ctx.GlobalIsSet(DBSnapshotBackendFlag.Name) {
+		cfg.DBSnapshotBackend = ctx.GlobalString(DBSnapshotBackendFlag.Name)
+	}
 
 	// vm options
 	if ctx.GlobalIsSet(VMWasmType.Name) {
@@ -1183,6 +1307,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(VmTimeoutDuration.Name) {
 		cfg.VmTimeoutDuration = ctx.GlobalUint64(VmTimeoutDuration.Name)
 	}
+	if ctx.GlobalIsSet(VMParallelCheckFlag.Name) {
+		cfg.VMParallelCheck = ctx.GlobalUint64(VMParallelCheckFlag.Name)
+	}
 
 }
 
@@ -1239,7 +1366,10 @@ func RegisterEthService(stack *node.Node, cfg *eth.Config) {
 			}
 
 			if fullNode != nil && cfg.LightServ > 0 {
-				ls, _ := les.NewLesServer(fullNode, cfg)
+				ls, err := les.NewLesServer(fullNode, cfg)
+				if err != nil {
+					return nil, err
+				}
 				fullNode.AddLesServer(ls)
 			}
 			return fullNode, err
@@ -1327,6 +1457,10 @@ func MakeGenesis(ctx *cli.Context) *core.Genesis {
 func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chainDb ethdb.Database) {
 	var err error
 	chainDb = MakeChainDatabase(ctx, stack)
+	snapshotdb.SetDBChainDatabase(chainDb)
+	if err := snapshotdb.SetDBBackend(ctx.GlobalString(DBSnapshotBackendFlag.Name)); err != nil {
+		Fatalf("%v", err)
+	}
 	basedb, err := snapshotdb.Open(stack.ResolvePath(snapshotdb.DBPath), 0, 0, true)
 	if err != nil {
 		Fatalf("%v", err)
@@ -1368,6 +1502,10 @@ func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chai
 func MakeChainForCBFT(ctx *cli.Context, stack *node.Node, cfg *eth.Config, nodeCfg *node.Config) (chain *core.BlockChain, chainDb ethdb.Database) {
 	var err error
 	chainDb = MakeChainDatabase(ctx, stack)
+	snapshotdb.SetDBChainDatabase(chainDb)
+	if err := snapshotdb.SetDBBackend(ctx.GlobalString(DBSnapshotBackendFlag.Name)); err != nil {
+		Fatalf("%v", err)
+	}
 	basedb, err := snapshotdb.Open(stack.ResolvePath(snapshotdb.DBPath), 0, 0, true)
 	if err != nil {
 		Fatalf("%v", err)
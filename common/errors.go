@@ -20,12 +20,16 @@ import "fmt"
 
 var (
 	OkCode           = uint32(0)
-	NoErr            = &BizError{Code: OkCode, Msg: "ok"}
-	InternalError    = &BizError{Code: 1, Msg: "System error"}
-	NotFound         = &BizError{Code: 2, Msg: "Object not found"}
-	InvalidParameter = &BizError{Code: 3, Msg: "Invalid parameter"}
+	NoErr            = NewBizError(OkCode, "ok")
+	InternalError    = NewBizError(1, "System error")
+	NotFound         = NewBizError(2, "Object not found")
+	InvalidParameter = NewBizError(3, "Invalid parameter")
 )
 
+// bizErrors holds the business errors created by NewBizError, keyed by code.
+// It is only written during package initialization.
+var bizErrors = make(map[uint32]*BizError)
+
 // business error, Gas will not be returned back to caller
 type BizError struct {
 	Code uint32
@@ -36,8 +40,21 @@ func (e *BizError) Error() string {
 	return e.Msg
 }
 
+// NewBizError creates a business error and registers it, so that the error
+// can be recovered from its code by LookupBizError. It must only be used to
+// declare package level errors.
 func NewBizError(code uint32, text string) *BizError {
-	return &BizError{Code: code, Msg: text}
+	err := &BizError{Code: code, Msg: text}
+	if _, exist := bizErrors[code]; !exist {
+		bizErrors[code] = err
+	}
+	return err
+}
+
+// LookupBizError returns the business error declared with the given code, or
+// nil if the package declaring it is not linked in.
+func LookupBizError(code uint32) *BizError {
+	return bizErrors[code]
 }
 
 func (be *BizError) Wrap(text string) *BizError {
@@ -138,6 +138,11 @@ func (bm *BftMock) Evidences() string {
 	return ""
 }
 
+// EvidenceList is a fake interface, no need to implement.
+func (bm *BftMock) EvidenceList() consensus.Evidences {
+	return nil
+}
+
 // UnmarshalEvidence is a fake interface, no need to implement.
 func (bm *BftMock) UnmarshalEvidence(data []byte) (consensus.Evidences, error) {
 	// todo implement me
@@ -17,7 +17,13 @@
 package cbft
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+
+	"github.com/AlayaNetwork/Alaya-Go/common/consensus"
+	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/evidence"
 	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/state"
 	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/types"
 	"github.com/AlayaNetwork/Alaya-Go/crypto/bls"
@@ -33,6 +39,7 @@ type Status struct {
 type API interface {
 	Status() []byte
 	Evidences() string
+	EvidenceList() consensus.Evidences
 	GetPrepareQC(number uint64) *types.QuorumCert
 	GetSchnorrNIZKProve() (*bls.SchnorrProof, error)
 }
@@ -65,6 +72,33 @@ func (s *PublicConsensusAPI) Evidences() string {
 	return s.engine.Evidences()
 }
 
+// EvidenceList returns the evidences selected by the filter with their
+// verification status, a nil filter selects all evidences.
+func (s *PublicConsensusAPI) EvidenceList(filter *evidence.EvidenceFilter) ([]*evidence.EvidenceInfo, error) {
+	infos := make([]*evidence.EvidenceInfo, 0)
+	for _, ev := range s.engine.EvidenceList() {
+		info, err := evidence.NewEvidenceInfo(ev)
+		if err != nil {
+			return nil, err
+		}
+		if filter.Match(info) {
+			infos = append(infos, info)
+		}
+	}
+	return infos, nil
+}
+
+// GetEvidence returns the evidence of the hash, its type and data are the
+// arguments of the reportDuplicateSign transaction of the slashing contract.
+func (s *PublicConsensusAPI) GetEvidence(hash hexutil.Bytes) (*evidence.EvidenceInfo, error) {
+	for _, ev := range s.engine.EvidenceList() {
+		if bytes.Equal(ev.Hash(), hash) {
+			return evidence.NewEvidenceInfo(ev)
+		}
+	}
+	return nil, fmt.Errorf("evidence %s not found", hash)
+}
+
 // GetPrepareQC returns the QC certificate corresponding to the blockNumber.
 func (s *PublicConsensusAPI) GetPrepareQC(number uint64) *types.QuorumCert {
 	return s.engine.GetPrepareQC(number)
@@ -39,6 +39,7 @@ import (
 	"time"
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
+	cconsensus "github.com/AlayaNetwork/Alaya-Go/common/consensus"
 	"github.com/AlayaNetwork/Alaya-Go/consensus"
 	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/evidence"
 	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/executor"
@@ -181,10 +182,17 @@ type Cbft struct {
 	netLatencyMap  map[string]*list.List
 	netLatencyLock sync.RWMutex
 
+	// Source of time, it is replaced by a virtual clock in the simulator.
+	clock utils.Clock
+
 	//test
 	insertBlockQCHook  func(block *types.Block, qc *ctypes.QuorumCert)
 	executeFinishHook  func(index uint32)
 	consensusNodesMock func() ([]discover.NodeID, error)
+	// simulated is set when the engine is driven by the Simulator of the tests,
+	// the receive loop and the network are not started and the events are
+	// handled by the Simulator on its own goroutine.
+	simulated bool
 }
 
 // New returns a new CBFT.
@@ -209,6 +217,7 @@ func New(sysConfig *params.CbftConfig, optConfig *ctypes.OptionsConfig, eventMux
 		statQueues:         make(map[common.Hash]map[string]int),
 		messageHashCache:   mapset.NewSet(),
 		netLatencyMap:      make(map[string]*list.List),
+		clock:              utils.SystemClock{},
 	}
 
 	if evPool, err := evidence.NewEvidencePool(ctx, optConfig.EvidenceDir); err == nil {
@@ -226,7 +235,9 @@ func (cbft *Cbft) Start(chain consensus.ChainReader, blockCacheWriter consensus.
 	cbft.blockChain = chain
 	cbft.txPool = txPool
 	cbft.blockCacheWriter = blockCacheWriter
-	cbft.asyncExecutor = executor.NewAsyncExecutor(blockCacheWriter.Execute)
+	if cbft.asyncExecutor == nil {
+		cbft.asyncExecutor = executor.NewAsyncExecutor(blockCacheWriter.Execute)
+	}
 
 	//Initialize block tree
 	block := chain.GetBlock(chain.CurrentHeader().Hash(), chain.CurrentHeader().Number.Uint64())
@@ -250,7 +261,7 @@ func (cbft *Cbft) Start(chain consensus.ChainReader, blockCacheWriter consensus.
 	utils.SetTrue(&cbft.loading)
 
 	//Initialize view state
-	cbft.state = cstate.NewViewState(cbft.config.Sys.Period, cbft.blockTree)
+	cbft.state = cstate.NewViewStateWithClock(cbft.config.Sys.Period, cbft.blockTree, cbft.clock)
 	cbft.state.SetHighestQCBlock(block)
 	cbft.state.SetHighestLockBlock(block)
 	cbft.state.SetHighestCommitBlock(block)
@@ -259,7 +270,9 @@ func (cbft *Cbft) Start(chain consensus.ChainReader, blockCacheWriter consensus.
 	// cbft -> handler -> router.
 	cbft.network = network.NewEngineManger(cbft) // init engineManager as handler.
 	// Start the handler to process the message.
-	go cbft.network.Start()
+	if !cbft.simulated {
+		go cbft.network.Start()
+	}
 
 	if isGenesis() {
 		cbft.validatorPool = validator.NewValidatorPool(agency, block.NumberU64(), cstate.DefaultEpoch, cbft.config.Option.NodeID)
@@ -290,7 +303,9 @@ func (cbft *Cbft) Start(chain consensus.ChainReader, blockCacheWriter consensus.
 	}
 	utils.SetFalse(&cbft.loading)
 
-	go cbft.receiveLoop()
+	if !cbft.simulated {
+		go cbft.receiveLoop()
+	}
 
 	cbft.fetcher.Start()
 
@@ -349,6 +364,17 @@ func (cbft *Cbft) ReceiveMessage(msg *ctypes.MsgInfo) error {
 	return nil
 }
 
+// receiveCachedMessage puts a message cached in csPool back to the message queue
+// without blocking the caller.
+func (cbft *Cbft) receiveCachedMessage(msg *ctypes.MsgInfo) {
+	if cbft.simulated {
+		// ReceiveMessage never blocks, the simulator keeps the order of messages.
+		cbft.ReceiveMessage(msg)
+		return
+	}
+	go cbft.ReceiveMessage(msg)
+}
+
 // recordMessage records the number of messages sent by each node,
 // mainly to prevent Dos attacks
 func (cbft *Cbft) recordMessage(msg *ctypes.MsgInfo) error {
@@ -484,59 +510,23 @@ func (cbft *Cbft) LoadWal() (err error) {
 
 // receiveLoop receives all consensus related messages, all processing logic in the same goroutine
 func (cbft *Cbft) receiveLoop() {
-
-	// Responsible for handling consensus message logic.
-	consensusMessageHandler := func(msg *ctypes.MsgInfo) {
-		if !cbft.network.ContainsHistoryMessageHash(msg.Msg.MsgHash()) {
-			err := cbft.handleConsensusMsg(msg)
-			if err == nil {
-				cbft.network.MarkHistoryMessageHash(msg.Msg.MsgHash())
-				if err := cbft.network.Forwarding(msg.PeerID, msg.Msg); err != nil {
-					cbft.log.Debug("Forward message failed", "err", err)
-				}
-			} else if e, ok := err.(HandleError); ok && e.AuthFailed() {
-				// If the verification signature is abnormal,
-				// the peer node is added to the local blacklist
-				// and disconnected.
-				cbft.log.Error("Verify signature failed, will add to blacklist", "peerID", msg.PeerID, "err", err)
-				cbft.network.MarkBlacklist(msg.PeerID)
-				cbft.network.RemovePeer(msg.PeerID)
-			}
-		} else {
-			//cbft.log.Trace("The message has been processed, discard it", "msgHash", msg.Msg.MsgHash(), "peerID", msg.PeerID)
-		}
-		cbft.forgetMessage(msg.PeerID)
-	}
-
 	// channel Divided into read-only type, writable type
 	// Read-only is the channel that gets the current CBFT status.
 	// Writable type is the channel that affects the consensus state.
 	for {
 		select {
 		case msg := <-cbft.peerMsgCh:
-			consensusMessageHandler(msg)
+			cbft.consensusMessageHandler(msg)
 		default:
 		}
 		select {
 		case msg := <-cbft.peerMsgCh:
 			// Forward the message before processing the message.
-			consensusMessageHandler(msg)
+			cbft.consensusMessageHandler(msg)
 		case msg := <-cbft.syncMsgCh:
-			if err := cbft.handleSyncMsg(msg); err != nil {
-				if err, ok := err.(HandleError); ok {
-					if err.AuthFailed() {
-						cbft.log.Error("Verify signature failed to sync message, will add to blacklist", "peerID", msg.PeerID)
-						cbft.network.MarkBlacklist(msg.PeerID)
-						cbft.network.RemovePeer(msg.PeerID)
-					}
-				}
-			}
-			cbft.forgetMessage(msg.PeerID)
+			cbft.syncMessageHandler(msg)
 		case msg := <-cbft.asyncExecutor.ExecuteStatus():
-			cbft.onAsyncExecuteStatus(msg)
-			if cbft.executeStatusHook != nil {
-				cbft.executeStatusHook(msg)
-			}
+			cbft.executeStatusHandler(msg)
 
 		case fn := <-cbft.asyncCallCh:
 			fn()
@@ -549,6 +539,51 @@ func (cbft *Cbft) receiveLoop() {
 	}
 }
 
+// consensusMessageHandler is responsible for handling consensus message logic.
+func (cbft *Cbft) consensusMessageHandler(msg *ctypes.MsgInfo) {
+	if !cbft.network.ContainsHistoryMessageHash(msg.Msg.MsgHash()) {
+		err := cbft.handleConsensusMsg(msg)
+		if err == nil {
+			cbft.network.MarkHistoryMessageHash(msg.Msg.MsgHash())
+			if err := cbft.network.Forwarding(msg.PeerID, msg.Msg); err != nil {
+				cbft.log.Debug("Forward message failed", "err", err)
+			}
+		} else if e, ok := err.(HandleError); ok && e.AuthFailed() {
+			// If the verification signature is abnormal,
+			// the peer node is added to the local blacklist
+			// and disconnected.
+			cbft.log.Error("Verify signature failed, will add to blacklist", "peerID", msg.PeerID, "err", err)
+			cbft.network.MarkBlacklist(msg.PeerID)
+			cbft.network.RemovePeer(msg.PeerID)
+		}
+	} else {
+		//cbft.log.Trace("The message has been processed, discard it", "msgHash", msg.Msg.MsgHash(), "peerID", msg.PeerID)
+	}
+	cbft.forgetMessage(msg.PeerID)
+}
+
+// syncMessageHandler is responsible for handling synchronization message logic.
+func (cbft *Cbft) syncMessageHandler(msg *ctypes.MsgInfo) {
+	if err := cbft.handleSyncMsg(msg); err != nil {
+		if err, ok := err.(HandleError); ok {
+			if err.AuthFailed() {
+				cbft.log.Error("Verify signature failed to sync message, will add to blacklist", "peerID", msg.PeerID)
+				cbft.network.MarkBlacklist(msg.PeerID)
+				cbft.network.RemovePeer(msg.PeerID)
+			}
+		}
+	}
+	cbft.forgetMessage(msg.PeerID)
+}
+
+// executeStatusHandler is responsible for handling the block execution results.
+func (cbft *Cbft) executeStatusHandler(s *executor.BlockExecuteStatus) {
+	cbft.onAsyncExecuteStatus(s)
+	if cbft.executeStatusHook != nil {
+		cbft.executeStatusHook(s)
+	}
+}
+
 // Handling consensus messages, there are three main types of messages. prepareBlock, prepareVote, viewChange
 func (cbft *Cbft) handleConsensusMsg(info *ctypes.MsgInfo) error {
 	if !cbft.running() {
@@ -1177,8 +1212,8 @@ func (cbft *Cbft) OnShouldSeal(result chan error) {
 	}
 
 	rtt := cbft.avgRTT()
-	if cbft.state.Deadline().Sub(time.Now()) <= rtt {
-		cbft.log.Debug("Not enough time to propagated block, stopped sealing", "deadline", cbft.state.Deadline(), "interval", cbft.state.Deadline().Sub(time.Now()), "rtt", rtt)
+	if cbft.state.Deadline().Sub(cbft.clock.Now()) <= rtt {
+		cbft.log.Debug("Not enough time to propagated block, stopped sealing", "deadline", cbft.state.Deadline(), "interval", cbft.state.Deadline().Sub(cbft.clock.Now()), "rtt", rtt)
 		result <- errors.New("not enough time to propagated block, stopped sealing")
 		return
 	}
@@ -1206,10 +1241,10 @@ func (cbft *Cbft) CalcNextBlockTime(blockTime time.Time) time.Time {
 	rtt := cbft.avgRTT()
 	executeTime := (produceInterval - rtt) / 2
 	cbft.log.Debug("Calc next block time",
-		"blockTime", blockTime, "now", time.Now(), "produceInterval", produceInterval,
+		"blockTime", blockTime, "now", cbft.clock.Now(), "produceInterval", produceInterval,
 		"period", cbft.config.Sys.Period, "amount", cbft.config.Sys.Amount,
-		"interval", time.Since(blockTime), "rtt", rtt, "executeTime", executeTime)
-	if time.Since(blockTime) < produceInterval {
+		"interval", cbft.clock.Now().Sub(blockTime), "rtt", rtt, "executeTime", executeTime)
+	if cbft.clock.Now().Sub(blockTime) < produceInterval {
 		return blockTime.Add(executeTime + rtt)
 	}
 	// Commit new block immediately.
@@ -1323,6 +1358,11 @@ func (cbft *Cbft) Evidences() string {
 	return string(js)
 }
 
+// EvidenceList returns the evidences of the evidence pool.
+func (cbft *Cbft) EvidenceList() cconsensus.Evidences {
+	return cbft.evPool.Evidences()
+}
+
 func (cbft *Cbft) verifySelfSigned(m []byte, sig []byte) bool {
 	recPubKey, err := crypto.Ecrecover(m, sig)
 	if err != nil {
@@ -1708,36 +1748,66 @@ func (cbft *Cbft) generateViewChangeQC(viewChanges map[uint32]*protocols.ViewCha
 }
 
 func (cbft *Cbft) verifyPrepareQC(oriNum uint64, oriHash common.Hash, qc *ctypes.QuorumCert) error {
+	aggSig, err := cbft.prepareQCAggSig(oriNum, oriHash, qc)
+	if err != nil {
+		return err
+	}
+	if err = cbft.validatorPool.VerifyAggSigByBA(qc.Epoch, qc.ValidatorSet, aggSig.Msg, aggSig.Signature); err != nil {
+		cbft.log.Error("Verify failed", "qc", qc.String(), "validators", cbft.validatorPool.Validators(qc.Epoch).String())
+		return authFailedError{err: fmt.Errorf("verify prepare qc failed: %v", err)}
+	}
+	return nil
+}
+
+// verifyPrepareQCs verifies the prepare QCs of a list of blocks, the signatures
+// of all the QCs are verified at once.
+func (cbft *Cbft) verifyPrepareQCs(blocks []*types.Block, qcs []*ctypes.QuorumCert) error {
+	if len(blocks) != len(qcs) {
+		return authFailedError{err: fmt.Errorf("verify prepare qcs failed,blocks:%d,qcs:%d", len(blocks), len(qcs))}
+	}
+	aggSigs := make([]*validator.AggSig, len(qcs))
+	for i, block := range blocks {
+		aggSig, err := cbft.prepareQCAggSig(block.NumberU64(), block.Hash(), qcs[i])
+		if err != nil {
+			return err
+		}
+		aggSigs[i] = aggSig
+	}
+	if i, err := cbft.validatorPool.VerifyAggSigsByBA(aggSigs); err != nil {
+		cbft.log.Error("Verify failed", "qc", qcs[i].String(), "validators", cbft.validatorPool.Validators(qcs[i].Epoch).String())
+		return authFailedError{err: fmt.Errorf("verify prepare qc failed,number:%d,hash:%s: %v", blocks[i].NumberU64(), blocks[i].Hash().String(), err)}
+	}
+	return nil
+}
+
+// prepareQCAggSig checks whether the prepare QC is the one of the block and is
+// signed by enough validators, it returns the signature to be verified.
+func (cbft *Cbft) prepareQCAggSig(oriNum uint64, oriHash common.Hash, qc *ctypes.QuorumCert) (*validator.AggSig, error) {
 	if qc == nil {
-		return fmt.Errorf("verify prepare qc failed,qc is nil")
+		return nil, fmt.Errorf("verify prepare qc failed,qc is nil")
 	}
 	if err := cbft.validatorPool.EnableVerifyEpoch(qc.Epoch); err != nil {
-		return err
+		return nil, err
 	}
 	// check signature number
 	threshold := cbft.threshold(cbft.validatorPool.Len(qc.Epoch))
 
 	signsTotal := qc.Len()
 	if signsTotal < threshold {
-		return authFailedError{err: fmt.Errorf("block qc has small number of signature total:%d, threshold:%d", signsTotal, threshold)}
+		return nil, authFailedError{err: fmt.Errorf("block qc has small number of signature total:%d, threshold:%d", signsTotal, threshold)}
 	}
 	// check if the corresponding block QC
 	if oriNum != qc.BlockNumber || oriHash != qc.BlockHash {
-		return authFailedError{
+		return nil, authFailedError{
 			err: fmt.Errorf("verify prepare qc failed,not the corresponding qc,oriNum:%d,oriHash:%s,qcNum:%d,qcHash:%s",
 				oriNum, oriHash.String(), qc.BlockNumber, qc.BlockHash.String())}
 	}
 
-	var cb []byte
-	var err error
-	if cb, err = qc.CannibalizeBytes(); err != nil {
-		return err
-	}
-	if err = cbft.validatorPool.VerifyAggSigByBA(qc.Epoch, qc.ValidatorSet, cb, qc.Signature.Bytes()); err != nil {
-		cbft.log.Error("Verify failed", "qc", qc.String(), "validators", cbft.validatorPool.Validators(qc.Epoch).String())
-		return authFailedError{err: fmt.Errorf("verify prepare qc failed: %v", err)}
+	cb, err := qc.CannibalizeBytes()
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return &validator.AggSig{Epoch: qc.Epoch, ValidatorSet: qc.ValidatorSet, Msg: cb, Signature: qc.Signature.Bytes()}, nil
 }
 
 func (cbft *Cbft) validateViewChangeQC(viewChangeQC *ctypes.ViewChangeQC) error {
@@ -1791,24 +1861,23 @@ func (cbft *Cbft) verifyViewChangeQC(viewChangeQC *ctypes.ViewChangeQC) error {
 		return err
 	}
 
-	var err error
-	for _, vc := range viewChangeQC.QCs {
-		var cb []byte
-		if cb, err = vc.CannibalizeBytes(); err != nil {
-			err = fmt.Errorf("get cannibalize bytes failed")
-			break
+	aggSigs := make([]*validator.AggSig, len(viewChangeQC.QCs))
+	for i, vc := range viewChangeQC.QCs {
+		cb, err := vc.CannibalizeBytes()
+		if err != nil {
+			return fmt.Errorf("get cannibalize bytes failed")
 		}
+		aggSigs[i] = &validator.AggSig{Epoch: vc.Epoch, ValidatorSet: vc.ValidatorSet, Msg: cb, Signature: vc.Signature.Bytes()}
+	}
 
-		if err = cbft.validatorPool.VerifyAggSigByBA(vc.Epoch, vc.ValidatorSet, cb, vc.Signature.Bytes()); err != nil {
-			cbft.log.Debug("verify failed", "qc", vc.String(), "validators", cbft.validatorPool.Validators(vc.Epoch).String())
+	if i, err := cbft.validatorPool.VerifyAggSigsByBA(aggSigs); err != nil {
+		vc := viewChangeQC.QCs[i]
+		cbft.log.Debug("verify failed", "qc", vc.String(), "validators", cbft.validatorPool.Validators(vc.Epoch).String())
 
-			err = authFailedError{err: fmt.Errorf("verify viewchange qc failed:number:%d,validators:%s,msg:%s,signature:%s,err:%v",
-				vc.BlockNumber, vc.ValidatorSet.String(), hexutil.Encode(cb), vc.Signature.String(), err)}
-			break
-		}
+		return authFailedError{err: fmt.Errorf("verify viewchange qc failed:number:%d,validators:%s,msg:%s,signature:%s,err:%v",
+			vc.BlockNumber, vc.ValidatorSet.String(), hexutil.Encode(aggSigs[i].Msg), vc.Signature.String(), err)}
 	}
-
-	return err
+	return nil
 }
 
 // NodeID returns the ID value of the current node
@@ -349,7 +349,7 @@ func (cbft *Cbft) onAsyncExecuteStatus(s *executor.BlockExecuteStatus) {
 
 				cbft.log.Debug("Sign block", "hash", s.Hash, "number", s.Number)
 				if msg := cbft.csPool.GetPrepareQC(cbft.state.Epoch(), cbft.state.ViewNumber(), index); msg != nil {
-					go cbft.ReceiveMessage(msg)
+					cbft.receiveCachedMessage(msg)
 				}
 			}
 		}
@@ -444,3 +444,43 @@ func TestDuplicateViewChangeEvidence_Validate(t *testing.T) {
 	}
 	assert.Nil(t, d.Validate())
 }
+
+func TestEvidenceInfo(t *testing.T) {
+	validateNodes, secretKeys := createValidateNode(2)
+
+	pvA := makePrepareVote(1, 1, common.BytesToHash(utils.Rand32Bytes(32)), 10, 1, validateNodes[0].Index, t, secretKeys[0])
+	evidenceVoteA, _ := NewEvidenceVote(pvA, validateNodes[0])
+	pvB := makePrepareVote(1, 1, common.BytesToHash(utils.Rand32Bytes(32)), 10, 1, validateNodes[0].Index, t, secretKeys[0])
+	evidenceVoteB, _ := NewEvidenceVote(pvB, validateNodes[0])
+
+	d := &DuplicatePrepareVoteEvidence{
+		VoteA: evidenceVoteA,
+		VoteB: evidenceVoteB,
+	}
+	info, err := NewEvidenceInfo(d)
+	assert.Nil(t, err)
+	assert.True(t, info.Valid)
+	assert.Equal(t, "duplicateVote", info.TypeName)
+	assert.Equal(t, uint64(10), info.BlockNumber)
+	assert.Equal(t, validateNodes[0].NodeID, info.NodeID)
+
+	// The data can be decoded by the slashing plugin.
+	evs, err := NewEvidence(consensus.EvidenceType(info.Type), info.Data)
+	assert.Nil(t, err)
+	assert.True(t, evs.Equal(d))
+
+	var filter *EvidenceFilter
+	assert.True(t, filter.Match(info))
+	assert.True(t, (&EvidenceFilter{Type: uint8(DuplicatePrepareVoteType), FromBlock: 10, ToBlock: 10, ValidOnly: true}).Match(info))
+	assert.False(t, (&EvidenceFilter{Type: uint8(DuplicatePrepareBlockType)}).Match(info))
+	assert.False(t, (&EvidenceFilter{NodeID: validateNodes[1].NodeID}).Match(info))
+	assert.False(t, (&EvidenceFilter{FromBlock: 11}).Match(info))
+	assert.False(t, (&EvidenceFilter{ToBlock: 9}).Match(info))
+
+	d.VoteB, _ = NewEvidenceVote(makePrepareVote(1, 1, common.BytesToHash(utils.Rand32Bytes(32)), 10, 1, validateNodes[1].Index, t, secretKeys[1]), validateNodes[1])
+	info, err = NewEvidenceInfo(d)
+	assert.Nil(t, err)
+	assert.False(t, info.Valid)
+	assert.NotEmpty(t, info.Error)
+	assert.False(t, (&EvidenceFilter{ValidOnly: true}).Match(info))
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package evidence
+
+import (
+	"encoding/json"
+
+	"github.com/AlayaNetwork/Alaya-Go/common/consensus"
+	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+)
+
+// EvidenceTypeName returns the name of the type of an evidence.
+func EvidenceTypeName(t consensus.EvidenceType) string {
+	switch t {
+	case DuplicatePrepareBlockType:
+		return "duplicatePrepare"
+	case DuplicatePrepareVoteType:
+		return "duplicateVote"
+	case DuplicateViewChangeType:
+		return "duplicateViewchange"
+	}
+	return "unknown"
+}
+
+// EvidenceInfo is an evidence with its verification status, Type and Data
+// are the arguments of the reportDuplicateSign transaction of the slashing contract.
+type EvidenceInfo struct {
+	Hash        hexutil.Bytes   `json:"hash"`
+	Type        uint8           `json:"type"`
+	TypeName    string          `json:"typeName"`
+	Epoch       uint64          `json:"epoch"`
+	ViewNumber  uint64          `json:"viewNumber"`
+	BlockNumber uint64          `json:"blockNumber"`
+	NodeID      discover.NodeID `json:"nodeId"`
+	Valid       bool            `json:"valid"`
+	Error       string          `json:"error,omitempty"`
+	Data        string          `json:"data"`
+}
+
+// NewEvidenceInfo verifies an evidence and encodes it for the slashing contract.
+func NewEvidenceInfo(ev consensus.Evidence) (*EvidenceInfo, error) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	info := &EvidenceInfo{
+		Hash:        ev.Hash(),
+		Type:        uint8(ev.Type()),
+		TypeName:    EvidenceTypeName(ev.Type()),
+		Epoch:       ev.Epoch(),
+		ViewNumber:  ev.ViewNumber(),
+		BlockNumber: ev.BlockNumber(),
+		NodeID:      ev.NodeID(),
+		Valid:       true,
+		Data:        string(data),
+	}
+	if err := ev.Validate(); err != nil {
+		info.Valid, info.Error = false, err.Error()
+	}
+	return info, nil
+}
+
+// EvidenceFilter selects evidences, the zero value of a field matches all evidences.
+type EvidenceFilter struct {
+	Type      uint8           `json:"type"`
+	NodeID    discover.NodeID `json:"nodeId"`
+	FromBlock uint64          `json:"fromBlock"`
+	ToBlock   uint64          `json:"toBlock"`
+	ValidOnly bool            `json:"validOnly"`
+}
+
+// Match returns whether the evidence is selected by the filter.
+func (f *EvidenceFilter) Match(info *EvidenceInfo) bool {
+	if f == nil {
+		return true
+	}
+	if f.Type != 0 && f.Type != info.Type {
+		return false
+	}
+	if f.NodeID != (discover.NodeID{}) && f.NodeID != info.NodeID {
+		return false
+	}
+	if info.BlockNumber < f.FromBlock || f.ToBlock != 0 && info.BlockNumber > f.ToBlock {
+		return false
+	}
+	return !f.ValidOnly || info.Valid
+}
@@ -125,3 +125,44 @@ func (exe *AsyncExecutor) loop() {
 		}
 	}
 }
+
+// SyncExecutor executes the blocks on the goroutine of the caller, the results
+// are queued until they are read from ExecuteStatus. It is used when the
+// engine is driven by a simulator which requires a deterministic order of events.
+type SyncExecutor struct {
+	AsyncBlockExecutor
+
+	executeFn      Executor
+	executeResults chan *BlockExecuteStatus
+}
+
+// NewSyncExecutor new a sync block executor.
+func NewSyncExecutor(executeFn Executor, size int) *SyncExecutor {
+	return &SyncExecutor{
+		executeFn:      executeFn,
+		executeResults: make(chan *BlockExecuteStatus, size),
+	}
+}
+
+// Stop stop sync executor.
+func (exe *SyncExecutor) Stop() {
+}
+
+// Execute executes the block and queues the result.
+func (exe *SyncExecutor) Execute(block *types.Block, parent *types.Block) error {
+	if len(exe.executeResults) == cap(exe.executeResults) {
+		return errors.New("execute result queue is full")
+	}
+	err := exe.executeFn(block, parent)
+	exe.executeResults <- &BlockExecuteStatus{
+		Hash:   block.Hash(),
+		Number: block.Number().Uint64(),
+		Err:    err,
+	}
+	return nil
+}
+
+// ExecuteStatus return a channel for notify block execute result.
+func (exe *SyncExecutor) ExecuteStatus() <-chan *BlockExecuteStatus {
+	return exe.executeResults
+}
@@ -78,3 +78,24 @@ loop:
 	assert.Equal(t, 20, success)
 	asyncExecutor.Stop()
 }
+
+func TestSyncExecute(t *testing.T) {
+	executed := 0
+	executor := func(block *types.Block, parent *types.Block) error {
+		executed++
+		return nil
+	}
+	syncExecutor := NewSyncExecutor(executor, 2)
+
+	parent := NewBlock(common.BytesToHash(utils.Rand32Bytes(32)), 1)
+	block := NewBlock(parent.Hash(), parent.NumberU64()+1)
+	assert.Nil(t, syncExecutor.Execute(block, parent))
+	assert.Equal(t, 1, executed)
+	assert.Nil(t, syncExecutor.Execute(NewBlock(block.Hash(), block.NumberU64()+1), block))
+	assert.NotNil(t, syncExecutor.Execute(NewBlock(block.Hash(), block.NumberU64()+1), block))
+	assert.Equal(t, 2, executed)
+
+	result := <-syncExecutor.ExecuteStatus()
+	assert.Equal(t, block.Hash(), result.Hash)
+	assert.Nil(t, result.Err)
+}
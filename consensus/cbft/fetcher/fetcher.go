@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/types"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/utils"
 )
 
 var (
@@ -61,6 +62,10 @@ type Fetcher struct {
 	newTask chan *task
 	quit    chan struct{}
 	tasks   map[string]*task
+
+	clock utils.Clock
+	// Whether the tasks are executed on the goroutine of the caller.
+	sync bool
 }
 
 // NewFetcher returns a new pointer to the Fetcher.
@@ -69,12 +74,27 @@ func NewFetcher() *Fetcher {
 		newTask: make(chan *task, 1),
 		tasks:   make(map[string]*task),
 		quit:    make(chan struct{}),
+		clock:   utils.SystemClock{},
 	}
 	return fetcher
 }
 
+// NewSyncFetcher returns a Fetcher without background loop, the executor of a
+// matched task runs on the goroutine of MatchTask and the expired tasks are
+// only removed by Expire. It keeps the order of events deterministic when the
+// consensus engine is driven by a simulator.
+func NewSyncFetcher(clock utils.Clock) *Fetcher {
+	fetcher := NewFetcher()
+	fetcher.clock = clock
+	fetcher.sync = true
+	return fetcher
+}
+
 // Start turns on for Fetch.
 func (f *Fetcher) Start() {
+	if f.sync {
+		return
+	}
 	go f.loop()
 }
 
@@ -85,24 +105,54 @@ func (f *Fetcher) Stop() {
 
 // AddTask adds a fetcher task.
 func (f *Fetcher) AddTask(id string, match MatchFunc, executor ExecutorFunc, expire ExpireFunc) {
+	t := &task{id: id, match: match, executor: executor, expire: expire, time: f.clock.Now()}
+	if f.sync {
+		f.lock.Lock()
+		f.tasks[id] = t
+		f.lock.Unlock()
+		return
+	}
 	select {
 	case <-f.quit:
-	case f.newTask <- &task{id: id, match: match, executor: executor, expire: expire, time: time.Now()}:
+	case f.newTask <- t:
 	}
 }
 
 // MatchTask matching task.
 func (f *Fetcher) MatchTask(id string, message types.Message) bool {
+	f.lock.Lock()
+	t, ok := f.tasks[id]
+	if !ok || !t.match(message) {
+		f.lock.Unlock()
+		return false
+	}
+	delete(f.tasks, id)
+	f.lock.Unlock()
+
+	if f.sync {
+		t.executor(message)
+	} else {
+		go t.executor(message)
+	}
+	return true
+}
+
+// Expire removes the tasks which have been waiting longer than arriveTimeout.
+func (f *Fetcher) Expire() {
 	f.lock.Lock()
 	defer f.lock.Unlock()
-	if t, ok := f.tasks[id]; ok {
-		if t.match(message) {
-			go t.executor(message)
+	f.expire()
+}
+
+func (f *Fetcher) expire() {
+	for id, task := range f.tasks {
+		if f.clock.Now().Sub(task.time) > arriveTimeout {
+			if task.expire != nil {
+				task.expire()
+			}
 			delete(f.tasks, id)
-			return true
 		}
 	}
-	return false
 }
 
 // Len returns the number of existing tasks.
@@ -128,14 +178,7 @@ func (f *Fetcher) loop() {
 
 		case <-fetchTimer.C:
 			f.lock.Lock()
-			for id, task := range f.tasks {
-				if time.Since(task.time) > arriveTimeout {
-					if task.expire != nil {
-						task.expire()
-					}
-					delete(f.tasks, id)
-				}
-			}
+			f.expire()
 			if len(f.tasks) == 0 {
 				fetchTimer.Stop()
 			} else {
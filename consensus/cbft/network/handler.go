@@ -156,6 +156,21 @@ func (h *EngineManager) sendMessage(m *types.MsgPackage) {
 	h.router.SendMessage(m)
 }
 
+// PendingMessages removes and returns the messages waiting in the send queue.
+// It is used when the sendLoop is not started and the messages are delivered
+// by the caller itself, such as the consensus simulator.
+func (h *EngineManager) PendingMessages() []*types.MsgPackage {
+	msgs := make([]*types.MsgPackage, 0, len(h.sendQueue))
+	for {
+		select {
+		case m := <-h.sendQueue:
+			msgs = append(msgs, m)
+		default:
+			return msgs
+		}
+	}
+}
+
 // PeerSetting sets the block height of the node related type.
 func (h *EngineManager) PeerSetting(peerID string, bType uint64, blockNumber uint64) error {
 	p, err := h.peers.get(peerID)
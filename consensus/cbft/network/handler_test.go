@@ -276,6 +276,20 @@ func Test_EngineManager_Send(t *testing.T) {
 	assert.Equal(t, 0, len(handle.sendQueue))
 }
 
+func Test_EngineManager_PendingMessages(t *testing.T) {
+	handle, _ := newHandle(t)
+
+	handle.Send("I", newFakeGetPrepareVote())
+	handle.Broadcast(newFakeViewChange())
+	handle.PartBroadcast(newFakePrepareBlockHash())
+	msgs := handle.PendingMessages()
+	assert.Equal(t, 3, len(msgs))
+	assert.Equal(t, "I", msgs[0].PeerID())
+	assert.Equal(t, uint64(types.FullMode), msgs[1].Mode())
+	assert.Equal(t, uint64(types.PartMode), msgs[2].Mode())
+	assert.Equal(t, 0, len(handle.PendingMessages()))
+}
+
 func Test_EngineManager_Synchronize(t *testing.T) {
 	handle, fake := newHandle(t)
 	peers := fake.peers
@@ -203,7 +203,7 @@ func (r *baseSafetyRules) PrepareBlockRules(block *protocols.PrepareBlock) Safet
 		}
 
 		// prepareBlock time cannot exceed system time by 1000 ms(default)
-		sysTime := time.Now()
+		sysTime := r.viewState.Clock().Now()
 		if !blockTime.Before(sysTime.Add(riseTimeLimit)) {
 			return newCommonError(fmt.Sprintf("prepareBlock time is advance(blockHash:%s, blockNum:%d, blockTime:%d, sysTime:%d)",
 				block.Block.Hash().String(), block.BlockNum(), block.Block.Time().Int64(), common.Millis(sysTime)))
@@ -291,7 +291,7 @@ func (r *baseSafetyRules) PrepareBlockRules(block *protocols.PrepareBlock) Safet
 	}
 
 	if r.viewState.IsDeadline() {
-		return newCommonError(fmt.Sprintf("view's deadline is expire(over:%s)", time.Since(r.viewState.Deadline())))
+		return newCommonError(fmt.Sprintf("view's deadline is expire(over:%s)", r.viewState.Clock().Now().Sub(r.viewState.Deadline())))
 	}
 	return nil
 }
@@ -356,7 +356,7 @@ func (r *baseSafetyRules) PrepareVoteRules(vote *protocols.PrepareVote) SafetyEr
 	}
 
 	if r.viewState.IsDeadline() {
-		return newCommonError(fmt.Sprintf("view's deadline is expire(over:%d)", time.Since(r.viewState.Deadline())))
+		return newCommonError(fmt.Sprintf("view's deadline is expire(over:%d)", r.viewState.Clock().Now().Sub(r.viewState.Deadline())))
 	}
 	return nil
 }
@@ -0,0 +1,133 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package cbft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/protocols"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSimulator(t *testing.T) *Simulator {
+	config := DefaultSimConfig
+	config.Period = 2000
+	config.Amount = 5
+	config.LivenessTimeout = 10 * time.Second
+	s, err := NewSimulator(config)
+	require.NoError(t, err)
+	return s
+}
+
+func TestSimulator(t *testing.T) {
+	s := newTestSimulator(t)
+	defer s.Close()
+
+	report := s.Run(20 * time.Second)
+	t.Log(report)
+	assert.True(t, report.Safe())
+	assert.True(t, report.Live())
+	for _, n := range report.Committed {
+		assert.True(t, n > 10)
+	}
+}
+
+func TestSimulatorDeterministic(t *testing.T) {
+	run := func() *SimReport {
+		s := newTestSimulator(t)
+		defer s.Close()
+		s.SetRule(protocols.PrepareVoteMsg, MsgRule{Drop: 0.1, Jitter: 200 * time.Millisecond})
+		return s.Run(10 * time.Second)
+	}
+	r1, r2 := run(), run()
+	assert.Equal(t, r1.String(), r2.String())
+}
+
+func TestSimulatorMessageFaults(t *testing.T) {
+	s := newTestSimulator(t)
+	defer s.Close()
+
+	s.SetRule(protocols.PrepareVoteMsg, MsgRule{Drop: 0.2, Delay: 100 * time.Millisecond, Jitter: 300 * time.Millisecond})
+	s.SetRule(protocols.PrepareBlockMsg, MsgRule{Jitter: 200 * time.Millisecond})
+	report := s.Run(30 * time.Second)
+	t.Log(report)
+	assert.True(t, report.Dropped > 0)
+	assert.True(t, report.Safe())
+	assert.True(t, report.Live())
+}
+
+func TestSimulatorPartition(t *testing.T) {
+	s := newTestSimulator(t)
+	defer s.Close()
+
+	// No group has a quorum, the views change until the partition heals.
+	s.At(5*time.Second, func() { s.Partition([]int{0, 1}, []int{2, 3}) })
+	s.At(25*time.Second, s.Heal)
+	report := s.Run(20 * time.Second)
+	assert.True(t, report.Safe())
+	assert.False(t, report.Live())
+	stalled := report.Committed
+
+	report = s.Run(30 * time.Second)
+	t.Log(report)
+	assert.True(t, report.Safe())
+	for i, n := range report.Committed {
+		assert.True(t, n > stalled[i], "node %d", i)
+	}
+}
+
+func TestSimulatorCrashRestart(t *testing.T) {
+	s := newTestSimulator(t)
+	defer s.Close()
+
+	// Without a quorum nothing is committed, the restarted nodes recover
+	// their views from the WAL and the chain moves on.
+	s.At(5*time.Second, func() {
+		s.Crash(2)
+		s.Crash(3)
+	})
+	s.At(15*time.Second, func() {
+		require.NoError(t, s.Restart(2))
+		require.NoError(t, s.Restart(3))
+	})
+	report := s.Run(15 * time.Second)
+	assert.True(t, report.Safe())
+	assert.False(t, report.Live())
+	assert.Nil(t, s.Engine(2))
+	stalled := report.Committed
+
+	report = s.Run(20 * time.Second)
+	t.Log(report)
+	assert.True(t, report.Safe())
+	for i, n := range report.Committed {
+		assert.True(t, n > stalled[i], "node %d", i)
+	}
+}
+
+func TestSimulatorEquivocation(t *testing.T) {
+	s := newTestSimulator(t)
+	defer s.Close()
+
+	s.Equivocate(0)
+	report := s.Run(30 * time.Second)
+	t.Log(report)
+	assert.True(t, report.Safe())
+	assert.True(t, report.Evidences > 0)
+}
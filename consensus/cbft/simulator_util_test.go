@@ -0,0 +1,899 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package cbft
+
+import (
+	"container/heap"
+	"crypto/ecdsa"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/consensus"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/executor"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/fetcher"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/network"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/protocols"
+	ctypes "github.com/AlayaNetwork/Alaya-Go/consensus/cbft/types"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/utils"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/validator"
+	"github.com/AlayaNetwork/Alaya-Go/core"
+	"github.com/AlayaNetwork/Alaya-Go/core/rawdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/core/vm"
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/crypto/bls"
+	"github.com/AlayaNetwork/Alaya-Go/ethdb"
+	"github.com/AlayaNetwork/Alaya-Go/event"
+	"github.com/AlayaNetwork/Alaya-Go/log"
+	"github.com/AlayaNetwork/Alaya-Go/node"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/params"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+)
+
+// simBaseTime is the wall clock time at which every simulation starts.
+var simBaseTime = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// SimConfig is the configuration of the Simulator.
+type SimConfig struct {
+	Nodes  int    // Number of validators
+	Period uint64 // Duration of a view in milliseconds
+	Amount uint32 // Number of blocks of a view
+	Seed   int64  // Seed of the keys of the validators and of the injected faults
+
+	Tick            time.Duration // Resolution of the virtual clock
+	Latency         time.Duration // One way latency of the virtual network
+	LivenessTimeout time.Duration // Longest time without a new committed block
+	DataDir         string        // Directory of the WAL, a temporary one is used if it is empty
+}
+
+// DefaultSimConfig is the default configuration of the Simulator.
+var DefaultSimConfig = SimConfig{
+	Nodes:           4,
+	Period:          10000,
+	Amount:          10,
+	Seed:            1,
+	Tick:            10 * time.Millisecond,
+	Latency:         50 * time.Millisecond,
+	LivenessTimeout: 30 * time.Second,
+}
+
+// MsgRule describes the faults injected into a type of message.
+type MsgRule struct {
+	Drop   float64       // Probability of dropping a message
+	Delay  time.Duration // Extra delay of a message
+	Jitter time.Duration // Upper bound of a random extra delay, it reorders the messages
+}
+
+// ViolationKind is the kind of a violated property.
+type ViolationKind int
+
+const (
+	SafetyViolation ViolationKind = iota
+	LivenessViolation
+)
+
+func (k ViolationKind) String() string {
+	switch k {
+	case SafetyViolation:
+		return "safety"
+	case LivenessViolation:
+		return "liveness"
+	default:
+		return "unknown"
+	}
+}
+
+// Violation is a violated safety or liveness property.
+type Violation struct {
+	Kind   ViolationKind
+	At     time.Duration // Virtual time since the start of the simulation
+	Node   int
+	Number uint64
+	Detail string
+}
+
+func (v *Violation) String() string {
+	return fmt.Sprintf("%s violation at %v, node:%d, number:%d, %s", v.Kind, v.At, v.Node, v.Number, v.Detail)
+}
+
+// SimReport is the result of a simulation.
+type SimReport struct {
+	Elapsed    time.Duration // Virtual time since the start of the simulation
+	Committed  []uint64      // Highest block committed by each node
+	Delivered  int           // Number of delivered messages
+	Dropped    int           // Number of dropped messages
+	Evidences  int           // Number of evidences of the running nodes
+	Violations []*Violation
+}
+
+// Safe returns whether no conflicting blocks were committed.
+func (r *SimReport) Safe() bool {
+	return r.count(SafetyViolation) == 0
+}
+
+// Live returns whether new blocks were always committed within the liveness timeout.
+func (r *SimReport) Live() bool {
+	return r.count(LivenessViolation) == 0
+}
+
+func (r *SimReport) count(kind ViolationKind) int {
+	n := 0
+	for _, v := range r.Violations {
+		if v.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *SimReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "elapsed:%v, committed:%v, delivered:%d, dropped:%d, evidences:%d", r.Elapsed, r.Committed, r.Delivered, r.Dropped, r.Evidences)
+	for _, v := range r.Violations {
+		fmt.Fprintf(&b, "\n%s", v)
+	}
+	return b.String()
+}
+
+// simMsg is a message in flight on the virtual network.
+type simMsg struct {
+	at       time.Duration
+	seq      uint64
+	from, to int
+	typ      reflect.Type
+	data     []byte
+}
+
+type simMsgQueue []*simMsg
+
+func (q simMsgQueue) Len() int { return len(q) }
+func (q simMsgQueue) Less(i, j int) bool {
+	if q[i].at == q[j].at {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].at < q[j].at
+}
+func (q simMsgQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *simMsgQueue) Push(x interface{}) { *q = append(*q, x.(*simMsg)) }
+func (q *simMsgQueue) Pop() interface{} {
+	old := *q
+	m := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return m
+}
+
+// simAction is a fault scheduled by the script of a simulation.
+type simAction struct {
+	at  time.Duration
+	seq uint64
+	fn  func()
+}
+
+// simFork is a conflicting proposal of an equivocating validator.
+type simFork struct {
+	origin common.Hash
+	block  *protocols.PrepareBlock
+	to     map[int]bool
+}
+
+type simForkKey struct {
+	epoch, viewNumber uint64
+	blockIndex        uint32
+}
+
+// simTxPool is a transaction pool without transactions.
+type simTxPool struct{}
+
+func (simTxPool) ForkedReset(newHeader *types.Header, rollback []*types.Block) {}
+func (simTxPool) Reset(newBlock *types.Block)                                  {}
+
+// simNode is a validator of the simulation, the database and the WAL are kept
+// across restarts.
+type simNode struct {
+	index  int
+	peerID string
+	pk     *ecdsa.PrivateKey
+	sk     *bls.SecretKey
+	db     ethdb.Database
+
+	engine *Cbft
+	chain  *core.BlockChain
+	cache  *core.BlockChainCache
+
+	committed    uint64
+	nextSeal     time.Duration
+	nextQCBnSync time.Duration
+	nextSync     time.Duration
+}
+
+// Simulator runs several CBFT engines in process over a virtual network and a
+// virtual clock. It plays the roles of the p2p network, the miner and the
+// worker of every node on a single goroutine, so the order of events only
+// depends on the seed and on the scripted faults:
+//
+//   - messages are dropped, delayed or reordered per message type,
+//   - the network is split into partitions,
+//   - nodes crash and restart, recovering from their WAL,
+//   - equivocating validators propose conflicting blocks and vote for both.
+//
+// Every committed block is checked against the blocks committed by the other
+// nodes, and a liveness violation is reported if no new block is committed
+// within the liveness timeout.
+//
+// A node which misses more blocks than the QC synchronization can fetch needs
+// the downloader, which is not simulated.
+type Simulator struct {
+	config SimConfig
+	clock  *utils.SimulatedClock
+	rand   *rand.Rand
+	dir    string
+	tmpDir bool
+
+	nodes     []*simNode
+	cbftNodes []params.CbftNode
+	peers     map[string]int
+
+	queue   simMsgQueue
+	actions []*simAction
+	seq     uint64
+
+	rules        map[uint64]MsgRule
+	partition    []int
+	equivocators map[int]bool
+	forks        map[simForkKey]*simFork
+
+	commits      map[uint64]common.Hash
+	highest      uint64
+	lastProgress time.Duration
+	stalled      bool
+	report       SimReport
+}
+
+// NewSimulator creates the validators of a simulation and starts their engines.
+func NewSimulator(config SimConfig) (*Simulator, error) {
+	if config.Nodes <= 0 || config.Period == 0 || config.Amount == 0 || config.Tick <= 0 {
+		return nil, fmt.Errorf("invalid simulator config, nodes:%d, period:%d, amount:%d, tick:%v", config.Nodes, config.Period, config.Amount, config.Tick)
+	}
+	s := &Simulator{
+		config:       config,
+		clock:        utils.NewSimulatedClock(simBaseTime),
+		rand:         rand.New(rand.NewSource(config.Seed)),
+		dir:          config.DataDir,
+		peers:        make(map[string]int),
+		rules:        make(map[uint64]MsgRule),
+		partition:    make([]int, config.Nodes),
+		equivocators: make(map[int]bool),
+		forks:        make(map[simForkKey]*simFork),
+		commits:      make(map[uint64]common.Hash),
+	}
+	if s.dir == "" {
+		dir, err := ioutil.TempDir("", "cbft-simulator")
+		if err != nil {
+			return nil, err
+		}
+		s.dir, s.tmpDir = dir, true
+	}
+
+	s.nodes = make([]*simNode, config.Nodes)
+	s.cbftNodes = make([]params.CbftNode, config.Nodes)
+	for i := range s.nodes {
+		pk, sk, err := s.generateKeys()
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		id := discover.PubkeyID(&pk.PublicKey)
+		s.cbftNodes[i] = params.CbftNode{Node: *discover.NewNode(id, nil, 0, 0), BlsPubKey: *sk.GetPublicKey()}
+		s.nodes[i] = &simNode{index: i, peerID: id.TerminalString(), pk: pk, sk: sk, db: rawdb.NewMemoryDatabase()}
+		s.peers[s.nodes[i].peerID] = i
+		CreateGenesis(s.nodes[i].db)
+	}
+	for _, n := range s.nodes {
+		if err := s.start(n); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// generateKeys derives the keys of a validator from the seed.
+func (s *Simulator) generateKeys() (*ecdsa.PrivateKey, *bls.SecretKey, error) {
+	buf := make([]byte, 32)
+	s.rand.Read(buf)
+	pk, err := crypto.ToECDSA(buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.rand.Read(buf)
+	var sk bls.SecretKey
+	if err := sk.SetLittleEndian(buf); err != nil {
+		return nil, nil, err
+	}
+	return pk, &sk, nil
+}
+
+// start creates the engine of a node on its database and WAL.
+func (s *Simulator) start(n *simNode) error {
+	sysConfig := &params.CbftConfig{
+		Period:       s.config.Period,
+		Amount:       s.config.Amount,
+		InitialNodes: s.cbftNodes,
+	}
+	optConfig := &ctypes.OptionsConfig{
+		NodePriKey:        n.pk,
+		NodeID:            discover.PubkeyID(&n.pk.PublicKey),
+		BlsPriKey:         n.sk,
+		WalMode:           true,
+		PeerMsgQueueSize:  1024,
+		EvidenceDir:       "evidence",
+		MaxPingLatency:    5000,
+		MaxQueuesLimit:    4096,
+		BlacklistDeadline: 5,
+	}
+	ctx := node.NewServiceContext(&node.Config{DataDir: filepath.Join(s.dir, fmt.Sprintf("node%d", n.index)), Name: "alaya"}, nil, new(event.TypeMux), nil)
+
+	engine := New(sysConfig, optConfig, ctx.EventMux, ctx)
+	if engine == nil {
+		return fmt.Errorf("create engine of node %d failed", n.index)
+	}
+	engine.simulated = true
+	engine.clock = s.clock
+	engine.syncingCache = ctypes.NewSyncCacheWithClock(syncCacheTimeout, s.clock)
+	engine.fetcher = fetcher.NewSyncFetcher(s.clock)
+	engine.updateChainStateHook = func(qcState, lockState, commitState *protocols.State) {
+		s.commit(n, qcState, lockState, commitState)
+	}
+
+	chain, err := core.NewBlockChain(n.db, nil, chainConfig, engine, vm.Config{}, nil)
+	if err != nil {
+		engine.evPool.Close()
+		return err
+	}
+	cache := core.NewBlockChainCache(chain)
+	engine.asyncExecutor = executor.NewSyncExecutor(cache.Execute, int(optConfig.PeerMsgQueueSize))
+
+	n.engine, n.chain, n.cache = engine, chain, cache
+	n.committed = chain.CurrentBlock().NumberU64()
+	now := s.Elapsed()
+	n.nextSeal, n.nextQCBnSync, n.nextSync = now, now+network.QCBnMonitorInterval*time.Second, now+network.SyncViewChangeInterval*time.Second
+
+	if err := engine.Start(chain, cache, simTxPool{}, validator.NewStaticAgency(s.cbftNodes)); err != nil {
+		s.stop(n)
+		return err
+	}
+	// Register the other validators so that the engine forwards messages and
+	// synchronizes with them.
+	ids := make([]discover.NodeID, 0, len(s.nodes)-1)
+	for _, p := range s.nodes {
+		if p != n {
+			ids = append(ids, discover.PubkeyID(&p.pk.PublicKey))
+		}
+	}
+	network.FillEngineManager(ids, engine.network)
+	return nil
+}
+
+// stop shuts down the engine of a node without any cleanup of its consensus state.
+func (s *Simulator) stop(n *simNode) {
+	n.engine.Close()
+	n.engine.fetcher.Stop()
+	n.engine.evPool.Close()
+	n.chain.Stop()
+	n.engine, n.chain, n.cache = nil, nil, nil
+}
+
+// Close stops all engines and removes the temporary data.
+func (s *Simulator) Close() {
+	for _, n := range s.nodes {
+		if n != nil && n.engine != nil {
+			s.stop(n)
+		}
+	}
+	if s.tmpDir {
+		os.RemoveAll(s.dir)
+	}
+}
+
+// Elapsed returns the virtual time since the start of the simulation.
+func (s *Simulator) Elapsed() time.Duration {
+	return time.Duration(s.clock.Simulated.Now())
+}
+
+// Engine returns the engine of a node, it is nil if the node is crashed.
+func (s *Simulator) Engine(i int) *Cbft {
+	return s.nodes[i].engine
+}
+
+// At schedules fn to run at the virtual time at, it is used to script faults.
+func (s *Simulator) At(at time.Duration, fn func()) {
+	s.seq++
+	s.actions = append(s.actions, &simAction{at: at, seq: s.seq, fn: fn})
+	sort.Slice(s.actions, func(i, j int) bool {
+		if s.actions[i].at == s.actions[j].at {
+			return s.actions[i].seq < s.actions[j].seq
+		}
+		return s.actions[i].at < s.actions[j].at
+	})
+}
+
+// SetRule sets the faults injected into the messages of the given type, such as protocols.PrepareVoteMsg.
+func (s *Simulator) SetRule(msgType uint64, rule MsgRule) {
+	s.rules[msgType] = rule
+}
+
+// ClearRules removes all message faults.
+func (s *Simulator) ClearRules() {
+	s.rules = make(map[uint64]MsgRule)
+}
+
+// Partition splits the network, nodes of different groups can't reach each
+// other. Nodes which are not in any group form a group of their own.
+func (s *Simulator) Partition(groups ...[]int) {
+	for i := range s.partition {
+		s.partition[i] = 0
+	}
+	for g, group := range groups {
+		for _, i := range group {
+			s.partition[i] = g + 1
+		}
+	}
+}
+
+// Heal removes all partitions.
+func (s *Simulator) Heal() {
+	s.Partition()
+}
+
+// Crash stops a node, the messages sent to it are dropped until it restarts.
+func (s *Simulator) Crash(i int) {
+	if n := s.nodes[i]; n.engine != nil {
+		log.Info("Simulator crash node", "node", i, "elapsed", s.Elapsed())
+		s.stop(n)
+	}
+}
+
+// Restart starts a crashed node again, it recovers its consensus state from the WAL.
+func (s *Simulator) Restart(i int) error {
+	n := s.nodes[i]
+	if n.engine != nil {
+		return nil
+	}
+	log.Info("Simulator restart node", "node", i, "elapsed", s.Elapsed())
+	return s.start(n)
+}
+
+// Equivocate makes a validator byzantine. When it proposes a block, another
+// block of the same height is sent to one half of the validators and it votes
+// for both of them.
+func (s *Simulator) Equivocate(i int) {
+	s.equivocators[i] = true
+}
+
+// Run runs the simulation for the duration d of virtual time.
+func (s *Simulator) Run(d time.Duration) *SimReport {
+	end := s.Elapsed() + d
+	for s.Elapsed() < end {
+		s.runActions()
+		s.deliver()
+		for _, n := range s.nodes {
+			if n.engine != nil {
+				s.synchronize(n)
+				s.seal(n)
+			}
+		}
+		s.drain()
+		s.checkLiveness()
+		s.clock.Run(s.config.Tick)
+	}
+	return s.Report()
+}
+
+// Report returns the current result of the simulation.
+func (s *Simulator) Report() *SimReport {
+	report := s.report
+	report.Elapsed = s.Elapsed()
+	report.Committed = make([]uint64, len(s.nodes))
+	report.Evidences = 0
+	for i, n := range s.nodes {
+		report.Committed[i] = n.committed
+		if n.engine != nil {
+			report.Evidences += len(n.engine.evPool.Evidences())
+		}
+	}
+	report.Violations = append([]*Violation(nil), s.report.Violations...)
+	return &report
+}
+
+func (s *Simulator) runActions() {
+	for len(s.actions) > 0 && s.actions[0].at <= s.Elapsed() {
+		action := s.actions[0]
+		s.actions = s.actions[1:]
+		action.fn()
+	}
+}
+
+// deliver hands the arrived messages to the engines, in the same way as the
+// message handler of the network.
+func (s *Simulator) deliver() {
+	for len(s.queue) > 0 && s.queue[0].at <= s.Elapsed() {
+		m := heap.Pop(&s.queue).(*simMsg)
+		n := s.nodes[m.to]
+		if n.engine == nil || s.partition[m.from] != s.partition[m.to] {
+			s.report.Dropped++
+			continue
+		}
+		msg := reflect.New(m.typ).Interface().(ctypes.Message)
+		if err := rlp.DecodeBytes(m.data, msg); err != nil {
+			log.Error("Simulator decode message failed", "type", m.typ, "err", err)
+			continue
+		}
+		s.report.Delivered++
+		info := ctypes.NewMsgInfo(msg, s.nodes[m.from].peerID)
+		switch msg.(type) {
+		case *protocols.PrepareBlock, *protocols.PrepareVote, *protocols.ViewChange:
+			n.engine.ReceiveMessage(info)
+		default:
+			n.engine.ReceiveSyncMsg(info)
+		}
+	}
+}
+
+// drain runs the engines until all their queues are empty and sends their messages.
+func (s *Simulator) drain() {
+	for _, n := range s.nodes {
+		if n.engine == nil {
+			continue
+		}
+		n.engine.fetcher.Expire()
+		for s.step(n.engine) {
+		}
+		for _, m := range n.engine.network.PendingMessages() {
+			s.route(n, m)
+		}
+	}
+}
+
+// step handles one event of the engine, in the same priority as the receive loop.
+func (s *Simulator) step(e *Cbft) bool {
+	select {
+	case msg := <-e.peerMsgCh:
+		e.consensusMessageHandler(msg)
+		return true
+	default:
+	}
+	select {
+	case msg := <-e.syncMsgCh:
+		e.syncMessageHandler(msg)
+		return true
+	default:
+	}
+	select {
+	case msg := <-e.asyncExecutor.ExecuteStatus():
+		e.executeStatusHandler(msg)
+		return true
+	default:
+	}
+	select {
+	case fn := <-e.asyncCallCh:
+		fn()
+		return true
+	default:
+	}
+	select {
+	case <-e.state.ViewTimeout():
+		e.OnViewTimeout()
+		return true
+	default:
+	}
+	select {
+	case err := <-e.commitErrCh:
+		e.OnCommitError(err)
+		return true
+	default:
+	}
+	return false
+}
+
+// route sends a message of a node to its targets on the virtual network.
+func (s *Simulator) route(n *simNode, m *ctypes.MsgPackage) {
+	var targets []int
+	if m.PeerID() != "" {
+		to, ok := s.peers[m.PeerID()]
+		if !ok {
+			return
+		}
+		targets = []int{to}
+	} else {
+		for _, p := range s.nodes {
+			if p != n {
+				targets = append(targets, p.index)
+			}
+		}
+	}
+
+	msg := m.Message()
+	var fork ctypes.Message
+	if s.equivocators[n.index] {
+		fork = s.equivocate(n, msg, targets)
+	}
+	for _, to := range targets {
+		if fork != nil && s.forks[forkKeyOf(msg)].to[to] {
+			s.send(n.index, to, fork)
+		} else {
+			s.send(n.index, to, msg)
+		}
+	}
+}
+
+// send puts a message on the virtual network, applying the message faults.
+func (s *Simulator) send(from, to int, msg ctypes.Message) {
+	rule := s.rules[protocols.MessageType(msg)]
+	if rule.Drop > 0 && s.rand.Float64() < rule.Drop {
+		s.report.Dropped++
+		return
+	}
+	delay := s.config.Latency + rule.Delay
+	if rule.Jitter > 0 {
+		delay += time.Duration(s.rand.Int63n(int64(rule.Jitter)))
+	}
+	data, err := rlp.EncodeToBytes(msg)
+	if err != nil {
+		log.Error("Simulator encode message failed", "type", reflect.TypeOf(msg), "err", err)
+		return
+	}
+	s.seq++
+	heap.Push(&s.queue, &simMsg{
+		at:   s.Elapsed() + delay,
+		seq:  s.seq,
+		from: from,
+		to:   to,
+		typ:  reflect.TypeOf(msg).Elem(),
+		data: data,
+	})
+}
+
+func forkKeyOf(msg ctypes.Message) simForkKey {
+	switch m := msg.(type) {
+	case *protocols.PrepareBlock:
+		return simForkKey{m.Epoch, m.ViewNumber, m.BlockIndex}
+	case *protocols.PrepareVote:
+		return simForkKey{m.Epoch, m.ViewNumber, m.BlockIndex}
+	}
+	return simForkKey{}
+}
+
+// equivocate returns the conflicting message of a proposal or a vote of an
+// equivocating validator, it is nil if there is no conflicting proposal.
+func (s *Simulator) equivocate(n *simNode, msg ctypes.Message, targets []int) ctypes.Message {
+	switch m := msg.(type) {
+	case *protocols.PrepareBlock:
+		key := forkKeyOf(m)
+		if f, ok := s.forks[key]; ok {
+			if f.origin == m.Block.Hash() {
+				return f.block
+			}
+			return nil
+		}
+		parent := n.engine.blockTree.FindBlockByHash(m.Block.ParentHash())
+		if parent == nil {
+			return nil
+		}
+		block, err := s.newBlock(n, parent, common.MillisToTime(m.Block.Time().Int64()), 1)
+		if err != nil {
+			log.Error("Simulator create conflicting block failed", "err", err)
+			return nil
+		}
+		pb := &protocols.PrepareBlock{
+			Epoch:         m.Epoch,
+			ViewNumber:    m.ViewNumber,
+			Block:         block,
+			BlockIndex:    m.BlockIndex,
+			ProposalIndex: m.ProposalIndex,
+			PrepareQC:     m.PrepareQC,
+			ViewChangeQC:  m.ViewChangeQC,
+		}
+		if err := n.engine.signMsgByBls(pb); err != nil {
+			return nil
+		}
+		// The second half of the validators receive the conflicting block.
+		f := &simFork{origin: m.Block.Hash(), block: pb, to: make(map[int]bool)}
+		for _, p := range s.nodes {
+			if p != n && p.index >= len(s.nodes)/2 {
+				f.to[p.index] = true
+			}
+		}
+		s.forks[key] = f
+		log.Info("Simulator equivocate PrepareBlock", "node", n.index, "number", block.NumberU64(), "origin", m.Block.Hash(), "fork", block.Hash())
+		return pb
+
+	case *protocols.PrepareVote:
+		f, ok := s.forks[forkKeyOf(m)]
+		if !ok || f.origin != m.BlockHash {
+			return nil
+		}
+		pv := &protocols.PrepareVote{
+			Epoch:          m.Epoch,
+			ViewNumber:     m.ViewNumber,
+			BlockHash:      f.block.Block.Hash(),
+			BlockNumber:    f.block.Block.NumberU64(),
+			BlockIndex:     m.BlockIndex,
+			ValidatorIndex: m.ValidatorIndex,
+			ParentQC:       m.ParentQC,
+		}
+		if err := n.engine.signMsgByBls(pv); err != nil {
+			return nil
+		}
+		return pv
+	}
+	return nil
+}
+
+// synchronize sends the periodical synchronization messages of the network handler.
+func (s *Simulator) synchronize(n *simNode) {
+	now, e := s.Elapsed(), n.engine
+	if now >= n.nextQCBnSync {
+		status := e.latestStatus()
+		status.LogicType = network.TypeForQCBn
+		e.network.PartBroadcast(status)
+		n.nextQCBnSync = now + time.Duration(2+s.rand.Intn(3))*time.Second
+	}
+	if now >= n.nextSync {
+		if msg, err := e.missingPrepareVote(); err == nil {
+			e.network.PartBroadcast(msg)
+		}
+		if msg, err := e.missingViewChangeNodes(); err == nil {
+			e.network.PartBroadcast(msg)
+		}
+		n.nextSync = now + network.SyncViewChangeInterval*time.Second
+	}
+}
+
+// seal produces a block if the node is the proposer, in the same way as the miner.
+func (s *Simulator) seal(n *simNode) {
+	now, e := s.Elapsed(), n.engine
+	if now < n.nextSeal || e.isLoading() {
+		return
+	}
+	n.nextSeal = now + s.config.Tick
+
+	result := make(chan error, 1)
+	e.OnShouldSeal(result)
+	if err := <-result; err != nil {
+		return
+	}
+
+	blockTime := s.clock.Now()
+	block, err := s.newBlock(n, e.state.HighestExecutedBlock(), blockTime, 0)
+	if err != nil {
+		log.Error("Simulator create block failed", "node", n.index, "err", err)
+		return
+	}
+	results := make(chan *types.Block, 1)
+	complete := make(chan struct{}, 1)
+	e.OnSeal(block, results, make(chan struct{}), complete)
+	if next := e.CalcNextBlockTime(blockTime).Sub(simBaseTime); next > n.nextSeal {
+		n.nextSeal = next
+	}
+}
+
+// newBlock creates an empty block on parent and saves its state to the cache
+// of the node, the vanity distinguishes the blocks of the same parent and time.
+func (s *Simulator) newBlock(n *simNode, parent *types.Block, blockTime time.Time, vanity byte) (*types.Block, error) {
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:   parent.GasLimit(),
+		Time:       big.NewInt(common.Millis(blockTime)),
+		Extra:      make([]byte, 32+consensus.ExtraSeal),
+	}
+	header.Extra[0] = vanity
+
+	state, err := n.cache.MakeStateDB(parent)
+	if err != nil {
+		return nil, err
+	}
+	receipts, _, _, err := n.chain.Processor().Process(types.NewBlockWithHeader(header), state, vm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	header.Root = state.IntermediateRoot(true)
+	header = types.NewBlock(header, nil, receipts).Header()
+
+	sign, err := n.engine.signFn(header.SealHash().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	copy(header.Extra[len(header.Extra)-consensus.ExtraSeal:], sign)
+	block := types.NewBlockWithHeader(header)
+
+	sealHash := header.SealHash()
+	n.cache.WriteStateDB(sealHash, state, block.NumberU64())
+	n.cache.WriteReceipts(sealHash, receipts, block.NumberU64())
+	n.cache.AddSealBlock(sealHash, block.NumberU64())
+	return block, nil
+}
+
+// commit writes a committed block to the chain in the same way as the worker,
+// and checks that no other block of the same number has been committed.
+func (s *Simulator) commit(n *simNode, qcState, lockState, commitState *protocols.State) {
+	block := commitState.Block
+	if hash, ok := s.commits[block.NumberU64()]; ok && hash != block.Hash() {
+		s.violate(SafetyViolation, n.index, block.NumberU64(), fmt.Sprintf("committed %s, other nodes committed %s", block.Hash().TerminalString(), hash.TerminalString()))
+	} else if !ok {
+		s.commits[block.NumberU64()] = block.Hash()
+	}
+	if block.NumberU64() > s.highest {
+		s.highest, s.lastProgress, s.stalled = block.NumberU64(), s.Elapsed(), false
+	}
+
+	n.engine.bridge.UpdateChainState(qcState, lockState, commitState)
+	if n.chain.HasBlock(block.Hash(), block.NumberU64()) {
+		return
+	}
+	extra, err := ctypes.EncodeExtra(byte(cbftVersion), commitState.QuorumCert)
+	if err != nil {
+		return
+	}
+	cpy := types.NewBlockWithHeader(block.Header()).WithBody(block.Transactions(), block.ExtraData())
+	cpy.SetExtraData(extra)
+
+	sealHash := cpy.Header().SealHash()
+	state := n.cache.ReadStateDB(sealHash)
+	if state == nil {
+		log.Error("Simulator commit block failed, state is nil", "node", n.index, "number", block.NumberU64(), "hash", block.Hash())
+		return
+	}
+	if _, err := n.chain.WriteBlockWithState(cpy, n.cache.ReadReceipts(sealHash), state); err != nil {
+		log.Error("Simulator commit block failed", "node", n.index, "number", block.NumberU64(), "hash", block.Hash(), "err", err)
+		return
+	}
+	n.committed = block.NumberU64()
+}
+
+// checkLiveness reports a violation once for every period without a new committed block.
+func (s *Simulator) checkLiveness() {
+	if s.stalled || s.config.LivenessTimeout <= 0 || s.Elapsed()-s.lastProgress <= s.config.LivenessTimeout {
+		return
+	}
+	s.stalled = true
+	views := make([]string, len(s.nodes))
+	for i, n := range s.nodes {
+		if n.engine == nil {
+			views[i] = "down"
+		} else {
+			views[i] = fmt.Sprintf("%d/%d", n.engine.state.Epoch(), n.engine.state.ViewNumber())
+		}
+	}
+	s.violate(LivenessViolation, -1, s.highest, fmt.Sprintf("no block committed since %v, views:[%s]", s.lastProgress, strings.Join(views, " ")))
+}
+
+func (s *Simulator) violate(kind ViolationKind, node int, number uint64, detail string) {
+	v := &Violation{Kind: kind, At: s.Elapsed(), Node: node, Number: number, Detail: detail}
+	log.Warn("Simulator found violation", "violation", v.String())
+	s.report.Violations = append(s.report.Violations, v)
+}
@@ -25,7 +25,9 @@ import (
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
 	"github.com/AlayaNetwork/Alaya-Go/common/math"
+	"github.com/AlayaNetwork/Alaya-Go/common/mclock"
 	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/protocols"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/utils"
 
 	ctypes "github.com/AlayaNetwork/Alaya-Go/consensus/cbft/types"
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
@@ -502,9 +504,14 @@ type ViewState struct {
 }
 
 func NewViewState(period uint64, blockTree *ctypes.BlockTree) *ViewState {
+	return NewViewStateWithClock(period, blockTree, utils.SystemClock{})
+}
+
+// NewViewStateWithClock creates a ViewState whose view timer runs on the given clock.
+func NewViewStateWithClock(period uint64, blockTree *ctypes.BlockTree, clock utils.Clock) *ViewState {
 	return &ViewState{
 		view:      newView(),
-		viewTimer: newViewTimer(period),
+		viewTimer: newViewTimer(period, clock),
 		blockTree: blockTree,
 	}
 }
@@ -531,6 +538,11 @@ func (vs *ViewState) Deadline() time.Time {
 	return vs.viewTimer.deadline
 }
 
+// Clock returns the clock of the view timer.
+func (vs *ViewState) Clock() utils.Clock {
+	return vs.viewTimer.clock
+}
+
 func (vs *ViewState) NextViewBlockIndex() uint32 {
 	return vs.viewBlocks.MaxIndex() + 1
 }
@@ -751,7 +763,7 @@ func (vs *ViewState) IsDeadline() bool {
 	return vs.viewTimer.isDeadline()
 }
 
-func (vs *ViewState) ViewTimeout() <-chan time.Time {
+func (vs *ViewState) ViewTimeout() <-chan mclock.AbsTime {
 	return vs.viewTimer.timerChan()
 }
 
@@ -20,6 +20,9 @@ package state
 import (
 	"math"
 	"time"
+
+	"github.com/AlayaNetwork/Alaya-Go/common/mclock"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/utils"
 )
 
 const (
@@ -30,17 +33,19 @@ const (
 type viewTimer struct {
 	//Timer last timeout
 	deadline time.Time
-	timer    *time.Timer
+	timer    mclock.ChanTimer
+	clock    utils.Clock
 
 	//Time window length calculation module
 	timeInterval    viewTimeInterval
 	preViewInterval uint64
 }
 
-func newViewTimer(period uint64) *viewTimer {
-	timer := time.NewTimer(0)
+func newViewTimer(period uint64, clock utils.Clock) *viewTimer {
+	timer := clock.NewTimer(time.Hour)
 	timer.Stop()
 	return &viewTimer{timer: timer,
+		clock:           clock,
 		timeInterval:    viewTimeInterval{baseMs: period * uint64(time.Millisecond), exponentBase: exponentBase, maxExponent: maxExponent},
 		preViewInterval: 1,
 	}
@@ -61,7 +66,7 @@ func (t *viewTimer) calViewInterval(viewInterval uint64) uint64 {
 func (t *viewTimer) setupTimer(viewInterval uint64) {
 	viewInterval = t.calViewInterval(viewInterval)
 	duration := t.timeInterval.getViewTimeInterval(viewInterval)
-	t.deadline = time.Now().Add(duration)
+	t.deadline = t.clock.Now().Add(duration)
 	t.stopTimer()
 	t.timer.Reset(duration)
 }
@@ -69,17 +74,17 @@ func (t *viewTimer) setupTimer(viewInterval uint64) {
 func (t *viewTimer) stopTimer() {
 	if !t.timer.Stop() {
 		select {
-		case <-t.timer.C:
+		case <-t.timer.C():
 		default:
 		}
 	}
 }
-func (t *viewTimer) timerChan() <-chan time.Time {
-	return t.timer.C
+func (t *viewTimer) timerChan() <-chan mclock.AbsTime {
+	return t.timer.C()
 }
 
 func (t viewTimer) isDeadline() bool {
-	return t.deadline.Before(time.Now())
+	return t.deadline.Before(t.clock.Now())
 }
 
 // Calculate the time window of each view，time=b*e^m
@@ -18,16 +18,16 @@
 package state
 
 import (
-	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/utils"
 )
 
 func TestTimer(t *testing.T) {
-	timer := time.NewTimer(0)
-	timer.Stop()
-
-	viewTimer := &viewTimer{timer: timer, timeInterval: viewTimeInterval{baseMs: uint64(1 * time.Second), exponentBase: exponentBase, maxExponent: maxExponent}}
+	viewTimer := newViewTimer(1000, utils.SystemClock{})
 	viewTimer.setupTimer(1)
 	assert.False(t, viewTimer.isDeadline())
 	select {
@@ -36,6 +36,29 @@ func TestTimer(t *testing.T) {
 	}
 }
 
+func TestSimulatedTimer(t *testing.T) {
+	clock := utils.NewSimulatedClock(time.Unix(1600000000, 0))
+	viewTimer := newViewTimer(1000, clock)
+	viewTimer.setupTimer(1)
+	assert.Equal(t, clock.Base.Add(time.Second), viewTimer.deadline)
+
+	clock.Run(999 * time.Millisecond)
+	assert.False(t, viewTimer.isDeadline())
+	select {
+	case <-viewTimer.timerChan():
+		t.Fatal("timer fired before the deadline")
+	default:
+	}
+
+	clock.Run(2 * time.Millisecond)
+	assert.True(t, viewTimer.isDeadline())
+	select {
+	case <-viewTimer.timerChan():
+	default:
+		t.Fatal("timer not fired after the deadline")
+	}
+}
+
 func TestCalViewInterval(t *testing.T) {
 	type views struct {
 		in  uint64
@@ -50,7 +73,7 @@ func TestCalViewInterval(t *testing.T) {
 		{{2, 2}, {2, 3}, {2, 2}, {2, 3}},
 	}
 	for row, test := range testcases {
-		timer := newViewTimer(10)
+		timer := newViewTimer(10, utils.SystemClock{})
 		timer.calViewInterval(1)
 		for cul, c := range test {
 			//fmt.Printf("row:%d, cul:%d, pre:%d in:%d, out:%d\n", row, cul, timer.preViewInterval, c.in, c.out)
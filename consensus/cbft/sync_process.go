@@ -97,11 +97,9 @@ func (cbft *Cbft) fetchBlock(id string, hash common.Hash, number uint64, qc *cty
 
 			// Update the results to the CBFT state machine
 			cbft.asyncCallCh <- func() {
-				for i, block := range blockList.Blocks {
-					if err := cbft.verifyPrepareQC(block.NumberU64(), block.Hash(), blockList.QC[i]); err != nil {
-						cbft.log.Error("Verify block prepare qc failed", "hash", block.Hash(), "number", block.NumberU64(), "error", err)
-						return
-					}
+				if err := cbft.verifyPrepareQCs(blockList.Blocks, blockList.QC); err != nil {
+					cbft.log.Error("Verify block prepare qc failed", "error", err)
+					return
 				}
 				if err := cbft.OnInsertQCBlock(blockList.Blocks, blockList.QC); err != nil {
 					cbft.log.Error("Insert block failed", "error", err)
@@ -174,11 +172,9 @@ func (cbft *Cbft) fetchBlock(id string, hash common.Hash, number uint64, qc *cty
 			}
 
 			cbft.asyncCallCh <- func() {
-				for i, forkedBlock := range filteredForkedBlocks {
-					if err := cbft.verifyPrepareQC(forkedBlock.NumberU64(), forkedBlock.Hash(), blockList.ForkedQC[i]); err != nil {
-						cbft.log.Error("Verify forked block prepare qc failed", "hash", forkedBlock.Hash(), "number", forkedBlock.NumberU64(), "error", err)
-						return
-					}
+				if err := cbft.verifyPrepareQCs(filteredForkedBlocks, filteredForkedQCs); err != nil {
+					cbft.log.Error("Verify forked block prepare qc failed", "error", err)
+					return
 				}
 				if err := cbft.OnInsertQCBlock(filteredForkedBlocks, filteredForkedQCs); err != nil {
 					cbft.log.Error("Insert forked block failed", "error", err)
@@ -654,30 +650,33 @@ func (cbft *Cbft) MissingViewChangeNodes() (v *protocols.GetViewChange, err erro
 
 	cbft.asyncCallCh <- func() {
 		defer func() { result <- struct{}{} }()
-		allViewChange := cbft.state.AllViewChange()
+		v, err = cbft.missingViewChangeNodes()
+	}
+	<-result
+	return
+}
 
-		length := cbft.currentValidatorLen()
-		vbits := utils.NewBitArray(uint32(length))
+func (cbft *Cbft) missingViewChangeNodes() (*protocols.GetViewChange, error) {
+	allViewChange := cbft.state.AllViewChange()
 
-		// enough qc or did not reach deadline
-		if len(allViewChange) >= cbft.threshold(length) || !cbft.state.IsDeadline() {
-			v, err = nil, fmt.Errorf("no need sync viewchange")
-			return
-		}
-		for i := uint32(0); i < vbits.Size(); i++ {
-			if _, ok := allViewChange[i]; !ok {
-				vbits.SetIndex(i, true)
-			}
-		}
+	length := cbft.currentValidatorLen()
+	vbits := utils.NewBitArray(uint32(length))
 
-		v, err = &protocols.GetViewChange{
-			Epoch:          cbft.state.Epoch(),
-			ViewNumber:     cbft.state.ViewNumber(),
-			ViewChangeBits: vbits,
-		}, nil
+	// enough qc or did not reach deadline
+	if len(allViewChange) >= cbft.threshold(length) || !cbft.state.IsDeadline() {
+		return nil, fmt.Errorf("no need sync viewchange")
 	}
-	<-result
-	return
+	for i := uint32(0); i < vbits.Size(); i++ {
+		if _, ok := allViewChange[i]; !ok {
+			vbits.SetIndex(i, true)
+		}
+	}
+
+	return &protocols.GetViewChange{
+		Epoch:          cbft.state.Epoch(),
+		ViewNumber:     cbft.state.ViewNumber(),
+		ViewChangeBits: vbits,
+	}, nil
 }
 
 // MissingPrepareVote returns missing vote.
@@ -686,48 +685,48 @@ func (cbft *Cbft) MissingPrepareVote() (v *protocols.GetPrepareVote, err error)
 
 	cbft.asyncCallCh <- func() {
 		defer func() { result <- struct{}{} }()
+		v, err = cbft.missingPrepareVote()
+	}
+	<-result
+	return
+}
 
-		begin := cbft.state.MaxQCIndex() + 1
-		end := cbft.state.NextViewBlockIndex()
-		len := cbft.currentValidatorLen()
-		cbft.log.Debug("MissingPrepareVote", "epoch", cbft.state.Epoch(), "viewNumber", cbft.state.ViewNumber(), "beginIndex", begin, "endIndex", end, "validatorLen", len)
-
-		block := cbft.state.HighestQCBlock()
-		blockTime := common.MillisToTime(block.Time().Int64())
-
-		for index := begin; index < end; index++ {
-			size := cbft.state.PrepareVoteLenByIndex(index)
-			cbft.log.Debug("The length of prepare vote", "index", index, "size", size)
-
-			// We need sync prepare votes when a long time not arrived QC.
-			if size < cbft.threshold(len) && time.Since(blockTime) >= syncPrepareVotesInterval { // need sync prepare votes
-				knownVotes := cbft.state.AllPrepareVoteByIndex(index)
-				unKnownSet := utils.NewBitArray(uint32(len))
-				for i := uint32(0); i < unKnownSet.Size(); i++ {
-					if vote := cbft.csPool.GetPrepareVote(cbft.state.Epoch(), cbft.state.ViewNumber(), index, i); vote != nil {
-						go cbft.ReceiveMessage(vote)
-						continue
-					}
-					if _, ok := knownVotes[i]; !ok {
-						unKnownSet.SetIndex(i, true)
-					}
+func (cbft *Cbft) missingPrepareVote() (*protocols.GetPrepareVote, error) {
+	begin := cbft.state.MaxQCIndex() + 1
+	end := cbft.state.NextViewBlockIndex()
+	len := cbft.currentValidatorLen()
+	cbft.log.Debug("MissingPrepareVote", "epoch", cbft.state.Epoch(), "viewNumber", cbft.state.ViewNumber(), "beginIndex", begin, "endIndex", end, "validatorLen", len)
+
+	block := cbft.state.HighestQCBlock()
+	blockTime := common.MillisToTime(block.Time().Int64())
+
+	for index := begin; index < end; index++ {
+		size := cbft.state.PrepareVoteLenByIndex(index)
+		cbft.log.Debug("The length of prepare vote", "index", index, "size", size)
+
+		// We need sync prepare votes when a long time not arrived QC.
+		if size < cbft.threshold(len) && cbft.clock.Now().Sub(blockTime) >= syncPrepareVotesInterval { // need sync prepare votes
+			knownVotes := cbft.state.AllPrepareVoteByIndex(index)
+			unKnownSet := utils.NewBitArray(uint32(len))
+			for i := uint32(0); i < unKnownSet.Size(); i++ {
+				if vote := cbft.csPool.GetPrepareVote(cbft.state.Epoch(), cbft.state.ViewNumber(), index, i); vote != nil {
+					cbft.receiveCachedMessage(vote)
+					continue
+				}
+				if _, ok := knownVotes[i]; !ok {
+					unKnownSet.SetIndex(i, true)
 				}
-
-				v, err = &protocols.GetPrepareVote{
-					Epoch:      cbft.state.Epoch(),
-					ViewNumber: cbft.state.ViewNumber(),
-					BlockIndex: index,
-					UnKnownSet: unKnownSet,
-				}, nil
-				break
 			}
-		}
-		if v == nil {
-			err = fmt.Errorf("not need sync prepare vote")
+
+			return &protocols.GetPrepareVote{
+				Epoch:      cbft.state.Epoch(),
+				ViewNumber: cbft.state.ViewNumber(),
+				BlockIndex: index,
+				UnKnownSet: unKnownSet,
+			}, nil
 		}
 	}
-	<-result
-	return
+	return nil, fmt.Errorf("not need sync prepare vote")
 }
 
 // LatestStatus returns latest status.
@@ -736,24 +735,27 @@ func (cbft *Cbft) LatestStatus() (v *protocols.GetLatestStatus) {
 
 	cbft.asyncCallCh <- func() {
 		defer func() { result <- struct{}{} }()
+		v = cbft.latestStatus()
+	}
+	<-result
+	return
+}
 
-		qcBn, qcHash := cbft.HighestQCBlockBn()
-		_, qc := cbft.blockTree.FindBlockAndQC(qcHash, qcBn)
+func (cbft *Cbft) latestStatus() *protocols.GetLatestStatus {
+	qcBn, qcHash := cbft.HighestQCBlockBn()
+	_, qc := cbft.blockTree.FindBlockAndQC(qcHash, qcBn)
 
-		lockBn, lockHash := cbft.HighestLockBlockBn()
-		_, lockQC := cbft.blockTree.FindBlockAndQC(lockHash, lockBn)
+	lockBn, lockHash := cbft.HighestLockBlockBn()
+	_, lockQC := cbft.blockTree.FindBlockAndQC(lockHash, lockBn)
 
-		v = &protocols.GetLatestStatus{
-			BlockNumber:  qcBn,
-			BlockHash:    qcHash,
-			QuorumCert:   qc,
-			LBlockNumber: lockBn,
-			LBlockHash:   lockHash,
-			LQuorumCert:  lockQC,
-		}
+	return &protocols.GetLatestStatus{
+		BlockNumber:  qcBn,
+		BlockHash:    qcHash,
+		QuorumCert:   qc,
+		LBlockNumber: lockBn,
+		LBlockHash:   lockHash,
+		LQuorumCert:  lockQC,
 	}
-	<-result
-	return
 }
 
 // OnPong is used to receive the average delay time.
@@ -866,7 +868,7 @@ func calAverage(latencyList *list.List) int64 {
 
 func (cbft *Cbft) SyncPrepareBlock(id string, epoch uint64, viewNumber uint64, blockIndex uint32) {
 	if msg := cbft.csPool.GetPrepareBlock(epoch, viewNumber, blockIndex); msg != nil {
-		go cbft.ReceiveMessage(msg)
+		cbft.receiveCachedMessage(msg)
 	}
 	if cbft.syncingCache.AddOrReplace(blockIndex) {
 		msg := &protocols.GetPrepareBlock{Epoch: epoch, ViewNumber: viewNumber, BlockIndex: blockIndex}
@@ -882,7 +884,7 @@ func (cbft *Cbft) SyncPrepareBlock(id string, epoch uint64, viewNumber uint64, b
 
 func (cbft *Cbft) SyncBlockQuorumCert(id string, blockNumber uint64, blockHash common.Hash, blockIndex uint32) {
 	if msg := cbft.csPool.GetPrepareQC(cbft.state.Epoch(), cbft.state.ViewNumber(), blockIndex); msg != nil {
-		go cbft.ReceiveMessage(msg)
+		cbft.receiveCachedMessage(msg)
 	}
 	if cbft.syncingCache.AddOrReplace(blockHash) {
 		msg := &protocols.GetBlockQuorumCert{BlockHash: blockHash, BlockNumber: blockNumber}
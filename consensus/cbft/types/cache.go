@@ -19,6 +19,7 @@ package types
 
 import (
 	"github.com/AlayaNetwork/Alaya-Go/common/math"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/utils"
 	"sync"
 	"time"
 )
@@ -27,12 +28,19 @@ type SyncCache struct {
 	lock    sync.RWMutex
 	items   map[interface{}]time.Time
 	timeout time.Duration
+	clock   utils.Clock
 }
 
 func NewSyncCache(timeout time.Duration) *SyncCache {
+	return NewSyncCacheWithClock(timeout, utils.SystemClock{})
+}
+
+// NewSyncCacheWithClock creates a SyncCache which expires the items on the given clock.
+func NewSyncCacheWithClock(timeout time.Duration, clock utils.Clock) *SyncCache {
 	cache := &SyncCache{
 		items:   make(map[interface{}]time.Time),
 		timeout: timeout,
+		clock:   clock,
 	}
 	return cache
 }
@@ -40,18 +48,18 @@ func NewSyncCache(timeout time.Duration) *SyncCache {
 func (s *SyncCache) Add(v interface{}) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
-	s.items[v] = time.Now()
+	s.items[v] = s.clock.Now()
 }
 
 func (s *SyncCache) AddOrReplace(v interface{}) bool {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 	if t, ok := s.items[v]; ok {
-		if time.Since(t) < s.timeout {
+		if s.clock.Now().Sub(t) < s.timeout {
 			return false
 		}
 	}
-	s.items[v] = time.Now()
+	s.items[v] = s.clock.Now()
 	return true
 }
 
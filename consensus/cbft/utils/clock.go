@@ -0,0 +1,67 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"time"
+
+	"github.com/AlayaNetwork/Alaya-Go/common/mclock"
+)
+
+// Clock is the source of time of the consensus engine, it makes it possible
+// to run the engine on a virtual clock.
+type Clock interface {
+	// Now returns the current wall clock time.
+	Now() time.Time
+	// NewTimer creates a timer which fires after d.
+	NewTimer(d time.Duration) mclock.ChanTimer
+}
+
+// SystemClock implements Clock using the system clock.
+type SystemClock struct{}
+
+// Now returns the current local time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer creates a timer on the system clock.
+func (SystemClock) NewTimer(d time.Duration) mclock.ChanTimer {
+	return mclock.System{}.NewTimer(d)
+}
+
+// SimulatedClock implements Clock on top of a virtual clock, the wall clock
+// time starts at Base and moves only when the virtual clock is run.
+type SimulatedClock struct {
+	*mclock.Simulated
+	Base time.Time
+}
+
+// NewSimulatedClock creates a virtual clock starting at base.
+func NewSimulatedClock(base time.Time) *SimulatedClock {
+	return &SimulatedClock{Simulated: new(mclock.Simulated), Base: base}
+}
+
+// Now returns the current virtual wall clock time.
+func (c *SimulatedClock) Now() time.Time {
+	return c.Base.Add(time.Duration(c.Simulated.Now()))
+}
+
+// NewTimer creates a timer which fires when the virtual clock has advanced by d.
+func (c *SimulatedClock) NewTimer(d time.Duration) mclock.ChanTimer {
+	return c.Simulated.NewTimer(d)
+}
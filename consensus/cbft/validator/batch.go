@@ -0,0 +1,114 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/utils"
+	"github.com/AlayaNetwork/Alaya-Go/crypto/bls"
+)
+
+// AggSig is an aggregated signature of the validators in ValidatorSet over Msg,
+// such as the signature of a QuorumCert or a ViewChangeQuorumCert.
+type AggSig struct {
+	Epoch        uint64
+	ValidatorSet *utils.BitArray
+	Msg          []byte
+	Signature    []byte
+}
+
+// VerifyAggSigsByBA verifies a batch of aggregated signatures at once.
+//
+// Every signature is weighted by a random scalar and the whole batch is checked
+// by a single product of pairings, which takes about half of the pairing work of
+// verifying the signatures one by one. A forged signature can't be cancelled out
+// by another one without knowing the random scalars. If the batch is invalid,
+// the signatures are verified one by one to find the bad one, its index is
+// returned together with the error. The index is -1 if the batch is valid.
+func (vp *ValidatorPool) VerifyAggSigsByBA(sigs []*AggSig) (int, error) {
+	if len(sigs) == 1 {
+		if err := vp.VerifyAggSigByBA(sigs[0].Epoch, sigs[0].ValidatorSet, sigs[0].Msg, sigs[0].Signature); err != nil {
+			return 0, err
+		}
+		return -1, nil
+	}
+
+	pubs := make([]*bls.PublicKey, len(sigs))
+	for i, sig := range sigs {
+		pub, _, err := vp.aggregatePubKey(sig.Epoch, sig.ValidatorSet)
+		if err != nil {
+			return i, err
+		}
+		pubs[i] = pub
+	}
+	if batchVerify(pubs, sigs) {
+		return -1, nil
+	}
+
+	for i, sig := range sigs {
+		if err := vp.VerifyAggSigByBA(sig.Epoch, sig.ValidatorSet, sig.Msg, sig.Signature); err != nil {
+			return i, err
+		}
+	}
+	return -1, nil
+}
+
+// batchVerify checks e(sum(r_i*S_i), G) == prod(e(r_i*H(m_i), P_i)) with random
+// scalars r_i, the Miller loops of all the pairings share one final exponentiation.
+func batchVerify(pubs []*bls.PublicKey, sigs []*AggSig) bool {
+	if len(sigs) == 0 {
+		return true
+	}
+	var (
+		aggSig bls.G1
+		acc, e bls.GT
+	)
+	for i, s := range sigs {
+		var sig, h bls.G1
+		if err := sig.Deserialize(s.Signature); err != nil {
+			return false
+		}
+		if err := h.HashAndMapTo(s.Msg); err != nil {
+			return false
+		}
+		var pub bls.G2
+		if err := pub.Deserialize(pubs[i].Serialize()); err != nil {
+			return false
+		}
+
+		var r bls.Fr
+		r.SetByCSPRNG()
+		bls.G1Mul(&sig, &sig, &r)
+		bls.G1Mul(&h, &h, &r)
+		bls.MillerLoop(&e, &h, &pub)
+		if i == 0 {
+			aggSig, acc = sig, e
+		} else {
+			bls.G1Add(&aggSig, &aggSig, &sig)
+			bls.GTMul(&acc, &acc, &e)
+		}
+	}
+
+	var g bls.G2
+	if err := g.Deserialize(bls.GetGeneratorOfG2().Serialize()); err != nil {
+		return false
+	}
+	bls.G1Neg(&aggSig, &aggSig)
+	bls.MillerLoop(&e, &aggSig, &g)
+	bls.GTMul(&acc, &acc, &e)
+	bls.FinalExp(&acc, &acc)
+	return acc.IsOne()
+}
@@ -0,0 +1,144 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/utils"
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/crypto/bls"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/params"
+)
+
+// newBatchTestPool creates a validator pool of n static validators.
+func newBatchTestPool(n int) (*ValidatorPool, []*bls.SecretKey) {
+	nodes := make([]params.CbftNode, n)
+	keys := make([]*bls.SecretKey, n)
+	for i := range nodes {
+		priKey, _ := crypto.GenerateKey()
+		var sec bls.SecretKey
+		sec.SetByCSPRNG()
+		nodes[i] = params.CbftNode{Node: discover.Node{ID: discover.PubkeyID(&priKey.PublicKey)}, BlsPubKey: *sec.GetPublicKey()}
+		keys[i] = &sec
+	}
+	return NewValidatorPool(NewStaticAgency(nodes), 0, 0, nodes[0].Node.ID), keys
+}
+
+// newBatchTestSigs creates count signatures, each one is aggregated by the
+// first signers validators over a distinct message.
+func newBatchTestSigs(keys []*bls.SecretKey, count, signers int) []*AggSig {
+	sigs := make([]*AggSig, count)
+	for i := range sigs {
+		msg := []byte(fmt.Sprintf("qc %d", i))
+		vSet := utils.NewBitArray(uint32(len(keys)))
+		var sig bls.Sign
+		for j := 0; j < signers; j++ {
+			s := keys[j].Sign(string(msg))
+			if j == 0 {
+				sig = *s
+			} else {
+				sig.Add(s)
+			}
+			vSet.SetIndex(uint32(j), true)
+		}
+		sigs[i] = &AggSig{Epoch: 0, ValidatorSet: vSet, Msg: msg, Signature: sig.Serialize()}
+	}
+	return sigs
+}
+
+func TestVerifyAggSigsByBA(t *testing.T) {
+	bls.Init(bls.BLS12_381)
+	vp, keys := newBatchTestPool(7)
+
+	sigs := newBatchTestSigs(keys, 10, 5)
+	i, err := vp.VerifyAggSigsByBA(sigs)
+	assert.Nil(t, err)
+	assert.Equal(t, -1, i)
+
+	i, err = vp.VerifyAggSigsByBA(sigs[:1])
+	assert.Nil(t, err)
+	assert.Equal(t, -1, i)
+
+	// A signature moved to another message is found
+	forged := newBatchTestSigs(keys, 10, 5)
+	forged[6].Signature = forged[7].Signature
+	i, err = vp.VerifyAggSigsByBA(forged)
+	assert.NotNil(t, err)
+	assert.Equal(t, 6, i)
+
+	// Two forged signatures which would cancel out without the random weights
+	forged = newBatchTestSigs(keys, 10, 5)
+	var d, s2, s3 bls.G1
+	d.HashAndMapTo([]byte("offset"))
+	s2.Deserialize(forged[2].Signature)
+	s3.Deserialize(forged[3].Signature)
+	bls.G1Add(&s2, &s2, &d)
+	bls.G1Sub(&s3, &s3, &d)
+	forged[2].Signature, forged[3].Signature = s2.Serialize(), s3.Serialize()
+	i, err = vp.VerifyAggSigsByBA(forged)
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, i)
+
+	// The signers must match the validator set
+	forged = newBatchTestSigs(keys, 10, 5)
+	forged[4].ValidatorSet.SetIndex(0, false)
+	i, err = vp.VerifyAggSigsByBA(forged)
+	assert.NotNil(t, err)
+	assert.Equal(t, 4, i)
+
+	i, err = vp.VerifyAggSigsByBA(forged[4:5])
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, i)
+
+	forged = newBatchTestSigs(keys, 10, 5)
+	forged[9].ValidatorSet = utils.NewBitArray(uint32(len(keys) + 1))
+	forged[9].ValidatorSet.SetIndex(uint32(len(keys)), true)
+	i, err = vp.VerifyAggSigsByBA(forged)
+	assert.NotNil(t, err)
+	assert.Equal(t, 9, i)
+}
+
+func benchmarkVerifyAggSigs(b *testing.B, count int, batch bool) {
+	bls.Init(bls.BLS12_381)
+	vp, keys := newBatchTestPool(25)
+	sigs := newBatchTestSigs(keys, count, 17)
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if batch {
+			if _, err := vp.VerifyAggSigsByBA(sigs); err != nil {
+				b.Fatal(err)
+			}
+			continue
+		}
+		for _, sig := range sigs {
+			if err := vp.VerifyAggSigByBA(sig.Epoch, sig.ValidatorSet, sig.Msg, sig.Signature); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkVerifyAggSigsSerial10(b *testing.B)  { benchmarkVerifyAggSigs(b, 10, false) }
+func BenchmarkVerifyAggSigsBatch10(b *testing.B)   { benchmarkVerifyAggSigs(b, 10, true) }
+func BenchmarkVerifyAggSigsSerial100(b *testing.B) { benchmarkVerifyAggSigs(b, 100, false) }
+func BenchmarkVerifyAggSigsBatch100(b *testing.B)  { benchmarkVerifyAggSigs(b, 100, true) }
@@ -635,6 +635,26 @@ func (vp *ValidatorPool) VerifyAggSig(epoch uint64, validatorIndexes []uint32, m
 }
 
 func (vp *ValidatorPool) VerifyAggSigByBA(epoch uint64, vSet *utils.BitArray, msg, signature []byte) error {
+	pub, validators, err := vp.aggregatePubKey(epoch, vSet)
+	if err != nil {
+		return err
+	}
+
+	var sig bls.Sign
+	err = sig.Deserialize(signature)
+	if err != nil {
+		return err
+	}
+	if !sig.Verify(pub, string(msg)) {
+		log.Error("Verify signature fail", "epoch", epoch, "vSet", vSet.String(), "msg", hex.EncodeToString(msg), "signature", hex.EncodeToString(signature), "validators", validators.String())
+		return errors.New("bls verifies signature fail")
+	}
+	return nil
+}
+
+// aggregatePubKey returns the aggregated public key of the validators in vSet,
+// and the validator set of the epoch which they belong to.
+func (vp *ValidatorPool) aggregatePubKey(epoch uint64, vSet *utils.BitArray) (*bls.PublicKey, *cbfttypes.Validators, error) {
 	vp.lock.RLock()
 	validators := vp.currentValidators
 	if vp.epochToBlockNumber(epoch) <= vp.switchPoint {
@@ -644,27 +664,16 @@ func (vp *ValidatorPool) VerifyAggSigByBA(epoch uint64, vSet *utils.BitArray, ms
 	nodeList, err := validators.NodeListByBitArray(vSet)
 	if err != nil || len(nodeList) == 0 {
 		vp.lock.RUnlock()
-		return fmt.Errorf("not found validators: %v", err)
+		return nil, nil, fmt.Errorf("not found validators: %v", err)
 	}
 	vp.lock.RUnlock()
 
 	var pub bls.PublicKey
 	pub = *nodeList[0].BlsPubKey
-	//pub.Deserialize(nodeList[0].BlsPubKey.Serialize())
 	for i := 1; i < len(nodeList); i++ {
 		pub.Add(nodeList[i].BlsPubKey)
 	}
-
-	var sig bls.Sign
-	err = sig.Deserialize(signature)
-	if err != nil {
-		return err
-	}
-	if !sig.Verify(&pub, string(msg)) {
-		log.Error("Verify signature fail", "epoch", epoch, "vSet", vSet.String(), "msg", hex.EncodeToString(msg), "signature", hex.EncodeToString(signature), "nodeList", nodeList, "validators", validators.String())
-		return errors.New("bls verifies signature fail")
-	}
-	return nil
+	return &pub, validators, nil
 }
 
 func (vp *ValidatorPool) epochToBlockNumber(epoch uint64) uint64 {
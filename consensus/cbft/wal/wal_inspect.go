@@ -0,0 +1,486 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package wal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/protocols"
+	ctypes "github.com/AlayaNetwork/Alaya-Go/consensus/cbft/types"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+)
+
+// The size of the header of a journal entry: crc (4 bytes), length (4 bytes) and type (2 bytes).
+const journalHeaderSize = 10
+
+// JournalFile is a journal file of the WAL directory.
+type JournalFile struct {
+	Name   string `json:"name"`
+	FileID uint32 `json:"fileID"`
+	Size   int64  `json:"size"`
+}
+
+// ListJournalFiles retrieves the journal files of the WAL directory in ascending order.
+func ListJournalFiles(path string) ([]JournalFile, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	files := make([]JournalFile, 0)
+	for _, f := range listJournalFiles(path) {
+		info, err := os.Stat(filepath.Join(path, f.name))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, JournalFile{Name: f.name, FileID: f.num, Size: info.Size()})
+	}
+	return files, nil
+}
+
+// JournalEntry is a decoded entry of the journal.
+type JournalEntry struct {
+	FileID    uint32      // The journal file of the entry
+	Seq       uint64      // The offset of the entry in the journal file
+	Type      uint16      // The type of the message
+	Length    uint32      // The length of the encoded message
+	Checksum  uint32      // The crc of the encoded message
+	Timestamp uint64      // The time the message was written, in nanoseconds
+	Data      interface{} // The decoded message
+}
+
+// Size returns the size of the entry in the journal file.
+func (e *JournalEntry) Size() uint64 {
+	return uint64(journalHeaderSize) + uint64(e.Length)
+}
+
+// View returns the epoch and the view number of the message.
+func (e *JournalEntry) View() (epoch uint64, viewNumber uint64) {
+	switch m := e.Data.(type) {
+	case *protocols.ConfirmedViewChange:
+		return m.Epoch, m.ViewNumber
+	case protocols.WalMsg:
+		return m.Epoch(), m.ViewNumber()
+	}
+	return 0, 0
+}
+
+// MarshalJSON marshals the entry, blocks are replaced by their summary.
+func (e *JournalEntry) MarshalJSON() ([]byte, error) {
+	epoch, viewNumber := e.View()
+	type journalEntry struct {
+		FileID     uint32      `json:"fileID"`
+		Seq        uint64      `json:"seq"`
+		Type       string      `json:"type"`
+		Length     uint32      `json:"length"`
+		Checksum   uint32      `json:"checksum"`
+		Timestamp  uint64      `json:"timestamp"`
+		Epoch      uint64      `json:"epoch"`
+		ViewNumber uint64      `json:"viewNumber"`
+		Data       interface{} `json:"data"`
+	}
+	return json.Marshal(&journalEntry{
+		FileID:     e.FileID,
+		Seq:        e.Seq,
+		Type:       WalMessageName(e.Type),
+		Length:     e.Length,
+		Checksum:   e.Checksum,
+		Timestamp:  e.Timestamp,
+		Epoch:      epoch,
+		ViewNumber: viewNumber,
+		Data:       walMessageJSON(e.Data),
+	})
+}
+
+// JournalError reports a corrupted entry of the journal.
+type JournalError struct {
+	FileID uint32
+	Seq    uint64
+	Reason string
+}
+
+func (e *JournalError) Error() string {
+	return fmt.Sprintf("corrupted journal entry, fileID:%d, seq:%d, %s", e.FileID, e.Seq, e.Reason)
+}
+
+// WalMessageName returns the name of the type of a journal message.
+func WalMessageName(msgType uint16) string {
+	switch msgType {
+	case protocols.ConfirmedViewChangeMsg:
+		return "ConfirmedViewChange"
+	case protocols.SendViewChangeMsg:
+		return "SendViewChange"
+	case protocols.SendPrepareBlockMsg:
+		return "SendPrepareBlock"
+	case protocols.SendPrepareVoteMsg:
+		return "SendPrepareVote"
+	}
+	return fmt.Sprintf("Unknown(%d)", msgType)
+}
+
+// decodeJournalMessage decodes a journal message with its timestamp,
+// unlike WALDecode it returns an error for unknown types.
+func decodeJournalMessage(pack []byte, msgType uint16) (*Message, error) {
+	switch msgType {
+	case protocols.ConfirmedViewChangeMsg:
+		var j MessageConfirmedViewChange
+		if err := rlp.DecodeBytes(pack, &j); err != nil {
+			return nil, err
+		}
+		return &Message{Timestamp: j.Timestamp, Data: j.Data}, nil
+
+	case protocols.SendViewChangeMsg:
+		var j MessageSendViewChange
+		if err := rlp.DecodeBytes(pack, &j); err != nil {
+			return nil, err
+		}
+		return &Message{Timestamp: j.Timestamp, Data: j.Data}, nil
+
+	case protocols.SendPrepareBlockMsg:
+		var j MessageSendPrepareBlock
+		if err := rlp.DecodeBytes(pack, &j); err != nil {
+			return nil, err
+		}
+		return &Message{Timestamp: j.Timestamp, Data: j.Data}, nil
+
+	case protocols.SendPrepareVoteMsg:
+		var j MessageSendPrepareVote
+		if err := rlp.DecodeBytes(pack, &j); err != nil {
+			return nil, err
+		}
+		return &Message{Timestamp: j.Timestamp, Data: j.Data}, nil
+	}
+	return nil, fmt.Errorf("unknown message type %d", msgType)
+}
+
+// IterateJournal decodes the entries of the journal files of the WAL
+// directory, starting from the offset seq of the file fromFileID, and calls fn
+// for each of them. The checksum of every entry is verified, a *JournalError is
+// returned at the first corrupted entry.
+func IterateJournal(path string, fromFileID uint32, fromSeq uint64, fn func(entry *JournalEntry) error) error {
+	for _, file := range listJournalFiles(path) {
+		if file.num < fromFileID {
+			continue
+		}
+		seq := uint64(0)
+		if file.num == fromFileID {
+			seq = fromSeq
+		}
+		if err := iterateJournalFile(path, file.num, seq, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func iterateJournalFile(path string, fileID uint32, seq uint64, fn func(entry *JournalEntry) error) error {
+	file, err := os.Open(filepath.Join(path, fmt.Sprintf("wal.%d", fileID)))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(int64(seq), io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReaderSize(file, readBufferLimitSize)
+	header := make([]byte, journalHeaderSize)
+	for {
+		if _, err := io.ReadFull(reader, header); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return &JournalError{FileID: fileID, Seq: seq, Reason: "truncated header"}
+		}
+		entry := &JournalEntry{
+			FileID:   fileID,
+			Seq:      seq,
+			Checksum: binary.BigEndian.Uint32(header[0:4]),
+			Length:   binary.BigEndian.Uint32(header[4:8]),
+			Type:     binary.BigEndian.Uint16(header[8:10]),
+		}
+		if entry.Length > journalLimitSize {
+			return &JournalError{FileID: fileID, Seq: seq, Reason: fmt.Sprintf("invalid length %d", entry.Length)}
+		}
+		pack := make([]byte, entry.Length)
+		if _, err := io.ReadFull(reader, pack); err != nil {
+			return &JournalError{FileID: fileID, Seq: seq, Reason: fmt.Sprintf("truncated %s, length:%d", WalMessageName(entry.Type), entry.Length)}
+		}
+		if crc := crc32.Checksum(pack, crc32c); crc != entry.Checksum {
+			return &JournalError{FileID: fileID, Seq: seq, Reason: fmt.Sprintf("checksum mismatch, want:%d, have:%d", entry.Checksum, crc)}
+		}
+		msg, err := decodeJournalMessage(pack, entry.Type)
+		if err != nil {
+			return &JournalError{FileID: fileID, Seq: seq, Reason: fmt.Sprintf("decode %s failed, %v", WalMessageName(entry.Type), err)}
+		}
+		entry.Timestamp, entry.Data = msg.Timestamp, msg.Data
+		if err := fn(entry); err != nil {
+			return err
+		}
+		seq += entry.Size()
+	}
+}
+
+// TruncateJournal discards the entries of the journal from the offset seq of
+// the file fileID, the following journal files are removed. The offset must be
+// the start of an entry or the end of the file. If the journal was replayed from
+// a discarded position, the replay position saved in the WAL database is moved
+// back to the truncation point, so the entries appended later are replayed.
+func TruncateJournal(path string, fileID uint32, seq uint64) error {
+	files := listJournalFiles(path)
+	found := false
+	for _, file := range files {
+		if file.num == fileID {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("journal file %d not found", fileID)
+	}
+
+	// Check the offset is the boundary of an entry, the entries before it must be valid.
+	boundary := seq == 0
+	err := iterateJournalFile(path, fileID, 0, func(entry *JournalEntry) error {
+		if entry.Seq+entry.Size() == seq {
+			boundary = true
+		}
+		if entry.Seq >= seq {
+			return errStopIterate
+		}
+		return nil
+	})
+	if jerr, ok := err.(*JournalError); ok && jerr.Seq == seq {
+		err = nil
+	}
+	if err != nil && err != errStopIterate {
+		return err
+	}
+	if !boundary {
+		return fmt.Errorf("offset %d of journal file %d is not the start of an entry", seq, fileID)
+	}
+
+	if err := os.Truncate(filepath.Join(path, fmt.Sprintf("wal.%d", fileID)), int64(seq)); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.num > fileID {
+			if err := os.Remove(filepath.Join(path, file.name)); err != nil {
+				return err
+			}
+		}
+	}
+	return clampViewChangeMeta(path, fileID, seq)
+}
+
+// clampViewChangeMeta moves the replay position of the WAL database back to the
+// offset seq of the file fileID if it is beyond it.
+func clampViewChangeMeta(path string, fileID uint32, seq uint64) error {
+	if _, err := os.Stat(filepath.Join(path, metaDBName)); os.IsNotExist(err) {
+		return nil
+	}
+	db, err := createWalDB(filepath.Join(path, metaDBName))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	data, err := db.Get(viewChangeKey)
+	if err != nil {
+		return nil
+	}
+	var vc ViewChangeMessage
+	if err := rlp.DecodeBytes(data, &vc); err != nil {
+		return errGetViewChangeMeta
+	}
+	if vc.FileID < fileID || vc.FileID == fileID && vc.Seq <= seq {
+		return nil
+	}
+	vc.FileID, vc.Seq = fileID, seq
+	if data, err = rlp.EncodeToBytes(&vc); err != nil {
+		return err
+	}
+	return db.Put(viewChangeKey, data, nil)
+}
+
+var errStopIterate = fmt.Errorf("stop iterate")
+
+// ViewChangeQCEntry is a viewChangeQC saved in the WAL database.
+type ViewChangeQCEntry struct {
+	Epoch        uint64               `json:"epoch"`
+	ViewNumber   uint64               `json:"viewNumber"`
+	ViewChangeQC *ctypes.ViewChangeQC `json:"viewChangeQC"`
+}
+
+// Meta is the content of the WAL database.
+type Meta struct {
+	ChainState    *protocols.ChainState
+	ViewChange    *ViewChangeMessage // The journal position from which the messages are replayed
+	ViewChangeQCs []*ViewChangeQCEntry
+}
+
+// MarshalJSON marshals the meta, blocks are replaced by their summary.
+func (m *Meta) MarshalJSON() ([]byte, error) {
+	type meta struct {
+		ChainState    interface{}          `json:"chainState"`
+		ViewChange    *ViewChangeMessage   `json:"viewChange"`
+		ViewChangeQCs []*ViewChangeQCEntry `json:"viewChangeQCs"`
+	}
+	return json.Marshal(&meta{
+		ChainState:    walMessageJSON(m.ChainState),
+		ViewChange:    m.ViewChange,
+		ViewChangeQCs: m.ViewChangeQCs,
+	})
+}
+
+// ReadMeta reads the WAL database of the WAL directory, the node must be stopped.
+func ReadMeta(path string) (*Meta, error) {
+	if _, err := os.Stat(filepath.Join(path, metaDBName)); err != nil {
+		return nil, err
+	}
+	db, err := createWalDB(filepath.Join(path, metaDBName))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	meta := &Meta{ViewChangeQCs: make([]*ViewChangeQCEntry, 0)}
+	if data, err := db.Get(chainStateKey); err == nil {
+		var cs protocols.ChainState
+		if err := rlp.DecodeBytes(data, &cs); err != nil {
+			return nil, errGetChainState
+		}
+		meta.ChainState = &cs
+	}
+	if data, err := db.Get(viewChangeKey); err == nil {
+		var vc ViewChangeMessage
+		if err := rlp.DecodeBytes(data, &vc); err != nil {
+			return nil, errGetViewChangeMeta
+		}
+		meta.ViewChange = &vc
+	}
+	it := db.NewIterator(viewChangeQCPrefix, nil)
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(viewChangeQCKey(0, 0)) {
+			continue
+		}
+		var qc ctypes.ViewChangeQC
+		if err := rlp.DecodeBytes(it.Value(), &qc); err != nil {
+			return nil, errGetViewChangeQC
+		}
+		offset := len(viewChangeQCPrefix)
+		meta.ViewChangeQCs = append(meta.ViewChangeQCs, &ViewChangeQCEntry{
+			Epoch:        binary.BigEndian.Uint64(key[offset : offset+8]),
+			ViewNumber:   binary.BigEndian.Uint64(key[offset+8+len(viewChangeQCSplit):]),
+			ViewChangeQC: &qc,
+		})
+	}
+	return meta, it.Error()
+}
+
+// walBlock is the summary of a block in the JSON output.
+type walBlock struct {
+	Number     uint64      `json:"number"`
+	Hash       common.Hash `json:"hash"`
+	ParentHash common.Hash `json:"parentHash"`
+	Time       uint64      `json:"timestamp"`
+	Txs        int         `json:"transactions"`
+}
+
+func walBlockJSON(b *types.Block) *walBlock {
+	if b == nil {
+		return nil
+	}
+	return &walBlock{
+		Number:     b.NumberU64(),
+		Hash:       b.Hash(),
+		ParentHash: b.ParentHash(),
+		Time:       b.Time().Uint64(),
+		Txs:        len(b.Transactions()),
+	}
+}
+
+// walMessageJSON converts the WAL messages containing blocks to a JSON friendly form.
+func walMessageJSON(msg interface{}) interface{} {
+	type state struct {
+		Block      *walBlock          `json:"block"`
+		QuorumCert *ctypes.QuorumCert `json:"qc"`
+	}
+	stateJSON := func(s *protocols.State) *state {
+		if s == nil {
+			return nil
+		}
+		return &state{Block: walBlockJSON(s.Block), QuorumCert: s.QuorumCert}
+	}
+
+	switch m := msg.(type) {
+	case *protocols.ChainState:
+		if m == nil {
+			return nil
+		}
+		qcs := make([]*state, 0, len(m.QC))
+		for _, s := range m.QC {
+			qcs = append(qcs, stateJSON(s))
+		}
+		return &struct {
+			Commit *state   `json:"commit"`
+			Lock   *state   `json:"lock"`
+			QC     []*state `json:"qc"`
+		}{stateJSON(m.Commit), stateJSON(m.Lock), qcs}
+
+	case *protocols.ConfirmedViewChange:
+		return &struct {
+			Epoch        uint64               `json:"epoch"`
+			ViewNumber   uint64               `json:"viewNumber"`
+			Block        *walBlock            `json:"block"`
+			QC           *ctypes.QuorumCert   `json:"qc"`
+			ViewChangeQC *ctypes.ViewChangeQC `json:"viewChangeQC"`
+		}{m.Epoch, m.ViewNumber, walBlockJSON(m.Block), m.QC, m.ViewChangeQC}
+
+	case *protocols.SendViewChange:
+		return &struct {
+			ViewChange *protocols.ViewChange `json:"viewChange"`
+		}{m.ViewChange}
+
+	case *protocols.SendPrepareBlock:
+		pb := m.Prepare
+		return &struct {
+			Epoch         uint64               `json:"epoch"`
+			ViewNumber    uint64               `json:"viewNumber"`
+			Block         *walBlock            `json:"block"`
+			BlockIndex    uint32               `json:"blockIndex"`
+			ProposalIndex uint32               `json:"proposalIndex"`
+			PrepareQC     *ctypes.QuorumCert   `json:"prepareQC"`
+			ViewChangeQC  *ctypes.ViewChangeQC `json:"viewchangeQC"`
+			Signature     ctypes.Signature     `json:"signature"`
+		}{pb.Epoch, pb.ViewNumber, walBlockJSON(pb.Block), pb.BlockIndex, pb.ProposalIndex, pb.PrepareQC, pb.ViewChangeQC, pb.Signature}
+
+	case *protocols.SendPrepareVote:
+		return &struct {
+			Block *walBlock              `json:"block"`
+			Vote  *protocols.PrepareVote `json:"vote"`
+		}{walBlockJSON(m.Block), m.Vote}
+	}
+	return msg
+}
@@ -0,0 +1,204 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package wal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/protocols"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestJournal(t *testing.T, dir string, count int) {
+	wal, err := NewWal(nil, dir)
+	require.NoError(t, err)
+	wal.SetMockJournalLimitSize(4 * 1024)
+	assert.Nil(t, testWalUpdateViewChange(wal))
+	_, err = testWalUpdateChainState(wal)
+	assert.Nil(t, err)
+	assert.Nil(t, wal.UpdateViewChangeQC(epoch, viewNumber, buildViewChangeQC()))
+	for i := 0; i < count; i++ {
+		switch i % 4 {
+		case 0:
+			err = wal.WriteSync(buildConfirmedViewChange())
+		case 1:
+			err = wal.WriteSync(buildSendViewChange())
+		case 2:
+			err = wal.WriteSync(buildSendPrepareBlock())
+		case 3:
+			err = wal.WriteSync(buildSendPrepareVote())
+		}
+		require.NoError(t, err)
+	}
+	wal.Close()
+}
+
+func TestIterateJournal(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "wal")
+	defer os.RemoveAll(tempDir)
+	writeTestJournal(t, tempDir, 40)
+
+	files, err := ListJournalFiles(tempDir)
+	assert.Nil(t, err)
+	assert.True(t, len(files) > 1)
+
+	var entries []*JournalEntry
+	assert.Nil(t, IterateJournal(tempDir, 0, 0, func(entry *JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	assert.Equal(t, 40, len(entries))
+	for i, entry := range entries {
+		assert.Equal(t, uint16(i%4+1), entry.Type)
+		e, v := entry.View()
+		assert.Equal(t, epoch, e)
+		assert.Equal(t, viewNumber, v)
+
+		data, err := json.Marshal(entry)
+		assert.Nil(t, err)
+		assert.Contains(t, string(data), fmt.Sprintf(`"type":"%s"`, WalMessageName(entry.Type)))
+	}
+
+	meta, err := ReadMeta(tempDir)
+	assert.Nil(t, err)
+	assert.NotNil(t, meta.ChainState)
+	assert.Equal(t, epoch, meta.ViewChange.Epoch)
+	assert.Equal(t, 1, len(meta.ViewChangeQCs))
+	_, err = json.Marshal(meta)
+	assert.Nil(t, err)
+}
+
+func TestVerifyJournal(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "wal")
+	defer os.RemoveAll(tempDir)
+	writeTestJournal(t, tempDir, 8)
+
+	var entries []*JournalEntry
+	IterateJournal(tempDir, 0, 0, func(entry *JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	require.Equal(t, 8, len(entries))
+	require.Equal(t, entries[0].FileID, entries[2].FileID)
+
+	// Flip a byte of the payload of the third entry.
+	path := filepath.Join(tempDir, fmt.Sprintf("wal.%d", entries[2].FileID))
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	data[entries[2].Seq+journalHeaderSize+1] ^= 0xff
+	require.NoError(t, ioutil.WriteFile(path, data, 0644))
+
+	count := 0
+	err = IterateJournal(tempDir, 0, 0, func(entry *JournalEntry) error {
+		count++
+		return nil
+	})
+	jerr, ok := err.(*JournalError)
+	require.True(t, ok)
+	assert.Equal(t, entries[2].FileID, jerr.FileID)
+	assert.Equal(t, entries[2].Seq, jerr.Seq)
+	assert.Equal(t, 2, count)
+
+	// Truncating the journal at the corrupted entry recovers the journal.
+	assert.NotNil(t, TruncateJournal(tempDir, entries[2].FileID, entries[2].Seq+1))
+	assert.Nil(t, TruncateJournal(tempDir, entries[2].FileID, entries[2].Seq))
+	count = 0
+	assert.Nil(t, IterateJournal(tempDir, 0, 0, func(entry *JournalEntry) error {
+		count++
+		return nil
+	}))
+	assert.Equal(t, 2, count)
+
+	wal, err := NewWal(nil, tempDir)
+	require.NoError(t, err)
+	assert.Nil(t, wal.Load(func(msg interface{}) error { return nil }))
+	wal.Close()
+}
+
+func TestTruncateJournalReplay(t *testing.T) {
+	tempDir, _ := ioutil.TempDir("", "wal")
+	defer os.RemoveAll(tempDir)
+
+	// The replay position is saved after the second entry.
+	wal, err := NewWal(nil, tempDir)
+	require.NoError(t, err)
+	require.NoError(t, wal.WriteSync(buildConfirmedViewChange()))
+	require.NoError(t, wal.WriteSync(buildSendViewChange()))
+	require.NoError(t, testWalUpdateViewChange(wal))
+	for i := 0; i < 4; i++ {
+		require.NoError(t, wal.WriteSync(buildSendPrepareBlock()))
+	}
+	wal.Close()
+
+	var entries []*JournalEntry
+	require.NoError(t, IterateJournal(tempDir, 0, 0, func(entry *JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	require.Equal(t, 6, len(entries))
+	meta, err := ReadMeta(tempDir)
+	require.NoError(t, err)
+	require.Equal(t, entries[2].Seq, meta.ViewChange.Seq)
+
+	// Truncate the journal before the replay position, it is moved back.
+	require.NoError(t, TruncateJournal(tempDir, entries[1].FileID, entries[1].Seq))
+	meta, err = ReadMeta(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, entries[1].FileID, meta.ViewChange.FileID)
+	assert.Equal(t, entries[1].Seq, meta.ViewChange.Seq)
+	assert.Equal(t, epoch, meta.ViewChange.Epoch)
+
+	// The entries appended after the truncation are replayed.
+	wal, err = NewWal(nil, tempDir)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, wal.WriteSync(buildSendPrepareVote()))
+	}
+	wal.Close()
+
+	wal, err = NewWal(nil, tempDir)
+	require.NoError(t, err)
+	var replayed []interface{}
+	require.NoError(t, wal.Load(func(msg interface{}) error {
+		replayed = append(replayed, msg)
+		return nil
+	}))
+	wal.Close()
+	require.Equal(t, 3, len(replayed))
+	for _, msg := range replayed {
+		_, ok := msg.(*protocols.SendPrepareVote)
+		assert.True(t, ok)
+	}
+
+	// Truncating after the replay position keeps it.
+	entries = entries[:0]
+	require.NoError(t, IterateJournal(tempDir, 0, 0, func(entry *JournalEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	require.Equal(t, 4, len(entries))
+	require.NoError(t, TruncateJournal(tempDir, entries[2].FileID, entries[2].Seq))
+	meta, err = ReadMeta(tempDir)
+	require.NoError(t, err)
+	assert.Equal(t, entries[1].Seq, meta.ViewChange.Seq)
+}
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/consensus"
 	"github.com/AlayaNetwork/Alaya-Go/core/cbfttypes"
 	"github.com/AlayaNetwork/Alaya-Go/core/state"
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
@@ -192,6 +193,9 @@ type Bft interface {
 
 	Evidences() string
 
+	// Returns the evidences of the duplicate signatures detected by the node.
+	EvidenceList() consensus.Evidences
+
 	TracingSwitch(flag int8)
 
 	// NodeID is temporary.
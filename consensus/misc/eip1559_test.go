@@ -0,0 +1,80 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/params"
+)
+
+func TestCalcBaseFee(t *testing.T) {
+	tests := []struct {
+		parentBaseFee   *big.Int
+		parentGasLimit  uint64
+		parentGasUsed   uint64
+		expectedBaseFee int64
+	}{
+		{nil, 20000000, 10000000, params.InitialBaseFee},                               // the first block of the dynamic fee market
+		{big.NewInt(params.InitialBaseFee), 20000000, 10000000, params.InitialBaseFee}, // usage == target
+		{big.NewInt(params.InitialBaseFee), 20000000, 9000000, 987500000},              // usage below target
+		{big.NewInt(params.InitialBaseFee), 20000000, 11000000, 1012500000},            // usage above target
+		{big.NewInt(params.InitialBaseFee), 20000000, 0, 875000000},                    // empty block
+		{big.NewInt(params.InitialBaseFee), 20000000, 20000000, 1125000000},            // full block
+		{big.NewInt(1), 20000000, 10000001, 2},                                         // the base fee goes up by 1 at least
+	}
+	for i, test := range tests {
+		parent := &types.Header{
+			Number:   big.NewInt(32),
+			GasLimit: test.parentGasLimit,
+			GasUsed:  test.parentGasUsed,
+			BaseFee:  test.parentBaseFee,
+		}
+		if have, want := CalcBaseFee(parent), big.NewInt(test.expectedBaseFee); have.Cmp(want) != 0 {
+			t.Errorf("test %d: have %d  want %d, ", i, have, want)
+		}
+	}
+}
+
+func TestVerifyBaseFee(t *testing.T) {
+	parent := &types.Header{
+		Number:   big.NewInt(32),
+		GasLimit: 20000000,
+		GasUsed:  20000000,
+		BaseFee:  big.NewInt(params.InitialBaseFee),
+	}
+	header := &types.Header{Number: big.NewInt(33)}
+	if err := VerifyBaseFee(parent, header, false); err != nil {
+		t.Fatalf("the header without base fee is valid before the dynamic fee market, have %v", err)
+	}
+	if err := VerifyBaseFee(parent, header, true); err == nil {
+		t.Fatal("the header must carry the base fee")
+	}
+	header.BaseFee = big.NewInt(params.InitialBaseFee)
+	if err := VerifyBaseFee(parent, header, true); err == nil {
+		t.Fatal("the base fee must go up after a full block")
+	}
+	if err := VerifyBaseFee(parent, header, false); err == nil {
+		t.Fatal("the base fee is not allowed before the dynamic fee market")
+	}
+	header.BaseFee = CalcBaseFee(parent)
+	if err := VerifyBaseFee(parent, header, true); err != nil {
+		t.Fatal(err)
+	}
+}
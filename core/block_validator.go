@@ -25,6 +25,7 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/log"
 
 	"github.com/AlayaNetwork/Alaya-Go/consensus"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/misc"
 	"github.com/AlayaNetwork/Alaya-Go/core/state"
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
 	"github.com/AlayaNetwork/Alaya-Go/params"
@@ -81,6 +82,13 @@ func (v *BlockValidator) ValidateState(block, parent *types.Block, statedb *stat
 	if block.GasUsed() != usedGas {
 		return fmt.Errorf("invalid gas used (remote: %d local: %d)", block.GasUsed(), usedGas)
 	}
+	// Verify the base fee of the dynamic fee market against the parent, the
+	// market is activated by the governance version of the state.
+	if parent != nil {
+		if err := misc.VerifyBaseFee(parent.Header(), header, gov.Gte0180VersionState(statedb)); err != nil {
+			return err
+		}
+	}
 	// Validate the received block's bloom with the one derived from the generated receipts.
 	// For valid blocks this should always validate to true.
 	rbloom := types.CreateBloom(receipts)
@@ -791,7 +791,7 @@ func (bc *BlockChain) Rollback(chain []common.Hash) {
 
 // SetReceiptsData computes all the non-consensus fields of the receipts
 func SetReceiptsData(config *params.ChainConfig, block *types.Block, receipts types.Receipts) error {
-	signer := types.NewEIP155Signer(config.ChainID)
+	signer := types.LatestSignerForChainID(config.ChainID)
 	transactions, logIndex := block.Transactions(), uint(0)
 	if len(transactions) != len(receipts) {
 		return errors.New("transaction and receipt count mismatch")
@@ -284,7 +284,7 @@ func (bcc *BlockChainCache) Execute(block *types.Block, parent *types.Block) err
 	if executed() {
 		return nil
 	}
-	SenderCacher.RecoverFromBlock(types.NewEIP155Signer(bcc.chainConfig.ChainID), block)
+	SenderCacher.RecoverFromBlock(types.LatestSignerForChainID(bcc.chainConfig.ChainID), block)
 
 	log.Debug("Start execute block", "hash", block.Hash(), "number", block.Number(), "sealHash", block.Header().SealHash())
 	start := time.Now()
@@ -147,14 +147,37 @@ func (bcr *BlockChainReactor) commit(block *types.Block) error {
 
 	}
 
+	events := snapshotdb.Instance().Events(block.Hash())
+
 	log.Info("Call snapshotdb commit on blockchain_reactor", "blockNumber", block.Number(), "blockHash", block.Hash())
 	if err := snapshotdb.Instance().Commit(block.Hash()); nil != err {
 		log.Error("Failed to call snapshotdb commit on blockchain_reactor", "blockNumber", block.Number(), "blockHash", block.Hash(), "err", err)
 		return err
 	}
+	bcr.postPPOSEvents(block, events)
 	return nil
 }
 
+// postPPOSEvents posts the PPOS events of the committed block to the event mux.
+func (bcr *BlockChainReactor) postPPOSEvents(block *types.Block, events []interface{}) {
+	if len(events) == 0 || bcr.eventMux == nil {
+		return
+	}
+	evs := make([]*xcom.PPOSEvent, 0, len(events))
+	for _, e := range events {
+		if ev, ok := e.(*xcom.PPOSEvent); ok {
+			ev.BlockHash = block.Hash()
+			evs = append(evs, ev)
+		}
+	}
+	if len(evs) == 0 {
+		return
+	}
+	if err := bcr.eventMux.Post(PPOSEventsEvent{Events: evs}); err != nil {
+		log.Debug("Failed to post PPOS events", "blockNumber", block.Number(), "blockHash", block.Hash(), "err", err)
+	}
+}
+
 func (bcr *BlockChainReactor) OnCommit(block *types.Block) error {
 	if bcr.validatorMode == common.PPOS_VALIDATOR_MODE {
 		return bcr.commit(block)
@@ -282,6 +305,51 @@ func (bcr *BlockChainReactor) BeginBlocker(header *types.Header, state xcom.Stat
 	return nil
 }
 
+// Called before re-executing the txs of a block on the snapshotDB trace block,
+// the blockHash is the hash of the trace block
+func (bcr *BlockChainReactor) TraceBeginBlocker(blockHash common.Hash, header *types.Header, state xcom.StateDB) error {
+	if bcr.validatorMode != common.PPOS_VALIDATOR_MODE {
+		return nil
+	}
+	for _, pluginRule := range bcr.beginRule {
+		if plugin, ok := bcr.basePluginMap[pluginRule]; ok {
+			if err := plugin.BeginBlock(blockHash, header, state); nil != err {
+				return err
+			}
+		}
+	}
+	// This must not be deleted
+	state.IntermediateRoot(true)
+	return nil
+}
+
+// Called after re-executing the txs of a block on the snapshotDB trace block,
+// the blockHash is the hash of the trace block
+func (bcr *BlockChainReactor) TraceEndBlocker(blockHash common.Hash, header *types.Header, state xcom.StateDB) error {
+	if bcr.validatorMode != common.PPOS_VALIDATOR_MODE {
+		return nil
+	}
+	// The trace block holds the nonces of its parent until the nonce of the
+	// block is stored
+	if err := bcr.vh.Storage(header.Number, blockHash, blockHash, header.Nonce.Bytes()); nil != err {
+		return err
+	}
+	for _, pluginRule := range bcr.endRule {
+		if plugin, ok := bcr.basePluginMap[pluginRule]; ok {
+			if err := plugin.EndBlock(blockHash, header, state); nil != err {
+				return err
+			}
+		}
+	}
+	pposHash := snapshotdb.Instance().GetLastKVHash(blockHash)
+	if len(pposHash) != 0 && !bytes.Equal(pposHash, make([]byte, len(pposHash))) {
+		state.SetState(cvm.StakingContractAddr, staking.GetPPOSHASHKey(), pposHash)
+	}
+	// This must not be deleted
+	state.IntermediateRoot(true)
+	return nil
+}
+
 // Called after every block had executed all txs
 func (bcr *BlockChainReactor) EndBlocker(header *types.Header, state xcom.StateDB) error {
 
@@ -393,6 +461,10 @@ func (bcr *BlockChainReactor) GetValidator(blockNumber uint64) (*cbfttypes.Valid
 	return plugin.StakingInstance().GetValidator(blockNumber)
 }
 
+func (bcr *BlockChainReactor) GetHistoryValidator(blockHash common.Hash, blockNumber uint64) (*cbfttypes.Validators, error) {
+	return plugin.StakingInstance().GetHistoryValidator(blockHash, blockNumber)
+}
+
 func (bcr *BlockChainReactor) IsCandidateNode(nodeID discover.NodeID) bool {
 	return plugin.StakingInstance().IsCandidateNode(nodeID)
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/common"
 	"github.com/AlayaNetwork/Alaya-Go/core/cbfttypes"
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
 )
 
 // NewTxsEvent is posted when a batch of transactions enter the transaction pool.
@@ -56,3 +57,6 @@ type ChainSideEvent struct {
 }
 
 type ChainHeadEvent struct{ Block *types.Block }
+
+// PPOSEventsEvent is posted when a block with PPOS events is committed.
+type PPOSEventsEvent struct{ Events []*xcom.PPOSEvent }
@@ -42,6 +42,11 @@ func NewEVMContext(msg Message, header *types.Header, chain ChainContext) vm.Con
 
 	beneficiary := header.Coinbase // we're must using header validation
 
+	var baseFee *big.Int
+	if header.BaseFee != nil {
+		baseFee = new(big.Int).Set(header.BaseFee)
+	}
+
 	blockHash := common.ZeroHash
 	// store the sign in  header.Extra[32:97]
 	if !xutil.IsWorker(header.Extra) {
@@ -60,6 +65,7 @@ func NewEVMContext(msg Message, header *types.Header, chain ChainContext) vm.Con
 		GasPrice:    new(big.Int).Set(msg.GasPrice()),
 		BlockHash:   blockHash,
 		Difficulty:  new(big.Int).SetUint64(0), // This one must not be deleted, otherwise the solidity contract will be failed
+		BaseFee:     baseFee,
 	}
 }
 
@@ -38,8 +38,10 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/core/rawdb"
 	"github.com/AlayaNetwork/Alaya-Go/core/state"
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/crypto/bls"
 	"github.com/AlayaNetwork/Alaya-Go/ethdb"
 	"github.com/AlayaNetwork/Alaya-Go/log"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
 	"github.com/AlayaNetwork/Alaya-Go/params"
 	"github.com/AlayaNetwork/Alaya-Go/rlp"
 	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
@@ -587,6 +589,64 @@ func DefaultGrapeGenesisBlock() *Genesis {
 	}
 }
 
+// DeveloperGenesisBlock returns the genesis block of a single node developer
+// chain, validated by the given node and prefunding the faucet account.
+//
+// Blocks are sealed every period seconds, or every second while transactions
+// are pending if period is zero. Epochs last four consensus rounds and the
+// proposals are voted within an epoch. The chain starts at the version of the
+// running code, so every governance upgrade is already active.
+//
+// The economic model of the developer chain is built anew and replaces the
+// global one, like the model of a genesis file does.
+func DeveloperGenesisBlock(period uint64, node discover.NodeID, blsPubKey bls.PublicKey, faucet common.Address) *Genesis {
+	emptyBlock := "on"
+	if period == 0 {
+		period, emptyBlock = 1, "off"
+	}
+
+	ec, ece := xcom.NewEconomicModel(xcom.DefaultDeveloperNet)
+	ec.Common.NodeBlockTimeWindow = period * ec.Common.PerRoundBlocks
+	roundSeconds := ec.Common.MaxConsensusVals * ec.Common.NodeBlockTimeWindow
+	ec.Common.MaxEpochMinutes = (4*roundSeconds + 59) / 60
+	ec.Common.AdditionalCycleTime = 4 * ec.Common.MaxEpochMinutes
+	ec.Gov.VersionProposalVoteDurationSeconds = 4 * roundSeconds
+	ec.Gov.TextProposalVoteDurationSeconds = 4 * roundSeconds
+	ec.Gov.ParamProposalVoteDurationSeconds = 4 * roundSeconds
+
+	faucetBalance := new(big.Int).Mul(big.NewInt(1e9), big.NewInt(params.ATP))
+	rewardMgrPoolIssue := new(big.Int).Mul(big.NewInt(2e8), big.NewInt(params.ATP))
+
+	genesis := Genesis{
+		Config: &params.ChainConfig{
+			ChainID:     big.NewInt(1337),
+			AddressHRP:  common.DefaultAddressHRP,
+			EmptyBlock:  emptyBlock,
+			EIP155Block: big.NewInt(0),
+			Cbft: &params.CbftConfig{
+				Period: ec.Common.NodeBlockTimeWindow * 1000,
+				Amount: uint32(ec.Common.PerRoundBlocks),
+				InitialNodes: []params.CbftNode{
+					{Node: *discover.NewNode(node, nil, 0, 0), BlsPubKey: blsPubKey},
+				},
+				ValidatorMode: common.PPOS_VALIDATOR_MODE,
+			},
+			GenesisVersion: params.CodeVersion(),
+		},
+		Nonce:     hexutil.MustDecode("0x024c6378c176ef6c717cd37a74c612c9abd615d13873ff6651e3d352b31cb0b2e1"),
+		GasLimit:  params.GenesisGasLimit,
+		ExtraData: hexutil.MustDecode("0xd782070186706c61746f6e86676f312e3131856c696e757800000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"),
+		Alloc: map[common.Address]GenesisAccount{
+			vm.RewardManagerPoolAddr: {Balance: rewardMgrPoolIssue},
+			faucet:                   {Balance: faucetBalance},
+		},
+		EconomicModel: ec,
+	}
+	xcom.ResetEconomicDefaultConfig(ec)
+	xcom.ResetEconomicExtendConfig(ece)
+	return &genesis
+}
+
 func decodePrealloc(data string) GenesisAlloc {
 	var p []struct{ Addr, Balance *big.Int }
 	if err := rlp.NewStream(strings.NewReader(data), 0).Decode(&p); err != nil {
@@ -0,0 +1,197 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/state"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/core/vm"
+	"github.com/AlayaNetwork/Alaya-Go/log"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+	"github.com/AlayaNetwork/Alaya-Go/x/xutil"
+)
+
+// serialExecution is the result of executing the transactions of a block
+// serially, to be compared with the parallel execution.
+type serialExecution struct {
+	state    *state.StateDB
+	receipts types.Receipts
+	logs     int
+	usedGas  uint64
+}
+
+// DivergenceReport describes how the parallel execution of a block diverges
+// from the serial execution.
+type DivergenceReport struct {
+	BlockNumber     uint64              `json:"blockNumber"`
+	BlockHash       common.Hash         `json:"blockHash"`
+	ParallelRoot    common.Hash         `json:"parallelRoot"`
+	SerialRoot      common.Hash         `json:"serialRoot"`
+	ParallelGasUsed uint64              `json:"parallelGasUsed"`
+	SerialGasUsed   uint64              `json:"serialGasUsed"`
+	ParallelLogs    int                 `json:"parallelLogs"`
+	SerialLogs      int                 `json:"serialLogs"`
+	TxIndex         int                 `json:"txIndex"` // The first transaction whose receipts differ, -1 if none
+	TxHash          *common.Hash        `json:"txHash,omitempty"`
+	ParallelReceipt *types.Receipt      `json:"parallelReceipt,omitempty"`
+	SerialReceipt   *types.Receipt      `json:"serialReceipt,omitempty"`
+	Accounts        []state.AccountDiff `json:"accounts"` // Value is the parallel one, Other is the serial one
+}
+
+// SetCrossCheck makes the processor execute the transactions of every interval
+// blocks serially as well, and compare the state roots, receipts, logs and gas
+// used of the two executions. The block is rejected if they diverge, and the
+// report is written to dir. The check is disabled if interval is zero.
+func (p *ParallelStateProcessor) SetCrossCheck(interval uint64, dir string) {
+	p.checkInterval = interval
+	p.checkDir = dir
+}
+
+func (p *ParallelStateProcessor) shouldCrossCheck(block *types.Block) bool {
+	return p.checkInterval > 0 && block.NumberU64()%p.checkInterval == 0
+}
+
+// executeSerially executes the transactions on a copy of the state, the PPOS
+// contracts are executed on a snapshotdb trace block which is discarded after.
+func (p *ParallelStateProcessor) executeSerially(block *types.Block, statedb *state.StateDB, cfg vm.Config) (*serialExecution, error) {
+	header := block.Header()
+	blockHash := common.ZeroHash
+	if !xutil.IsWorker(header.Extra) {
+		blockHash = header.CacheHash()
+	}
+	traceHash, err := snapshotdb.Instance().NewTraceBlock(header.Number, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	defer snapshotdb.Instance().DiscardTraceBlock(traceHash)
+
+	serial := &serialExecution{state: statedb.Copy()}
+	gp := new(GasPool).AddGas(block.GasLimit())
+	signer := types.LatestSignerForChainID(p.config.ChainID)
+	for _, tx := range block.Transactions() {
+		msg, err := tx.AsMessage(signer, header.BaseFee)
+		if err != nil {
+			return nil, err
+		}
+		context := NewEVMContext(msg, header, p.bc)
+		context.BlockHash = traceHash
+		vmenv := vm.NewEVM(context, snapshotdb.Instance(), serial.state, p.config, cfg)
+
+		// the transactions failed are skipped as the parallel executor does
+		snap := serial.state.Snapshot()
+		serial.state.Prepare(tx.Hash(), block.Hash(), len(serial.receipts))
+		receipt, _, err := applyTransaction(msg, gp, serial.state, header, tx, &serial.usedGas, vmenv)
+		if err != nil {
+			serial.state.RevertToSnapshot(snap)
+			continue
+		}
+		serial.receipts = append(serial.receipts, receipt)
+	}
+	serial.logs = len(serial.state.Logs())
+	return serial, nil
+}
+
+// crossCheck compares the parallel execution with the serial one.
+func (p *ParallelStateProcessor) crossCheck(block *types.Block, statedb *state.StateDB, receipts types.Receipts, logs []*types.Log, usedGas uint64, serial *serialExecution) error {
+	report := &DivergenceReport{
+		BlockNumber:     block.NumberU64(),
+		BlockHash:       block.Hash(),
+		ParallelRoot:    statedb.IntermediateRoot(true),
+		SerialRoot:      serial.state.IntermediateRoot(true),
+		ParallelGasUsed: usedGas,
+		SerialGasUsed:   serial.usedGas,
+		ParallelLogs:    len(logs),
+		SerialLogs:      serial.logs,
+		TxIndex:         -1,
+	}
+	for i := 0; i < len(receipts) || i < len(serial.receipts); i++ {
+		var receipt, serialReceipt *types.Receipt
+		if i < len(receipts) {
+			receipt = receipts[i]
+		}
+		if i < len(serial.receipts) {
+			serialReceipt = serial.receipts[i]
+		}
+		if !sameReceipt(receipt, serialReceipt) {
+			report.TxIndex = i
+			report.ParallelReceipt, report.SerialReceipt = receipt, serialReceipt
+			if i < len(block.Transactions()) {
+				hash := block.Transactions()[i].Hash()
+				report.TxHash = &hash
+			}
+			break
+		}
+	}
+	if report.ParallelRoot == report.SerialRoot && report.ParallelGasUsed == report.SerialGasUsed &&
+		report.ParallelLogs == report.SerialLogs && report.TxIndex < 0 {
+		log.Debug("Parallel execution cross-checked", "blockNumber", block.NumberU64(), "blockHash", block.Hash())
+		return nil
+	}
+	if report.ParallelRoot != report.SerialRoot {
+		report.Accounts = statedb.Diff(serial.state)
+	}
+
+	ctx := []interface{}{"blockNumber", report.BlockNumber, "blockHash", report.BlockHash,
+		"parallelRoot", report.ParallelRoot, "serialRoot", report.SerialRoot,
+		"parallelGasUsed", report.ParallelGasUsed, "serialGasUsed", report.SerialGasUsed,
+		"txIndex", report.TxIndex, "accounts", len(report.Accounts)}
+	if p.checkDir != "" {
+		path, err := writeDivergenceReport(p.checkDir, report)
+		if err != nil {
+			log.Error("Failed to write the divergence report", "err", err)
+		} else {
+			ctx = append(ctx, "report", path)
+		}
+	}
+	log.Error("Parallel execution diverges from serial execution", ctx...)
+	return ErrParallelDivergence
+}
+
+// sameReceipt compares the consensus fields of the receipts, the parallel
+// executor doesn't fill the block fields of the transfer receipts.
+func sameReceipt(a, b *types.Receipt) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.TxHash != b.TxHash || a.GasUsed != b.GasUsed || a.ContractAddress != b.ContractAddress {
+		return false
+	}
+	encA, _ := rlp.EncodeToBytes(a)
+	encB, _ := rlp.EncodeToBytes(b)
+	return bytes.Equal(encA, encB)
+}
+
+func writeDivergenceReport(dir string, report *DivergenceReport) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("divergence-%d-%x.json", report.BlockNumber, report.BlockHash[:8]))
+	return path, ioutil.WriteFile(path, data, 0644)
+}
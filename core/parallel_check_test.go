@@ -0,0 +1,107 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
+	"github.com/AlayaNetwork/Alaya-Go/consensus"
+	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+)
+
+func TestParallel_CrossCheck(t *testing.T) {
+	fromAccountList, toAccountList, contractAccountList := initAccount()
+	blockchain, stateDb, header := initChain(fromAccountList, toAccountList, contractAccountList)
+
+	code := hexutil.MustDecode("0x600054600101600055336000526020600060a000")
+	for _, contract := range contractAccountList {
+		stateDb.SetCode(contract.address, code)
+	}
+	stateDb.Finalise(true)
+
+	txs := make(types.Transactions, 0)
+	for i := 0; i < 20; i++ {
+		from := fromAccountList[i%3]
+		to := contractAccountList[i%4].address
+		if i%5 == 0 {
+			to = toAccountList[i].address
+		}
+		tx, _ := types.SignTx(types.NewTransaction(from.nonce, to, big.NewInt(int64(i)), 100000, gasPrice, nil), signer, from.priKey)
+		txs = append(txs, tx)
+		from.nonce++
+	}
+	block := types.NewBlock(header, txs, nil)
+
+	if err := snapshotdb.Instance().NewBlock(header.Number, header.ParentHash, header.CacheHash()); err != nil {
+		t.Fatal(err)
+	}
+	defer snapshotdb.Instance().Clear()
+
+	dir, err := ioutil.TempDir("", "parallelcheck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	NewExecutor(chainConfig, blockchain, blockchain.vmConfig, nil)
+	processor := NewParallelStateProcessor(chainConfig, blockchain, consensus.NewFaker())
+	processor.SetCrossCheck(1, dir)
+	receipts, logs, usedGas, err := processor.Process(block, stateDb, blockchain.vmConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(receipts) != len(txs) {
+		t.Fatalf("want %d receipts, have %d", len(txs), len(receipts))
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Fatalf("want no divergence report, have %d", len(files))
+	}
+
+	// diverge the serial execution by an account
+	serialState := stateDb.Copy()
+	serialState.AddBalance(toAccountList[0].address, big.NewInt(1))
+	serialState.Finalise(true)
+	serial := &serialExecution{state: serialState, receipts: receipts, logs: len(logs), usedGas: usedGas}
+	if err := processor.crossCheck(block, stateDb, receipts, logs, usedGas, serial); err != ErrParallelDivergence {
+		t.Fatalf("want %v, have %v", ErrParallelDivergence, err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "divergence-1-*.json"))
+	if len(files) != 1 {
+		t.Fatalf("want 1 divergence report, have %d", len(files))
+	}
+	data, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report DivergenceReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.TxIndex != -1 || len(report.Accounts) != 1 {
+		t.Fatalf("report not right: %s", data)
+	}
+	if diff := report.Accounts[0]; diff.Address != toAccountList[0].address || diff.Field != "balance" {
+		t.Fatalf("account diff not right: %+v", diff)
+	}
+}
@@ -20,8 +20,14 @@ type Result struct {
 	toStateObject     *state.ParallelStateObject
 	receipt           *types.Receipt
 	minerEarnings     *big.Int
+	poolFees          *big.Int
 	err               error
 	needRefundGasPool bool
+
+	// the speculative execution of a contract transaction
+	msg           types.Message
+	parallelState *state.ParallelStateDB
+	execResult    *ExecutionResult
 }
 
 type ParallelContext struct {
@@ -37,6 +43,7 @@ type ParallelContext struct {
 
 	blockGasUsedHolder *uint64
 	earnings           *big.Int
+	poolFees           *big.Int
 	blockDeadline      time.Time
 	packNewBlock       bool
 	wg                 sync.WaitGroup
@@ -51,6 +58,7 @@ func NewParallelContext(state *state.StateDB, header *types.Header, blockHash co
 		gp:              gp,
 		poppedAddresses: make(map[common.Address]struct{}),
 		earnings:        big.NewInt(0),
+		poolFees:        big.NewInt(0),
 		packNewBlock:    packNewBlock,
 		signer:          signer,
 	}
@@ -147,6 +155,15 @@ func (ctx *ParallelContext) AddEarnings(earning *big.Int) {
 	ctx.earnings = new(big.Int).Add(ctx.earnings, earning)
 }
 
+// GetPoolFees returns the base fees of the transfers which go to the reward pool.
+func (ctx *ParallelContext) GetPoolFees() *big.Int {
+	return ctx.poolFees
+}
+
+func (ctx *ParallelContext) AddPoolFees(fees *big.Int) {
+	ctx.poolFees = new(big.Int).Add(ctx.poolFees, fees)
+}
+
 func (ctx *ParallelContext) SetBlockDeadline(blockDeadline time.Time) {
 	ctx.blockDeadline = blockDeadline
 }
@@ -176,10 +193,11 @@ func (ctx *ParallelContext) buildTransferFailedResult(idx int, err error, needRe
 		"txValue", tx.Value().Uint64(), "needRefundGasPool", needRefundGasPool, "error", err.Error())
 }
 
-func (ctx *ParallelContext) buildTransferSuccessResult(idx int, fromStateObject, toStateObject *state.ParallelStateObject, txGasUsed uint64, minerEarnings *big.Int) {
+func (ctx *ParallelContext) buildTransferSuccessResult(idx int, fromStateObject, toStateObject *state.ParallelStateObject, txGasUsed uint64, minerEarnings, poolFees *big.Int) {
 	tx := ctx.GetTx(idx)
 	var root []byte
 	receipt := types.NewReceipt(root, false, txGasUsed)
+	receipt.Type = tx.Type()
 	receipt.TxHash = tx.Hash()
 	receipt.GasUsed = txGasUsed
 	// Set the receipt logs and create a bloom for filtering
@@ -193,6 +211,7 @@ func (ctx *ParallelContext) buildTransferSuccessResult(idx int, fromStateObject,
 		toStateObject:   toStateObject,
 		receipt:         receipt,
 		minerEarnings:   minerEarnings,
+		poolFees:        poolFees,
 		err:             nil,
 	}
 	ctx.SetResult(idx, result)
@@ -227,6 +246,7 @@ func (ctx *ParallelContext) batchMerge(batchNo int, originIdxList []int, deleteE
 
 				// Cumulate the miner's earnings
 				ctx.AddEarnings(resultList[idx].minerEarnings)
+				ctx.AddPoolFees(resultList[idx].poolFees)
 
 			} else {
 				if resultList[idx].needRefundGasPool {
@@ -10,16 +10,21 @@ import (
 	"github.com/panjf2000/ants/v2"
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
+	cvm "github.com/AlayaNetwork/Alaya-Go/common/vm"
 	"github.com/AlayaNetwork/Alaya-Go/core/state"
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
 	"github.com/AlayaNetwork/Alaya-Go/core/vm"
 	"github.com/AlayaNetwork/Alaya-Go/log"
 	"github.com/AlayaNetwork/Alaya-Go/params"
+	"github.com/AlayaNetwork/Alaya-Go/x/gov"
 )
 
 const (
 	// Number of contractAddress->bool associations to keep.
 	contractCacheSize = 100000
+
+	// Number of contract transactions speculatively executed on the same state.
+	speculativeBatchSize = 16
 )
 
 var (
@@ -42,6 +47,16 @@ type TaskArgs struct {
 	ctx          *ParallelContext
 	idx          int
 	intrinsicGas uint64
+	speculative  bool
+}
+
+// baseFee returns the base fee of the block executed in the context, nil if the
+// dynamic fee market is not active.
+func baseFee(ctx *ParallelContext) *big.Int {
+	if !gov.Gte0180VersionState(ctx.GetState()) {
+		return nil
+	}
+	return ctx.GetHeader().BaseFee
 }
 
 func NewExecutor(chainConfig *params.ChainConfig, chainContext ChainContext, vmCfg vm.Config, txpool *TxPool) {
@@ -53,12 +68,16 @@ func NewExecutor(chainConfig *params.ChainConfig, chainContext ChainContext, vmC
 			ctx := args.ctx
 			idx := args.idx
 			intrinsicGas := args.intrinsicGas
-			executor.executeParallelTx(ctx, idx, intrinsicGas)
+			if args.speculative {
+				executor.executeSpeculativeTx(ctx, idx)
+			} else {
+				executor.executeParallelTx(ctx, idx, intrinsicGas)
+			}
 			ctx.wg.Done()
 		})
 		executor.chainConfig = chainConfig
 		executor.chainContext = chainContext
-		executor.signer = types.NewEIP155Signer(chainConfig.ChainID)
+		executor.signer = types.LatestSignerForChainID(chainConfig.ChainID)
 		executor.vmCfg = vmCfg
 		csc, _ := lru.New(contractCacheSize)
 		executor.contractCache = csc
@@ -86,6 +105,8 @@ func (exe *Executor) ExecuteTransactions(ctx *ParallelContext) error {
 
 		start = time.Now()
 		batchNo := 0
+		accessListEnabled := gov.Gte0170VersionState(ctx.GetState())
+		dynamicFeeEnabled := gov.Gte0180VersionState(ctx.GetState())
 		for !ctx.IsTimeout() && txDag.HasNext() {
 			parallelTxIdxs := txDag.Next()
 
@@ -94,7 +115,15 @@ func (exe *Executor) ExecuteTransactions(ctx *ParallelContext) error {
 			}
 
 			if len(parallelTxIdxs) == 1 && txDag.IsContract(parallelTxIdxs[0]) {
-				exe.executeContractTransaction(ctx, parallelTxIdxs[0])
+				if run := txDag.SpeculativeRun(parallelTxIdxs[0]); len(run) > 1 && !exe.vmCfg.Debug {
+					exe.executeSpeculativeRun(ctx, run)
+					// the transactions of the run depend on the previous one in turn
+					for i := 1; i < len(run); i++ {
+						txDag.Next()
+					}
+				} else {
+					exe.executeContractTransaction(ctx, parallelTxIdxs[0])
+				}
 			} else {
 				for _, originIdx := range parallelTxIdxs {
 					tx := ctx.GetTx(originIdx)
@@ -111,7 +140,11 @@ func (exe *Executor) ExecuteTransactions(ctx *ParallelContext) error {
 						}
 					}
 
-					intrinsicGas, err := IntrinsicGas(tx.Data(), false, nil)
+					if !txTypeSupported(tx, accessListEnabled, dynamicFeeEnabled) {
+						ctx.buildTransferFailedResult(originIdx, ErrTxTypeNotSupported, false)
+						continue
+					}
+					intrinsicGas, err := IntrinsicGas(tx.Data(), tx.AccessList(), false, nil)
 					if err != nil {
 						ctx.buildTransferFailedResult(originIdx, err, false)
 						continue
@@ -123,7 +156,7 @@ func (exe *Executor) ExecuteTransactions(ctx *ParallelContext) error {
 					}
 
 					ctx.wg.Add(1)
-					args := TaskArgs{ctx, originIdx, intrinsicGas}
+					args := TaskArgs{ctx: ctx, idx: originIdx, intrinsicGas: intrinsicGas}
 					_ = exe.workerPool.Invoke(args)
 				}
 				// waiting for current batch done
@@ -138,6 +171,10 @@ func (exe *Executor) ExecuteTransactions(ctx *ParallelContext) error {
 		if ctx.GetEarnings().Cmp(big.NewInt(0)) > 0 {
 			ctx.state.AddMinerEarnings(ctx.header.Coinbase, ctx.GetEarnings())
 		}
+		//add the base fees of the transfers to the reward pool
+		if ctx.GetPoolFees().Sign() > 0 {
+			ctx.state.AddBalance(cvm.RewardManagerPoolAddr, ctx.GetPoolFees())
+		}
 		start = time.Now()
 		ctx.state.Finalise(true)
 		log.Trace("Finalise stateDB cost", "number", ctx.header.Number, "time", time.Since(start))
@@ -160,12 +197,19 @@ func (exe *Executor) executeParallelTx(ctx *ParallelContext, idx int, intrinsicG
 	}
 	tx := ctx.GetTx(idx)
 
-	msg, err := tx.AsMessage(exe.signer)
+	baseFee := baseFee(ctx)
+	msg, err := tx.AsMessage(exe.signer, baseFee)
 	if err != nil {
 		//gas pool is subbed
 		ctx.buildTransferFailedResult(idx, err, true)
 		return
 	}
+	if baseFee != nil {
+		if err := checkFeeCaps(msg, baseFee); err != nil {
+			ctx.buildTransferFailedResult(idx, err, true)
+			return
+		}
+	}
 
 	if msg.Gas() < intrinsicGas {
 		ctx.buildTransferFailedResult(idx, vm.ErrOutOfGas, true)
@@ -178,7 +222,7 @@ func (exe *Executor) executeParallelTx(ctx *ParallelContext, idx int, intrinsicG
 		log.Debug("Get state object overtime", "address", msg.From().String(), "duration", time.Since(start))
 	}
 
-	mgval := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasPrice())
+	mgval := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasFeeCap())
 	if fromObj.GetBalance().Cmp(mgval) < 0 {
 		ctx.buildTransferFailedResult(idx, errInsufficientBalanceForGas, true)
 		return
@@ -192,8 +236,9 @@ func (exe *Executor) executeParallelTx(ctx *ParallelContext, idx int, intrinsicG
 		return
 	}
 
-	minerEarnings := new(big.Int).Mul(new(big.Int).SetUint64(intrinsicGas), msg.GasPrice())
-	subTotal := new(big.Int).Add(msg.Value(), minerEarnings)
+	gasUsed := new(big.Int).SetUint64(intrinsicGas)
+	fees := new(big.Int).Mul(gasUsed, msg.GasPrice())
+	subTotal := new(big.Int).Add(msg.Value(), fees)
 	if fromObj.GetBalance().Cmp(subTotal) < 0 {
 		ctx.buildTransferFailedResult(idx, errInsufficientBalanceForGas, true)
 		return
@@ -210,7 +255,10 @@ func (exe *Executor) executeParallelTx(ctx *ParallelContext, idx int, intrinsicG
 	}
 	toObj.AddBalance(msg.Value())
 
-	ctx.buildTransferSuccessResult(idx, fromObj, toObj, intrinsicGas, minerEarnings)
+	pooledPrice, tip := SplitGasPrice(msg.GasPrice(), baseFee, baseFee != nil)
+	minerEarnings := new(big.Int).Mul(gasUsed, tip)
+	poolFees := new(big.Int).Mul(gasUsed, pooledPrice)
+	ctx.buildTransferSuccessResult(idx, fromObj, toObj, intrinsicGas, minerEarnings, poolFees)
 	return
 }
 
@@ -235,6 +283,115 @@ func (exe *Executor) executeContractTransaction(ctx *ParallelContext, idx int) {
 	log.Debug("Execute contract transaction success", "blockNumber", ctx.GetHeader().Number.Uint64(), "txHash", tx.Hash().Hex(), "gasPool", ctx.gp.Gas(), "txGasLimit", tx.Gas(), "gasUsed", receipt.GasUsed)
 }
 
+// executeSpeculativeRun executes the consecutive contract transactions in parallel
+// on the current state, then commits them in order. A transaction observing the
+// accounts modified by the ones committed before it is executed again on the
+// latest state, so the results are the same as executing them one by one.
+func (exe *Executor) executeSpeculativeRun(ctx *ParallelContext, run []int) {
+	for begin := 0; begin < len(run); begin += speculativeBatchSize {
+		end := begin + speculativeBatchSize
+		if end > len(run) {
+			end = len(run)
+		}
+		batch := run[begin:end]
+		for _, idx := range batch {
+			ctx.wg.Add(1)
+			_ = exe.workerPool.Invoke(TaskArgs{ctx: ctx, idx: idx, speculative: true})
+		}
+		ctx.wg.Wait()
+
+		// the accounts modified by the transactions committed in the batch,
+		// stale is set if they are unknown
+		written := make(map[common.Address]struct{})
+		stale := false
+		for _, idx := range batch {
+			if ctx.IsTimeout() {
+				return
+			}
+			if stale {
+				exe.executeContractTransaction(ctx, idx)
+				continue
+			}
+			result := ctx.GetResults()[idx]
+			if result.err == nil && conflicted(result.parallelState, written) {
+				log.Trace("Speculative execution conflicted", "blockNumber", ctx.GetHeader().Number.Uint64(), "txHash", ctx.GetTx(idx).Hash())
+				exe.executeSpeculativeTx(ctx, idx)
+				result = ctx.GetResults()[idx]
+			}
+			if result.err != nil || ctx.GetGasPool().Gas() < ctx.GetTx(idx).Gas() {
+				exe.executeContractTransaction(ctx, idx)
+				stale = true
+				continue
+			}
+			exe.commitSpeculativeTx(ctx, idx, result)
+			for _, addr := range result.parallelState.WriteSet() {
+				written[addr] = struct{}{}
+			}
+		}
+	}
+}
+
+// executeSpeculativeTx executes the contract transaction on a ParallelStateDB,
+// the execution is aborted if it calls the PPOS contracts.
+func (exe *Executor) executeSpeculativeTx(ctx *ParallelContext, idx int) {
+	tx := ctx.GetTx(idx)
+	msg, err := tx.AsMessage(exe.signer, ctx.GetHeader().BaseFee)
+	if err != nil {
+		ctx.SetResult(idx, &Result{err: err})
+		return
+	}
+	parallelState := ctx.GetState().NewParallelStateDB()
+	parallelState.Prepare(tx.Hash(), ctx.GetBlockHash(), 0)
+
+	cfg := exe.vmCfg
+	cfg.Speculative = true
+	vmenv := vm.NewEVM(NewEVMContext(msg, ctx.GetHeader(), exe.chainContext), nil, parallelState, exe.chainConfig, cfg)
+	if !txTypeSupported(tx, vmenv.AccessListEnabled(), vmenv.DynamicFeeEnabled()) {
+		ctx.SetResult(idx, &Result{err: ErrTxTypeNotSupported})
+		return
+	}
+	result, err := ApplyMessage(vmenv, msg, new(GasPool).AddGas(tx.Gas()))
+	if err == nil && vmenv.Cancelled() {
+		err = vm.ErrAbort
+	}
+	ctx.SetResult(idx, &Result{
+		err:           err,
+		msg:           msg,
+		parallelState: parallelState,
+		execResult:    result,
+	})
+}
+
+func (exe *Executor) commitSpeculativeTx(ctx *ParallelContext, idx int, result *Result) {
+	tx := ctx.GetTx(idx)
+	state := ctx.GetState()
+	state.Prepare(tx.Hash(), ctx.GetBlockHash(), int(state.TxIdx()))
+	state.MergeParallelStateDB(result.parallelState)
+	state.Finalise(true)
+
+	_ = ctx.GetGasPool().SubGas(result.execResult.UsedGas)
+	ctx.CumulateBlockGasUsed(result.execResult.UsedGas)
+	receipt, err := newReceipt(state, ctx.GetHeader(), tx, result.msg, result.execResult, ctx.GetBlockGasUsed())
+	if err != nil {
+		log.Error("Failed to create the receipt of speculative transaction", "blockNumber", ctx.GetHeader().Number.Uint64(), "txHash", tx.Hash(), "err", err)
+		return
+	}
+	ctx.AddPackedTx(tx)
+	state.IncreaseTxIdx()
+	ctx.AddReceipt(receipt)
+	log.Debug("Execute speculative transaction success", "blockNumber", ctx.GetHeader().Number.Uint64(), "txHash", tx.Hash().Hex(), "gasPool", ctx.gp.Gas(), "txGasLimit", tx.Gas(), "gasUsed", receipt.GasUsed)
+}
+
+// conflicted returns whether the speculative execution observed the accounts written.
+func conflicted(parallelState *state.ParallelStateDB, written map[common.Address]struct{}) bool {
+	for addr := range parallelState.ReadSet() {
+		if _, ok := written[addr]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (exe *Executor) isContract(address *common.Address, state *state.StateDB) bool {
 	if address == nil { // create contract
 		return true
@@ -15,6 +15,9 @@ type ParallelStateProcessor struct {
 	config *params.ChainConfig // Chain configuration options
 	bc     *BlockChain         // Canonical block chain
 	engine consensus.Engine    // Consensus engine used for block rewards
+
+	checkInterval uint64 // Cross-check the parallel execution of every checkInterval blocks, 0 to disable
+	checkDir      string // Directory to write the divergence reports
 }
 
 func NewParallelStateProcessor(config *params.ChainConfig, bc *BlockChain, engine consensus.Engine) *ParallelStateProcessor {
@@ -45,6 +48,16 @@ func (p *ParallelStateProcessor) Process(block *types.Block, statedb *state.Stat
 
 	// Iterate over and process the individual transactions
 	if len(block.Transactions()) > 0 {
+		// the serial execution must be done before the parallel one changes the snapshotdb
+		var serial *serialExecution
+		if p.shouldCrossCheck(block) {
+			var err error
+			if serial, err = p.executeSerially(block, statedb, cfg); err != nil {
+				log.Warn("Failed to execute transactions serially, skip the cross-check", "blockNumber", block.Number(),
+					"blockHash", block.Hash(), "err", err)
+			}
+		}
+
 		start := time.Now()
 		ctx := NewParallelContext(statedb, header, block.Hash(), gp, false, GetExecutor().Signer())
 		ctx.SetBlockGasUsedHolder(usedGas)
@@ -74,6 +87,12 @@ func (p *ParallelStateProcessor) Process(block *types.Block, statedb *state.Stat
 		receipts = ctx.GetReceipts()
 		allLogs = ctx.GetLogs()
 		log.Trace("Process parallel execute transactions cost time", "blockNumber", block.Number(), "blockHash", block.Hash(), "time", time.Since(start))
+
+		if serial != nil {
+			if err := p.crossCheck(block, statedb, receipts, allLogs, *usedGas, serial); err != nil {
+				return nil, nil, 0, err
+			}
+		}
 	}
 
 	if bcr != nil {
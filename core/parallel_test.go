@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/ecdsa"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"math/big"
 	"math/rand"
@@ -363,3 +364,79 @@ func serialMode(t testing.TB, testTxList types.Transactions, blockchain *BlockCh
 	//	}
 	//}
 }
+
+func TestParallel_SpeculativeContracts(t *testing.T) {
+	fromAccountList, toAccountList, contractAccountList := initAccount()
+	blockchain, stateDb, header := initChain(fromAccountList, toAccountList, contractAccountList)
+
+	// increases the counter in the slot 0 and logs the caller
+	code := hexutil.MustDecode("0x600054600101600055336000526020600060a000")
+	for _, contract := range contractAccountList {
+		stateDb.SetCode(contract.address, code)
+	}
+	root, err := stateDb.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the senders and the contracts are reused to make conflicts
+	txs := make(types.Transactions, 0)
+	for i := 0; i < 40; i++ {
+		from := fromAccountList[i%3]
+		to := contractAccountList[i%4].address
+		if i == 20 {
+			to = toAccountList[0].address
+		}
+		tx, _ := types.SignTx(types.NewTransaction(from.nonce, to, big.NewInt(int64(i)), 100000, gasPrice, nil), signer, from.priKey)
+		txs = append(txs, tx)
+		from.nonce++
+	}
+
+	NewExecutor(chainConfig, blockchain, blockchain.vmConfig, nil)
+
+	parallelState, _ := state2.New(root, stateDb.Database())
+	parallelHeader := types.CopyHeader(header)
+	ctx := NewParallelContext(parallelState, parallelHeader, common.Hash{}, new(GasPool).AddGas(header.GasLimit), false, GetExecutor().Signer())
+	ctx.SetBlockGasUsedHolder(&parallelHeader.GasUsed)
+	ctx.SetTxList(txs)
+	if err := GetExecutor().ExecuteTransactions(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	serialState, _ := state2.New(root, stateDb.Database())
+	serialHeader := types.CopyHeader(header)
+	gp := new(GasPool).AddGas(header.GasLimit)
+	receipts := types.Receipts{}
+	for _, tx := range txs {
+		serialState.Prepare(tx.Hash(), common.Hash{}, len(receipts))
+		receipt, _, err := ApplyTransaction(chainConfig, blockchain, gp, serialState, serialHeader, tx, &serialHeader.GasUsed, blockchain.vmConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	if len(ctx.GetReceipts()) != len(receipts) {
+		t.Fatalf("receipts count mismatch: have %d, want %d", len(ctx.GetReceipts()), len(receipts))
+	}
+	if have, want := types.DeriveSha(ctx.GetReceipts()), types.DeriveSha(receipts); have != want {
+		t.Fatalf("receipts mismatch: have %x, want %x", have, want)
+	}
+	for i, receipt := range receipts {
+		if i == 20 {
+			// the receipt of the transfer is created by the parallel executor
+			continue
+		}
+		have, _ := json.Marshal(ctx.GetReceipts()[i])
+		want, _ := json.Marshal(receipt)
+		if !bytes.Equal(have, want) {
+			t.Fatalf("receipt %d mismatch:\nhave %s\nwant %s", i, have, want)
+		}
+	}
+	if have, want := parallelState.IntermediateRoot(true), serialState.IntermediateRoot(true); have != want {
+		t.Fatalf("state root mismatch: have %x, want %x", have, want)
+	}
+	if parallelHeader.GasUsed != serialHeader.GasUsed || ctx.GetGasPool().Gas() != gp.Gas() {
+		t.Fatalf("gas mismatch: have %d %d, want %d %d", parallelHeader.GasUsed, ctx.GetGasPool().Gas(), serialHeader.GasUsed, gp.Gas())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	dag3 "github.com/AlayaNetwork/Alaya-Go/core/dag"
 	"github.com/AlayaNetwork/Alaya-Go/core/state"
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/core/vm"
 	"github.com/AlayaNetwork/Alaya-Go/log"
 )
 
@@ -12,12 +13,18 @@ type TxDag struct {
 	dag       *dag3.Dag
 	signer    types.Signer
 	contracts map[int]struct{}
+
+	// runs of the consecutive contract transactions which can be executed
+	// speculatively, i.e. the ones not calling the PPOS contracts directly
+	runs     [][]int
+	runIndex map[int]int
 }
 
 func NewTxDag(signer types.Signer) *TxDag {
 	txDag := &TxDag{
 		signer:    signer,
 		contracts: make(map[int]struct{}),
+		runIndex:  make(map[int]int),
 	}
 	return txDag
 }
@@ -44,6 +51,9 @@ func (txDag *TxDag) MakeDagGraph(blockNumber uint64, state *state.StateDB, txs [
 					txDag.dag.AddEdge(latestPrecompiledIndex, index)
 				}
 			}
+			if tx.To() == nil || !vm.IsPlatONPrecompiledContract(*tx.To()) {
+				txDag.addToRun(index)
+			}
 			latestPrecompiledIndex = index
 			//reset transferAddressMap
 			if len(transferAddressMap) > 0 {
@@ -92,6 +102,27 @@ func (txDag *TxDag) Next() []int {
 	return txDag.dag.Next()
 }
 
+func (txDag *TxDag) addToRun(idx int) {
+	if n := len(txDag.runs); n > 0 {
+		if run := txDag.runs[n-1]; run[len(run)-1] == idx-1 {
+			txDag.runs[n-1] = append(run, idx)
+			txDag.runIndex[idx] = n - 1
+			return
+		}
+	}
+	txDag.runs = append(txDag.runs, []int{idx})
+	txDag.runIndex[idx] = len(txDag.runs) - 1
+}
+
+// SpeculativeRun returns the run of the consecutive contract transactions
+// starting from idx, or nil if idx is not the first one of a run.
+func (txDag *TxDag) SpeculativeRun(idx int) []int {
+	if i, ok := txDag.runIndex[idx]; ok && txDag.runs[i][0] == idx {
+		return txDag.runs[i]
+	}
+	return nil
+}
+
 func (txDag *TxDag) IsContract(idx int) bool {
 	if _, ok := txDag.contracts[idx]; ok {
 		return true
@@ -0,0 +1,283 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/memdb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/ethdb"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+)
+
+// In archive mode every block written to the baseDB leaves a reverse diff
+// behind: for each key modified by block N the value the key had before N
+// is stored under ArchiveKeyPrefix + key + N.  The value of a key at block M
+// is then the value recorded by the first archived block greater than M, or
+// the value in the baseDB if the key has not been modified since M.
+const (
+	ArchiveKeyPrefix = "archive-"
+	ArchiveBegin     = "snapshotdbArchiveBegin"
+
+	archiveNumLength = 8
+)
+
+var (
+	archiveMode bool
+
+	// ErrArchiveNotAvailable is returned when the requested block is older
+	// than the first block the snapshotdb has archived.
+	ErrArchiveNotAvailable = errors.New("snapshotDB: archive data not available for the block")
+)
+
+// SetDBArchive enables or disables archive mode for the snapshotdb Instance.
+// It must be called before the Instance is opened.
+func SetDBArchive(enable bool) {
+	archiveMode = enable
+	logger.Info("set archive mode", "enable", enable)
+}
+
+func EncodeArchiveKey(key []byte, blockNum uint64) []byte {
+	buf := make([]byte, len(ArchiveKeyPrefix)+len(key)+archiveNumLength)
+	copy(buf, ArchiveKeyPrefix)
+	copy(buf[len(ArchiveKeyPrefix):], key)
+	binary.BigEndian.PutUint64(buf[len(buf)-archiveNumLength:], blockNum)
+	return buf
+}
+
+func DecodeArchiveKey(archiveKey []byte) ([]byte, uint64) {
+	key := archiveKey[len(ArchiveKeyPrefix) : len(archiveKey)-archiveNumLength]
+	return key, binary.BigEndian.Uint64(archiveKey[len(archiveKey)-archiveNumLength:])
+}
+
+// loadArchiveBegin reads the first archived block from the baseDB, the block is
+// initialized with the current base num the first time archive mode is enabled.
+func (s *snapshotDB) loadArchiveBegin() error {
+	v, err := s.baseDB.Get([]byte(ArchiveBegin))
+	if err == nil {
+		begin := new(big.Int)
+		if err := rlp.DecodeBytes(v, begin); err != nil {
+			return fmt.Errorf("decode archive begin fail:%v", err)
+		}
+		s.archiveBegin = begin
+		return nil
+	}
+	if err != ErrNotFound {
+		return err
+	}
+	begin := new(big.Int)
+	if s.current != nil {
+		begin.Set(s.current.GetBase(false).Num)
+	}
+	return s.saveArchiveBegin(begin)
+}
+
+func (s *snapshotDB) saveArchiveBegin(begin *big.Int) error {
+	v, err := rlp.EncodeToBytes(begin)
+	if err != nil {
+		return err
+	}
+	if err := s.baseDB.Put([]byte(ArchiveBegin), v); err != nil {
+		return fmt.Errorf("save archive begin fail:%v", err)
+	}
+	s.archiveBegin = new(big.Int).Set(begin)
+	logger.Info("save archive begin", "num", begin)
+	return nil
+}
+
+// writeArchive records the reverse diffs of the committed blocks which will be
+// written to the baseDB by the same batch.
+func (s *snapshotDB) writeArchive(batch ethdb.Batch, commitNum int) error {
+	written := make(map[string][]byte)
+	for i := 0; i < commitNum; i++ {
+		block := s.committed[i]
+		itr := block.data.NewIterator(nil)
+		for itr.Next() {
+			key := string(itr.Key())
+			old, ok := written[key]
+			if !ok {
+				v, err := s.baseDB.Get(itr.Key())
+				if err != nil && err != ErrNotFound {
+					itr.Release()
+					return err
+				}
+				old = v
+			}
+			if err := batch.Put(EncodeArchiveKey(itr.Key(), block.Number.Uint64()), old); err != nil {
+				itr.Release()
+				return err
+			}
+			written[key] = common.CopyBytes(itr.Value())
+		}
+		itr.Release()
+	}
+	return nil
+}
+
+// historyNum returns the number of a block which state has already been
+// committed or written to baseDB, the hash must be on the canonical chain.
+func (s *snapshotDB) historyNum(hash common.Hash) (uint64, bool) {
+	if s.archiveBegin == nil || blockchain == nil || hash == common.ZeroHash {
+		return 0, false
+	}
+	if block := s.unCommit.Get(hash); block != nil {
+		return 0, false
+	}
+	header := blockchain.GetHeaderByHash(hash)
+	if header == nil {
+		return 0, false
+	}
+	if header.Number.Cmp(s.current.GetHighest(false).Num) >= 0 {
+		return 0, false
+	}
+	if canonical := blockchain.GetHeaderByNumber(header.Number.Uint64()); canonical == nil || canonical.Hash() != hash {
+		return 0, false
+	}
+	return header.Number.Uint64(), true
+}
+
+// getHistory return the value of the key at the given block num
+// committed block(<=num) > archive(>num) > baseDB
+func (s *snapshotDB) getHistory(num uint64, key []byte) ([]byte, error) {
+	if num < s.archiveBegin.Uint64() {
+		return nil, ErrArchiveNotAvailable
+	}
+	s.commitLock.RLock()
+	defer s.commitLock.RUnlock()
+	for i := len(s.committed) - 1; i >= 0; i-- {
+		if s.committed[i].Number.Uint64() > num {
+			continue
+		}
+		v, err := s.committed[i].data.Get(key)
+		if err == nil {
+			if len(v) == 0 {
+				return nil, ErrNotFound
+			}
+			return v, nil
+		}
+		if err != memdb.ErrNotFound {
+			return nil, err
+		}
+	}
+
+	snapshot, err := s.baseDB.GetSnapshot()
+	if err != nil {
+		return nil, errors.New("[snapshotdb] get snapshot fail:" + err.Error())
+	}
+	defer snapshot.Release()
+
+	archiveKey := EncodeArchiveKey(key, num+1)
+	itr := snapshot.NewIterator(archiveKey, util.BytesPrefix(archiveKey[:len(archiveKey)-archiveNumLength]).Limit)
+	defer itr.Release()
+	for itr.Next() {
+		// the key with same prefix may be in the range, skip it
+		if len(itr.Key()) != len(archiveKey) {
+			continue
+		}
+		if len(itr.Value()) == 0 {
+			return nil, ErrNotFound
+		}
+		return common.CopyBytes(itr.Value()), nil
+	}
+	if err := itr.Error(); err != nil {
+		return nil, err
+	}
+
+	return snapshot.Get(key)
+}
+
+// rankingHistory is the same as Ranking, but iterates the state at the given block num
+func (s *snapshotDB) rankingHistory(num uint64, prefix *util.Range, rangeNumber int) iterator.Iterator {
+	if num < s.archiveBegin.Uint64() {
+		return iterator.NewEmptyIterator(ErrArchiveNotAvailable)
+	}
+	s.commitLock.RLock()
+	defer s.commitLock.RUnlock()
+
+	rankingHeap := newRankingHeap(rangeNumber)
+	for i := len(s.committed) - 1; i >= 0; i-- {
+		if s.committed[i].Number.Uint64() <= num {
+			rankingHeap.itr2Heap(s.committed[i].data.NewIterator(prefix), false, false)
+		}
+	}
+
+	snapshot, err := s.baseDB.GetSnapshot()
+	if err != nil {
+		return iterator.NewEmptyIterator(errors.New("[snapshotdb] get snapshot fail:" + err.Error()))
+	}
+	defer snapshot.Release()
+
+	// the oldest value recorded after the num is the value at the num
+	history := make(map[string][]byte)
+	archivePrefix := &util.Range{
+		Start: append([]byte(ArchiveKeyPrefix), prefix.Start...),
+		Limit: append([]byte(ArchiveKeyPrefix), prefix.Limit...),
+	}
+	archiveItr := newRangeIterator(snapshot, archivePrefix)
+	for archiveItr.Next() {
+		key, blockNum := DecodeArchiveKey(archiveItr.Key())
+		if blockNum <= num {
+			continue
+		}
+		if _, ok := history[string(key)]; !ok {
+			history[string(key)] = common.CopyBytes(archiveItr.Value())
+		}
+	}
+	archiveItr.Release()
+	if err := archiveItr.Error(); err != nil {
+		return iterator.NewEmptyIterator(err)
+	}
+
+	base := memdb.New(DefaultComparer, 100)
+	baseItr := newRangeIterator(snapshot, prefix)
+	for baseItr.Next() {
+		if _, ok := history[string(baseItr.Key())]; ok {
+			continue
+		}
+		if err := base.Put(baseItr.Key(), baseItr.Value()); err != nil {
+			baseItr.Release()
+			return iterator.NewEmptyIterator(errors.New("put to mdb fail" + err.Error()))
+		}
+	}
+	baseItr.Release()
+	for key, value := range history {
+		if len(value) == 0 {
+			continue
+		}
+		if err := base.Put([]byte(key), value); err != nil {
+			return iterator.NewEmptyIterator(errors.New("put to mdb fail" + err.Error()))
+		}
+	}
+	rankingHeap.itr2Heap(base.NewIterator(nil), true, true)
+
+	mdb := memdb.New(DefaultComparer, rangeNumber)
+	for rankingHeap.heap.Len() > 0 {
+		kv := heap.Pop(&rankingHeap.heap).(kv)
+		if err := mdb.Put(kv.key, kv.value); err != nil {
+			return iterator.NewEmptyIterator(errors.New("put to mdb fail" + err.Error()))
+		}
+	}
+	return mdb.NewIterator(nil)
+}
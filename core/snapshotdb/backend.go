@@ -0,0 +1,312 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/ethdb"
+)
+
+const (
+	// BackendLevelDB stores the baseDB in a goleveldb database under the snapshotdb directory.
+	BackendLevelDB = "leveldb"
+	// BackendChainDB stores the baseDB in the chain database, see SetDBChainDatabase.
+	BackendChainDB = "chaindb"
+	// BackendMemory stores the baseDB in memory, the data is lost when the snapshotdb is closed.
+	BackendMemory = "memory"
+	// BackendBolt stores the baseDB in a bbolt file under the snapshotdb directory.
+	BackendBolt = "bolt"
+)
+
+// Backend is the storage of the baseDB, the data committed by the blocks.
+type Backend interface {
+	// Get returns ErrNotFound if the key doesn't exist.
+	Get(key []byte) ([]byte, error)
+	Put(key, value []byte) error
+	Delete(key []byte) error
+
+	// NewBatch returns a batch of changes, they are applied atomically by the
+	// Write of the batch.
+	NewBatch() ethdb.Batch
+
+	// NewIterator returns an iterator of the keys in [start, limit) in
+	// ascending order, a nil limit means the iteration has no upper bound.
+	NewIterator(start, limit []byte) ethdb.Iterator
+
+	// GetSnapshot returns a read-only view of the backend at the current time,
+	// the writes done after are not seen by the view.
+	GetSnapshot() (BackendSnapshot, error)
+
+	Close() error
+}
+
+// BackendSnapshot is a read-only view of a Backend, it must be released after use.
+type BackendSnapshot interface {
+	Get(key []byte) ([]byte, error)
+	NewIterator(start, limit []byte) ethdb.Iterator
+	Release()
+}
+
+// OpenBackendFunc opens the backend of the snapshotdb at the path.
+type OpenBackendFunc func(path string, cache int, handles int) (Backend, error)
+
+var (
+	backends = map[string]OpenBackendFunc{
+		BackendLevelDB: openLevelDBBackend,
+		BackendChainDB: openChainDBBackend,
+		BackendMemory:  openMemoryBackend,
+		BackendBolt:    openBoltBackend,
+	}
+	backendLock sync.RWMutex
+
+	// dbBackend is the backend used by the snapshotdb
+	dbBackend = BackendLevelDB
+
+	// chainDB is the chain database used by the chaindb backend
+	chainDB ethdb.KeyValueStore
+)
+
+// RegisterBackend makes a backend available by the name. Only the leveldb,
+// bolt, chaindb and memory backends are built in, other storage engines must
+// be registered by the program before the snapshotdb is opened.
+func RegisterBackend(name string, open OpenBackendFunc) {
+	backendLock.Lock()
+	defer backendLock.Unlock()
+	backends[name] = open
+}
+
+// Backends returns the names of the available backends.
+func Backends() []string {
+	backendLock.RLock()
+	defer backendLock.RUnlock()
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetDBBackend sets the backend used by the snapshotdb opened after, the
+// leveldb backend is used if the name is empty.
+func SetDBBackend(name string) error {
+	if name == "" {
+		name = BackendLevelDB
+	}
+	backendLock.RLock()
+	_, ok := backends[name]
+	backendLock.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown snapshotdb backend %q, available: %s", name, strings.Join(Backends(), ", "))
+	}
+	dbBackend = name
+	logger.Info("set backend", "backend", name)
+	return nil
+}
+
+// SetDBChainDatabase sets the chain database used by the chaindb backend.
+func SetDBChainDatabase(db ethdb.KeyValueStore) {
+	chainDB = db
+}
+
+// OpenBackend opens the backend by the name.
+func OpenBackend(name string, path string, cache int, handles int) (Backend, error) {
+	backendLock.RLock()
+	open, ok := backends[name]
+	backendLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown snapshotdb backend %q", name)
+	}
+	return open(path, cache, handles)
+}
+
+// MigrateBackend copies all the keys of the backend from to the backend to,
+// which is expected to be empty. It returns the number of keys copied.
+func MigrateBackend(from, to Backend) (int, error) {
+	snapshot, err := from.GetSnapshot()
+	if err != nil {
+		return 0, err
+	}
+	defer snapshot.Release()
+
+	itr := snapshot.NewIterator(nil, nil)
+	defer itr.Release()
+	var (
+		batch = to.NewBatch()
+		count int
+	)
+	for itr.Next() {
+		if err := batch.Put(itr.Key(), itr.Value()); err != nil {
+			return count, err
+		}
+		count++
+		if count%kvLIMIT == 0 {
+			if err := batch.Write(); err != nil {
+				return count, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return count, err
+	}
+	if err := batch.Write(); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// backendDestroyer is implemented by the backends whose data is not removed
+// with the snapshotdb directory.
+type backendDestroyer interface {
+	Destroy() error
+}
+
+// backendIteratee is implemented by the backends and their snapshots.
+type backendIteratee interface {
+	NewIterator(start, limit []byte) ethdb.Iterator
+}
+
+// newRangeIterator iterates the keys of the goleveldb range, all the keys if the
+// range is nil.
+func newRangeIterator(db backendIteratee, slice *util.Range) ethdb.Iterator {
+	if slice == nil {
+		return db.NewIterator(nil, nil)
+	}
+	return db.NewIterator(slice.Start, slice.Limit)
+}
+
+// backendBatch is the batch of the backends, the changes are applied by the
+// write function of the backend.
+type backendBatch struct {
+	writes []keyvalue
+	size   int
+	write  func(writes []keyvalue) error
+}
+
+type keyvalue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+func newBackendBatch(write func(writes []keyvalue) error) *backendBatch {
+	return &backendBatch{write: write}
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *backendBatch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyvalue{common.CopyBytes(key), common.CopyBytes(value), false})
+	b.size += len(value)
+	return nil
+}
+
+// Delete inserts the a key removal into the batch for later committing.
+func (b *backendBatch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyvalue{common.CopyBytes(key), nil, true})
+	b.size++
+	return nil
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *backendBatch) ValueSize() int {
+	return b.size
+}
+
+// Write flushes any accumulated data to the backend.
+func (b *backendBatch) Write() error {
+	if len(b.writes) == 0 {
+		return nil
+	}
+	return b.write(b.writes)
+}
+
+// Reset resets the batch for reuse.
+func (b *backendBatch) Reset() {
+	b.writes = b.writes[:0]
+	b.size = 0
+}
+
+// Replay replays the batch contents.
+func (b *backendBatch) Replay(w ethdb.Writer) error {
+	for _, kv := range b.writes {
+		if kv.delete {
+			if err := w.Delete(kv.key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := w.Put(kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backendIterator adds Valid to the iterator of a backend, so that it is the
+// Iterator of the snapshotdb.
+type backendIterator struct {
+	it   ethdb.Iterator
+	done bool
+}
+
+func newBackendIterator(it ethdb.Iterator) Iterator {
+	return &backendIterator{it: it}
+}
+
+func (i *backendIterator) Next() bool {
+	if i.done {
+		return false
+	}
+	if !i.it.Next() {
+		i.done = true
+		return false
+	}
+	return true
+}
+
+func (i *backendIterator) Valid() bool { return !i.done && i.it.Key() != nil }
+
+func (i *backendIterator) Key() []byte {
+	if i.done {
+		return nil
+	}
+	return i.it.Key()
+}
+
+func (i *backendIterator) Value() []byte {
+	if i.done {
+		return nil
+	}
+	return i.it.Value()
+}
+
+func (i *backendIterator) Error() error {
+	return i.it.Error()
+}
+
+func (i *backendIterator) Release() {
+	i.it.Release()
+	i.done = true
+}
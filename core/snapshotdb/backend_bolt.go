@@ -0,0 +1,228 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"bytes"
+	"os"
+	"path"
+
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/ethdb"
+)
+
+const (
+	// boltFile is the file of the bolt backend under the snapshotdb directory.
+	boltFile = "base.bolt"
+
+	// boltMmapSize is the initial mmap size of the bolt file. A write which
+	// grows the file beyond the mmap waits for all the open snapshots and
+	// iterators to be released, a large mmap makes it rare.
+	boltMmapSize = 1 << 30
+)
+
+var boltBucket = []byte("snapshotdb")
+
+// boltBackend keeps the baseDB in a single bucket of a bbolt file. The reads
+// are done in read-only transactions, a snapshot or an iterator keeps its
+// transaction open until it is released.
+type boltBackend struct {
+	db *bolt.DB
+}
+
+func openBoltBackend(snapshotDBPath string, cache int, handles int) (Backend, error) {
+	if err := os.MkdirAll(snapshotDBPath, 0700); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path.Join(snapshotDBPath, boltFile), 0600, &bolt.Options{
+		InitialMmapSize: boltMmapSize,
+		FreelistType:    bolt.FreelistMapType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltBackend{db: db}, nil
+}
+
+func (b *boltBackend) Get(key []byte) ([]byte, error) {
+	var value []byte
+	err := b.db.View(func(tx *bolt.Tx) error {
+		v, err := boltGet(tx, key)
+		value = common.CopyBytes(v)
+		return err
+	})
+	return value, err
+}
+
+func (b *boltBackend) Put(key, value []byte) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put(key, value)
+	})
+}
+
+func (b *boltBackend) Delete(key []byte) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete(key)
+	})
+}
+
+func (b *boltBackend) NewBatch() ethdb.Batch {
+	return newBackendBatch(func(writes []keyvalue) error {
+		return b.db.Update(func(tx *bolt.Tx) error {
+			bucket := tx.Bucket(boltBucket)
+			for _, kv := range writes {
+				if kv.delete {
+					if err := bucket.Delete(kv.key); err != nil {
+						return err
+					}
+				} else if err := bucket.Put(kv.key, kv.value); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// NewIterator returns an iterator on its own read-only transaction, the
+// iterator doesn't see the writes done after it is created.
+func (b *boltBackend) NewIterator(start, limit []byte) ethdb.Iterator {
+	tx, err := b.db.Begin(false)
+	if err != nil {
+		return iterator.NewEmptyIterator(err)
+	}
+	return newBoltIterator(tx, start, limit, true)
+}
+
+func (b *boltBackend) GetSnapshot() (BackendSnapshot, error) {
+	tx, err := b.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	return &boltSnapshot{tx: tx}, nil
+}
+
+func (b *boltBackend) Close() error {
+	return b.db.Close()
+}
+
+type boltSnapshot struct {
+	tx *bolt.Tx
+}
+
+func (s *boltSnapshot) Get(key []byte) ([]byte, error) {
+	v, err := boltGet(s.tx, key)
+	return common.CopyBytes(v), err
+}
+
+func (s *boltSnapshot) NewIterator(start, limit []byte) ethdb.Iterator {
+	return newBoltIterator(s.tx, start, limit, false)
+}
+
+func (s *boltSnapshot) Release() {
+	s.tx.Rollback()
+}
+
+// boltGet returns the value of the key in the transaction, it is only valid
+// while the transaction is open.
+func boltGet(tx *bolt.Tx, key []byte) ([]byte, error) {
+	k, v := tx.Bucket(boltBucket).Cursor().Seek(key)
+	if k == nil || !bytes.Equal(k, key) {
+		return nil, ErrNotFound
+	}
+	return v, nil
+}
+
+// boltIterator iterates the keys of [start, limit) with a cursor of the
+// transaction, the transaction is closed by Release if the iterator owns it.
+type boltIterator struct {
+	tx         *bolt.Tx
+	ownTx      bool
+	cursor     *bolt.Cursor
+	start      []byte
+	limit      []byte
+	key, value []byte
+	started    bool
+	released   bool
+}
+
+func newBoltIterator(tx *bolt.Tx, start, limit []byte, ownTx bool) *boltIterator {
+	return &boltIterator{
+		tx:     tx,
+		ownTx:  ownTx,
+		cursor: tx.Bucket(boltBucket).Cursor(),
+		start:  start,
+		limit:  limit,
+	}
+}
+
+func (i *boltIterator) Next() bool {
+	if i.released {
+		return false
+	}
+	var k, v []byte
+	if !i.started {
+		i.started = true
+		if i.start == nil {
+			k, v = i.cursor.First()
+		} else {
+			k, v = i.cursor.Seek(i.start)
+		}
+	} else if i.key != nil {
+		k, v = i.cursor.Next()
+	}
+	if k == nil || (i.limit != nil && bytes.Compare(k, i.limit) >= 0) {
+		i.key, i.value = nil, nil
+		return false
+	}
+	i.key, i.value = k, v
+	return true
+}
+
+func (i *boltIterator) Error() error {
+	return nil
+}
+
+func (i *boltIterator) Key() []byte {
+	return i.key
+}
+
+func (i *boltIterator) Value() []byte {
+	return i.value
+}
+
+func (i *boltIterator) Release() {
+	if i.released {
+		return
+	}
+	i.released = true
+	i.key, i.value = nil, nil
+	if i.ownTx {
+		i.tx.Rollback()
+	}
+}
@@ -0,0 +1,327 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/ethdb"
+)
+
+// ChainDBPrefix is the prefix of the snapshotdb keys in the chain database.
+var ChainDBPrefix = []byte("snapshotdb-")
+
+// chainDBBackend keeps the baseDB in the chain database with the ChainDBPrefix.
+// The chain database is owned by the blockchain, it isn't closed with the
+// snapshotdb.
+//
+// The chain database has no snapshots, so the backend keeps the values which a
+// write replaces for every snapshot alive. The iterators of the chain database
+// must see a consistent view of the keys, as the leveldb and memorydb ones do.
+type chainDBBackend struct {
+	db        ethdb.KeyValueStore
+	lock      sync.RWMutex
+	snapshots map[*chainDBSnapshot]struct{}
+}
+
+func openChainDBBackend(path string, cache int, handles int) (Backend, error) {
+	if chainDB == nil {
+		return nil, errors.New("the chain database of snapshotdb is not set")
+	}
+	return newChainDBBackend(chainDB), nil
+}
+
+func newChainDBBackend(db ethdb.KeyValueStore) *chainDBBackend {
+	return &chainDBBackend{db: db, snapshots: make(map[*chainDBSnapshot]struct{})}
+}
+
+func chainDBKey(key []byte) []byte {
+	return append(append(make([]byte, 0, len(ChainDBPrefix)+len(key)), ChainDBPrefix...), key...)
+}
+
+func (b *chainDBBackend) Get(key []byte) ([]byte, error) {
+	return b.get(key)
+}
+
+func (b *chainDBBackend) get(key []byte) ([]byte, error) {
+	v, err := b.db.Get(chainDBKey(key))
+	if err != nil {
+		// the databases return different errors if the key is not found
+		if has, hasErr := b.db.Has(chainDBKey(key)); hasErr == nil && !has {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+func (b *chainDBBackend) Put(key, value []byte) error {
+	return b.write([]keyvalue{{key: key, value: value}})
+}
+
+func (b *chainDBBackend) Delete(key []byte) error {
+	return b.write([]keyvalue{{key: key, delete: true}})
+}
+
+func (b *chainDBBackend) NewBatch() ethdb.Batch {
+	return newBackendBatch(b.write)
+}
+
+// write applies the changes by a batch of the chain database, the values they
+// replace are kept first for the snapshots.
+func (b *chainDBBackend) write(writes []keyvalue) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if len(b.snapshots) > 0 {
+		for _, kv := range writes {
+			if err := b.keep(kv.key); err != nil {
+				return err
+			}
+		}
+	}
+	batch := b.db.NewBatch()
+	for _, kv := range writes {
+		var err error
+		if kv.delete {
+			err = batch.Delete(chainDBKey(kv.key))
+		} else {
+			err = batch.Put(chainDBKey(kv.key), kv.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// keep records the current value of the key in the snapshots which don't have
+// it yet.
+func (b *chainDBBackend) keep(key []byte) error {
+	var (
+		old    *keptValue
+		strKey = string(key)
+	)
+	for snapshot := range b.snapshots {
+		if _, ok := snapshot.kept[strKey]; ok {
+			continue
+		}
+		if old == nil {
+			v, err := b.get(key)
+			if err != nil && err != ErrNotFound {
+				return err
+			}
+			old = &keptValue{value: common.CopyBytes(v), exist: err == nil}
+		}
+		snapshot.kept[strKey] = *old
+	}
+	return nil
+}
+
+// NewIterator seeks to the start of the range in the keys of the snapshotdb.
+func (b *chainDBBackend) NewIterator(start, limit []byte) ethdb.Iterator {
+	return &chainDBIterator{
+		it:    b.db.NewIteratorWithStart(chainDBKey(start)),
+		limit: limit,
+	}
+}
+
+// GetSnapshot returns a view of the backend, the values replaced after it by the
+// writes of the backend are kept until the snapshot is released.
+func (b *chainDBBackend) GetSnapshot() (BackendSnapshot, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	snapshot := &chainDBSnapshot{backend: b, kept: make(map[string]keptValue)}
+	b.snapshots[snapshot] = struct{}{}
+	return snapshot, nil
+}
+
+func (b *chainDBBackend) Close() error {
+	return nil
+}
+
+// Destroy removes all the snapshotdb keys from the chain database.
+func (b *chainDBBackend) Destroy() error {
+	it := b.db.NewIteratorWithPrefix(ChainDBPrefix)
+	defer it.Release()
+	batch := b.db.NewBatch()
+	for it.Next() {
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+type keptValue struct {
+	value []byte
+	exist bool
+}
+
+// chainDBSnapshot is a view of the chainDBBackend, it reads the values kept for
+// the keys written after it was taken and the chain database for the others.
+type chainDBSnapshot struct {
+	backend *chainDBBackend
+	kept    map[string]keptValue
+}
+
+func (s *chainDBSnapshot) Get(key []byte) ([]byte, error) {
+	s.backend.lock.RLock()
+	defer s.backend.lock.RUnlock()
+	if old, ok := s.kept[string(key)]; ok {
+		if !old.exist {
+			return nil, ErrNotFound
+		}
+		return common.CopyBytes(old.value), nil
+	}
+	return s.backend.get(key)
+}
+
+// NewIterator merges the kept values in the range with an iterator of the chain
+// database, both are taken at the same time so the keys written later are
+// either kept or not seen.
+func (s *chainDBSnapshot) NewIterator(start, limit []byte) ethdb.Iterator {
+	s.backend.lock.RLock()
+	defer s.backend.lock.RUnlock()
+	kept := make([]keyvalue, 0, len(s.kept))
+	for key, old := range s.kept {
+		k := []byte(key)
+		if bytes.Compare(k, start) < 0 || (limit != nil && bytes.Compare(k, limit) >= 0) {
+			continue
+		}
+		kept = append(kept, keyvalue{key: k, value: old.value, delete: !old.exist})
+	}
+	sort.Slice(kept, func(i, j int) bool { return bytes.Compare(kept[i].key, kept[j].key) < 0 })
+	return &chainDBSnapshotIterator{
+		it:       s.backend.NewIterator(start, limit),
+		kept:     kept,
+		nextIt:   true,
+		nextKept: true,
+	}
+}
+
+func (s *chainDBSnapshot) Release() {
+	s.backend.lock.Lock()
+	defer s.backend.lock.Unlock()
+	delete(s.backend.snapshots, s)
+	s.kept = nil
+}
+
+// chainDBIterator iterates the keys of the snapshotdb in the chain database from
+// the start of the range, the prefix is removed from the keys.
+type chainDBIterator struct {
+	it         ethdb.Iterator
+	limit      []byte
+	key, value []byte
+	done       bool
+}
+
+func (i *chainDBIterator) Next() bool {
+	if i.done {
+		return false
+	}
+	if i.it.Next() && bytes.HasPrefix(i.it.Key(), ChainDBPrefix) {
+		key := i.it.Key()[len(ChainDBPrefix):]
+		if i.limit == nil || bytes.Compare(key, i.limit) < 0 {
+			i.key, i.value = key, i.it.Value()
+			return true
+		}
+	}
+	i.done = true
+	i.key, i.value = nil, nil
+	return false
+}
+
+func (i *chainDBIterator) Error() error  { return i.it.Error() }
+func (i *chainDBIterator) Key() []byte   { return i.key }
+func (i *chainDBIterator) Value() []byte { return i.value }
+
+func (i *chainDBIterator) Release() {
+	i.it.Release()
+	i.done = true
+	i.key, i.value = nil, nil
+}
+
+// chainDBSnapshotIterator merges the sorted kept values of a snapshot into an
+// iterator of the chain database, the kept value wins if both have the key.
+type chainDBSnapshotIterator struct {
+	it               ethdb.Iterator
+	kept             []keyvalue
+	itOK, keptOK     bool
+	nextIt, nextKept bool
+	key, value       []byte
+}
+
+func (i *chainDBSnapshotIterator) Next() bool {
+	for {
+		if i.nextIt {
+			i.itOK, i.nextIt = i.it.Next(), false
+		}
+		if i.nextKept {
+			if i.keptOK {
+				i.kept = i.kept[1:]
+			}
+			i.keptOK, i.nextKept = len(i.kept) > 0, false
+		}
+		switch {
+		case !i.itOK && !i.keptOK:
+			i.key, i.value = nil, nil
+			return false
+
+		case i.keptOK && (!i.itOK || bytes.Compare(i.kept[0].key, i.it.Key()) <= 0):
+			kept := i.kept[0]
+			if i.itOK && bytes.Equal(kept.key, i.it.Key()) {
+				i.nextIt = true
+			}
+			i.nextKept = true
+			if kept.delete {
+				// the key didn't exist when the snapshot was taken
+				continue
+			}
+			i.key, i.value = kept.key, kept.value
+			return true
+
+		default:
+			i.nextIt = true
+			i.key, i.value = i.it.Key(), i.it.Value()
+			return true
+		}
+	}
+}
+
+func (i *chainDBSnapshotIterator) Error() error  { return i.it.Error() }
+func (i *chainDBSnapshotIterator) Key() []byte   { return i.key }
+func (i *chainDBSnapshotIterator) Value() []byte { return i.value }
+
+func (i *chainDBSnapshotIterator) Release() {
+	i.it.Release()
+	i.kept = nil
+	i.itOK, i.keptOK = false, false
+	i.key, i.value = nil, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"fmt"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	leveldbError "github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/syndtr/goleveldb/leveldb/filter"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/AlayaNetwork/Alaya-Go/ethdb"
+)
+
+type levelDBBackend struct {
+	db *leveldb.DB
+}
+
+func openLevelDBBackend(snapshotDBPath string, cache int, handles int) (Backend, error) {
+	leveldbPath := getBaseDBPath(snapshotDBPath)
+	db, err := leveldb.OpenFile(leveldbPath, &opt.Options{
+		OpenFilesCacheCapacity: handles,
+		BlockCacheCapacity:     cache / 2 * opt.MiB,
+		WriteBuffer:            cache / 4 * opt.MiB, // Two of these are used internally
+		Filter:                 filter.NewBloomFilter(10),
+	})
+	if err != nil {
+		if _, corrupted := err.(*leveldbError.ErrCorrupted); corrupted {
+			db, err = leveldb.RecoverFile(leveldbPath, nil)
+			if err != nil {
+				return nil, fmt.Errorf("[SnapshotDB.recover]RecoverFile baseDB fail:%v", err)
+			}
+		} else {
+			return nil, err
+		}
+	}
+	return &levelDBBackend{db: db}, nil
+}
+
+func (b *levelDBBackend) Get(key []byte) ([]byte, error) {
+	return levelDBGet(b.db, key)
+}
+
+func (b *levelDBBackend) Put(key, value []byte) error {
+	return b.db.Put(key, value, nil)
+}
+
+func (b *levelDBBackend) Delete(key []byte) error {
+	return b.db.Delete(key, nil)
+}
+
+func (b *levelDBBackend) NewBatch() ethdb.Batch {
+	return newBackendBatch(func(writes []keyvalue) error {
+		batch := leveldb.MakeBatch(len(writes))
+		for _, kv := range writes {
+			if kv.delete {
+				batch.Delete(kv.key)
+			} else {
+				batch.Put(kv.key, kv.value)
+			}
+		}
+		return b.db.Write(batch, nil)
+	})
+}
+
+func (b *levelDBBackend) NewIterator(start, limit []byte) ethdb.Iterator {
+	return b.db.NewIterator(&util.Range{Start: start, Limit: limit}, nil)
+}
+
+func (b *levelDBBackend) GetSnapshot() (BackendSnapshot, error) {
+	snapshot, err := b.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &levelDBSnapshot{snapshot: snapshot}, nil
+}
+
+func (b *levelDBBackend) Close() error {
+	return b.db.Close()
+}
+
+type levelDBSnapshot struct {
+	snapshot *leveldb.Snapshot
+}
+
+func (s *levelDBSnapshot) Get(key []byte) ([]byte, error) {
+	return levelDBGet(s.snapshot, key)
+}
+
+func (s *levelDBSnapshot) NewIterator(start, limit []byte) ethdb.Iterator {
+	return s.snapshot.NewIterator(&util.Range{Start: start, Limit: limit}, nil)
+}
+
+func (s *levelDBSnapshot) Release() {
+	s.snapshot.Release()
+}
+
+func levelDBGet(db leveldb.Reader, key []byte) ([]byte, error) {
+	v, err := db.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil, ErrNotFound
+	}
+	return v, err
+}
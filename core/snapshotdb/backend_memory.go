@@ -0,0 +1,130 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb/memdb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/ethdb"
+)
+
+// memoryBackend keeps the baseDB in a memdb, it is mostly used by the tests.
+// The iterators see the writes done after they are created, use a snapshot
+// for a consistent view.
+type memoryBackend struct {
+	db   *memdb.DB
+	lock sync.RWMutex
+}
+
+func openMemoryBackend(path string, cache int, handles int) (Backend, error) {
+	return newMemoryBackend(), nil
+}
+
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{db: memdb.New(DefaultComparer, 0)}
+}
+
+func (b *memoryBackend) Get(key []byte) ([]byte, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return memoryGet(b.db, key)
+}
+
+func (b *memoryBackend) Put(key, value []byte) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.db.Put(key, value)
+}
+
+func (b *memoryBackend) Delete(key []byte) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if err := b.db.Delete(key); err != nil && err != memdb.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+func (b *memoryBackend) NewBatch() ethdb.Batch {
+	return newBackendBatch(func(writes []keyvalue) error {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		for _, kv := range writes {
+			if kv.delete {
+				if err := b.db.Delete(kv.key); err != nil && err != memdb.ErrNotFound {
+					return err
+				}
+			} else if err := b.db.Put(kv.key, kv.value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *memoryBackend) NewIterator(start, limit []byte) ethdb.Iterator {
+	return b.db.NewIterator(&util.Range{Start: start, Limit: limit})
+}
+
+func (b *memoryBackend) GetSnapshot() (BackendSnapshot, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	snapshot := memdb.New(DefaultComparer, b.db.Size())
+	itr := b.db.NewIterator(nil)
+	defer itr.Release()
+	for itr.Next() {
+		if err := snapshot.Put(itr.Key(), itr.Value()); err != nil {
+			return nil, err
+		}
+	}
+	return &memorySnapshot{db: snapshot}, nil
+}
+
+func (b *memoryBackend) Close() error {
+	return nil
+}
+
+type memorySnapshot struct {
+	db *memdb.DB
+}
+
+func (s *memorySnapshot) Get(key []byte) ([]byte, error) {
+	return memoryGet(s.db, key)
+}
+
+func (s *memorySnapshot) NewIterator(start, limit []byte) ethdb.Iterator {
+	return s.db.NewIterator(&util.Range{Start: start, Limit: limit})
+}
+
+func (s *memorySnapshot) Release() {
+	s.db = nil
+}
+
+func memoryGet(db *memdb.DB, key []byte) ([]byte, error) {
+	v, err := db.Get(key)
+	if err == memdb.ErrNotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return common.CopyBytes(v), nil
+}
@@ -0,0 +1,236 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/ethdb"
+	"github.com/AlayaNetwork/Alaya-Go/ethdb/memorydb"
+)
+
+func iteratorKeys(itr ethdb.Iterator) string {
+	defer itr.Release()
+	var keys []string
+	for itr.Next() {
+		keys = append(keys, string(itr.Key())+"="+string(itr.Value()))
+	}
+	return strings.Join(keys, ",")
+}
+
+func TestBackends(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshotdb-backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	chainDB := memorydb.New()
+	// the keys of the chain around the snapshotdb ones must not be seen
+	for _, key := range []string{"a", "snapshotdb", "t"} {
+		if err := chainDB.Put([]byte(key), []byte("chain")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	SetDBChainDatabase(chainDB)
+	defer SetDBChainDatabase(nil)
+
+	for _, name := range []string{BackendLevelDB, BackendBolt, BackendChainDB, BackendMemory} {
+		t.Run(name, func(t *testing.T) {
+			b, err := OpenBackend(name, dir, 16, 16)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer b.Close()
+
+			for _, key := range []string{"a", "b", "ba", "c"} {
+				if err := b.Put([]byte(key), []byte(key)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			batch := b.NewBatch()
+			batch.Delete([]byte("b"))
+			batch.Put([]byte("bb"), []byte("bb"))
+			batch.Put([]byte("c"), []byte("c2"))
+			if err := batch.Write(); err != nil {
+				t.Fatal(err)
+			}
+			if err := b.Delete([]byte("a")); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := b.Get([]byte("b")); err != ErrNotFound {
+				t.Errorf("want ErrNotFound, have %v", err)
+			}
+			if v, err := b.Get([]byte("c")); err != nil || !bytes.Equal(v, []byte("c2")) {
+				t.Errorf("want c2, have %s %v", v, err)
+			}
+			if keys := iteratorKeys(b.NewIterator(nil, nil)); keys != "ba=ba,bb=bb,c=c2" {
+				t.Errorf("iterate all keys: %s", keys)
+			}
+			if keys := iteratorKeys(b.NewIterator([]byte("b"), []byte("c"))); keys != "ba=ba,bb=bb" {
+				t.Errorf("iterate the prefix: %s", keys)
+			}
+			if keys := iteratorKeys(b.NewIterator([]byte("bb"), nil)); keys != "bb=bb,c=c2" {
+				t.Errorf("iterate from the start: %s", keys)
+			}
+
+			snapshot, err := b.GetSnapshot()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer snapshot.Release()
+			// the writes after the snapshot must not be seen by it
+			batch = b.NewBatch()
+			batch.Put([]byte("a"), []byte("a2"))
+			batch.Delete([]byte("ba"))
+			batch.Put([]byte("bb"), []byte("bb2"))
+			if err := batch.Write(); err != nil {
+				t.Fatal(err)
+			}
+			if err := b.Put([]byte("bb"), []byte("bb3")); err != nil {
+				t.Fatal(err)
+			}
+			if v, err := snapshot.Get([]byte("ba")); err != nil || !bytes.Equal(v, []byte("ba")) {
+				t.Errorf("want ba, have %s %v", v, err)
+			}
+			if _, err := snapshot.Get([]byte("a")); err != ErrNotFound {
+				t.Errorf("want ErrNotFound, have %v", err)
+			}
+			if keys := iteratorKeys(snapshot.NewIterator(nil, nil)); keys != "ba=ba,bb=bb,c=c2" {
+				t.Errorf("iterate the snapshot: %s", keys)
+			}
+			if keys := iteratorKeys(snapshot.NewIterator([]byte("bb"), []byte("c"))); keys != "bb=bb" {
+				t.Errorf("iterate the range of the snapshot: %s", keys)
+			}
+			if keys := iteratorKeys(b.NewIterator(nil, nil)); keys != "a=a2,bb=bb3,c=c2" {
+				t.Errorf("iterate all keys after the snapshot: %s", keys)
+			}
+		})
+	}
+}
+
+func TestMigrateBackend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshotdb-migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	from, err := OpenBackend(BackendLevelDB, dir, 16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer from.Close()
+	for _, kv := range generatekv(kvLIMIT + 10) {
+		if err := from.Put(kv.key, kv.value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chainDB := memorydb.New()
+	to := newChainDBBackend(chainDB)
+	count, err := MigrateBackend(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != kvLIMIT+10 || chainDB.Len() != count {
+		t.Fatalf("want %d keys, have %d, chaindb has %d", kvLIMIT+10, count, chainDB.Len())
+	}
+	if keys, migrated := iteratorKeys(from.NewIterator(nil, nil)), iteratorKeys(to.NewIterator(nil, nil)); keys != migrated {
+		t.Fatal("the keys are not migrated")
+	}
+
+	if err := to.Destroy(); err != nil {
+		t.Fatal(err)
+	}
+	if chainDB.Len() != 0 {
+		t.Fatalf("want no keys after destroy, have %d", chainDB.Len())
+	}
+}
+
+func TestSnapshotDB_ChainDBBackend(t *testing.T) {
+	chainDB := memorydb.New()
+	SetDBChainDatabase(chainDB)
+	if err := SetDBBackend(BackendChainDB); err != nil {
+		t.Fatal(err)
+	}
+	defer SetDBBackend(BackendLevelDB)
+
+	ch := newTestchain(dbpath)
+	var (
+		baseKV      = kv{key: []byte("rankingKey1"), value: []byte("base")}
+		committedKV = kv{key: []byte("rankingKey2"), value: []byte("committed")}
+	)
+	if err := ch.insert(true, kvs{baseKV}, newBlockBaseDB); err != nil {
+		t.Fatal(err)
+	}
+	if err := ch.insert(true, kvs{committedKV}, newBlockCommited); err != nil {
+		t.Fatal(err)
+	}
+	ch.db.walSync.Wait()
+	if v, err := ch.db.GetBaseDB(baseKV.key); err != nil || !bytes.Equal(v, baseKV.value) {
+		t.Fatalf("want %s, have %s %v", baseKV.value, v, err)
+	}
+	if keys := iteratorKeys(ch.db.Ranking(ch.CurrentHeader().Hash(), []byte("rankingKey"), 10)); keys != "rankingKey1=base,rankingKey2=committed" {
+		t.Errorf("ranking not right: %s", keys)
+	}
+	if chainDB.Len() == 0 {
+		t.Fatal("baseDB is not written to the chain database")
+	}
+
+	ch.clear()
+	if chainDB.Len() != 0 {
+		t.Fatalf("want the chain database cleared, have %d keys", chainDB.Len())
+	}
+	if err := SetDBBackend("unknown"); err == nil {
+		t.Fatal("unknown backend must not be set")
+	}
+}
+
+func TestSnapshotDB_BoltBackend(t *testing.T) {
+	if err := SetDBBackend(BackendBolt); err != nil {
+		t.Fatal(err)
+	}
+	defer SetDBBackend(BackendLevelDB)
+
+	ch := newTestchain(dbpath)
+	defer ch.clear()
+	var (
+		baseKV      = kv{key: []byte("rankingKey1"), value: []byte("base")}
+		committedKV = kv{key: []byte("rankingKey2"), value: []byte("committed")}
+	)
+	if err := ch.insert(true, kvs{baseKV}, newBlockBaseDB); err != nil {
+		t.Fatal(err)
+	}
+	if err := ch.insert(true, kvs{committedKV}, newBlockCommited); err != nil {
+		t.Fatal(err)
+	}
+	ch.db.walSync.Wait()
+	if _, ok := ch.db.baseDB.(*boltBackend); !ok {
+		t.Fatalf("want the bolt backend, have %T", ch.db.baseDB)
+	}
+	if v, err := ch.db.GetBaseDB(baseKV.key); err != nil || !bytes.Equal(v, baseKV.value) {
+		t.Fatalf("want %s, have %s %v", baseKV.value, v, err)
+	}
+	if keys := iteratorKeys(ch.db.Ranking(ch.CurrentHeader().Hash(), []byte("rankingKey"), 10)); keys != "rankingKey1=base,rankingKey2=committed" {
+		t.Errorf("ranking not right: %s", keys)
+	}
+}
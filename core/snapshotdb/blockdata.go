@@ -39,10 +39,18 @@ type blockData struct {
 	readOnly   bool
 	kvHash     common.Hash
 
+	// writes are the key-values written in order, the kv hash is computed
+	// from them and data only keeps the latest values. They are only recorded
+	// when writes is not nil, see snapshotDB.recordWrites.
+	writes []journalData
+
 	//only use for not commit block
 	journal        []journalEntry // Current changes tracked by the journal
 	validRevisions []revision
 	nextRevisionId int
+
+	// events emitted while executing the block, they are kept in memory only
+	events []interface{}
 }
 
 type revision struct {
@@ -64,6 +72,7 @@ func (b *blockData) DecodeRLP(s *rlp.Stream) error {
 	for _, kv := range jk.Data {
 		b.data.Put(kv.Key, kv.Value)
 	}
+	b.writes = jk.Writes
 	return nil
 }
 
@@ -73,6 +82,7 @@ func (b *blockData) EncodeRLP(w io.Writer) error {
 	jk.ParentHash = b.ParentHash
 	jk.BlockNumber = new(big.Int).Set(b.Number)
 	jk.KvHash = b.kvHash
+	jk.Writes = b.writes
 	jk.Data = make([]journalData, 0)
 	if b.data.Size() != 0 {
 		itr := b.data.NewIterator(nil)
@@ -111,11 +121,18 @@ func (b *blockData) revert(snapshot int) {
 	for i := len(b.journal) - 1; i >= snapshot; i-- {
 		// Undo the changes made by the operation
 		en := b.journal[i]
+		if en.event {
+			b.events = b.events[:len(b.events)-1]
+			continue
+		}
 		if en.oldValNotExist {
 			b.data.Delete(en.key)
 		} else {
 			b.data.Put(en.key, en.oldVal)
 		}
+		if b.writes != nil {
+			b.writes = b.writes[:len(b.writes)-1]
+		}
 		b.kvHash = en.oldkvHash
 	}
 	b.journal = b.journal[:snapshot]
@@ -162,13 +179,24 @@ func (b *blockData) Write(key, val []byte) error {
 		return err
 	}
 	b.kvHash = generateKVHash(key, val, b.kvHash)
+	if b.writes != nil {
+		b.writes = append(b.writes, journalData{Key: common.CopyBytes(key), Value: common.CopyBytes(val)})
+	}
 
 	// append inserts a new modification entry to the end of the change journal.
 	b.journal = append(b.journal, entry)
 	return nil
 }
 
+// AddEvent appends an event to the block, the event is dropped if the change
+// journal is reverted before it.
+func (b *blockData) AddEvent(ev interface{}) {
+	b.events = append(b.events, ev)
+	b.journal = append(b.journal, journalEntry{event: true, oldkvHash: b.kvHash})
+}
+
 type journalEntry struct {
+	event          bool // The entry is an event added by AddEvent
 	key            []byte
 	newVal         []byte
 	oldValNotExist bool
@@ -23,7 +23,6 @@ import (
 
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
 
-	"github.com/syndtr/goleveldb/leveldb"
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
 	"github.com/AlayaNetwork/Alaya-Go/rlp"
@@ -84,8 +83,8 @@ func (c *current) GetHighest(copy bool) *CurrentHighest {
 	return c.highest
 }
 
-func (c *current) GetHighestFromDB(baseDB *leveldb.DB) (*CurrentHighest, error) {
-	hight, err := baseDB.Get([]byte(CurrentHighestBlock), nil)
+func (c *current) GetHighestFromDB(baseDB Backend) (*CurrentHighest, error) {
+	hight, err := baseDB.Get([]byte(CurrentHighestBlock))
 	if err != nil {
 		return nil, fmt.Errorf("get current highest block fail:%v", err)
 	}
@@ -114,7 +113,7 @@ func (c *current) Valid() error {
 }
 
 //the current highest  must not  greater than block chain current
-func (c *current) resetHighestByChainCurrentHeader(currentHead *types.Header, baseDB *leveldb.DB) error {
+func (c *current) resetHighestByChainCurrentHeader(currentHead *types.Header, baseDB Backend) error {
 	if c.base.Num.Cmp(currentHead.Number) > 0 {
 		return fmt.Errorf("base num %v can't be greater than currentHead Number %v", c.base.Num, currentHead.Number)
 	}
@@ -128,8 +127,8 @@ func (c *current) resetHighestByChainCurrentHeader(currentHead *types.Header, ba
 	return nil
 }
 
-func (c *current) loadFromBaseDB(baseDB *leveldb.DB) error {
-	base, err := baseDB.Get([]byte(CurrentBaseNum), nil)
+func (c *current) loadFromBaseDB(baseDB Backend) error {
+	base, err := baseDB.Get([]byte(CurrentBaseNum))
 	if err != nil {
 		return fmt.Errorf("get current base num fail:%v", err)
 	}
@@ -137,7 +136,7 @@ func (c *current) loadFromBaseDB(baseDB *leveldb.DB) error {
 	if err := rlp.DecodeBytes(base, c.base); err != nil {
 		return fmt.Errorf("decode current base num fail:%v", err)
 	}
-	hight, err := baseDB.Get([]byte(CurrentHighestBlock), nil)
+	hight, err := baseDB.Get([]byte(CurrentHighestBlock))
 	if err != nil {
 		return fmt.Errorf("get current highest block fail:%v", err)
 	}
@@ -148,7 +147,7 @@ func (c *current) loadFromBaseDB(baseDB *leveldb.DB) error {
 	return nil
 }
 
-func (c *current) increaseBase(commitNum uint64, baseDB *leveldb.DB) error {
+func (c *current) increaseBase(commitNum uint64, baseDB Backend) error {
 	c.base.Num.Add(c.base.Num, new(big.Int).SetUint64(commitNum))
 	if err := c.saveCurrentToBaseDB(CurrentBaseNum, baseDB, false); err != nil {
 		return err
@@ -162,8 +161,8 @@ func (c *current) increaseHighest(hash common.Hash) {
 	logger.Debug("increase current highest", "hash", hash, "num", c.highest.Num)
 }
 
-func (c *current) saveCurrentToBaseDB(currentType string, db *leveldb.DB, init bool) error {
-	batch := new(leveldb.Batch)
+func (c *current) saveCurrentToBaseDB(currentType string, db Backend, init bool) error {
+	batch := db.NewBatch()
 	switch currentType {
 	case CurrentHighestBlock:
 		height := c.EncodeHighest()
@@ -184,7 +183,7 @@ func (c *current) saveCurrentToBaseDB(currentType string, db *leveldb.DB, init b
 	} else {
 		logger.Debug("save current to baseDB", "height", c.highest, "base", c.base, "type", currentType)
 	}
-	if err := db.Write(batch, nil); err != nil {
+	if err := batch.Write(); err != nil {
 		return fmt.Errorf("write %v  to base db fail:%v", currentType, err)
 	}
 	return nil
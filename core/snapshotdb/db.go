@@ -25,7 +25,6 @@ import (
 
 	"github.com/AlayaNetwork/Alaya-Go/rlp"
 
-	"github.com/syndtr/goleveldb/leveldb"
 
 	"github.com/syndtr/goleveldb/leveldb/util"
 
@@ -69,8 +68,8 @@ func (s *snapshotDB) recover() error {
 	baseNum := s.current.GetBase(false).Num
 	highestNum := s.current.GetHighest(false).Num
 
-	walNeedDelete := leveldb.MakeBatch(0)
-	itr := s.baseDB.NewIterator(util.BytesPrefix([]byte(WalKeyPrefix)), nil)
+	walNeedDelete := s.baseDB.NewBatch()
+	itr := newRangeIterator(s.baseDB, util.BytesPrefix([]byte(WalKeyPrefix)))
 	defer itr.Release()
 
 	var sortBlockWals blockOrigin
@@ -132,7 +131,7 @@ func (s *snapshotDB) recover() error {
 			return err
 		}
 	}
-	if err := s.baseDB.Write(walNeedDelete, nil); err != nil {
+	if err := walNeedDelete.Write(); err != nil {
 		return err
 	}
 	return nil
@@ -143,6 +142,9 @@ func (s *snapshotDB) getUnRecognizedHash() common.Hash {
 }
 
 func (s *snapshotDB) put(hash common.Hash, key, value []byte) error {
+	if block, ok := s.getTraceBlock(hash); ok {
+		return block.write(key, value)
+	}
 	s.unCommit.Lock()
 	defer s.unCommit.Unlock()
 	block, ok := s.unCommit.blocks[hash]
@@ -0,0 +1,65 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+)
+
+// The events of a block are the notifications emitted by the plugins while
+// executing it. They follow the lifecycle of the block data: the events added
+// after a snapshot are dropped by RevertToSnapshot, the events of the
+// unRecognized block move to its hash on Flush, and they are released with the
+// block on Commit. They are never written to the journal or the baseDB and
+// don't change the kv hash.
+
+// AddEvent appends an event to the block, the block must be writable.
+func (s *snapshotDB) AddEvent(hash common.Hash, ev interface{}) error {
+	if block, ok := s.getTraceBlock(hash); ok {
+		block.AddEvent(ev)
+		return nil
+	}
+	s.unCommit.Lock()
+	defer s.unCommit.Unlock()
+	block, ok := s.unCommit.blocks[hash]
+	if !ok {
+		return fmt.Errorf("not find the block by hash:%v", hash.String())
+	}
+	if block.readOnly {
+		return errors.New("can't add event to read only block")
+	}
+	block.AddEvent(ev)
+	return nil
+}
+
+// Events returns the events of the block in the order they are added, nil if
+// the block is committed or not found.
+func (s *snapshotDB) Events(hash common.Hash) []interface{} {
+	if block, ok := s.getTraceBlock(hash); ok {
+		return append([]interface{}{}, block.events...)
+	}
+	s.unCommit.RLock()
+	defer s.unCommit.RUnlock()
+	block, ok := s.unCommit.blocks[hash]
+	if !ok || len(block.events) == 0 {
+		return nil
+	}
+	return append([]interface{}{}, block.events...)
+}
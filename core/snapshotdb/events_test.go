@@ -0,0 +1,72 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+)
+
+func TestSnapshotDB_Events(t *testing.T) {
+	ch := newTestchain(dbpath)
+	defer ch.clear()
+
+	ch.addBlock()
+	header := ch.CurrentHeader()
+	assert.Nil(t, ch.db.NewBlock(header.Number, header.ParentHash, common.ZeroHash))
+
+	assert.Nil(t, ch.db.AddEvent(common.ZeroHash, "a"))
+	snap := ch.db.Snapshot(common.ZeroHash)
+	assert.Nil(t, ch.db.Put(common.ZeroHash, []byte("k"), []byte("v")))
+	kvHash := ch.db.GetLastKVHash(common.ZeroHash)
+	assert.Nil(t, ch.db.AddEvent(common.ZeroHash, "b"))
+	assert.Equal(t, kvHash, ch.db.GetLastKVHash(common.ZeroHash), "events must not change the kv hash")
+	assert.Equal(t, []interface{}{"a", "b"}, ch.db.Events(common.ZeroHash))
+
+	// The reverted tx drops its events.
+	ch.db.RevertToSnapshot(common.ZeroHash, snap)
+	assert.Equal(t, []interface{}{"a"}, ch.db.Events(common.ZeroHash))
+	assert.Nil(t, ch.db.AddEvent(common.ZeroHash, "c"))
+
+	// The events move to the block hash on flush.
+	assert.Nil(t, ch.db.Flush(header.Hash(), header.Number))
+	assert.Nil(t, ch.db.Events(common.ZeroHash))
+	assert.Equal(t, []interface{}{"a", "c"}, ch.db.Events(header.Hash()))
+	assert.NotNil(t, ch.db.AddEvent(header.Hash(), "d"), "the flushed block is read only")
+
+	assert.Nil(t, ch.db.Commit(header.Hash()))
+	assert.Nil(t, ch.db.Events(header.Hash()))
+	assert.NotNil(t, ch.db.AddEvent(common.HexToHash("0x01"), "e"))
+}
+
+func TestSnapshotDB_TraceEvents(t *testing.T) {
+	ch := newTestchain(dbpath)
+	defer ch.clear()
+
+	ch.insert(true, generatekv(1), newBlockCommited)
+	header := ch.CurrentHeader()
+	hash, err := ch.db.NewTraceBlock(new(big.Int).Add(header.Number, common.Big1), header.Hash())
+	assert.Nil(t, err)
+	assert.Nil(t, ch.db.AddEvent(hash, "a"))
+	assert.Equal(t, []interface{}{"a"}, ch.db.Events(hash))
+	ch.db.DiscardTraceBlock(hash)
+	assert.Nil(t, ch.db.Events(hash))
+}
@@ -0,0 +1,261 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/ethdb"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+)
+
+// The export file is an RLP stream of the ExportHeader, the chunks of the base
+// DB key-values, an empty chunk, and the footer with the checksum. The checksum
+// is the KV hash chain of all the key-values, starting from the header hash.
+const (
+	ExportMagic   = "alaya-snapshotdb"
+	ExportVersion = 1
+)
+
+var (
+	// ErrExportChecksum is returned if the key-values imported don't match the
+	// checksum of the export file.
+	ErrExportChecksum = errors.New("snapshotdb export checksum mismatch")
+)
+
+// ExportHeader describes the base DB in the export file.
+type ExportHeader struct {
+	Magic    string
+	Version  uint32
+	Number   uint64      // The base num of the snapshotdb
+	Hash     common.Hash // The hash of the block at the base num
+	PPOSHash common.Hash // The last kv hash committed in the state of the block, see GetLastKVHash
+}
+
+// hash returns the hash of the header, it is the beginning of the checksum.
+func (h *ExportHeader) hash() common.Hash {
+	return rlpHash(h)
+}
+
+type exportFooter struct {
+	Count    uint64
+	Checksum common.Hash
+}
+
+// IsPPOSKey reports whether the key of the base DB is PPOS data. The keys of
+// current, the journal and the archive are local to the snapshotdb, they are
+// neither exported nor served to fast syncing peers.
+func IsPPOSKey(key []byte) bool {
+	switch string(key) {
+	case CurrentHighestBlock, CurrentBaseNum, CurrentSet, ArchiveBegin:
+		return false
+	}
+	return !bytes.HasPrefix(key, []byte(WalKeyPrefix)) && !bytes.HasPrefix(key, []byte(ArchiveKeyPrefix))
+}
+
+// ExportBaseDB writes the base DB to w, the header number must be the base num.
+func ExportBaseDB(db DB, w io.Writer, header *ExportHeader) (uint64, error) {
+	header.Magic, header.Version = ExportMagic, ExportVersion
+	if err := rlp.Encode(w, header); err != nil {
+		return 0, err
+	}
+	chunk := make([][2][]byte, 0, kvLIMIT)
+	footer, err := walkExportKeys(db, header, func(k, v []byte) error {
+		chunk = append(chunk, [2][]byte{common.CopyBytes(k), common.CopyBytes(v)})
+		if len(chunk) == kvLIMIT {
+			if err := rlp.Encode(w, chunk); err != nil {
+				return err
+			}
+			chunk = chunk[:0]
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(chunk) > 0 {
+		if err := rlp.Encode(w, chunk); err != nil {
+			return 0, err
+		}
+	}
+	if err := rlp.Encode(w, [][2][]byte{}); err != nil {
+		return 0, err
+	}
+	return footer.Count, rlp.Encode(w, footer)
+}
+
+// walkExportKeys calls fn with the exported key-values of the base DB and
+// returns their count and KV hash, the base num must be the header number.
+func walkExportKeys(db DB, header *ExportHeader, fn func(k, v []byte) error) (*exportFooter, error) {
+	footer := &exportFooter{Checksum: header.hash()}
+	err := db.WalkBaseDB(nil, func(num *big.Int, iter Iterator) error {
+		if num.Uint64() != header.Number {
+			return fmt.Errorf("the base num %d is not the block %d to export", num, header.Number)
+		}
+		return footer.add(iter, fn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return footer, nil
+}
+
+// add counts the PPOS key-values of the iterator into the footer and calls fn
+// with them.
+func (f *exportFooter) add(itr ethdb.Iterator, fn func(k, v []byte) error) error {
+	for itr.Next() {
+		if !IsPPOSKey(itr.Key()) {
+			continue
+		}
+		f.Checksum = generateKVHash(itr.Key(), itr.Value(), f.Checksum)
+		f.Count++
+		if fn != nil {
+			if err := fn(itr.Key(), itr.Value()); err != nil {
+				return err
+			}
+		}
+	}
+	return itr.Error()
+}
+
+// ImportBaseDB replaces the content of db with the base DB read from r. The
+// header is checked by verify before anything is written, verify should compare
+// the block and the PPOS hash of the header with the local chain. The key-values
+// are staged in the empty backend tmp first, db is only replaced once their KV
+// hash recomputed from tmp matches the checksum of the file.
+//
+// The checksum and the PPOS hash are both taken from the file: the PPOS hash is
+// the kv hash of the writes of the last block, not a hash of the base DB, so the
+// content of the base DB can't be checked against the chain. The checksum only
+// detects corrupted files, the file must come from a trusted source.
+func ImportBaseDB(db DB, tmp Backend, r io.Reader, verify func(header *ExportHeader) error) (*ExportHeader, error) {
+	stream := rlp.NewStream(r, 0)
+	header := new(ExportHeader)
+	if err := stream.Decode(header); err != nil {
+		return nil, fmt.Errorf("decode export header fail:%v", err)
+	}
+	if header.Magic != ExportMagic {
+		return nil, errors.New("not a snapshotdb export file")
+	}
+	if header.Version != ExportVersion {
+		return nil, fmt.Errorf("unsupported snapshotdb export version %d", header.Version)
+	}
+	if verify != nil {
+		if err := verify(header); err != nil {
+			return nil, err
+		}
+	}
+	if err := stageBaseDB(tmp, stream, header); err != nil {
+		return nil, err
+	}
+
+	if err := db.SetEmpty(); err != nil {
+		return nil, err
+	}
+	num := new(big.Int).SetUint64(header.Number)
+	if err := db.SetCurrent(header.Hash, *num, *num); err != nil {
+		return nil, err
+	}
+	if err := copyBaseDB(db, tmp, header); err != nil {
+		if emptyErr := db.SetEmpty(); emptyErr != nil {
+			logger.Error("set snapshotdb empty fail", "err", emptyErr)
+		}
+		return nil, err
+	}
+	return header, nil
+}
+
+// stageBaseDB writes the key-values of the stream to tmp and checks them
+// against the footer of the stream.
+func stageBaseDB(tmp Backend, stream *rlp.Stream, header *ExportHeader) error {
+	for {
+		var chunk [][2][]byte
+		if err := stream.Decode(&chunk); err != nil {
+			return fmt.Errorf("decode export chunk fail:%v", err)
+		}
+		if len(chunk) == 0 {
+			break
+		}
+		batch := tmp.NewBatch()
+		for _, kv := range chunk {
+			if !IsPPOSKey(kv[0]) {
+				return fmt.Errorf("the key %x is not a ppos key", kv[0])
+			}
+			if err := batch.Put(kv[0], kv[1]); err != nil {
+				return err
+			}
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	var footer exportFooter
+	if err := stream.Decode(&footer); err != nil {
+		return fmt.Errorf("decode export footer fail:%v", err)
+	}
+	staged := &exportFooter{Checksum: header.hash()}
+	itr := tmp.NewIterator(nil, nil)
+	defer itr.Release()
+	if err := staged.add(itr, nil); err != nil {
+		return err
+	}
+	if *staged != footer {
+		return ErrExportChecksum
+	}
+	return nil
+}
+
+// copyBaseDB copies the staged key-values of tmp into the base DB of db, and
+// checks the KV hash of the base DB matches the one of tmp.
+func copyBaseDB(db DB, tmp Backend, header *ExportHeader) error {
+	var (
+		staged = &exportFooter{Checksum: header.hash()}
+		chunk  = make([][2][]byte, 0, kvLIMIT)
+	)
+	itr := tmp.NewIterator(nil, nil)
+	defer itr.Release()
+	err := staged.add(itr, func(k, v []byte) error {
+		chunk = append(chunk, [2][]byte{common.CopyBytes(k), common.CopyBytes(v)})
+		if len(chunk) < kvLIMIT {
+			return nil
+		}
+		err := db.WriteBaseDB(chunk)
+		chunk = chunk[:0]
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(chunk) > 0 {
+		if err := db.WriteBaseDB(chunk); err != nil {
+			return err
+		}
+	}
+	imported, err := walkExportKeys(db, header, nil)
+	if err != nil {
+		return err
+	}
+	if *imported != *staged {
+		return ErrExportChecksum
+	}
+	return nil
+}
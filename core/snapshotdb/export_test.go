@@ -0,0 +1,130 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+)
+
+func TestExportImportBaseDB(t *testing.T) {
+	// The db is emptied by removing its directory, so it must be a real one
+	// even without the test build tag
+	dir, err := ioutil.TempDir("", "snapshotdb-export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ch := newTestchain(dir)
+	defer ch.clear()
+
+	base := generatekv(kvLIMIT + 10)
+	for _, kvs := range []kvs{base[:kvLIMIT], base[kvLIMIT:]} {
+		if err := ch.insert(true, kvs, newBlockBaseDB); err != nil {
+			t.Fatal(err)
+		}
+	}
+	head := ch.CurrentHeader()
+	if ch.db.current.GetBase(false).Num.Cmp(head.Number) != 0 {
+		t.Fatal("the blocks must be written to baseDB")
+	}
+
+	var buf bytes.Buffer
+	header := &ExportHeader{Number: head.Number.Uint64(), Hash: head.Hash(), PPOSHash: common.HexToHash("0x01")}
+	count, err := ExportBaseDB(ch.db, &buf, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != uint64(len(base)) {
+		t.Fatalf("want %d keys exported, have %d", len(base), count)
+	}
+	if err := ch.insert(true, kvs{kv{key: []byte("afterExport"), value: []byte("v")}}, newBlockBaseDB); err != nil {
+		t.Fatal(err)
+	}
+	newStage := func() Backend {
+		tmp, err := OpenBackend(BackendMemory, "", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tmp
+	}
+
+	t.Run("verify fail", func(t *testing.T) {
+		errVerify := errors.New("verify fail")
+		if _, err := ImportBaseDB(ch.db, newStage(), bytes.NewReader(buf.Bytes()), func(*ExportHeader) error { return errVerify }); err != errVerify {
+			t.Fatalf("want %v, have %v", errVerify, err)
+		}
+		if _, err := ch.db.GetBaseDB([]byte("afterExport")); err != nil {
+			t.Fatal("the db must not be changed if the header is not verified", err)
+		}
+	})
+	t.Run("checksum mismatch", func(t *testing.T) {
+		data := common.CopyBytes(buf.Bytes())
+		// change a value of the last chunk
+		i := bytes.LastIndex(data, base[len(base)-1].value)
+		data[i] ^= 0xff
+		if _, err := ImportBaseDB(ch.db, newStage(), bytes.NewReader(data), nil); err != ErrExportChecksum {
+			t.Fatalf("want %v, have %v", ErrExportChecksum, err)
+		}
+		if _, err := ch.db.GetBaseDB([]byte("afterExport")); err != nil {
+			t.Fatal("the db must not be changed if the checksum mismatch", err)
+		}
+	})
+	t.Run("local key", func(t *testing.T) {
+		var data bytes.Buffer
+		kvs := [][2][]byte{{[]byte(CurrentSet), []byte("v")}}
+		footer := exportFooter{Count: 1, Checksum: generateKVHash(kvs[0][0], kvs[0][1], header.hash())}
+		for _, item := range []interface{}{header, kvs, [][2][]byte{}, &footer} {
+			if err := rlp.Encode(&data, item); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := ImportBaseDB(ch.db, newStage(), &data, nil); err == nil {
+			t.Fatal("the keys local to the snapshotdb must not be imported")
+		}
+		if _, err := ch.db.GetBaseDB([]byte("afterExport")); err != nil {
+			t.Fatal("the db must not be changed if a key is rejected", err)
+		}
+	})
+	t.Run("import", func(t *testing.T) {
+		imported, err := ImportBaseDB(ch.db, newStage(), bytes.NewReader(buf.Bytes()), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if imported.Hash != head.Hash() || imported.PPOSHash != header.PPOSHash {
+			t.Fatalf("header not right: %+v", imported)
+		}
+		db := ch.db
+		if db.current.GetBase(false).Num.Cmp(head.Number) != 0 || db.current.GetHighest(false).Hash != head.Hash() {
+			t.Fatalf("current not right: %v %v", db.current.GetBase(false).Num, db.current.GetHighest(false).Hash)
+		}
+		for _, kv := range base {
+			if v, err := db.GetBaseDB(kv.key); err != nil || !bytes.Equal(v, kv.value) {
+				t.Fatalf("key %x not imported: %v", kv.key, err)
+			}
+		}
+		if _, err := db.GetBaseDB([]byte("afterExport")); err != ErrNotFound {
+			t.Fatal("the key written after export must be removed", err)
+		}
+	})
+}
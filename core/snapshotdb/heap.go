@@ -21,7 +21,7 @@ import (
 	"bytes"
 	"container/heap"
 
-	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/AlayaNetwork/Alaya-Go/ethdb"
 )
 
 func newRankingHeap(hepNum int) *rankingHeap {
@@ -64,7 +64,7 @@ func (r *rankingHeap) findHandledKey(key []byte) bool {
 // except baseDB, every block must range.
 // find key, continue ,handle key add to HandledKey.
 // the key must less than the top.
-func (r *rankingHeap) itr2Heap(itr iterator.Iterator, baseDB, deepCopy bool) {
+func (r *rankingHeap) itr2Heap(itr ethdb.Iterator, baseDB, deepCopy bool) {
 	unlimited := r.hepMaxNum <= 0
 	if unlimited {
 		for itr.Next() {
@@ -17,7 +17,11 @@
 
 package snapshotdb
 
-import "github.com/AlayaNetwork/Alaya-Go/common"
+import (
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+)
 
 // Putter wraps the database write operation supported by both batches and regular databases.
 type Putter interface {
@@ -29,6 +33,31 @@ type Deleter interface {
 	Delete(hash common.Hash, key []byte) error
 }
 
+// Iterator iterates over the key-value pairs of the snapshotdb in key order.
+// The key and the value must not be kept after the next call of Next, copy
+// them instead. The iterator must be released after use.
+type Iterator interface {
+	Next() bool
+	Valid() bool
+	Key() []byte
+	Value() []byte
+	Error() error
+	Release()
+}
+
+// Range is the key range [Start, Limit) of an iteration, a nil Limit means
+// the range has no upper bound.
+type Range struct {
+	Start []byte
+	Limit []byte
+}
+
+// BytesPrefix returns the key range of the keys with the given prefix.
+func BytesPrefix(prefix []byte) *Range {
+	r := util.BytesPrefix(prefix)
+	return &Range{Start: r.Start, Limit: r.Limit}
+}
+
 type Writer interface {
 	Putter
 	Deleter
@@ -43,12 +43,15 @@ type blockWal struct {
 	BlockNumber *big.Int `rlp:"nil"`
 	KvHash      common.Hash
 	Data        []journalData
+	Writes      []journalData `rlp:"optional"` // The writes in order, only recorded in archive mode
 }
 
 func (s *snapshotDB) loopWriteWal() {
+	// A closed db reopened by Instance replaces the channels, keep the ones of this loop
+	walCh, walExitCh := s.walCh, s.walExitCh
 	for {
 		select {
-		case block := <-s.walCh:
+		case block := <-walCh:
 			if err := s.writeWal(block); err != nil {
 				logger.Error("asynchronous write Journal fail", "err", err, "block", block.Number, "hash", block.BlockHash.String())
 				s.dbError = err
@@ -63,9 +66,9 @@ func (s *snapshotDB) loopWriteWal() {
 				continue
 			}
 			s.walSync.Done()
-		case <-s.walExitCh:
+		case <-walExitCh:
 			logger.Info("loopWriteWal exist")
-			close(s.walCh)
+			close(walCh)
 			return
 		}
 	}
@@ -77,5 +80,5 @@ func (s *snapshotDB) writeBlockToWalAsynchronous(block *blockData) {
 }
 
 func (s *snapshotDB) writeWal(block *blockData) error {
-	return s.baseDB.Put(block.BlockKey(), block.BlockVal(), nil)
+	return s.baseDB.Put(block.BlockKey(), block.BlockVal())
 }
@@ -27,14 +27,9 @@ import (
 
 	"github.com/AlayaNetwork/Alaya-Go/metrics"
 
-	"github.com/syndtr/goleveldb/leveldb/filter"
-	"github.com/syndtr/goleveldb/leveldb/opt"
-
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
 
 	"github.com/robfig/cron"
-	"github.com/syndtr/goleveldb/leveldb"
-	leveldbError "github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/memdb"
 	"github.com/syndtr/goleveldb/leveldb/util"
@@ -76,7 +71,7 @@ type DB interface {
 	Del(hash common.Hash, key []byte) error
 	Has(hash common.Hash, key []byte) (bool, error)
 	Flush(hash common.Hash, blocknumber *big.Int) error
-	Ranking(hash common.Hash, key []byte, ranges int) iterator.Iterator
+	Ranking(hash common.Hash, key []byte, ranges int) Iterator
 	//notice , iter.key or iter.value is slice，if you want to save it to a slice,you can use copy
 	// container:=make([]byte,0)
 	// for iter.next{
@@ -85,7 +80,7 @@ type DB interface {
 	// 	container = append(container,tosave)
 	// }
 	//
-	WalkBaseDB(slice *util.Range, f func(num *big.Int, iter iterator.Iterator) error) error
+	WalkBaseDB(slice *Range, f func(num *big.Int, iter Iterator) error) error
 	Commit(hash common.Hash) error
 
 	// Clear close db , remove all db file
@@ -102,6 +97,16 @@ type DB interface {
 	//ues to Revert failed tx
 	RevertToSnapshot(hash common.Hash, revid int)
 	Snapshot(hash common.Hash) int
+
+	//use to re-execute the txs of a block without changing the db
+	NewTraceBlock(blockNumber *big.Int, parentHash common.Hash) (common.Hash, error)
+	FlattenTraceBlock(hash common.Hash) (common.Hash, error)
+	DiscardTraceBlock(hash common.Hash)
+	SetTraceHook(hash common.Hash, hook TraceHook) error
+
+	//use to collect the events emitted while executing a block
+	AddEvent(hash common.Hash, ev interface{}) error
+	Events(hash common.Hash) []interface{}
 }
 
 type BaseDB interface {
@@ -142,7 +147,7 @@ type snapshotDB struct {
 
 	current *current
 
-	baseDB *leveldb.DB
+	baseDB Backend
 
 	unCommit *unCommitBlocks
 
@@ -155,6 +160,17 @@ type snapshotDB struct {
 
 	corn *cron.Cron
 
+	// archiveBegin is the first block archived, nil if archive mode is disabled
+	archiveBegin *big.Int
+
+	// recordWrites keeps the writes of the new blocks in order in their
+	// journals, so that verify can recompute their kv hashes. It doubles the
+	// size of the journals and is only enabled in archive mode.
+	recordWrites bool
+
+	// traces holds the trace blocks, see NewTraceBlock
+	traces sync.Map
+
 	closed bool
 
 	dbError error
@@ -202,31 +218,10 @@ func Instance() DB {
 	return dbInstance
 }
 
-func openBaseDB(snapshotDBPath string, cache int, handles int) (*leveldb.DB, error) {
-	leveldbPath := getBaseDBPath(snapshotDBPath)
-	baseDB, err := leveldb.OpenFile(leveldbPath, &opt.Options{
-		OpenFilesCacheCapacity: handles,
-		BlockCacheCapacity:     cache / 2 * opt.MiB,
-		WriteBuffer:            cache / 4 * opt.MiB, // Two of these are used internally
-		Filter:                 filter.NewBloomFilter(10),
-	})
-	if err != nil {
-		if _, corrupted := err.(*leveldbError.ErrCorrupted); corrupted {
-			baseDB, err = leveldb.RecoverFile(leveldbPath, nil)
-			if err != nil {
-				return nil, fmt.Errorf("[SnapshotDB.recover]RecoverFile baseDB fail:%v", err)
-			}
-		} else {
-			return nil, err
-		}
-	}
-	return baseDB, nil
-}
-
 func open(path string, cache int, handles int, baseOnly bool) (*snapshotDB, error) {
-	logger.Info("open snapshot db Allocated cache and file handles", "cache", cache, "handles", handles, "baseDB", baseOnly)
+	logger.Info("open snapshot db Allocated cache and file handles", "cache", cache, "handles", handles, "baseDB", baseOnly, "backend", dbBackend)
 
-	baseDB, err := openBaseDB(path, cache, handles)
+	baseDB, err := OpenBackend(dbBackend, path, cache, handles)
 	if err != nil {
 		return nil, err
 	}
@@ -241,12 +236,13 @@ func open(path string, cache int, handles int, baseOnly bool) (*snapshotDB, erro
 		snapshotLockC: snapshotUnLock,
 		walCh:         make(chan *blockData, 2),
 		walExitCh:     make(chan struct{}),
+		recordWrites:  archiveMode,
 	}
 	if baseOnly {
 		return db, nil
 	}
 
-	_, getCurrentError := baseDB.Get([]byte(CurrentSet), nil)
+	_, getCurrentError := baseDB.Get([]byte(CurrentSet))
 	if getCurrentError == nil {
 		logger.Info("begin recover", "path", path)
 		if err := db.loadCurrent(); err != nil {
@@ -257,7 +253,7 @@ func open(path string, cache int, handles int, baseOnly bool) (*snapshotDB, erro
 			logger.Error("recover db fail:", "error", err)
 			return nil, err
 		}
-	} else if getCurrentError == leveldb.ErrNotFound {
+	} else if getCurrentError == ErrNotFound {
 		logger.Info("begin init db current", "path", path)
 		if err := db.SetCurrent(common.ZeroHash, *common.Big0, *common.Big0); err != nil {
 			return nil, err
@@ -265,6 +261,11 @@ func open(path string, cache int, handles int, baseOnly bool) (*snapshotDB, erro
 	} else {
 		return nil, getCurrentError
 	}
+	if archiveMode {
+		if err := db.loadArchiveBegin(); err != nil {
+			return nil, err
+		}
+	}
 	return db, nil
 }
 
@@ -292,6 +293,8 @@ func copyDB(from, to *snapshotDB) {
 	to.snapshotLockC = from.snapshotLockC
 	to.walExitCh = from.walExitCh
 	to.walCh = from.walCh
+	to.archiveBegin = from.archiveBegin
+	to.recordWrites = from.recordWrites
 }
 
 func initDB(path string, sdb *snapshotDB) error {
@@ -331,14 +334,13 @@ func (s *snapshotDB) cornStart() error {
 }
 
 func (s *snapshotDB) WriteBaseDB(kvs [][2][]byte) error {
-	batch := new(leveldb.Batch)
+	batch := s.baseDB.NewBatch()
 	for _, value := range kvs {
-		batch.Put(value[0], value[1])
-	}
-	if err := s.baseDB.Write(batch, nil); err != nil {
-		return err
+		if err := batch.Put(value[0], value[1]); err != nil {
+			return err
+		}
 	}
-	return nil
+	return batch.Write()
 }
 
 func (s *snapshotDB) SetCurrent(highestHash common.Hash, base, height big.Int) error {
@@ -346,6 +348,12 @@ func (s *snapshotDB) SetCurrent(highestHash common.Hash, base, height big.Int) e
 	if err := current.saveCurrentToBaseDB(CurrentAll, s.baseDB, true); err != nil {
 		return err
 	}
+	// the base is moved without writing blocks to baseDB(fast sync), so there is no archive below it
+	if s.archiveBegin != nil && s.current != nil && base.Cmp(s.current.GetBase(false).Num) > 0 {
+		if err := s.saveArchiveBegin(&base); err != nil {
+			return err
+		}
+	}
 	s.current = current
 	logger.Debug("SetCurrent", "base", s.current.base, "height", s.current.highest)
 	return nil
@@ -378,7 +386,7 @@ func (s *snapshotDB) SetEmpty() error {
 }
 
 func (s *snapshotDB) PutBaseDB(key, value []byte) error {
-	err := s.baseDB.Put(key, value, nil)
+	err := s.baseDB.Put(key, value)
 	if err != nil {
 		return err
 	}
@@ -386,7 +394,7 @@ func (s *snapshotDB) PutBaseDB(key, value []byte) error {
 }
 
 func (s *snapshotDB) DelBaseDB(key []byte) error {
-	err := s.baseDB.Delete(key, nil)
+	err := s.baseDB.Delete(key)
 	if err != nil {
 		return err
 	}
@@ -397,6 +405,9 @@ func (s *snapshotDB) DelBaseDB(key []byte) error {
 // if hash is nil ,get unRecognized block lastkv hash,
 // else, get recognized block lastkv  hash
 func (s *snapshotDB) GetLastKVHash(blockHash common.Hash) []byte {
+	if block, ok := s.getTraceBlock(blockHash); ok {
+		return block.kvHash.Bytes()
+	}
 	block := s.unCommit.Get(blockHash)
 	if block == nil {
 		return nil
@@ -511,7 +522,13 @@ func (s *snapshotDB) findToWrite() int {
 }
 
 func (s *snapshotDB) writeToBasedb(commitNum int) error {
-	batch := new(leveldb.Batch)
+	batch := s.baseDB.NewBatch()
+	if s.archiveBegin != nil {
+		if err := s.writeArchive(batch, commitNum); err != nil {
+			logger.Error("write archive fail", "err", err)
+			return errors.New("[SnapshotDB]write archive fail:" + err.Error())
+		}
+	}
 	for i := 0; i < commitNum; i++ {
 		itr := s.committed[i].data.NewIterator(nil)
 		for itr.Next() {
@@ -525,7 +542,7 @@ func (s *snapshotDB) writeToBasedb(commitNum int) error {
 		itr.Release()
 	}
 	logger.Debug("write to basedb", "from", s.committed[0].Number, "to", s.committed[commitNum-1].Number, "len", len(s.committed), "commitNum", commitNum)
-	if err := s.baseDB.Write(batch, nil); err != nil {
+	if err := batch.Write(); err != nil {
 		logger.Error("write to baseDB fail", "err", err)
 		return errors.New("[SnapshotDB]write to baseDB fail:" + err.Error())
 	}
@@ -551,6 +568,9 @@ func (s *snapshotDB) NewBlock(blockNumber *big.Int, parentHash common.Hash, hash
 	block.data = memdb.New(DefaultComparer, 100)
 	block.journal = make([]journalEntry, 0)
 	block.validRevisions = make([]revision, 0)
+	if s.recordWrites {
+		block.writes = make([]journalData, 0)
+	}
 
 	s.unCommit.Set(hash, block)
 	logger.Info("NewBlock", "num", block.Number, "hash", hash, "parent", parentHash)
@@ -558,6 +578,10 @@ func (s *snapshotDB) NewBlock(blockNumber *big.Int, parentHash common.Hash, hash
 }
 
 func (s *snapshotDB) RevertToSnapshot(hash common.Hash, revid int) {
+	if block, ok := s.getTraceBlock(hash); ok {
+		block.RevertToSnapshot(revid)
+		return
+	}
 	s.unCommit.Lock()
 	defer s.unCommit.Unlock()
 	block, ok := s.unCommit.blocks[hash]
@@ -566,6 +590,9 @@ func (s *snapshotDB) RevertToSnapshot(hash common.Hash, revid int) {
 	}
 }
 func (s *snapshotDB) Snapshot(hash common.Hash) int {
+	if block, ok := s.getTraceBlock(hash); ok {
+		return block.Snapshot()
+	}
 	s.unCommit.Lock()
 	defer s.unCommit.Unlock()
 	block, ok := s.unCommit.blocks[hash]
@@ -629,7 +656,15 @@ func (s *snapshotDB) getFromCommit(key []byte) ([]byte, error) {
 // Get get key,val from  snapshotDB
 // if hash is nil, unRecognizedBlockData > RecognizedBlockData > CommittedBlockData > baseDB
 // if hash is not nil,it will find from the chain, RecognizedBlockData > CommittedBlockData > baseDB
+// if archive mode is enabled and hash is a history block, it will find the value at the block
+// if hash is a trace block, it will find from the trace block and then its parent
 func (s *snapshotDB) Get(hash common.Hash, key []byte) ([]byte, error) {
+	if block, ok := s.getTraceBlock(hash); ok {
+		return s.getTrace(block, key)
+	}
+	if num, ok := s.historyNum(hash); ok {
+		return s.getHistory(num, key)
+	}
 	v, err := s.getFromUnCommit(hash, key)
 	if err != nil && err != ErrNotFound {
 		return nil, err
@@ -654,13 +689,7 @@ func (s *snapshotDB) Get(hash common.Hash, key []byte) ([]byte, error) {
 }
 
 func (s *snapshotDB) GetBaseDB(key []byte) ([]byte, error) {
-	if v, err := s.baseDB.Get(key, nil); err == nil {
-		return v, nil
-	} else if err != leveldb.ErrNotFound {
-		return nil, err
-	} else {
-		return nil, ErrNotFound
-	}
+	return s.baseDB.Get(key)
 }
 
 //Has check the key is exist in chain
@@ -782,14 +811,19 @@ func (s *snapshotDB) BaseNum() (*big.Int, error) {
 // is a frozen snapshot of a DB state at a particular point in time. The
 // content of snapshot are guaranteed to be consistent.
 // slice
-func (s *snapshotDB) WalkBaseDB(slice *util.Range, f func(num *big.Int, iter iterator.Iterator) error) error {
+func (s *snapshotDB) WalkBaseDB(slice *Range, f func(num *big.Int, iter Iterator) error) error {
 	logger.Debug("begin walkbase db")
 	snapshot, err := s.baseDB.GetSnapshot()
 	if err != nil {
 		return errors.New("[snapshotdb] get snapshot fail:" + err.Error())
 	}
 	defer snapshot.Release()
-	t := snapshot.NewIterator(slice, nil)
+	var t Iterator
+	if slice == nil {
+		t = newBackendIterator(snapshot.NewIterator(nil, nil))
+	} else {
+		t = newBackendIterator(snapshot.NewIterator(slice.Start, slice.Limit))
+	}
 	defer func() {
 		logger.Debug("WalkBaseDB release ")
 		t.Release()
@@ -802,9 +836,16 @@ func (s *snapshotDB) Clear() error {
 	if s == nil {
 		return errors.New("snapshotDB is nil")
 	}
+	destroyer, destroy := s.baseDB.(backendDestroyer)
 	if err := s.Close(); err != nil {
 		return err
 	}
+	// the data outside the directory is removed after the wal is written
+	if destroy {
+		if err := destroyer.Destroy(); err != nil {
+			return err
+		}
+	}
 	logger.Info("begin clear file", "path", s.path)
 	if err := os.RemoveAll(s.path); err != nil {
 		return err
@@ -816,10 +857,15 @@ func (s *snapshotDB) Clear() error {
 // the key range that satisfy the given prefix
 // the hash means from  unRecognized or recognized
 // return iterates ,iterates over a DB's key/value pairs in key order.
-// The iterator must be released after use, by calling Release method.t
-// Also read Iterator documentation of the leveldb/iterator package.
-func (s *snapshotDB) Ranking(hash common.Hash, key []byte, rangeNumber int) iterator.Iterator {
+// The iterator must be released after use, by calling Release method.
+func (s *snapshotDB) Ranking(hash common.Hash, key []byte, rangeNumber int) Iterator {
+	if block, ok := s.getTraceBlock(hash); ok {
+		return s.rankingTrace(block, key, rangeNumber)
+	}
 	prefix := util.BytesPrefix(key)
+	if num, ok := s.historyNum(hash); ok {
+		return s.rankingHistory(num, prefix, rangeNumber)
+	}
 	var itrs []iterator.Iterator
 	var parentHash common.Hash
 	parentHash = hash
@@ -848,7 +894,7 @@ func (s *snapshotDB) Ranking(hash common.Hash, key []byte, rangeNumber int) iter
 		rankingHeap.itr2Heap(itrs[i], false, false)
 	}
 	//put baseDB itr to heap
-	itr := s.baseDB.NewIterator(prefix, nil)
+	itr := newRangeIterator(s.baseDB, prefix)
 	rankingHeap.itr2Heap(itr, true, true)
 	//generate memdb Iterator
 	mdb := memdb.New(DefaultComparer, rangeNumber)
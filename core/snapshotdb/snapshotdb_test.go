@@ -30,9 +30,6 @@ import (
 
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
 
-	"github.com/syndtr/goleveldb/leveldb/iterator"
-	"github.com/syndtr/goleveldb/leveldb/util"
-
 	"github.com/AlayaNetwork/Alaya-Go/common"
 )
 
@@ -744,7 +741,7 @@ func TestSnapshotDB_Ranking3(t *testing.T) {
 func TestSnapshotDB_WalkBaseDB(t *testing.T) {
 	ch := newTestchain(dbpath)
 	defer ch.clear()
-	var prefix = util.BytesPrefix([]byte("a"))
+	var prefix = BytesPrefix([]byte("a"))
 
 	kvsWithA := generatekvWithPrefix(100, "a")
 	if err := ch.insert(true, kvsWithA, newBlockBaseDB); err != nil {
@@ -755,7 +752,7 @@ func TestSnapshotDB_WalkBaseDB(t *testing.T) {
 	}
 	t.Run("kv should compare", func(t *testing.T) {
 		var kvGetFromWalk kvs
-		f := func(num *big.Int, iter iterator.Iterator) error {
+		f := func(num *big.Int, iter Iterator) error {
 			if num.Int64() != 2 {
 				return fmt.Errorf("basenum is wrong:%v,should be 2", num)
 			}
@@ -785,7 +782,7 @@ func TestSnapshotDB_WalkBaseDB(t *testing.T) {
 			time.Sleep(time.Millisecond * 500)
 			ch.db.snapshotLockC = snapshotUnLock
 		}()
-		f2 := func(num *big.Int, iter iterator.Iterator) error {
+		f2 := func(num *big.Int, iter Iterator) error {
 			return nil
 		}
 		var wg sync.WaitGroup
@@ -932,7 +929,7 @@ func TestSnapshotDB_Compaction222222(t *testing.T) {
 			t.Error("must be 14:", len(ch.db.committed))
 		}
 		for _, kv := range kvs1 {
-			v, err := ch.db.baseDB.Get(kv.key, nil)
+			v, err := ch.db.baseDB.Get(kv.key)
 			if err != nil {
 				t.Error(err)
 			}
@@ -954,7 +951,7 @@ func TestSnapshotDB_Compaction222222(t *testing.T) {
 		}
 		for _, kvs := range [][]kv{kvs2, kvs3, kvs4} {
 			for _, kv := range kvs {
-				v, err := ch.db.baseDB.Get(kv.key, nil)
+				v, err := ch.db.baseDB.Get(kv.key)
 				if err != nil {
 					t.Error(err)
 				}
@@ -978,6 +975,203 @@ func TestSnapshotDB_Compaction222222(t *testing.T) {
 	})
 }
 
+func TestSnapshotDB_Archive(t *testing.T) {
+	SetDBArchive(true)
+	defer SetDBArchive(false)
+	ch := newTestchain(dbpath)
+	defer ch.clear()
+	var (
+		key    = []byte("rankingKey")
+		keyExt = []byte("rankingKeyExt")
+		blocks = []kvs{
+			{kv{key, []byte("v1")}, kv{keyExt, []byte("e1")}},
+			{kv{key, []byte("v2")}},
+			{kv{key, nil}, kv{keyExt, []byte("e3")}},
+			{kv{key, []byte("v4")}},
+		}
+		want = [][]byte{[]byte("v1"), []byte("v2"), nil, []byte("v4"), []byte("v5")}
+	)
+	for _, kvs := range blocks {
+		if err := ch.insert(true, kvs, newBlockBaseDB); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ch.insert(true, kvs{kv{key, []byte("v5")}}, newBlockCommited); err != nil {
+		t.Fatal(err)
+	}
+	ch.db.walSync.Wait()
+	if ch.db.current.base.Num.Uint64() != 4 {
+		t.Fatal("base num must be 4", ch.db.current.base.Num)
+	}
+
+	t.Run("get history value", func(t *testing.T) {
+		for i, header := range ch.h {
+			v, err := ch.db.Get(header.Hash(), key)
+			if want[i] == nil {
+				if err != ErrNotFound {
+					t.Errorf("block %d: want not found, have %x %v", header.Number, v, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("block %d: %v", header.Number, err)
+			}
+			if !bytes.Equal(v, want[i]) {
+				t.Errorf("block %d: want %s, have %s", header.Number, want[i], v)
+			}
+		}
+	})
+	t.Run("ranking history value", func(t *testing.T) {
+		itr := ch.db.Ranking(ch.h[1].Hash(), []byte("rankingKey"), 0)
+		defer itr.Release()
+		var values []string
+		for itr.Next() {
+			values = append(values, string(itr.Value()))
+		}
+		if len(values) != 2 || values[0] != "v2" || values[1] != "e1" {
+			t.Errorf("ranking history value not right:%v", values)
+		}
+	})
+	t.Run("get value before archive", func(t *testing.T) {
+		ch.db.archiveBegin = big.NewInt(2)
+		defer func() { ch.db.archiveBegin = new(big.Int) }()
+		if _, err := ch.db.Get(ch.h[0].Hash(), key); err != ErrArchiveNotAvailable {
+			t.Error("must be ErrArchiveNotAvailable", err)
+		}
+	})
+}
+
+func TestSnapshotDB_TraceBlock(t *testing.T) {
+	ch := newTestchain(dbpath)
+	defer ch.clear()
+	var (
+		key    = []byte("rankingKey")
+		keyExt = []byte("rankingKeyExt")
+	)
+	if err := ch.insert(true, kvs{kv{key, []byte("v1")}, kv{keyExt, []byte("e1")}}, newBlockBaseDB); err != nil {
+		t.Fatal(err)
+	}
+	if err := ch.insert(true, kvs{kv{key, []byte("v2")}}, newBlockRecognizedDirect); err != nil {
+		t.Fatal(err)
+	}
+	parent := ch.CurrentHeader()
+	number := new(big.Int).Add(parent.Number, common.Big1)
+
+	if _, err := ch.db.NewTraceBlock(number, generateHash("unknown")); err != ErrArchiveNotAvailable {
+		t.Fatal("must be ErrArchiveNotAvailable", err)
+	}
+	if _, err := ch.db.NewTraceBlock(parent.Number, parent.ParentHash); err != nil {
+		t.Fatal("the highest committed block must be traceable", err)
+	}
+	hash, err := ch.db.NewTraceBlock(number, parent.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("read and write trace block", func(t *testing.T) {
+		if v, err := ch.db.Get(hash, key); err != nil || !bytes.Equal(v, []byte("v2")) {
+			t.Fatalf("want v2, have %s %v", v, err)
+		}
+		if err := ch.db.Put(hash, key, []byte("v3")); err != nil {
+			t.Fatal(err)
+		}
+		if err := ch.db.Del(hash, keyExt); err != nil {
+			t.Fatal(err)
+		}
+		if v, err := ch.db.Get(hash, key); err != nil || !bytes.Equal(v, []byte("v3")) {
+			t.Errorf("want v3, have %s %v", v, err)
+		}
+		if _, err := ch.db.Get(hash, keyExt); err != ErrNotFound {
+			t.Error("the deleted key must be not found", err)
+		}
+		if v, err := ch.db.Get(parent.Hash(), key); err != nil || !bytes.Equal(v, []byte("v2")) {
+			t.Errorf("the parent must not be changed, have %s %v", v, err)
+		}
+	})
+	t.Run("ranking trace block", func(t *testing.T) {
+		itr := ch.db.Ranking(hash, []byte("rankingKey"), 1)
+		defer itr.Release()
+		var values []string
+		for itr.Next() {
+			values = append(values, string(itr.Value()))
+		}
+		if len(values) != 1 || values[0] != "v3" {
+			t.Errorf("ranking trace value not right:%v", values)
+		}
+	})
+	t.Run("revert trace block", func(t *testing.T) {
+		id := ch.db.Snapshot(hash)
+		if err := ch.db.Put(hash, key, []byte("v4")); err != nil {
+			t.Fatal(err)
+		}
+		ch.db.RevertToSnapshot(hash, id)
+		if v, err := ch.db.Get(hash, key); err != nil || !bytes.Equal(v, []byte("v3")) {
+			t.Errorf("want v3, have %s %v", v, err)
+		}
+	})
+	t.Run("child trace block", func(t *testing.T) {
+		child, err := ch.db.NewTraceBlock(number, hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ch.db.DiscardTraceBlock(child)
+		if v, err := ch.db.Get(child, key); err != nil || !bytes.Equal(v, []byte("v3")) {
+			t.Errorf("want v3, have %s %v", v, err)
+		}
+		// The trace blocks of one block carry on its kv hash.
+		if kvHash := ch.db.GetLastKVHash(hash); bytes.Equal(kvHash, common.ZeroHash.Bytes()) || !bytes.Equal(ch.db.GetLastKVHash(child), kvHash) {
+			t.Errorf("kv hash mismatch: have %x, want %x", ch.db.GetLastKVHash(child), kvHash)
+		}
+		next, err := ch.db.NewTraceBlock(new(big.Int).Add(number, common.Big1), hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ch.db.DiscardTraceBlock(next)
+		if kvHash := ch.db.GetLastKVHash(next); !bytes.Equal(kvHash, common.ZeroHash.Bytes()) {
+			t.Errorf("the kv hash of the next block must be empty, have %x", kvHash)
+		}
+	})
+	t.Run("flatten trace block", func(t *testing.T) {
+		child, err := ch.db.NewTraceBlock(number, hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ch.db.Put(child, keyExt, []byte("v6")); err != nil {
+			t.Fatal(err)
+		}
+		if err := ch.db.Del(child, key); err != nil {
+			t.Fatal(err)
+		}
+		flat, err := ch.db.FlattenTraceBlock(child)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ch.db.DiscardTraceBlock(flat)
+		kvHash := ch.db.GetLastKVHash(child)
+		// The flattened block is on top of the parent of the chain, it is still
+		// readable after the chain is gone.
+		ch.db.DiscardTraceBlock(child)
+		if block, ok := ch.db.getTraceBlock(flat); !ok || block.ParentHash != parent.Hash() {
+			t.Fatal("the flattened block must be on top of the first block which is not a trace block")
+		}
+		if _, err := ch.db.Get(flat, key); err != ErrNotFound {
+			t.Error("the deleted key must be not found", err)
+		}
+		if v, err := ch.db.Get(flat, keyExt); err != nil || !bytes.Equal(v, []byte("v6")) {
+			t.Errorf("want v6, have %s %v", v, err)
+		}
+		if !bytes.Equal(ch.db.GetLastKVHash(flat), kvHash) {
+			t.Error("the flattened block must carry on the kv hash")
+		}
+	})
+	t.Run("discard trace block", func(t *testing.T) {
+		ch.db.DiscardTraceBlock(hash)
+		if err := ch.db.Put(hash, key, []byte("v5")); err == nil {
+			t.Error("the discarded trace block must not be written")
+		}
+	})
+}
+
 func TestFlush(t *testing.T) {
 	ch := newTestchain(dbpath)
 	defer ch.clear()
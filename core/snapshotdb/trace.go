@@ -0,0 +1,212 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"container/heap"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/memdb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+)
+
+// A trace block is a throwaway block used to re-execute transactions of an
+// existing block, e.g. by debug_traceTransaction. It reads the state of its
+// parent block and keeps every write in memory, it is never recognized,
+// committed or written to the baseDB.
+
+// TraceHook is notified of the keys read or written on a trace block.
+type TraceHook interface {
+	CaptureRead(key, value []byte)
+	CaptureWrite(key, value []byte)
+}
+
+type traceBlock struct {
+	*blockData
+	hook TraceHook
+}
+
+// NewTraceBlock creates a trace block on top of the state of the parent block,
+// which may be another trace block. The returned hash must be used as the block
+// hash of the re-execution and released by DiscardTraceBlock after use.
+func (s *snapshotDB) NewTraceBlock(blockNumber *big.Int, parentHash common.Hash) (common.Hash, error) {
+	if blockNumber == nil {
+		return common.ZeroHash, errors.New("[SnapshotDB]the blockNumber must not be nil ")
+	}
+	if !s.stateAvailable(parentHash) {
+		logger.Debug("the state of the trace parent is not available", "num", blockNumber, "parent", parentHash)
+		return common.ZeroHash, ErrArchiveNotAvailable
+	}
+	var hash common.Hash
+	if _, err := rand.Read(hash[:]); err != nil {
+		return common.ZeroHash, err
+	}
+	block := new(blockData)
+	block.Number = new(big.Int).Set(blockNumber)
+	block.ParentHash = parentHash
+	block.BlockHash = hash
+	// Trace blocks stacked on a trace block of the same number re-execute the
+	// rest of one block, they carry on its kv hash.
+	if parent, ok := s.getTraceBlock(parentHash); ok && parent.Number.Cmp(blockNumber) == 0 {
+		block.kvHash = parent.kvHash
+	}
+	block.data = memdb.New(DefaultComparer, 100)
+	block.journal = make([]journalEntry, 0)
+	block.validRevisions = make([]revision, 0)
+	s.traces.Store(hash, &traceBlock{blockData: block})
+	logger.Debug("NewTraceBlock", "num", block.Number, "hash", hash, "parent", parentHash)
+	return hash, nil
+}
+
+// FlattenTraceBlock creates a trace block holding the writes of the trace block
+// and of all its trace ancestors, on top of the first ancestor which is not a
+// trace block. Reading a long chain of trace blocks recurses through every one
+// of them, the flattened block is read in one step and lets the caller discard
+// the chain. The trace blocks themselves are left unchanged.
+func (s *snapshotDB) FlattenTraceBlock(hash common.Hash) (common.Hash, error) {
+	block, ok := s.getTraceBlock(hash)
+	if !ok {
+		return common.ZeroHash, fmt.Errorf("not find the trace block by hash:%v", hash.String())
+	}
+	var chain []*traceBlock
+	for ok {
+		chain = append(chain, block)
+		block, ok = s.getTraceBlock(block.ParentHash)
+	}
+	flatHash, err := s.NewTraceBlock(chain[0].Number, chain[len(chain)-1].ParentHash)
+	if err != nil {
+		return common.ZeroHash, err
+	}
+	flat, _ := s.getTraceBlock(flatHash)
+	// The deleted keys are kept as empty values, so they hide the keys of the
+	// parent like they did in the chain.
+	for i := len(chain) - 1; i >= 0; i-- {
+		itr := chain[i].data.NewIterator(nil)
+		for itr.Next() {
+			if err := flat.data.Put(itr.Key(), itr.Value()); err != nil {
+				itr.Release()
+				s.DiscardTraceBlock(flatHash)
+				return common.ZeroHash, err
+			}
+		}
+		itr.Release()
+	}
+	flat.kvHash = chain[0].kvHash
+	logger.Debug("FlattenTraceBlock", "num", flat.Number, "hash", flatHash, "from", hash, "depth", len(chain))
+	return flatHash, nil
+}
+
+// DiscardTraceBlock drops the trace block and all the changes made on it.
+func (s *snapshotDB) DiscardTraceBlock(hash common.Hash) {
+	s.traces.Delete(hash)
+}
+
+// SetTraceHook sets the hook of the trace block, the hook is notified of the
+// reads and writes until it is replaced or the block is discarded.
+func (s *snapshotDB) SetTraceHook(hash common.Hash, hook TraceHook) error {
+	block, ok := s.getTraceBlock(hash)
+	if !ok {
+		return fmt.Errorf("not find the trace block by hash:%v", hash.String())
+	}
+	block.hook = hook
+	return nil
+}
+
+func (s *snapshotDB) getTraceBlock(hash common.Hash) (*traceBlock, bool) {
+	if block, ok := s.traces.Load(hash); ok {
+		return block.(*traceBlock), true
+	}
+	return nil, false
+}
+
+func (b *traceBlock) write(key, value []byte) error {
+	if err := b.Write(key, value); err != nil {
+		return err
+	}
+	if b.hook != nil {
+		b.hook.CaptureWrite(key, value)
+	}
+	return nil
+}
+
+// stateAvailable reports whether Get(hash) returns the state of the block,
+// the block must be a trace block, unCommit, the highest committed block or an
+// archived one.
+func (s *snapshotDB) stateAvailable(hash common.Hash) bool {
+	if _, ok := s.getTraceBlock(hash); ok {
+		return true
+	}
+	if block := s.unCommit.Get(hash); block != nil {
+		return true
+	}
+	if s.current.GetHighest(false).Hash == hash {
+		return true
+	}
+	_, ok := s.historyNum(hash)
+	return ok
+}
+
+// getTrace return the value of the key from the trace block
+// trace block > parent block
+func (s *snapshotDB) getTrace(block *traceBlock, key []byte) ([]byte, error) {
+	v, err := block.data.Get(key)
+	switch {
+	case err == nil && len(v) == 0:
+		err = ErrNotFound
+	case err == memdb.ErrNotFound:
+		v, err = s.Get(block.ParentHash, key)
+	}
+	if err != nil && err != ErrNotFound {
+		return nil, err
+	}
+	if block.hook != nil {
+		block.hook.CaptureRead(key, v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// rankingTrace merges the trace block into the ranking of the parent block,
+// the keys deleted by the trace block may hide as many keys of the parent.
+func (s *snapshotDB) rankingTrace(block *traceBlock, key []byte, rangeNumber int) iterator.Iterator {
+	parentRangeNumber := rangeNumber
+	if rangeNumber > 0 {
+		parentRangeNumber += block.data.Len()
+	}
+	rankingHeap := newRankingHeap(rangeNumber)
+	rankingHeap.itr2Heap(block.data.NewIterator(util.BytesPrefix(key)), false, false)
+	rankingHeap.itr2Heap(s.Ranking(block.ParentHash, key, parentRangeNumber), false, false)
+	mdb := memdb.New(DefaultComparer, rangeNumber)
+	for rankingHeap.heap.Len() > 0 {
+		kv := heap.Pop(&rankingHeap.heap).(kv)
+		if err := mdb.Put(kv.key, kv.value); err != nil {
+			return iterator.NewEmptyIterator(errors.New("put to mdb fail" + err.Error()))
+		}
+		if block.hook != nil {
+			block.hook.CaptureRead(kv.key, kv.value)
+		}
+	}
+	return mdb.NewIterator(nil)
+}
@@ -0,0 +1,365 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/ethdb"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+)
+
+// VerifyChain is the chain the snapshotdb is verified against.
+type VerifyChain interface {
+	Chain
+	// PPOSHash returns the ppos kv hash stored in the state of the block, an
+	// error is returned if the state is not available.
+	PPOSHash(header *types.Header) (common.Hash, error)
+}
+
+// VerifyReport is the result of Verify.
+type VerifyReport struct {
+	BaseNum     uint64
+	BaseHash    common.Hash // The kv hash of the baseDB, it is the same on the nodes at the same base num
+	BaseKeys    uint64
+	HighestNum  uint64
+	HighestHash common.Hash
+	Journals    uint64 // The number of the journal blocks consistent with the chain
+
+	// Divergent is the first block the snapshotdb doesn't match the chain, it is
+	// 0 if the snapshotdb is consistent.
+	Divergent uint64
+	Reason    string
+	Warnings  []string
+}
+
+// Consistent reports whether the snapshotdb matches the chain.
+func (r *VerifyReport) Consistent() bool {
+	return r.Divergent == 0
+}
+
+func (r *VerifyReport) diverge(num uint64, format string, args ...interface{}) {
+	r.Divergent = num
+	r.Reason = fmt.Sprintf(format, args...)
+}
+
+func (r *VerifyReport) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Verify checks the snapshotdb stored in db against the chain without
+// changing it. The journal blocks between the base num and the highest must
+// be continuous and match the headers of the chain. The kv hash of the journal
+// blocks written in archive mode is recomputed from the writes they record, the
+// writes must end with the key-values of the block. The kv hash must match the
+// ppos hash committed in the state when the state of the block is available.
+func Verify(db Backend, chain VerifyChain) (*VerifyReport, error) {
+	ct := new(current)
+	if err := ct.loadFromBaseDB(db); err != nil {
+		return nil, err
+	}
+	report := &VerifyReport{
+		BaseNum:     ct.base.Num.Uint64(),
+		HighestNum:  ct.highest.Num.Uint64(),
+		HighestHash: ct.highest.Hash,
+	}
+	if err := verifyBaseDB(db, report); err != nil {
+		return nil, err
+	}
+	if err := ct.Valid(); err != nil {
+		report.diverge(report.HighestNum, "%v", err)
+		return report, nil
+	}
+	if head := chain.CurrentHeader(); head == nil || head.Number.Uint64() < report.BaseNum {
+		report.diverge(report.BaseNum, "the base num is greater than the chain head")
+		return report, nil
+	}
+	parent := chain.GetHeaderByNumber(report.BaseNum)
+	if parent == nil {
+		report.diverge(report.BaseNum, "the base block is not in the chain")
+		return report, nil
+	}
+
+	journals, err := sortedJournals(db)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		next     = report.BaseNum + 1
+		noState  uint64
+		noWrites uint64
+	)
+	for _, wal := range journals {
+		if wal.Number <= report.BaseNum {
+			report.warn("the journal of block %d is below the base num, it is removed on recovery", wal.Number)
+			continue
+		}
+		if wal.Number > report.HighestNum {
+			report.warn("the journal of block %d is above the highest, it is removed on recovery", wal.Number)
+			continue
+		}
+		if wal.Number != next {
+			report.diverge(next, "the journal of block %d is missing", next)
+			return report, nil
+		}
+		block := new(blockData)
+		if err := rlp.DecodeBytes(wal.Val, block); err != nil {
+			report.diverge(wal.Number, "the journal is broken: %v", err)
+			return report, nil
+		}
+		if block.Number == nil || block.Number.Uint64() != wal.Number {
+			report.diverge(wal.Number, "the journal is of block %v", block.Number)
+			return report, nil
+		}
+		header := chain.GetHeaderByNumber(wal.Number)
+		if header == nil {
+			report.diverge(wal.Number, "the block is not in the chain")
+			return report, nil
+		}
+		if block.BlockHash != header.Hash() || block.ParentHash != parent.Hash() {
+			report.diverge(wal.Number, "the journal block %x (parent %x) is not the chain block %x (parent %x)",
+				block.BlockHash, block.ParentHash, header.Hash(), parent.Hash())
+			return report, nil
+		}
+		if err := verifyWrites(block); err == errNoWrites {
+			noWrites++
+		} else if err != nil {
+			report.diverge(wal.Number, "%v", err)
+			return report, nil
+		}
+		if err := verifyKVHash(chain, block, header, parent); err == errNoState {
+			noState++
+		} else if err != nil {
+			report.diverge(wal.Number, "%v", err)
+			return report, nil
+		}
+		parent = header
+		next++
+		report.Journals++
+	}
+	if next <= report.HighestNum {
+		report.diverge(next, "the journal of block %d is missing", next)
+		return report, nil
+	}
+	if report.HighestNum > report.BaseNum && report.HighestHash != parent.Hash() {
+		report.diverge(report.HighestNum, "the highest block %x is not the chain block %x", report.HighestHash, parent.Hash())
+		return report, nil
+	}
+	if noWrites > 0 {
+		report.warn("%d journal blocks don't record their writes, their kv hashes are not recomputed, run the node with --db.snapshot_archive to record them", noWrites)
+	}
+	if noState > 0 {
+		report.warn("the state of %d journal blocks is not available, their kv hashes are not checked", noState)
+	}
+	return report, nil
+}
+
+var (
+	errNoState  = errors.New("state not available")
+	errNoWrites = errors.New("writes not recorded")
+)
+
+// verifyWrites recomputes the kv hash of the journal block from its writes, and
+// checks the latest values written are the key-values of the block.
+func verifyWrites(block *blockData) error {
+	if block.writes == nil {
+		if block.data.Len() == 0 && block.kvHash == common.ZeroHash {
+			return nil
+		}
+		return errNoWrites
+	}
+	var (
+		kvHash common.Hash
+		latest = make(map[string][]byte, len(block.writes))
+	)
+	for _, kv := range block.writes {
+		kvHash = generateKVHash(kv.Key, kv.Value, kvHash)
+		latest[string(kv.Key)] = kv.Value
+	}
+	if kvHash != block.kvHash {
+		return fmt.Errorf("the kv hash %x of the journal is not the hash %x of its writes", block.kvHash, kvHash)
+	}
+	if len(latest) != block.data.Len() {
+		return fmt.Errorf("the journal has %d keys, its writes have %d", block.data.Len(), len(latest))
+	}
+	itr := block.data.NewIterator(nil)
+	defer itr.Release()
+	for itr.Next() {
+		if v, ok := latest[string(itr.Key())]; !ok || !bytes.Equal(v, itr.Value()) {
+			return fmt.Errorf("the value of key %x in the journal is not the last one written", itr.Key())
+		}
+	}
+	return nil
+}
+
+// verifyKVHash compares the kv hash of the journal block with the ppos hash
+// stored in the state by BlockChainReactor.EndBlocker.
+func verifyKVHash(chain VerifyChain, block *blockData, header, parent *types.Header) error {
+	pposHash, err := chain.PPOSHash(header)
+	if err != nil {
+		return errNoState
+	}
+	want := block.kvHash
+	if want == common.ZeroHash {
+		// the ppos hash is not stored if the block changes nothing
+		if want, err = chain.PPOSHash(parent); err != nil {
+			return errNoState
+		}
+	}
+	if want != pposHash {
+		return fmt.Errorf("the kv hash %x of the journal is not the ppos hash %x of the state", block.kvHash, pposHash)
+	}
+	return nil
+}
+
+// verifyBaseDB computes the kv hash of the PPOS data in the baseDB.
+func verifyBaseDB(db Backend, report *VerifyReport) error {
+	itr := db.NewIterator(nil, nil)
+	defer itr.Release()
+	for itr.Next() {
+		if !IsPPOSKey(itr.Key()) {
+			continue
+		}
+		report.BaseHash = generateKVHash(itr.Key(), itr.Value(), report.BaseHash)
+		report.BaseKeys++
+	}
+	return itr.Error()
+}
+
+func sortedJournals(db Backend) (blockOrigin, error) {
+	itr := newRangeIterator(db, util.BytesPrefix([]byte(WalKeyPrefix)))
+	defer itr.Release()
+	var journals blockOrigin
+	for itr.Next() {
+		journals = append(journals, struct {
+			Number uint64
+			key    []byte
+			Val    []byte
+		}{Number: DecodeWalKey(itr.Key()).Uint64(), key: common.CopyBytes(itr.Key()), Val: common.CopyBytes(itr.Value())})
+	}
+	sort.Sort(journals)
+	return journals, itr.Error()
+}
+
+// Rewind sets the highest block of the snapshotdb stored in db back to the
+// block, and removes the journals above it. The blocks after are replayed from
+// the chain by the node on start. The baseDB is only rewound below the base num
+// with the reverse diffs kept in archive mode, the block must not be below the
+// first archived block. It returns the number of the journals removed.
+func Rewind(db Backend, number uint64, hash common.Hash) (int, error) {
+	ct := new(current)
+	if err := ct.loadFromBaseDB(db); err != nil {
+		return 0, err
+	}
+	if number > ct.highest.Num.Uint64() {
+		return 0, errors.New("can't rewind the snapshotdb to a block above the highest")
+	}
+	journals, err := sortedJournals(db)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		batch   = db.NewBatch()
+		removed int
+		base    = ct.base.Num
+	)
+	if number < base.Uint64() {
+		if err := rewindBaseDB(db, batch, number); err != nil {
+			return 0, err
+		}
+		base = new(big.Int).SetUint64(number)
+	}
+	for _, wal := range journals {
+		if wal.Number > number {
+			if err := batch.Delete(wal.key); err != nil {
+				return 0, err
+			}
+			removed++
+		}
+	}
+	nc := newCurrent(new(big.Int).SetUint64(number), base, hash)
+	if err := batch.Put([]byte(CurrentHighestBlock), nc.EncodeHighest()); err != nil {
+		return 0, err
+	}
+	if err := batch.Put([]byte(CurrentBaseNum), nc.EncodeBase()); err != nil {
+		return 0, err
+	}
+	if err := batch.Write(); err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// rewindBaseDB adds to the batch the changes setting the baseDB back to the
+// state of the block. The oldest reverse diff recorded after the block is the
+// value of the key at the block, the reverse diffs after are removed.
+func rewindBaseDB(db Backend, batch ethdb.Batch, number uint64) error {
+	v, err := db.Get([]byte(ArchiveBegin))
+	if err == ErrNotFound {
+		return errors.New("can't rewind the baseDB without the archive, the node must run with --db.snapshot_archive")
+	} else if err != nil {
+		return err
+	}
+	begin := new(big.Int)
+	if err := rlp.DecodeBytes(v, begin); err != nil {
+		return fmt.Errorf("decode archive begin fail:%v", err)
+	}
+	if number < begin.Uint64() {
+		return fmt.Errorf("can't rewind the baseDB to block %d before the first archived block %d", number, begin)
+	}
+	type reverseDiff struct {
+		num   uint64
+		value []byte
+	}
+	diffs := make(map[string]reverseDiff)
+	itr := newRangeIterator(db, util.BytesPrefix([]byte(ArchiveKeyPrefix)))
+	defer itr.Release()
+	for itr.Next() {
+		key, num := DecodeArchiveKey(itr.Key())
+		if num <= number {
+			continue
+		}
+		if diff, ok := diffs[string(key)]; !ok || num < diff.num {
+			diffs[string(key)] = reverseDiff{num, common.CopyBytes(itr.Value())}
+		}
+		if err := batch.Delete(common.CopyBytes(itr.Key())); err != nil {
+			return err
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	for key, diff := range diffs {
+		if len(diff.value) == 0 {
+			err = batch.Delete([]byte(key))
+		} else {
+			err = batch.Put([]byte(key), diff.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+)
+
+type verifyTestchain struct {
+	*testchain
+	ppos map[common.Hash]common.Hash
+}
+
+func (c *verifyTestchain) PPOSHash(header *types.Header) (common.Hash, error) {
+	if hash, ok := c.ppos[header.Hash()]; ok {
+		return hash, nil
+	}
+	return common.Hash{}, errors.New("missing state")
+}
+
+func TestVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshotdb-verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ch := newTestchain(dir)
+	defer ch.clear()
+	ch.db.recordWrites = true
+	chain := &verifyTestchain{testchain: ch, ppos: make(map[common.Hash]common.Hash)}
+
+	for i := 0; i < 2; i++ {
+		if err := ch.insert(true, generatekv(10), newBlockBaseDB); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// block 4 changes nothing, its ppos hash is the one of block 3
+	for _, kvs := range []kvs{generatekv(10), nil, generatekv(10)} {
+		if err := ch.insert(true, kvs, newBlockCommited); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ch.db.walSync.Wait()
+	var pposHash common.Hash
+	for _, block := range ch.db.committed {
+		if block.kvHash != common.ZeroHash {
+			pposHash = block.kvHash
+		}
+		chain.ppos[block.BlockHash] = pposHash
+	}
+
+	report, err := Verify(ch.db.baseDB, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Consistent() || report.BaseNum != 2 || report.HighestNum != 5 || report.Journals != 3 || report.BaseKeys != 20 {
+		t.Fatalf("report not right: %+v", report)
+	}
+
+	// the kv hash is recomputed from the writes of the journal
+	walKey := EncodeWalKey(ch.h[2].Number)
+	wal, err := ch.db.baseDB.Get(walKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, change := range []func(*blockData){
+		func(block *blockData) { block.writes[0].Value = []byte("changed") },
+		func(block *blockData) { block.data.Put(block.writes[0].Key, []byte("changed")) },
+	} {
+		block := new(blockData)
+		if err := rlp.DecodeBytes(wal, block); err != nil {
+			t.Fatal(err)
+		}
+		change(block)
+		if err := ch.db.baseDB.Put(walKey, block.BlockVal()); err != nil {
+			t.Fatal(err)
+		}
+		if report, _ := Verify(ch.db.baseDB, chain); report.Divergent != 3 {
+			t.Fatalf("change %d: want divergent block 3, have %+v", i, report)
+		}
+	}
+	// the journals written outside archive mode don't record the writes
+	block := new(blockData)
+	if err := rlp.DecodeBytes(wal, block); err != nil {
+		t.Fatal(err)
+	}
+	block.writes = nil
+	if err := ch.db.baseDB.Put(walKey, block.BlockVal()); err != nil {
+		t.Fatal(err)
+	}
+	if report, _ := Verify(ch.db.baseDB, chain); !report.Consistent() || len(report.Warnings) != 1 {
+		t.Fatalf("the journal without writes must be skipped: %+v", report)
+	}
+	if err := ch.db.baseDB.Put(walKey, wal); err != nil {
+		t.Fatal(err)
+	}
+
+	block4 := ch.h[3].Hash()
+	chain.ppos[block4] = common.HexToHash("0x01")
+	if report, _ := Verify(ch.db.baseDB, chain); report.Divergent != 4 {
+		t.Fatalf("want divergent block 4, have %+v", report)
+	}
+	delete(chain.ppos, block4)
+	if report, _ := Verify(ch.db.baseDB, chain); !report.Consistent() || len(report.Warnings) != 1 {
+		t.Fatalf("the blocks without state must be skipped: %+v", report)
+	}
+
+	if err := ch.db.baseDB.Delete(EncodeWalKey(ch.h[3].Number)); err != nil {
+		t.Fatal(err)
+	}
+	if report, _ := Verify(ch.db.baseDB, chain); report.Divergent != 4 {
+		t.Fatalf("want divergent block 4, have %+v", report)
+	}
+
+	if _, err := Rewind(ch.db.baseDB, 1, ch.h[0].Hash()); err == nil {
+		t.Fatal("the snapshotdb must not be rewound below the base num without the archive")
+	}
+	removed, err := Rewind(ch.db.baseDB, 3, ch.h[2].Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 {
+		t.Fatalf("want 1 journal removed, have %d", removed)
+	}
+	report, err = Verify(ch.db.baseDB, chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Consistent() || report.HighestNum != 3 || report.HighestHash != ch.h[2].Hash() || report.Journals != 1 {
+		t.Fatalf("report not right after rewind: %+v", report)
+	}
+}
+
+func TestRewindArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshotdb-rewind")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	SetDBArchive(true)
+	defer SetDBArchive(false)
+	ch := newTestchain(dir)
+	defer ch.clear()
+	var (
+		key    = []byte("rankingKey")
+		keyExt = []byte("rankingKeyExt")
+	)
+	for _, kvs := range []kvs{
+		{kv{key, []byte("v1")}},
+		{kv{key, []byte("v2")}},
+		{kv{key, []byte("v3")}, kv{keyExt, []byte("e3")}},
+		{kv{key, []byte("v4")}},
+	} {
+		if err := ch.insert(true, kvs, newBlockBaseDB); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ch.insert(true, kvs{kv{key, []byte("v5")}}, newBlockCommited); err != nil {
+		t.Fatal(err)
+	}
+	ch.db.walSync.Wait()
+
+	if _, err := Rewind(ch.db.baseDB, 2, ch.h[1].Hash()); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := ch.db.baseDB.Get(key); err != nil || string(v) != "v2" {
+		t.Errorf("want v2, have %s %v", v, err)
+	}
+	if _, err := ch.db.baseDB.Get(keyExt); err != ErrNotFound {
+		t.Error("the key written after the block must be removed", err)
+	}
+	ct := new(current)
+	if err := ct.loadFromBaseDB(ch.db.baseDB); err != nil {
+		t.Fatal(err)
+	}
+	if ct.base.Num.Uint64() != 2 || ct.highest.Num.Uint64() != 2 || ct.highest.Hash != ch.h[1].Hash() {
+		t.Fatalf("current not right: base %v, highest %v", ct.base.Num, ct.highest.Num)
+	}
+	journals, err := sortedJournals(ch.db.baseDB)
+	if err != nil || len(journals) != 0 {
+		t.Fatalf("the journals must be removed: %d %v", len(journals), err)
+	}
+	// the reverse diffs before the block are kept, it can be rewound again
+	if _, err := Rewind(ch.db.baseDB, 1, ch.h[0].Hash()); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := ch.db.baseDB.Get(key); err != nil || string(v) != "v1" {
+		t.Errorf("want v1, have %s %v", v, err)
+	}
+}
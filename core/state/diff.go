@@ -0,0 +1,104 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
+)
+
+// AccountDiff is a difference of an account between two states.
+type AccountDiff struct {
+	Address common.Address `json:"address"`
+	Field   string         `json:"field"` // exist, balance, nonce, codeHash, storage or storageRoot
+	Key     hexutil.Bytes  `json:"key,omitempty"`
+	Value   string         `json:"value"`
+	Other   string         `json:"other"`
+}
+
+// Diff compares the accounts cached by the two states, which are all the
+// accounts modified since they were created. Both states should be finalised.
+func (self *StateDB) Diff(other *StateDB) []AccountDiff {
+	addrs := make(map[common.Address]struct{})
+	for _, db := range []*StateDB{self, other} {
+		for addr := range db.stateObjects {
+			addrs[addr] = struct{}{}
+		}
+		for addr := range db.stateObjectsDirty {
+			addrs[addr] = struct{}{}
+		}
+	}
+	sorted := make([]common.Address, 0, len(addrs))
+	for addr := range addrs {
+		sorted = append(sorted, addr)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i][:], sorted[j][:]) < 0
+	})
+
+	var diffs []AccountDiff
+	for _, addr := range sorted {
+		diffs = append(diffs, self.diffAccount(other, addr)...)
+	}
+	return diffs
+}
+
+func (self *StateDB) diffAccount(other *StateDB, addr common.Address) []AccountDiff {
+	var diffs []AccountDiff
+	add := func(field string, key []byte, value, otherValue string) {
+		if value != otherValue {
+			diffs = append(diffs, AccountDiff{Address: addr, Field: field, Key: key, Value: value, Other: otherValue})
+		}
+	}
+
+	obj, otherObj := self.getStateObject(addr), other.getStateObject(addr)
+	if obj == nil || otherObj == nil {
+		add("exist", nil, strconv.FormatBool(obj != nil), strconv.FormatBool(otherObj != nil))
+		return diffs
+	}
+	add("balance", nil, obj.Balance().String(), otherObj.Balance().String())
+	add("nonce", nil, strconv.FormatUint(obj.Nonce(), 10), strconv.FormatUint(otherObj.Nonce(), 10))
+	add("codeHash", nil, hexutil.Encode(obj.CodeHash()), hexutil.Encode(otherObj.CodeHash()))
+
+	keys := make(map[string]struct{})
+	for _, o := range []*stateObject{obj, otherObj} {
+		for key := range o.originStorage {
+			keys[key] = struct{}{}
+		}
+		for key := range o.dirtyStorage {
+			keys[key] = struct{}{}
+		}
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+	for _, key := range sortedKeys {
+		value := obj.GetState(self.db, []byte(key))
+		otherValue := otherObj.GetState(other.db, []byte(key))
+		add("storage", []byte(key), hexutil.Encode(value), hexutil.Encode(otherValue))
+	}
+	if len(diffs) == 0 {
+		add("storageRoot", nil, obj.data.Root.Hex(), otherObj.data.Root.Hex())
+	}
+	return diffs
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
 	"github.com/AlayaNetwork/Alaya-Go/log"
 	"github.com/AlayaNetwork/Alaya-Go/rlp"
 )
@@ -108,3 +109,132 @@ func (self *StateDB) justCreateObject(addr common.Address) *ParallelStateObject
 	}
 	//return self.createObject(addr)
 }
+
+// ParallelStateDB is the state a contract transaction is speculatively executed
+// on by the parallel executor. It reads through the base state without modifying
+// it, and records the accounts observed by the execution, so that the executor
+// can tell whether the transaction conflicts with the ones committed before it.
+//
+// The accounts which are only credited by AddBalance, e.g. the coinbase, are not
+// observed, their balances are merged as deltas.
+type ParallelStateDB struct {
+	*StateDB
+	base *StateDB
+
+	reads   map[common.Address]struct{}
+	origins map[common.Address]*big.Int // The balances of the accounts credited without being observed
+	blind   bool
+}
+
+// NewParallelStateDB returns a ParallelStateDB based on the state, the state must
+// not be modified until all the ParallelStateDBs based on it are merged or discarded.
+func (self *StateDB) NewParallelStateDB() *ParallelStateDB {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	p := &ParallelStateDB{
+		base:    self,
+		reads:   make(map[common.Address]struct{}),
+		origins: make(map[common.Address]*big.Int),
+	}
+	p.StateDB = &StateDB{
+		db:                 self.db,
+		trie:               self.db.CopyTrie(self.trie),
+		stateObjects:       make(map[common.Address]*stateObject),
+		stateObjectsDirty:  make(map[common.Address]struct{}),
+		logs:               make(map[common.Hash][]*types.Log),
+		preimages:          make(map[common.Hash][]byte),
+		journal:            newJournal(),
+		accessList:         newAccessList(),
+		clearReferenceFunc: make([]func(), 0),
+		parent:             self,
+		originRoot:         self.originRoot,
+		parallel:           p,
+	}
+	return p
+}
+
+// AddBalance adds amount to the account without observing it.
+func (p *ParallelStateDB) AddBalance(addr common.Address, amount *big.Int) {
+	if _, ok := p.reads[addr]; !ok {
+		p.blind = true
+		defer func() { p.blind = false }()
+		if _, ok := p.origins[addr]; !ok {
+			p.origins[addr] = new(big.Int).Set(p.StateDB.GetBalance(addr))
+		}
+	}
+	p.StateDB.AddBalance(addr, amount)
+}
+
+// ReadSet returns the accounts observed by the execution.
+func (p *ParallelStateDB) ReadSet() map[common.Address]struct{} {
+	return p.reads
+}
+
+// WriteSet returns the accounts modified by the execution.
+func (p *ParallelStateDB) WriteSet() []common.Address {
+	writes := make([]common.Address, 0, len(p.journal.dirties))
+	for addr := range p.journal.dirties {
+		writes = append(writes, addr)
+	}
+	return writes
+}
+
+func (p *ParallelStateDB) observe(addr common.Address) {
+	if !p.blind {
+		p.reads[addr] = struct{}{}
+	}
+}
+
+// getBaseStateObject copies the state object cached by the base state, the base
+// state is only read, so it's safe for the concurrent speculative executions.
+func (p *ParallelStateDB) getBaseStateObject(addr common.Address) *stateObject {
+	obj := p.base.justGetStateObjectCache(addr)
+	if obj == nil {
+		return nil
+	}
+	cpy := obj.deepCopy(p.StateDB)
+	p.setStateObject(cpy)
+	return cpy
+}
+
+// MergeParallelStateDB applies the changes of a speculative execution based on
+// the state, the state must be prepared for the transaction. The observed
+// accounts are replaced, the others are credited by the delta of their balances.
+func (self *StateDB) MergeParallelStateDB(p *ParallelStateDB) {
+	for addr := range p.journal.dirties {
+		obj, exist := p.stateObjects[addr]
+		if !exist {
+			continue
+		}
+		if _, ok := p.origins[addr]; ok {
+			if _, observed := p.reads[addr]; !observed {
+				continue
+			}
+		}
+		obj.db = self
+		self.setStateObject(obj)
+		self.journal.dirty(addr)
+	}
+	for addr, origin := range p.origins {
+		if _, observed := p.reads[addr]; observed {
+			continue
+		}
+		obj, exist := p.stateObjects[addr]
+		if !exist {
+			continue
+		}
+		_, dirty := p.journal.dirties[addr]
+		delta := new(big.Int).Sub(obj.Balance(), origin)
+		if delta.Sign() == 0 && !dirty {
+			continue
+		}
+		self.AddBalance(addr, delta)
+	}
+	for _, l := range p.logs[p.thash] {
+		self.AddLog(l)
+	}
+	for hash, preimage := range p.preimages {
+		self.AddPreimage(hash, preimage)
+	}
+}
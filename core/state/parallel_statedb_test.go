@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/AlayaNetwork/Alaya-Go/core/rawdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
 
 	"github.com/AlayaNetwork/Alaya-Go/crypto"
 	"github.com/AlayaNetwork/Alaya-Go/rlp"
@@ -15,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/vm"
 )
 
 var (
@@ -99,3 +101,50 @@ func TestParallelStateDB_random(t *testing.T) {
 	}
 
 }
+
+func TestParallelStateDB_Merge(t *testing.T) {
+	vm.PrecompiledContractCheckInstance = &TestPrecompiledContractCheck{}
+	db := rawdb.NewMemoryDatabase()
+	statedb, _ := New(common.Hash{}, NewDatabase(db))
+
+	from := common.HexToAddress("0x01")
+	contract := common.HexToAddress("0x02")
+	coinbase := common.HexToAddress("0x03")
+	statedb.SetBalance(from, big.NewInt(100))
+	statedb.SetCode(contract, []byte{0x60})
+	statedb.SetState(contract, []byte("key"), []byte("v1"))
+	statedb.SetBalance(coinbase, big.NewInt(10))
+	statedb.Finalise(true)
+
+	p := statedb.NewParallelStateDB()
+	p.Prepare(common.HexToHash("0x1"), common.Hash{}, 0)
+	p.SubBalance(from, big.NewInt(10))
+	assert.Equal(t, []byte("v1"), p.GetState(contract, []byte("key")))
+	p.SetState(contract, []byte("key"), []byte("v2"))
+	p.AddBalance(coinbase, big.NewInt(5))
+	p.AddLog(&types.Log{Address: contract})
+
+	// the base state is not modified by the speculative execution
+	assert.Equal(t, big.NewInt(100), statedb.GetBalance(from))
+	assert.Equal(t, []byte("v1"), statedb.GetState(contract, []byte("key")))
+
+	reads := p.ReadSet()
+	assert.Contains(t, reads, from)
+	assert.Contains(t, reads, contract)
+	assert.NotContains(t, reads, coinbase)
+	assert.ElementsMatch(t, []common.Address{from, contract, coinbase}, p.WriteSet())
+
+	// the coinbase is credited before the merge, the balance is merged as a delta
+	statedb.AddBalance(coinbase, big.NewInt(7))
+	statedb.Finalise(true)
+
+	statedb.Prepare(common.HexToHash("0x1"), common.Hash{}, 1)
+	statedb.MergeParallelStateDB(p)
+	statedb.Finalise(true)
+	assert.Equal(t, big.NewInt(90), statedb.GetBalance(from))
+	assert.Equal(t, []byte("v2"), statedb.GetState(contract, []byte("key")))
+	assert.Equal(t, big.NewInt(22), statedb.GetBalance(coinbase))
+	if logs := statedb.GetLogs(common.HexToHash("0x1")); len(logs) != 1 || logs[0].TxIndex != 1 {
+		t.Fatalf("logs not merged: %v", logs)
+	}
+}
@@ -84,6 +84,9 @@ type StateDB struct {
 
 	preimages map[common.Hash][]byte
 
+	// Per-transaction access list
+	accessList *accessList
+
 	// Journal of state modifications. This is the backbone of
 	// Snapshot and RevertToSnapshot.
 	journal        *journal
@@ -105,6 +108,9 @@ type StateDB struct {
 	referenceFuncIndex int
 	// statedb is created based on this root
 	originRoot common.Hash
+
+	// The ParallelStateDB wrapping this state, if it is a speculative one
+	parallel *ParallelStateDB
 }
 
 // Create a new state from a given trie.
@@ -121,6 +127,7 @@ func New(root common.Hash, db Database) (*StateDB, error) {
 		logs:               make(map[common.Hash][]*types.Log),
 		preimages:          make(map[common.Hash][]byte),
 		journal:            newJournal(),
+		accessList:         newAccessList(),
 		clearReferenceFunc: make([]func(), 0),
 		originRoot:         root,
 	}
@@ -137,6 +144,7 @@ func (self *StateDB) NewStateDB() *StateDB {
 		logs:               make(map[common.Hash][]*types.Log),
 		preimages:          make(map[common.Hash][]byte),
 		journal:            newJournal(),
+		accessList:         newAccessList(),
 		parent:             self,
 		clearReferenceFunc: make([]func(), 0),
 		originRoot:         self.Root(),
@@ -589,6 +597,9 @@ func (self *StateDB) getStateObjectCache(addr common.Address) (stateObject *stat
 	if obj := self.stateObjects[addr]; obj != nil {
 		return obj
 	}
+	if self.parallel != nil {
+		return self.parallel.getBaseStateObject(addr)
+	}
 	self.refLock.Lock()
 	parentDB := self.parent
 	parentCommitted := self.parentCommitted
@@ -731,6 +742,9 @@ func (self *StateDB) ClearParentReference() {
 
 // Retrieve a state object given by the address. Returns nil if not found.
 func (self *StateDB) getStateObject(addr common.Address) (stateObject *stateObject) {
+	if self.parallel != nil {
+		self.parallel.observe(addr)
+	}
 	if obj := self.getStateObjectCache(addr); obj != nil {
 		if obj.deleted {
 			return nil
@@ -872,6 +886,7 @@ func (self *StateDB) Copy() *StateDB {
 		logSize:            self.logSize,
 		preimages:          make(map[common.Hash][]byte),
 		journal:            newJournal(),
+		accessList:         self.accessList.Copy(),
 		clearReferenceFunc: make([]func(), 0),
 		originRoot:         self.originRoot,
 	}
@@ -1013,6 +1028,68 @@ func (self *StateDB) Prepare(thash, bhash common.Hash, ti int) {
 	self.thash = thash
 	self.bhash = bhash
 	self.txIndex = ti
+	self.accessList = newAccessList()
+}
+
+// PrepareAccessList handles the preparatory steps for executing a state transition
+// with the EIP-2929 gas rules:
+//
+// - Add sender to access list
+// - Add destination to access list
+// - Add precompiles to access list
+// - Add the contents of the optional tx access list (EIP-2930)
+//
+// This method should only be called if the access list is enabled by the governance version.
+func (self *StateDB) PrepareAccessList(sender common.Address, dst *common.Address, precompiles []common.Address, list types.AccessList) {
+	self.AddAddressToAccessList(sender)
+	if dst != nil {
+		self.AddAddressToAccessList(*dst)
+		// If it's a create-tx, the destination will be added inside evm.create
+	}
+	for _, addr := range precompiles {
+		self.AddAddressToAccessList(addr)
+	}
+	for _, el := range list {
+		self.AddAddressToAccessList(el.Address)
+		for _, key := range el.StorageKeys {
+			self.AddSlotToAccessList(el.Address, key)
+		}
+	}
+}
+
+// AddAddressToAccessList adds the given address to the access list
+func (self *StateDB) AddAddressToAccessList(addr common.Address) {
+	if self.accessList.AddAddress(addr) {
+		self.journal.append(accessListAddAccountChange{&addr})
+	}
+}
+
+// AddSlotToAccessList adds the given (address, slot)-tuple to the access list
+func (self *StateDB) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	addrMod, slotMod := self.accessList.AddSlot(addr, slot)
+	if addrMod {
+		// In practice, this should not happen, since there is no way to enter the
+		// scope of 'address' without having the 'address' become already added
+		// to the access list (via call-variant, create, etc).
+		// Better safe than sorry, though
+		self.journal.append(accessListAddAccountChange{&addr})
+	}
+	if slotMod {
+		self.journal.append(accessListAddSlotChange{
+			address: &addr,
+			slot:    &slot,
+		})
+	}
+}
+
+// AddressInAccessList returns true if the given address is in the access list.
+func (self *StateDB) AddressInAccessList(addr common.Address) bool {
+	return self.accessList.ContainsAddress(addr)
+}
+
+// SlotInAccessList returns true if the given (address, slot)-tuple is in the access list.
+func (self *StateDB) SlotInAccessList(addr common.Address, slot common.Hash) (addressPresent bool, slotPresent bool) {
+	return self.accessList.Contains(addr, slot)
 }
 
 func (s *StateDB) clearJournalAndRefund() {
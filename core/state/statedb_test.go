@@ -806,3 +806,47 @@ func TestGetAfterDelete(t *testing.T) {
 	assert.Equal(t, buf, []byte("value"))
 	assert.Equal(t, buf1, []byte("value1"))
 }
+
+func TestStateDBAccessList(t *testing.T) {
+	s, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()))
+	var (
+		addr  = common.HexToAddress("aa")
+		addr2 = common.HexToAddress("bb")
+		slot  = common.HexToHash("01")
+	)
+	s.PrepareAccessList(addr, &addr2, nil, types.AccessList{{Address: addr2, StorageKeys: []common.Hash{slot}}})
+	if !s.AddressInAccessList(addr) || !s.AddressInAccessList(addr2) {
+		t.Fatal("the sender and the destination must be in the access list")
+	}
+	if addrOk, slotOk := s.SlotInAccessList(addr2, slot); !addrOk || !slotOk {
+		t.Fatal("the slot of the tx access list must be in the access list")
+	}
+
+	snapshot := s.Snapshot()
+	addr3 := common.HexToAddress("cc")
+	s.AddSlotToAccessList(addr3, slot)
+	s.AddSlotToAccessList(addr, common.HexToHash("02"))
+	if addrOk, slotOk := s.SlotInAccessList(addr3, slot); !addrOk || !slotOk {
+		t.Fatal("the slot added must be in the access list")
+	}
+	cpy := s.Copy()
+
+	s.RevertToSnapshot(snapshot)
+	if s.AddressInAccessList(addr3) {
+		t.Fatal("the address must be removed from the access list after revert")
+	}
+	if _, slotOk := s.SlotInAccessList(addr, common.HexToHash("02")); slotOk {
+		t.Fatal("the slot must be removed from the access list after revert")
+	}
+	if !s.AddressInAccessList(addr) {
+		t.Fatal("the address added before the snapshot must stay in the access list")
+	}
+	if _, slotOk := cpy.SlotInAccessList(addr3, slot); !slotOk {
+		t.Fatal("the copy must not be affected by the revert")
+	}
+
+	s.Prepare(common.Hash{}, common.Hash{}, 1)
+	if s.AddressInAccessList(addr) {
+		t.Fatal("the access list must be reset by prepare")
+	}
+}
@@ -117,7 +117,7 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, gp *GasPool,
 	statedb *state.StateDB, header *types.Header, tx *types.Transaction,
 	usedGas *uint64, cfg vm.Config) (*types.Receipt, uint64, error) {
 
-	msg, err := tx.AsMessage(types.NewEIP155Signer(config.ChainID))
+	msg, err := tx.AsMessage(types.LatestSignerForChainID(config.ChainID), header.BaseFee)
 
 	if err != nil {
 		return nil, 0, err
@@ -127,9 +127,19 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, gp *GasPool,
 	// Create a new environment which holds all relevant information
 	// about the transaction and calling mechanisms.
 	vmenv := vm.NewEVM(context, snapshotdb.Instance(), statedb, config, cfg)
+	return applyTransaction(msg, gp, statedb, header, tx, usedGas, vmenv)
+}
+
+// applyTransaction applies the transaction message in the EVM environment.
+func applyTransaction(msg types.Message, gp *GasPool, statedb *state.StateDB, header *types.Header,
+	tx *types.Transaction, usedGas *uint64, vmenv *vm.EVM) (*types.Receipt, uint64, error) {
 
 	log.Trace("execute tx start", "blockNumber", header.Number, "txHash", tx.Hash().String())
 
+	if !txTypeSupported(tx, vmenv.AccessListEnabled(), vmenv.DynamicFeeEnabled()) {
+		return nil, 0, ErrTxTypeNotSupported
+	}
+
 	// Apply the transaction to the current state (included in the env)
 	result, err := ApplyMessage(vmenv, msg, gp)
 	if err != nil {
@@ -138,17 +148,43 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, gp *GasPool,
 	// Update the state with pending changes
 	statedb.Finalise(true)
 
-	var root []byte
 	*usedGas += result.UsedGas
 
+	receipt, err := newReceipt(statedb, header, tx, msg, result, *usedGas)
+	if err != nil {
+		return nil, 0, err
+	}
+	return receipt, result.UsedGas, nil
+}
+
+// txTypeSupported returns whether the type of the transaction is enabled by the
+// active governance version.
+func txTypeSupported(tx *types.Transaction, accessListEnabled, dynamicFeeEnabled bool) bool {
+	switch tx.Type() {
+	case types.LegacyTxType:
+		return true
+	case types.AccessListTxType:
+		return accessListEnabled
+	case types.DynamicFeeTxType:
+		return dynamicFeeEnabled
+	default:
+		return false
+	}
+}
+
+// newReceipt creates the receipt of the transaction applied to the state.
+func newReceipt(statedb *state.StateDB, header *types.Header, tx *types.Transaction, msg types.Message, result *ExecutionResult, usedGas uint64) (*types.Receipt, error) {
+	var root []byte
+
 	// Create a new receipt for the transaction, storing the intermediate root and gas used by the tx
 	// based on the eip phase, we're passing whether the root touch-delete accounts.
-	receipt := types.NewReceipt(root, result.Failed(), *usedGas)
+	receipt := types.NewReceipt(root, result.Failed(), usedGas)
+	receipt.Type = tx.Type()
 	receipt.TxHash = tx.Hash()
 	receipt.GasUsed = result.UsedGas
 	// if the transaction created a contract, store the creation address in the receipt.
 	if msg.To() == nil {
-		receipt.ContractAddress = crypto.CreateAddress(vmenv.Context.Origin, tx.Nonce())
+		receipt.ContractAddress = crypto.CreateAddress(msg.From(), tx.Nonce())
 	}
 	// Set the receipt logs and create a bloom for filtering
 
@@ -158,7 +194,7 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, gp *GasPool,
 			res := strconv.Itoa(int(bizError.Code))
 			if err := rlp.Encode(buf, [][]byte{[]byte(res)}); nil != err {
 				log.Error("Cannot RlpEncode the log data", "data", bizError.Code, "err", err)
-				return nil, 0, err
+				return nil, err
 			}
 			receipt.Logs = []*types.Log{
 				&types.Log{
@@ -178,5 +214,5 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, gp *GasPool,
 	receipt.BlockHash = statedb.BlockHash()
 	receipt.BlockNumber = header.Number
 	receipt.TransactionIndex = uint(statedb.TxIndex())
-	return receipt, result.UsedGas, err
+	return receipt, nil
 }
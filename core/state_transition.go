@@ -19,11 +19,15 @@ package core
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"time"
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
+	cvm "github.com/AlayaNetwork/Alaya-Go/common/vm"
+	cmath "github.com/AlayaNetwork/Alaya-Go/common/math"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
 	"github.com/AlayaNetwork/Alaya-Go/core/vm"
 	"github.com/AlayaNetwork/Alaya-Go/log"
 	"github.com/AlayaNetwork/Alaya-Go/params"
@@ -52,6 +56,8 @@ type StateTransition struct {
 	msg        Message
 	gas        uint64
 	gasPrice   *big.Int
+	gasFeeCap  *big.Int
+	gasTipCap  *big.Int
 	initialGas uint64
 	value      *big.Int
 	data       []byte
@@ -66,12 +72,15 @@ type Message interface {
 	To() *common.Address
 
 	GasPrice() *big.Int
+	GasFeeCap() *big.Int
+	GasTipCap() *big.Int
 	Gas() uint64
 	Value() *big.Int
 
 	Nonce() uint64
 	CheckNonce() bool
 	Data() []byte
+	AccessList() types.AccessList
 }
 
 // ExecutionResult includes all output after executing given evm
@@ -110,7 +119,7 @@ func (result *ExecutionResult) Revert() []byte {
 }
 
 // IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func IntrinsicGas(data []byte, contractCreation bool, state vm.StateDB) (uint64, error) {
+func IntrinsicGas(data []byte, accessList types.AccessList, contractCreation bool, state vm.StateDB) (uint64, error) {
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if contractCreation {
@@ -149,19 +158,25 @@ func IntrinsicGas(data []byte, contractCreation bool, state vm.StateDB) (uint64,
 		}
 		gas += z * zeroGas
 	}
+	if accessList != nil {
+		gas += uint64(len(accessList)) * params.TxAccessListAddressGas
+		gas += uint64(accessList.StorageKeys()) * params.TxAccessListStorageKeyGas
+	}
 	return gas, nil
 }
 
 // NewStateTransition initialises and returns a new state transition object.
 func NewStateTransition(evm *vm.EVM, msg Message, gp *GasPool) *StateTransition {
 	return &StateTransition{
-		gp:       gp,
-		evm:      evm,
-		msg:      msg,
-		gasPrice: msg.GasPrice(),
-		value:    msg.Value(),
-		data:     msg.Data(),
-		state:    evm.StateDB,
+		gp:        gp,
+		evm:       evm,
+		msg:       msg,
+		gasPrice:  msg.GasPrice(),
+		gasFeeCap: msg.GasFeeCap(),
+		gasTipCap: msg.GasTipCap(),
+		value:     msg.Value(),
+		data:      msg.Data(),
+		state:     evm.StateDB,
 	}
 }
 
@@ -186,7 +201,13 @@ func (st *StateTransition) to() common.Address {
 
 func (st *StateTransition) buyGas() error {
 	mgval := new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Gas()), st.gasPrice)
-	if st.state.GetBalance(st.msg.From()).Cmp(mgval) < 0 {
+	balanceCheck := mgval
+	if st.gasFeeCap != nil {
+		// The sender must be able to pay the fee cap even if the
+		// effective gas price is lower.
+		balanceCheck = new(big.Int).Mul(new(big.Int).SetUint64(st.msg.Gas()), st.gasFeeCap)
+	}
+	if st.state.GetBalance(st.msg.From()).Cmp(balanceCheck) < 0 {
 		return errInsufficientBalanceForGas
 	}
 	if err := st.gp.SubGas(st.msg.Gas()); err != nil {
@@ -209,9 +230,42 @@ func (st *StateTransition) preCheck() error {
 			return ErrNonceTooLow
 		}
 	}
+	// Make sure that the fee caps are valid once the dynamic fee market is active.
+	// Skip the checks if the fee fields are zero and the base fee is explicitly
+	// disabled, it's used by eth_call to run the messages without a gas price.
+	if st.evm.DynamicFeeEnabled() && st.gasFeeCap != nil && st.gasTipCap != nil {
+		if !st.evm.GetVMConfig().NoBaseFee || st.gasFeeCap.BitLen() > 0 || st.gasTipCap.BitLen() > 0 {
+			if err := checkFeeCaps(st.msg, st.evm.Context.BaseFee); err != nil {
+				return err
+			}
+		}
+	}
 	return st.buyGas()
 }
 
+// checkFeeCaps checks that the tip of the message is not above its fee cap and
+// that the fee cap covers the base fee of the block.
+func checkFeeCaps(msg Message, baseFee *big.Int) error {
+	gasFeeCap, gasTipCap := msg.GasFeeCap(), msg.GasTipCap()
+	if l := gasFeeCap.BitLen(); l > 256 {
+		return fmt.Errorf("%w: address %v, maxFeePerGas bit length: %d", ErrFeeCapVeryHigh,
+			msg.From().Hex(), l)
+	}
+	if l := gasTipCap.BitLen(); l > 256 {
+		return fmt.Errorf("%w: address %v, maxPriorityFeePerGas bit length: %d", ErrTipVeryHigh,
+			msg.From().Hex(), l)
+	}
+	if gasFeeCap.Cmp(gasTipCap) < 0 {
+		return fmt.Errorf("%w: address %v, maxPriorityFeePerGas: %s, maxFeePerGas: %s", ErrTipAboveFeeCap,
+			msg.From().Hex(), gasTipCap, gasFeeCap)
+	}
+	if baseFee != nil && gasFeeCap.Cmp(baseFee) < 0 {
+		return fmt.Errorf("%w: address %v, maxFeePerGas: %s baseFee: %s", ErrFeeCapTooLow,
+			msg.From().Hex(), gasFeeCap, baseFee)
+	}
+	return nil
+}
+
 // TransitionDb will transition the state by applying the current message and
 // returning the result including the used gas. It returns an error if failed.
 // An error indicates a consensus issue.
@@ -226,7 +280,7 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	contractCreation := msg.To() == nil
 
 	// Pay intrinsic gas
-	gas, err := IntrinsicGas(st.data, contractCreation, st.state)
+	gas, err := IntrinsicGas(st.data, msg.AccessList(), contractCreation, st.state)
 	if err != nil {
 		return nil, err
 	}
@@ -236,6 +290,10 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	}
 	st.gas -= gas
 
+	if st.evm.AccessListEnabled() {
+		st.state.PrepareAccessList(msg.From(), msg.To(), vm.ActivePrecompiles(), msg.AccessList())
+	}
+
 	// Limit the time it takes for a virtual machine to execute the smart contract,
 	// Except precompiled contracts.
 	ctx := context.Background()
@@ -285,8 +343,7 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	}
 
 	st.refundGas()
-
-	st.state.AddBalance(st.evm.Coinbase, new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.gasPrice))
+	st.payFees()
 
 	return &ExecutionResult{
 		UsedGas:    st.gasUsed(),
@@ -312,6 +369,31 @@ func (st *StateTransition) refundGas() {
 	st.gp.AddGas(st.gas)
 }
 
+// payFees pays the fees of the used gas. The block producer earns the whole
+// gas price before the dynamic fee market. Once it is active, the base fee part
+// goes to the reward pool and funds the staking rewards of the next years, and
+// the block producer earns the tip.
+func (st *StateTransition) payFees() {
+	gasUsed := new(big.Int).SetUint64(st.gasUsed())
+	baseFee, tip := SplitGasPrice(st.gasPrice, st.evm.Context.BaseFee, st.evm.DynamicFeeEnabled())
+	if baseFee.Sign() > 0 {
+		st.state.AddBalance(cvm.RewardManagerPoolAddr, new(big.Int).Mul(gasUsed, baseFee))
+	}
+	st.state.AddBalance(st.evm.Coinbase, new(big.Int).Mul(gasUsed, tip))
+}
+
+// SplitGasPrice splits the effective gas price of a transaction into the part
+// of the base fee that goes to the reward pool and the tip of the block producer.
+func SplitGasPrice(gasPrice, baseFee *big.Int, dynamicFee bool) (*big.Int, *big.Int) {
+	if !dynamicFee || baseFee == nil {
+		return new(big.Int), gasPrice
+	}
+	// The gas price is lower than the base fee only if the base fee is
+	// disabled for the call.
+	pooled := cmath.BigMin(gasPrice, baseFee)
+	return pooled, new(big.Int).Sub(gasPrice, pooled)
+}
+
 // gasUsed returns the amount of gas used up by the state transition.
 func (st *StateTransition) gasUsed() uint64 {
 	return st.initialGas - st.gas
@@ -0,0 +1,116 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	cvm "github.com/AlayaNetwork/Alaya-Go/common/vm"
+	"github.com/AlayaNetwork/Alaya-Go/core/rawdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/state"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/core/vm"
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/params"
+	"github.com/AlayaNetwork/Alaya-Go/x/gov"
+)
+
+// Tests that the base fee of the dynamic fee transactions goes to the reward
+// pool and the block producer earns the tip.
+func TestDynamicFeePayment(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	coinbase := common.Address{0xcb}
+	signer := types.NewLondonSigner(common.Big1)
+	baseFee := big.NewInt(100)
+
+	tests := []struct {
+		version   uint32
+		tx        types.TxData
+		err       error
+		tip, pool int64 // the fees per gas paid to the coinbase and the reward pool
+	}{
+		{params.FORKVERSION_0_17_0, &types.LegacyTx{GasPrice: big.NewInt(150)}, nil, 150, 0},
+		{params.FORKVERSION_0_18_0, &types.LegacyTx{GasPrice: big.NewInt(150)}, nil, 50, 100},
+		{params.FORKVERSION_0_18_0, &types.LegacyTx{GasPrice: big.NewInt(50)}, ErrFeeCapTooLow, 0, 0},
+		{params.FORKVERSION_0_18_0, &types.DynamicFeeTx{GasFeeCap: big.NewInt(300), GasTipCap: big.NewInt(20)}, nil, 20, 100},
+		{params.FORKVERSION_0_18_0, &types.DynamicFeeTx{GasFeeCap: big.NewInt(110), GasTipCap: big.NewInt(20)}, nil, 10, 100},
+		{params.FORKVERSION_0_18_0, &types.DynamicFeeTx{GasFeeCap: big.NewInt(10), GasTipCap: big.NewInt(20)}, ErrTipAboveFeeCap, 0, 0},
+	}
+	for i, test := range tests {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		if err := gov.AddActiveVersion(test.version, 0, statedb); err != nil {
+			t.Fatal(err)
+		}
+		statedb.AddBalance(from, big.NewInt(params.ATP))
+		statedb.Finalise(true)
+
+		to := common.Address{0x01}
+		switch inner := test.tx.(type) {
+		case *types.LegacyTx:
+			inner.To, inner.Gas, inner.Value = &to, params.TxGas, new(big.Int)
+		case *types.DynamicFeeTx:
+			inner.ChainID, inner.To, inner.Gas, inner.Value = common.Big1, &to, params.TxGas, new(big.Int)
+		}
+		tx, err := types.SignTx(types.NewTx(test.tx), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		header := &types.Header{Number: common.Big1, Time: common.Big1, Coinbase: coinbase, GasLimit: params.TxGas}
+		if test.version >= params.FORKVERSION_0_18_0 {
+			header.BaseFee = baseFee
+		}
+		msg, err := tx.AsMessage(signer, header.BaseFee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vmctx := vm.Context{
+			CanTransfer: CanTransfer,
+			Transfer:    Transfer,
+			Coinbase:    coinbase,
+			BlockNumber: header.Number,
+			Time:        header.Time,
+			GasPrice:    msg.GasPrice(),
+			BaseFee:     header.BaseFee,
+			Ctx:         context.Background(),
+		}
+		vmenv := vm.NewEVM(vmctx, nil, statedb, params.TestChainConfig, vm.Config{})
+
+		result, err := ApplyMessage(vmenv, msg, new(GasPool).AddGas(params.TxGas))
+		if !errors.Is(err, test.err) {
+			t.Fatalf("test %d: error mismatch: have %v, want %v", i, err, test.err)
+		}
+		if err != nil {
+			continue
+		}
+		gasUsed := new(big.Int).SetUint64(result.UsedGas)
+		if have, want := statedb.GetBalance(coinbase), new(big.Int).Mul(gasUsed, big.NewInt(test.tip)); have.Cmp(want) != 0 {
+			t.Errorf("test %d: coinbase balance mismatch: have %v, want %v", i, have, want)
+		}
+		if have, want := statedb.GetBalance(cvm.RewardManagerPoolAddr), new(big.Int).Mul(gasUsed, big.NewInt(test.pool)); have.Cmp(want) != 0 {
+			t.Errorf("test %d: reward pool balance mismatch: have %v, want %v", i, have, want)
+		}
+		paid := new(big.Int).Sub(big.NewInt(params.ATP), statedb.GetBalance(from))
+		if want := new(big.Int).Mul(gasUsed, big.NewInt(test.tip+test.pool)); paid.Cmp(want) != 0 {
+			t.Errorf("test %d: sender payment mismatch: have %v, want %v", i, paid, want)
+		}
+	}
+}
@@ -17,6 +17,8 @@
 package core
 
 import (
+	"crypto/ecdsa"
+	"math/big"
 	"math/rand"
 	"testing"
 
@@ -51,3 +53,71 @@ func TestStrictTxListAdd(t *testing.T) {
 		}
 	}
 }
+
+func dynamicFeeTx(nonce uint64, gasFeeCap, gasTipCap int64, key *ecdsa.PrivateKey) *types.Transaction {
+	tx, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   common.Big1,
+		Nonce:     nonce,
+		To:        &common.Address{},
+		Value:     big.NewInt(100),
+		Gas:       21000,
+		GasFeeCap: big.NewInt(gasFeeCap),
+		GasTipCap: big.NewInt(gasTipCap),
+	}), types.NewLondonSigner(common.Big1), key)
+	return tx
+}
+
+// Tests that a dynamic fee transaction is replaced only if both the fee cap and
+// the tip are bumped.
+func TestTxListReplaceDynamicFee(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+
+	list := newTxList(true)
+	if ok, _ := list.Add(dynamicFeeTx(0, 100, 10, key), 10); !ok {
+		t.Fatal("failed to add the first transaction")
+	}
+	for i, test := range []struct {
+		gasFeeCap, gasTipCap int64
+		replaced             bool
+	}{
+		{120, 10, false}, // the tip is not bumped
+		{105, 20, false}, // the fee cap is bumped less than 10%
+		{110, 11, true},
+	} {
+		if ok, _ := list.Add(dynamicFeeTx(0, test.gasFeeCap, test.gasTipCap, key), 10); ok != test.replaced {
+			t.Errorf("test %d: replaced %v, want %v", i, ok, test.replaced)
+		}
+	}
+}
+
+// Tests that the priced list sorts the transactions by the effective tips once
+// the base fee is set.
+func TestTxPricedListBaseFee(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+
+	all := newTxLookup()
+	priced := newTxPricedList(all)
+	txs := types.Transactions{
+		dynamicFeeTx(0, 300, 5, key),                                   // effective tip 5 at the base fee 90
+		dynamicFeeTx(1, 200, 20, key),                                  // effective tip 20 at the base fee 90
+		pricedTransaction(2, 21000, big.NewInt(150), key, common.Big1), // effective tip 60 at the base fee 90
+	}
+	for _, tx := range txs {
+		all.Add(tx)
+		priced.Put(tx)
+	}
+	if cheapest := priced.items.list[0]; cheapest != txs[2] {
+		t.Fatalf("the cheapest transaction must have the lowest fee cap without base fee, have nonce %d", cheapest.Nonce())
+	}
+	priced.SetBaseFee(big.NewInt(90))
+	if cheapest := priced.items.list[0]; cheapest != txs[0] {
+		t.Fatalf("the cheapest transaction must have the lowest effective tip, have nonce %d", cheapest.Nonce())
+	}
+	locals := newAccountSet(types.NewLondonSigner(common.Big1))
+	if !priced.Underpriced(dynamicFeeTx(3, 93, 50, key), locals) {
+		t.Error("the transaction with the effective tip 3 must be underpriced")
+	}
+	if priced.Underpriced(dynamicFeeTx(3, 100, 10, key), locals) {
+		t.Error("the transaction with the effective tip 10 must not be underpriced")
+	}
+}
@@ -84,6 +84,9 @@ type Header struct {
 	Extra       []byte         `json:"extraData"        gencodec:"required"`
 	Nonce       BlockNonce     `json:"nonce"            gencodec:"required"`
 
+	// BaseFee was added by EIP-1559 and is ignored in legacy headers.
+	BaseFee *big.Int `json:"baseFeePerGas" rlp:"optional"`
+
 	// caches
 	sealHash  atomic.Value `json:"-" rlp:"-"`
 	hash      atomic.Value `json:"-" rlp:"-"`
@@ -97,6 +100,7 @@ type headerMarshaling struct {
 	GasUsed  hexutil.Uint64
 	Time     *hexutil.Big
 	Extra    hexutil.Bytes
+	BaseFee  *hexutil.Big
 	Hash     common.Hash `json:"hash"` // adds call to Hash() in MarshalJSON
 }
 
@@ -150,7 +154,7 @@ func (h *Header) _sealHash() (hash common.Hash) {
 	if len(h.Extra) > 32 {
 		extra = h.Extra[0:32]
 	}
-	rlp.Encode(hasher, []interface{}{
+	enc := []interface{}{
 		h.ParentHash,
 		h.Coinbase,
 		h.Root,
@@ -163,7 +167,11 @@ func (h *Header) _sealHash() (hash common.Hash) {
 		h.Time,
 		extra,
 		h.Nonce,
-	})
+	}
+	if h.BaseFee != nil {
+		enc = append(enc, h.BaseFee)
+	}
+	rlp.Encode(hasher, enc)
 
 	hasher.Sum(hash[:0])
 	return hash
@@ -211,6 +219,18 @@ func rlpHash(x interface{}) (h common.Hash) {
 	return h
 }
 
+// prefixedRlpHash writes the prefix into the hasher before rlp-encoding x.
+// It's used for typed transactions.
+func prefixedRlpHash(prefix byte, x interface{}) (h common.Hash) {
+	sha := hasherPool.Get().(crypto.KeccakState)
+	defer hasherPool.Put(sha)
+	sha.Reset()
+	sha.Write([]byte{prefix})
+	rlp.Encode(sha, x)
+	sha.Read(h[:])
+	return h
+}
+
 // Body is a simple (mutable, non-safe) data container for storing and moving
 // a block's data contents (transactions) together.
 type Body struct {
@@ -312,6 +332,9 @@ func CopyHeader(h *Header) *Header {
 	if cpy.Number = new(big.Int); h.Number != nil {
 		cpy.Number.Set(h.Number)
 	}
+	if h.BaseFee != nil {
+		cpy.BaseFee = new(big.Int).Set(h.BaseFee)
+	}
 	if len(h.Extra) > 0 {
 		cpy.Extra = make([]byte, len(h.Extra))
 		copy(cpy.Extra, h.Extra)
@@ -379,6 +402,15 @@ func (b *Block) TxHash() common.Hash      { return b.header.TxHash }
 func (b *Block) ReceiptHash() common.Hash { return b.header.ReceiptHash }
 func (b *Block) Extra() []byte            { return common.CopyBytes(b.header.Extra) }
 
+// BaseFee returns the base fee of the block, it is nil if the dynamic fee
+// market is not enabled at the block.
+func (b *Block) BaseFee() *big.Int {
+	if b.header.BaseFee == nil {
+		return nil
+	}
+	return new(big.Int).Set(b.header.BaseFee)
+}
+
 func (b *Block) Header() *Header { return CopyHeader(b.header) }
 
 // Body returns the non-header content of the block.
@@ -68,3 +68,47 @@ func TestBlockEncoding(t *testing.T) {
 		t.Errorf("encoded block mismatch:\ngot:  %x\nwant: %x", ourBlockEnc, blockEnc)
 	}
 }
+
+func TestBlockEncodingWithBaseFee(t *testing.T) {
+	header := &Header{
+		Number:   big.NewInt(1),
+		GasLimit: 3141592,
+		GasUsed:  21000,
+		Time:     big.NewInt(1426516743),
+		Extra:    make([]byte, 97),
+	}
+	legacy := NewBlockWithHeader(header)
+	header.BaseFee = big.NewInt(1000000000)
+	block := NewBlockWithHeader(header)
+	if block.Hash() == legacy.Hash() {
+		t.Fatal("the base fee must be covered by the block hash")
+	}
+
+	enc, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		t.Fatal("encode error: ", err)
+	}
+	var dec Block
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal("decode error: ", err)
+	}
+	if dec.BaseFee() == nil || dec.BaseFee().Cmp(header.BaseFee) != 0 {
+		t.Fatalf("base fee mismatch: got %v, want %v", dec.BaseFee(), header.BaseFee)
+	}
+	if dec.Hash() != block.Hash() {
+		t.Errorf("hash mismatch: got %x, want %x", dec.Hash(), block.Hash())
+	}
+
+	// The header without base fee is decoded with a nil base fee.
+	enc, err = rlp.EncodeToBytes(legacy)
+	if err != nil {
+		t.Fatal("encode error: ", err)
+	}
+	var legacyDec Block
+	if err := rlp.DecodeBytes(enc, &legacyDec); err != nil {
+		t.Fatal("decode error: ", err)
+	}
+	if legacyDec.BaseFee() != nil || legacyDec.Hash() != legacy.Hash() {
+		t.Errorf("the legacy header is decoded with base fee %v", legacyDec.BaseFee())
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+)
+
+func TestReceiptEncoding(t *testing.T) {
+	logs := []*Log{{
+		Address: common.BytesToAddress([]byte{0x11}),
+		Topics:  []common.Hash{common.HexToHash("dead"), common.HexToHash("beef")},
+		Data:    []byte{0x01, 0x00, 0xff},
+	}}
+	for _, typ := range []uint8{LegacyTxType, AccessListTxType, DynamicFeeTxType} {
+		want := &Receipt{
+			Type:              typ,
+			Status:            ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000,
+			Logs:              logs,
+		}
+		want.Bloom = CreateBloom(Receipts{want})
+
+		// Round-trip the binary encoding.
+		bin, err := want.MarshalBinary()
+		if err != nil {
+			t.Fatalf("type %d: marshal error: %v", typ, err)
+		}
+		if typ != LegacyTxType && bin[0] != typ {
+			t.Errorf("type %d: envelope type mismatch: have %d", typ, bin[0])
+		}
+		have := new(Receipt)
+		if err := have.UnmarshalBinary(bin); err != nil {
+			t.Fatalf("type %d: unmarshal error: %v", typ, err)
+		}
+		if !reflect.DeepEqual(have, want) {
+			t.Errorf("type %d: binary round-trip mismatch: have %+v, want %+v", typ, have, want)
+		}
+
+		// Round-trip the RLP encoding, which wraps typed receipts in a string.
+		enc, err := rlp.EncodeToBytes(want)
+		if err != nil {
+			t.Fatalf("type %d: encode error: %v", typ, err)
+		}
+		have = new(Receipt)
+		if err := rlp.DecodeBytes(enc, have); err != nil {
+			t.Fatalf("type %d: decode error: %v", typ, err)
+		}
+		if !reflect.DeepEqual(have, want) {
+			t.Errorf("type %d: RLP round-trip mismatch: have %+v, want %+v", typ, have, want)
+		}
+		// The derived hash is built from the binary encoding.
+		if typ != LegacyTxType && !bytes.Equal(Receipts{have}.GetRlp(0), bin) {
+			t.Errorf("type %d: derivable encoding mismatch", typ)
+		}
+	}
+}
+
+func TestReceiptUnsupportedType(t *testing.T) {
+	receipt := &Receipt{Type: DynamicFeeTxType + 1, Status: ReceiptStatusFailed}
+	bin, err := receipt.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := new(Receipt).UnmarshalBinary(bin); err != ErrTxTypeNotSupported {
+		t.Fatalf("binary decoding error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+	enc, _ := rlp.EncodeToBytes(receipt)
+	if err := rlp.DecodeBytes(enc, new(Receipt)); err != ErrTxTypeNotSupported {
+		t.Fatalf("RLP decoding error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+	if err := new(Receipt).UnmarshalBinary(nil); err != errEmptyTypedReceipt {
+		t.Fatalf("empty decoding error mismatch: have %v, want %v", err, errEmptyTypedReceipt)
+	}
+}
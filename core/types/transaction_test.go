@@ -199,7 +199,7 @@ func TestTransactionPriceNonceSort(t *testing.T) {
 		}
 	}
 	// Sort the transactions and cross check the nonce ordering
-	txset := NewTransactionsByPriceAndNonce(signer, groups)
+	txset := NewTransactionsByPriceAndNonce(signer, groups, nil)
 
 	txs := Transactions{}
 	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
@@ -273,3 +273,239 @@ func TestTransactionJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestAccessListTransaction(t *testing.T) {
+	key, addr := defaultTestKey()
+	signer := NewEIP2930Signer(big.NewInt(201030))
+	to := common.Address{1}
+	tx, err := SignTx(NewTx(&AccessListTx{
+		ChainID:  big.NewInt(201030),
+		Nonce:    3,
+		To:       &to,
+		Value:    big.NewInt(10),
+		Gas:      25000,
+		GasPrice: big.NewInt(1),
+		Data:     common.FromHex("5544"),
+		AccessList: AccessList{
+			{Address: to, StorageKeys: []common.Hash{{0}, {1}}},
+		},
+	}), signer, key)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	if tx.Type() != AccessListTxType {
+		t.Fatalf("wrong transaction type %d", tx.Type())
+	}
+	if from, err := Sender(signer, tx); err != nil || from != addr {
+		t.Fatalf("wrong sender %s, want %s, err %v", from.String(), addr.String(), err)
+	}
+	if _, err := Sender(NewEIP155Signer(big.NewInt(201030)), tx); err != ErrTxTypeNotSupported {
+		t.Fatalf("want ErrTxTypeNotSupported from the EIP155 signer, have %v", err)
+	}
+
+	// The binary encoding is the EIP-2718 envelope, the RLP encoding wraps it in a string.
+	bin, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bin[0] != AccessListTxType {
+		t.Fatalf("the binary encoding must start with the type, have %x", bin[0])
+	}
+	if tx.Hash() != crypto.Keccak256Hash(bin) {
+		t.Fatal("the hash must be the hash of the binary encoding")
+	}
+	var binTx Transaction
+	if err := binTx.UnmarshalBinary(bin); err != nil || binTx.Hash() != tx.Hash() {
+		t.Fatalf("binary decoding fail %v", err)
+	}
+	enc, err := rlp.EncodeToBytes(Transactions{tx, rightvrsTx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var txs Transactions
+	if err := rlp.DecodeBytes(enc, &txs); err != nil {
+		t.Fatal(err)
+	}
+	if txs[0].Hash() != tx.Hash() || txs[1].Hash() != rightvrsTx.Hash() || txs[0].Size() != tx.Size() {
+		t.Fatal("the transactions are not decoded from rlp")
+	}
+	if len(txs[0].AccessList()) != 1 || txs[0].AccessList().StorageKeys() != 2 {
+		t.Fatalf("wrong access list %v", txs[0].AccessList())
+	}
+
+	data, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsedTx *Transaction
+	if err := json.Unmarshal(data, &parsedTx); err != nil {
+		t.Fatal(err)
+	}
+	if parsedTx.Hash() != tx.Hash() || parsedTx.ChainId().Cmp(tx.ChainId()) != 0 {
+		t.Errorf("parsed tx differs from original tx, want %v, got %v", tx, parsedTx)
+	}
+}
+
+func TestTypedReceiptEncoding(t *testing.T) {
+	receipts := Receipts{
+		&Receipt{Status: ReceiptStatusSuccessful, CumulativeGasUsed: 1, Logs: []*Log{}},
+		&Receipt{Type: AccessListTxType, Status: ReceiptStatusFailed, CumulativeGasUsed: 2, Logs: []*Log{}},
+	}
+	if receipts.GetRlp(1)[0] != AccessListTxType {
+		t.Fatal("the typed receipt must be derived in the EIP-2718 envelope")
+	}
+	enc, err := rlp.EncodeToBytes(receipts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec Receipts
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range dec {
+		if r.Type != receipts[i].Type || r.Status != receipts[i].Status || r.CumulativeGasUsed != receipts[i].CumulativeGasUsed {
+			t.Errorf("receipt %d: want %+v, have %+v", i, receipts[i], r)
+		}
+	}
+}
+
+func TestDynamicFeeTransaction(t *testing.T) {
+	key, addr := defaultTestKey()
+	signer := NewLondonSigner(big.NewInt(201030))
+	to := common.Address{1}
+	tx, err := SignTx(NewTx(&DynamicFeeTx{
+		ChainID:   big.NewInt(201030),
+		Nonce:     3,
+		To:        &to,
+		Value:     big.NewInt(10),
+		Gas:       25000,
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(10),
+		Data:      common.FromHex("5544"),
+	}), signer, key)
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	if tx.Type() != DynamicFeeTxType {
+		t.Fatalf("wrong transaction type %d", tx.Type())
+	}
+	if from, err := Sender(signer, tx); err != nil || from != addr {
+		t.Fatalf("wrong sender %s, want %s, err %v", from.String(), addr.String(), err)
+	}
+	if _, err := Sender(NewEIP2930Signer(big.NewInt(201030)), tx); err != ErrTxTypeNotSupported {
+		t.Fatalf("want ErrTxTypeNotSupported from the EIP2930 signer, have %v", err)
+	}
+	if tx.GasPrice().Cmp(tx.GasFeeCap()) != 0 || tx.Cost().Cmp(big.NewInt(25000*10+10)) != 0 {
+		t.Fatalf("the cost must be paid at the fee cap, have %v", tx.Cost())
+	}
+
+	// The effective tip is the tip capped by the fee cap minus the base fee.
+	for i, test := range []struct {
+		baseFee *big.Int
+		tip     int64
+		err     error
+	}{
+		{nil, 2, nil},
+		{big.NewInt(5), 2, nil},
+		{big.NewInt(9), 1, nil},
+		{big.NewInt(11), -1, ErrGasFeeCapTooLow},
+	} {
+		tip, err := tx.EffectiveGasTip(test.baseFee)
+		if tip.Cmp(big.NewInt(test.tip)) != 0 || err != test.err {
+			t.Errorf("test %d: have tip %v err %v, want tip %d err %v", i, tip, err, test.tip, test.err)
+		}
+	}
+	msg, err := tx.AsMessage(signer, big.NewInt(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.GasPrice().Cmp(big.NewInt(10)) != 0 || msg.GasTipCap().Cmp(big.NewInt(2)) != 0 || msg.GasFeeCap().Cmp(big.NewInt(10)) != 0 {
+		t.Fatalf("wrong message prices %v %v %v", msg.GasPrice(), msg.GasTipCap(), msg.GasFeeCap())
+	}
+
+	bin, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bin[0] != DynamicFeeTxType {
+		t.Fatalf("the binary encoding must start with the type, have %x", bin[0])
+	}
+	var binTx Transaction
+	if err := binTx.UnmarshalBinary(bin); err != nil || binTx.Hash() != tx.Hash() {
+		t.Fatalf("binary decoding fail %v", err)
+	}
+	if binTx.GasTipCap().Cmp(tx.GasTipCap()) != 0 || binTx.GasFeeCap().Cmp(tx.GasFeeCap()) != 0 {
+		t.Fatal("the fee caps are not decoded")
+	}
+
+	data, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsedTx *Transaction
+	if err := json.Unmarshal(data, &parsedTx); err != nil {
+		t.Fatal(err)
+	}
+	if parsedTx.Hash() != tx.Hash() || parsedTx.GasTipCap().Cmp(tx.GasTipCap()) != 0 {
+		t.Errorf("parsed tx differs from original tx, want %v, got %v", tx, parsedTx)
+	}
+}
+
+// Tests that the transactions are sorted by the effective tips if the base fee
+// is given, and the ones whose fee cap is below the base fee are dropped.
+func TestTransactionPriceNonceSortWithBaseFee(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 25)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	baseFee := big.NewInt(10)
+	signer := NewLondonSigner(common.Big1)
+	groups := map[common.Address]Transactions{}
+	expected := 0
+	for start, key := range keys {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		var inner TxData
+		if start%2 == 0 {
+			inner = &LegacyTx{
+				Nonce:    uint64(start),
+				To:       &common.Address{},
+				Value:    big.NewInt(100),
+				Gas:      100,
+				GasPrice: big.NewInt(int64(start)),
+			}
+		} else {
+			inner = &DynamicFeeTx{
+				ChainID:   common.Big1,
+				Nonce:     uint64(start),
+				To:        &common.Address{},
+				Value:     big.NewInt(100),
+				Gas:       100,
+				GasFeeCap: big.NewInt(int64(start)),
+				GasTipCap: big.NewInt(int64(start % 7)),
+			}
+		}
+		tx, _ := SignTx(NewTx(inner), signer, key)
+		groups[addr] = append(groups[addr], tx)
+		if tx.GasFeeCapIntCmp(baseFee) >= 0 {
+			expected++
+		}
+	}
+	txset := NewTransactionsByPriceAndNonce(signer, groups, baseFee)
+
+	txs := Transactions{}
+	for tx := txset.Peek(); tx != nil; tx = txset.Peek() {
+		txs = append(txs, tx)
+		txset.Shift()
+	}
+	if len(txs) != expected {
+		t.Errorf("expected %d transactions, found %d", expected, len(txs))
+	}
+	for i, tx := range txs {
+		if tx.GasFeeCapIntCmp(baseFee) < 0 {
+			t.Errorf("tx #%d: the fee cap %v is below the base fee", i, tx.GasFeeCap())
+		}
+		if i+1 < len(txs) && tx.EffectiveGasTipCmp(txs[i+1], baseFee) < 0 {
+			t.Errorf("invalid tip ordering: tx #%d (T=%v) < tx #%d (T=%v)", i, tx.EffectiveGasTipValue(baseFee), i+1, txs[i+1].EffectiveGasTipValue(baseFee))
+		}
+	}
+}
@@ -130,6 +130,19 @@ func IsPlatONPrecompiledContract(addr common.Address) bool {
 	return false
 }
 
+// ActivePrecompiles returns the addresses of the EVM and the PlatON precompiled
+// contracts, they are warm in the access list of every transaction.
+func ActivePrecompiles() []common.Address {
+	addrs := make([]common.Address, 0, len(PrecompiledContractsByzantium)+len(PlatONPrecompiledContracts))
+	for addr := range PrecompiledContractsByzantium {
+		addrs = append(addrs, addr)
+	}
+	for addr := range PlatONPrecompiledContracts {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
 func IsPrecompiledContract(addr common.Address) bool {
 	if IsEVMPrecompiledContract(addr) {
 		return true
@@ -26,6 +26,7 @@ import (
 
 	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
 
+	"github.com/AlayaNetwork/Alaya-Go/x/gov"
 	"github.com/AlayaNetwork/Alaya-Go/x/plugin"
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
@@ -57,6 +58,10 @@ func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, err
 		}
 
 		if p := PlatONPrecompiledContracts[*contract.CodeAddr]; p != nil {
+			if evm.vmConfig.Speculative {
+				evm.Cancel()
+				return nil, ErrAbort
+			}
 			switch p.(type) {
 
 			case *validatorInnerContract:
@@ -72,28 +77,28 @@ func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, err
 					Contract: contract,
 					Evm:      evm,
 				}
-				return RunPlatONPrecompiledContract(staking, input, contract)
+				return runPlatONPrecompiledContract(evm, staking, input, contract)
 			case *RestrictingContract:
 				restricting := &RestrictingContract{
 					Plugin:   plugin.RestrictingInstance(),
 					Contract: contract,
 					Evm:      evm,
 				}
-				return RunPlatONPrecompiledContract(restricting, input, contract)
+				return runPlatONPrecompiledContract(evm, restricting, input, contract)
 			case *SlashingContract:
 				slashing := &SlashingContract{
 					Plugin:   plugin.SlashInstance(),
 					Contract: contract,
 					Evm:      evm,
 				}
-				return RunPlatONPrecompiledContract(slashing, input, contract)
+				return runPlatONPrecompiledContract(evm, slashing, input, contract)
 			case *GovContract:
 				govContract := &GovContract{
 					Plugin:   plugin.GovPluginInstance(),
 					Contract: contract,
 					Evm:      evm,
 				}
-				return RunPlatONPrecompiledContract(govContract, input, contract)
+				return runPlatONPrecompiledContract(evm, govContract, input, contract)
 			case *DelegateRewardContract:
 				delegateRewardContract := &DelegateRewardContract{
 					Plugin:    plugin.RewardMgrInstance(),
@@ -101,7 +106,7 @@ func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, err
 					Contract:  contract,
 					Evm:       evm,
 				}
-				return RunPlatONPrecompiledContract(delegateRewardContract, input, contract)
+				return runPlatONPrecompiledContract(evm, delegateRewardContract, input, contract)
 
 			}
 		}
@@ -148,6 +153,7 @@ type Context struct {
 	BlockNumber *big.Int       // Provides information for NUMBER
 	Time        *big.Int       // Provides information for TIME
 	Difficulty  *big.Int       // Provides information for DIFFICULTY  (This one must not be deleted, otherwise the solidity contract will be failed)
+	BaseFee     *big.Int       // Provides information for BASEFEE, nil if the dynamic fee market is not enabled
 
 	BlockHash common.Hash // Only, the value will be available after the current block has been sealed.
 
@@ -189,6 +195,15 @@ type EVM struct {
 	// available gas is calculated in gasCall* according to the 63/64 rule and later
 	// applied in opCall*.
 	callGasTemp uint64
+	// wasmCallStack holds the addresses of the WASM contracts being executed,
+	// it is only maintained when the WASM contracts are traced.
+	wasmCallStack []common.Address
+	// accessListEnabled is set if the access list gas rules are active in the
+	// version of the state, see AccessListEnabled.
+	accessListEnabled bool
+	// dynamicFeeEnabled is set if the dynamic fee market is active in the
+	// version of the state, see DynamicFeeEnabled.
+	dynamicFeeEnabled bool
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -202,6 +217,11 @@ func NewEVM(ctx Context, snapshotDB snapshotdb.DB, statedb StateDB, chainConfig
 		chainConfig:  chainConfig,
 		interpreters: make([]Interpreter, 0, 1),
 	}
+	if statedb != nil {
+		version := statedb.GetCurrentActiveVersion()
+		evm.accessListEnabled = gov.Gte0170Version(version)
+		evm.dynamicFeeEnabled = gov.Gte0180Version(version)
+	}
 
 	evm.interpreters = append(evm.interpreters, NewEVMInterpreter(evm, vmConfig))
 	evm.interpreters = append(evm.interpreters, NewWASMInterpreter(evm, vmConfig))
@@ -226,6 +246,18 @@ func (evm *EVM) DBSnapshot() (snapshotID int, StateDBID int) {
 	return 0, evm.StateDB.Snapshot()
 }
 
+// AccessListEnabled returns whether the typed transactions and the EIP-2929
+// access list gas rules are enabled by the governance version 0.17.0.
+func (evm *EVM) AccessListEnabled() bool {
+	return evm.accessListEnabled
+}
+
+// DynamicFeeEnabled returns whether the dynamic fee transactions and the BASEFEE
+// opcode are enabled by the governance version 0.18.0.
+func (evm *EVM) DynamicFeeEnabled() bool {
+	return evm.dynamicFeeEnabled
+}
+
 // Cancel cancels any running EVM operation. This may be called concurrently and
 // it's safe to be called multiple times.
 func (evm *EVM) Cancel() {
@@ -450,6 +482,11 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	}
 	nonce := evm.StateDB.GetNonce(caller.Address())
 	evm.StateDB.SetNonce(caller.Address(), nonce+1)
+	// We add this to the access list _before_ taking a snapshot. Even if the creation fails,
+	// the access-list change should not be rolled back
+	if evm.accessListEnabled {
+		evm.StateDB.AddAddressToAccessList(address)
+	}
 
 	// Ensure there's no existing contract already at the designated address
 	contractHash := evm.StateDB.GetCodeHash(address)
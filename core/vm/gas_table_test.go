@@ -21,7 +21,9 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
 	"github.com/AlayaNetwork/Alaya-Go/core/rawdb"
 	"github.com/AlayaNetwork/Alaya-Go/core/state"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
 	"github.com/AlayaNetwork/Alaya-Go/params"
+	"github.com/AlayaNetwork/Alaya-Go/x/gov"
 	"math/big"
 	"testing"
 
@@ -108,3 +110,92 @@ func TestEIP2200(t *testing.T) {
 		}
 	}
 }
+
+var eip2929Tests = []struct {
+	version    uint32
+	accessList types.AccessList
+	used       uint64
+}{
+	{params.FORKVERSION_0_16_0, nil, 406},                                                 // SLOAD is 200 before the access list
+	{params.FORKVERSION_0_17_0, nil, 2206},                                                // cold SLOAD then warm SLOAD
+	{params.FORKVERSION_0_17_0, types.AccessList{{StorageKeys: []common.Hash{{}}}}, 2206}, // the slot of another address
+	{params.FORKVERSION_0_17_0, types.AccessList{{Address: common.BytesToAddress([]byte("contract")), StorageKeys: []common.Hash{{}}}}, 206},
+}
+
+func TestEIP2929(t *testing.T) {
+	for i, tt := range eip2929Tests {
+		address := common.BytesToAddress([]byte("contract"))
+
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		if err := gov.AddActiveVersion(tt.version, 0, statedb); err != nil {
+			t.Fatal(err)
+		}
+		statedb.CreateAccount(address)
+		statedb.SetCode(address, hexutil.MustDecode("0x60005460005400"))
+		statedb.Finalise(true)
+
+		vmctx := Context{
+			CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+			Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+			Ctx:         context.Background(),
+		}
+		vmenv := NewEVM(vmctx, nil, statedb, params.AllEthashProtocolChanges, Config{})
+		if enabled := tt.version >= params.FORKVERSION_0_17_0; vmenv.AccessListEnabled() != enabled {
+			t.Fatalf("test %d: access list enabled mismatch: have %v, want %v", i, vmenv.AccessListEnabled(), enabled)
+		}
+		if vmenv.AccessListEnabled() {
+			statedb.PrepareAccessList(common.Address{}, &address, ActivePrecompiles(), tt.accessList)
+		}
+
+		_, gas, err := vmenv.Call(AccountRef(common.Address{}), address, nil, math.MaxUint64, new(big.Int))
+		if err != nil {
+			t.Fatalf("test %d: call fail: %v", i, err)
+		}
+		if used := math.MaxUint64 - gas; used != tt.used {
+			t.Errorf("test %d: gas used mismatch: have %v, want %v", i, used, tt.used)
+		}
+	}
+}
+
+func TestEIP3198(t *testing.T) {
+	// BASEFEE, PUSH1 0, MSTORE, PUSH1 32, PUSH1 0, RETURN
+	code := hexutil.MustDecode("0x4860005260206000f3")
+	baseFee := big.NewInt(params.InitialBaseFee)
+	for i, version := range []uint32{params.FORKVERSION_0_17_0, params.FORKVERSION_0_18_0} {
+		address := common.BytesToAddress([]byte("contract"))
+
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+		if err := gov.AddActiveVersion(version, 0, statedb); err != nil {
+			t.Fatal(err)
+		}
+		statedb.CreateAccount(address)
+		statedb.SetCode(address, code)
+		statedb.Finalise(true)
+
+		vmctx := Context{
+			CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+			Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+			Ctx:         context.Background(),
+			BaseFee:     baseFee,
+		}
+		vmenv := NewEVM(vmctx, nil, statedb, params.AllEthashProtocolChanges, Config{})
+		enabled := version >= params.FORKVERSION_0_18_0
+		if vmenv.DynamicFeeEnabled() != enabled {
+			t.Fatalf("test %d: dynamic fee enabled mismatch: have %v, want %v", i, vmenv.DynamicFeeEnabled(), enabled)
+		}
+
+		ret, _, err := vmenv.Call(AccountRef(common.Address{}), address, nil, 100000, new(big.Int))
+		if !enabled {
+			if err == nil {
+				t.Errorf("test %d: BASEFEE must be invalid before the dynamic fee market", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test %d: call fail: %v", i, err)
+		}
+		if have := new(big.Int).SetBytes(ret); have.Cmp(baseFee) != 0 {
+			t.Errorf("test %d: base fee mismatch: have %v, want %v", i, have, baseFee)
+		}
+	}
+}
@@ -29,6 +29,10 @@ func (s StructLog) MarshalJSON() ([]byte, error) {
 		Depth         int                         `json:"depth"`
 		RefundCounter uint64                      `json:"refund"`
 		Err           error                       `json:"-"`
+		HostFunc      string                      `json:"hostFunc,omitempty"`
+		HostArgs      []math.HexOrDecimal64       `json:"hostArgs,omitempty"`
+		WasmState     []WasmStateAccess           `json:"wasmState,omitempty"`
+		CallStack     []common.Address            `json:"callStack,omitempty"`
 		OpName        string                      `json:"opName"`
 		ErrorString   string                      `json:"error"`
 	}
@@ -56,6 +60,15 @@ func (s StructLog) MarshalJSON() ([]byte, error) {
 	enc.Depth = s.Depth
 	enc.RefundCounter = s.RefundCounter
 	enc.Err = s.Err
+	enc.HostFunc = s.HostFunc
+	if s.HostArgs != nil {
+		enc.HostArgs = make([]math.HexOrDecimal64, len(s.HostArgs))
+		for k, v := range s.HostArgs {
+			enc.HostArgs[k] = math.HexOrDecimal64(v)
+		}
+	}
+	enc.WasmState = s.WasmState
+	enc.CallStack = s.CallStack
 	enc.OpName = s.OpName()
 	enc.ErrorString = s.ErrorString()
 	return json.Marshal(&enc)
@@ -77,6 +90,10 @@ func (s *StructLog) UnmarshalJSON(input []byte) error {
 		Depth         *int                        `json:"depth"`
 		RefundCounter *uint64                     `json:"refund"`
 		Err           error                       `json:"-"`
+		HostFunc      *string                     `json:"hostFunc,omitempty"`
+		HostArgs      []math.HexOrDecimal64       `json:"hostArgs,omitempty"`
+		WasmState     []WasmStateAccess           `json:"wasmState,omitempty"`
+		CallStack     []common.Address            `json:"callStack,omitempty"`
 	}
 	var dec StructLog
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -127,5 +144,20 @@ func (s *StructLog) UnmarshalJSON(input []byte) error {
 	if dec.Err != nil {
 		s.Err = dec.Err
 	}
+	if dec.HostFunc != nil {
+		s.HostFunc = *dec.HostFunc
+	}
+	if dec.HostArgs != nil {
+		s.HostArgs = make([]uint64, len(dec.HostArgs))
+		for k, v := range dec.HostArgs {
+			s.HostArgs[k] = uint64(v)
+		}
+	}
+	if dec.WasmState != nil {
+		s.WasmState = dec.WasmState
+	}
+	if dec.CallStack != nil {
+		s.CallStack = dec.CallStack
+	}
 	return nil
 }
@@ -47,6 +47,15 @@ type Config struct {
 
 	// VM execution timeout duration (unit: ms)
 	VmTimeoutDuration uint64
+
+	// Speculative is set when the transaction is executed speculatively by the
+	// parallel executor, the execution is aborted if it calls the PPOS contracts
+	// because their changes to the snapshotdb can't be discarded.
+	Speculative bool
+
+	// NoBaseFee skips the checks of the fee caps against the base fee, it's
+	// used to run calls without a gas price once the dynamic fee market is active.
+	NoBaseFee bool
 }
 
 // Interpreter is used to run Ethereum based contracts and will utilise the
@@ -106,7 +115,13 @@ func NewEVMInterpreter(evm *EVM, cfg Config) *EVMInterpreter {
 	// the jump table was initialised. If it was not
 	// we'll set the default jump table.
 	if cfg.JumpTable[STOP] == nil {
-		cfg.JumpTable = istanbulInstructionSet
+		if evm.dynamicFeeEnabled {
+			cfg.JumpTable = londonInstructionSet
+		} else if evm.accessListEnabled {
+			cfg.JumpTable = accessListInstructionSet
+		} else {
+			cfg.JumpTable = istanbulInstructionSet
+		}
 	}
 
 	return &EVMInterpreter{
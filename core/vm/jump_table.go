@@ -57,11 +57,29 @@ var (
 	byzantiumInstructionSet      = newByzantiumInstructionSet()
 	constantinopleInstructionSet = newConstantinopleInstructionSet()
 	istanbulInstructionSet       = newIstanbulInstructionSet()
+	accessListInstructionSet     = newAccessListInstructionSet()
+	londonInstructionSet         = newLondonInstructionSet()
 )
 
 // JumpTable contains the EVM opcodes supported at a given fork.
 type JumpTable [256]*operation
 
+// newLondonInstructionSet returns the access list instructions with the BASEFEE
+// opcode, it is used since the governance version 0.18.0.
+func newLondonInstructionSet() JumpTable {
+	instructionSet := newAccessListInstructionSet()
+	enable3198(&instructionSet) // Base fee opcode https://eips.ethereum.org/EIPS/eip-3198
+	return instructionSet
+}
+
+// newAccessListInstructionSet returns the istanbul instructions with the
+// EIP-2929 gas rules, it is used since the governance version 0.17.0.
+func newAccessListInstructionSet() JumpTable {
+	instructionSet := newIstanbulInstructionSet()
+	enable2929(&instructionSet) // Access lists for trie accesses https://eips.ethereum.org/EIPS/eip-2929
+	return instructionSet
+}
+
 // newIstanbulInstructionSet returns the frontier, homestead
 // byzantium, contantinople and petersburg instructions.
 func newIstanbulInstructionSet() JumpTable {
@@ -76,6 +76,10 @@ type StructLog struct {
 	Depth         int                         `json:"depth"`
 	RefundCounter uint64                      `json:"refund"`
 	Err           error                       `json:"-"`
+	HostFunc      string                      `json:"hostFunc,omitempty"`  // The host function called by a WASM contract
+	HostArgs      []uint64                    `json:"hostArgs,omitempty"`  // The arguments of the host function
+	WasmState     []WasmStateAccess           `json:"wasmState,omitempty"` // The contract state accessed by the host function
+	CallStack     []common.Address            `json:"callStack,omitempty"` // The WASM contracts being executed
 }
 
 // overrides for gencodec
@@ -85,12 +89,17 @@ type structLogMarshaling struct {
 	Gas         math.HexOrDecimal64
 	GasCost     math.HexOrDecimal64
 	Memory      hexutil.Bytes
+	HostArgs    []math.HexOrDecimal64
 	OpName      string `json:"opName"` // adds call to OpName() in MarshalJSON
 	ErrorString string `json:"error"`  // adds call to ErrorString() in MarshalJSON
 }
 
-// OpName formats the operand name in a human-readable format.
+// OpName formats the operand name in a human-readable format, the name of
+// the host function is used for the host function calls of WASM contracts.
 func (s *StructLog) OpName() string {
+	if s.HostFunc != "" {
+		return s.HostFunc
+	}
 	return s.Op.String()
 }
 
@@ -122,10 +131,11 @@ type Tracer interface {
 type StructLogger struct {
 	cfg LogConfig
 
-	storage map[common.Address]Storage
-	logs    []StructLog
-	output  []byte
-	err     error
+	storage   map[common.Address]Storage
+	logs      []StructLog
+	hostCalls []int // The indexes of the logs of the unfinished host function calls
+	output    []byte
+	err       error
 }
 
 // NewStructLogger returns a new logger
@@ -203,11 +213,65 @@ func (l *StructLogger) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost ui
 		copy(rdata, rData)
 	}
 	// create a new snapshot of the EVM.
-	log := StructLog{pc, op, gas, cost, mem, memory.Len(), stck, rstack, rdata, storage, depth, env.StateDB.GetRefund(), err}
+	log := StructLog{
+		Pc:            pc,
+		Op:            op,
+		Gas:           gas,
+		GasCost:       cost,
+		Memory:        mem,
+		MemorySize:    memory.Len(),
+		Stack:         stck,
+		ReturnStack:   rstack,
+		ReturnData:    rdata,
+		Storage:       storage,
+		Depth:         depth,
+		RefundCounter: env.StateDB.GetRefund(),
+		Err:           err,
+	}
 	l.logs = append(l.logs, log)
 	return nil
 }
 
+// CaptureWasmHostStart implements the WasmTracer interface, it logs the host
+// function call of a WASM contract, the cost is filled in once it returns.
+func (l *StructLogger) CaptureWasmHostStart(env *EVM, call *WasmHostCall) error {
+	if l.cfg.Limit != 0 && l.cfg.Limit <= len(l.logs) {
+		l.hostCalls = append(l.hostCalls, -1)
+		return errTraceLimitReached
+	}
+	log := StructLog{
+		Gas:           call.Gas,
+		MemorySize:    call.MemorySize,
+		Depth:         call.Depth,
+		RefundCounter: env.StateDB.GetRefund(),
+		HostFunc:      call.Name,
+		HostArgs:      call.Args,
+		CallStack:     call.CallStack,
+	}
+	l.hostCalls = append(l.hostCalls, len(l.logs))
+	l.logs = append(l.logs, log)
+	return nil
+}
+
+// CaptureWasmHostEnd implements the WasmTracer interface.
+func (l *StructLogger) CaptureWasmHostEnd(env *EVM, call *WasmHostCall) error {
+	if len(l.hostCalls) == 0 {
+		return nil
+	}
+	index := l.hostCalls[len(l.hostCalls)-1]
+	l.hostCalls = l.hostCalls[:len(l.hostCalls)-1]
+	if index < 0 {
+		return nil
+	}
+	log := &l.logs[index]
+	log.GasCost = call.Cost
+	log.Err = call.Err
+	if !l.cfg.DisableStorage {
+		log.WasmState = call.State
+	}
+	return nil
+}
+
 // CaptureFault implements the Tracer interface to trace an execution fault
 // while running an opcode.
 func (l *StructLogger) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, rStack *ReturnStack, contract *Contract, depth int, err error) error {
@@ -239,7 +303,7 @@ func (l *StructLogger) Output() []byte { return l.output }
 // WriteTrace writes a formatted trace to the given writer
 func WriteTrace(writer io.Writer, logs []StructLog) {
 	for _, log := range logs {
-		fmt.Fprintf(writer, "%-16spc=%08d gas=%v cost=%v", log.Op, log.Pc, log.Gas, log.GasCost)
+		fmt.Fprintf(writer, "%-16spc=%08d gas=%v cost=%v", log.OpName(), log.Pc, log.Gas, log.GasCost)
 		if log.Err != nil {
 			fmt.Fprintf(writer, " ERROR: %v", log.Err)
 		}
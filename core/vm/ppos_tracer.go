@@ -0,0 +1,66 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+)
+
+// PPOSTracer is implemented by the Tracer which wants to be notified of the
+// calls to the PPOS precompiled contracts and the balance movements performed
+// by the plugins during the calls.
+type PPOSTracer interface {
+	CapturePPOSStart(env *EVM, p PlatONPrecompiledContract, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int)
+	CapturePPOSBalance(addr common.Address, amount *big.Int, add bool)
+	CapturePPOSEnd(output []byte, gasUsed uint64, err error)
+}
+
+// pposTraceStateDB notifies the PPOSTracer of the balance movements.
+type pposTraceStateDB struct {
+	StateDB
+	tracer PPOSTracer
+}
+
+func (s *pposTraceStateDB) AddBalance(addr common.Address, amount *big.Int) {
+	s.tracer.CapturePPOSBalance(addr, amount, true)
+	s.StateDB.AddBalance(addr, amount)
+}
+
+func (s *pposTraceStateDB) SubBalance(addr common.Address, amount *big.Int) {
+	s.tracer.CapturePPOSBalance(addr, amount, false)
+	s.StateDB.SubBalance(addr, amount)
+}
+
+// runPlatONPrecompiledContract runs the PPOS contract, the tracer is notified
+// of the call if it implements PPOSTracer.
+func runPlatONPrecompiledContract(evm *EVM, p PlatONPrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
+	tracer, ok := evm.vmConfig.Tracer.(PPOSTracer)
+	if !evm.vmConfig.Debug || !ok {
+		return RunPlatONPrecompiledContract(p, input, contract)
+	}
+	tracer.CapturePPOSStart(evm, p, contract.Caller(), *contract.CodeAddr, input, contract.Gas, contract.Value())
+
+	stateDB := evm.StateDB
+	evm.StateDB = &pposTraceStateDB{StateDB: stateDB, tracer: tracer}
+	defer func(startGas uint64) {
+		evm.StateDB = stateDB
+		tracer.CapturePPOSEnd(ret, startGas-contract.Gas, err)
+	}(contract.Gas)
+	return RunPlatONPrecompiledContract(p, input, contract)
+}
@@ -310,6 +310,8 @@ func (stkc *StakingContract) createStaking(typ uint16, benefitAddress common.Add
 		}
 	}
 
+	xcom.AddPPOSEvent(blockHash, blockNumber.Uint64(), state,
+		xcom.NewPPOSEvent(xcom.CreateStakingEvent, can.NodeId, can.StakingBlockNum, can.StakingAddress, amount))
 	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
 		"", TxCreateStaking, common.NoErr)
 }
@@ -496,6 +498,8 @@ func (stkc *StakingContract) editCandidate(benefitAddress *common.Address, nodeI
 		}
 	}
 
+	xcom.AddPPOSEvent(blockHash, blockNumber.Uint64(), state,
+		xcom.NewPPOSEvent(xcom.EditCandidateEvent, canOld.NodeId, canOld.StakingBlockNum, canOld.StakingAddress, nil))
 	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
 		"", TxEditorCandidate, common.NoErr)
 }
@@ -13,9 +13,18 @@ import (
 )
 
 func ReadWasmModule(Code []byte, verify bool) (*exec.CompiledModule, error) {
+	return readWasmModule(Code, verify, false)
+}
+
+// readWasmModule reads the module, the host functions report their invocations
+// to the WasmTracer if trace is true.
+func readWasmModule(Code []byte, verify bool, trace bool) (*exec.CompiledModule, error) {
 	m, err := wasm.ReadModule(bytes.NewReader(Code), func(name string) (*wasm.Module, error) {
 		switch name {
 		case "env":
+			if trace {
+				return NewTraceHostModule(), nil
+			}
 			return NewHostModule(), nil
 		}
 		return nil, fmt.Errorf("module %q unknown", name)
@@ -97,6 +97,11 @@ func (engine *wagonEngine) Run(input []byte, readOnly bool) ([]byte, error) {
 		return nil, err
 	}
 
+	if _, ok := wasmTracer(engine.config); ok {
+		engine.evm.wasmCallStack = append(engine.evm.wasmCallStack, engine.Contract().Address())
+		defer func() { engine.evm.wasmCallStack = engine.evm.wasmCallStack[:len(engine.evm.wasmCallStack)-1] }()
+	}
+
 	if readOnly && !engine.isReadOnly() {
 		engine.setReadOnly(true)
 		defer func() { engine.setReadOnly(false) }()
@@ -220,8 +225,11 @@ func validateCallFunc(input []byte) error {
 
 func (engine *wagonEngine) makeModuleWithDeploy() (*exec.CompiledModule, int64, error) {
 
+	// The traced module is never cached, its host functions are slower
+	_, trace := wasmTracer(engine.config)
+
 	cache := &lru.WasmModule{}
-	module, err := ReadWasmModule(engine.Contract().Code, verifyModule)
+	module, err := readWasmModule(engine.Contract().Code, verifyModule, trace)
 	if nil != err {
 		return nil, 0, err
 	}
@@ -238,8 +246,10 @@ func (engine *wagonEngine) makeModuleWithDeploy() (*exec.CompiledModule, int64,
 		return nil, 0, errors.New("function sig error")
 	}
 
-	cache.Module = module
-	lru.WasmCache().Add(*(engine.Contract().CodeAddr), cache)
+	if !trace {
+		cache.Module = module
+		lru.WasmCache().Add(*(engine.Contract().CodeAddr), cache)
+	}
 	return module, index, nil
 }
 
@@ -247,7 +257,14 @@ func (engine *wagonEngine) makeModuleWithCall() (*exec.CompiledModule, int64, er
 
 	// load module
 	cache, ok := lru.WasmCache().Get(*(engine.Contract().CodeAddr))
-	if !ok || (ok && nil == cache.Module) {
+	if _, trace := wasmTracer(engine.config); trace {
+		// The traced module is never cached, its host functions are slower
+		module, err := readWasmModule(engine.Contract().Code, unVerifyModule, true)
+		if nil != err {
+			return nil, 0, err
+		}
+		cache = &lru.WasmModule{Module: module}
+	} else if !ok || (ok && nil == cache.Module) {
 		cache = &lru.WasmModule{}
 
 		module, err := ReadWasmModule(engine.Contract().Code, unVerifyModule)
@@ -12,6 +12,7 @@ import (
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
 	"github.com/AlayaNetwork/Alaya-Go/common/mock"
+	"github.com/AlayaNetwork/Alaya-Go/core/lru"
 )
 
 func TestWasmRun(t *testing.T) {
@@ -191,6 +192,76 @@ func TestWasmRun(t *testing.T) {
 	assert.Nil(t, ret)
 }
 
+func TestWasmRunTrace(t *testing.T) {
+	codeAddr := common.Address{1, 2, 5}
+	tracer := NewStructLogger(nil)
+	engine := &wagonEngine{
+		evm: &EVM{Context: Context{
+			CanTransfer: func(db StateDB, addr common.Address, amount *big.Int) bool {
+				return db.GetBalance(addr).Cmp(amount) >= 0
+			},
+			Transfer: func(db StateDB, sender, recipient common.Address, amount *big.Int) {
+				db.SubBalance(sender, amount)
+				db.AddBalance(recipient, amount)
+			},
+			Ctx: context.TODO(),
+		},
+			StateDB: &mock.MockStateDB{
+				Balance: map[common.Address]*big.Int{
+					addr1: big.NewInt(2000),
+					addr2: big.NewInt(1000),
+				},
+				State:    map[common.Address]map[string][]byte{},
+				Code:     map[common.Address][]byte{},
+				CodeHash: map[common.Address][]byte{},
+				Journal:  mock.NewJournal(),
+			}},
+		config: Config{WasmType: Wagon, Debug: true, Tracer: tracer},
+		contract: &Contract{
+			self:           &AccountRef{1, 2, 3},
+			Gas:            1000000,
+			Code:           deployData(t, "init", "./testdata/contract_hello.wasm"),
+			CodeAddr:       &codeAddr,
+			CodeHash:       common.ZeroHash,
+			DeployContract: true,
+		},
+	}
+	ret, err := engine.Run(nil, false)
+	assert.Nil(t, err)
+	assert.NotNil(t, ret)
+	engine.evm.StateDB.SetCode(addr2, ret)
+
+	engine.contract.DeployContract = false
+	tracer.logs = nil
+	ret, err = engine.Run(callData(t, "add_message"), false)
+	assert.Nil(t, err)
+	assert.NotNil(t, ret)
+
+	// the traced module must not be cached
+	assert.False(t, lru.WasmCache().Contains(codeAddr))
+	assert.Empty(t, engine.evm.wasmCallStack)
+
+	var setState *StructLog
+	for i, log := range tracer.StructLogs() {
+		assert.NotEmpty(t, log.HostFunc)
+		assert.Equal(t, log.HostFunc, log.OpName())
+		assert.Equal(t, []common.Address{engine.contract.Address()}, log.CallStack)
+		if log.HostFunc == "platon_set_state" {
+			setState = &tracer.StructLogs()[i]
+		}
+	}
+	if assert.NotNil(t, setState) {
+		assert.Len(t, setState.HostArgs, 4)
+		assert.True(t, setState.GasCost > 0)
+		// the old value is read to compute the gas
+		if assert.Len(t, setState.WasmState, 2) {
+			assert.False(t, setState.WasmState[0].Write)
+			assert.True(t, setState.WasmState[1].Write)
+			assert.Equal(t, engine.contract.Address(), setState.WasmState[1].Address)
+		}
+	}
+}
+
 func deployData(t *testing.T, funcName, filePath string) []byte {
 
 	buf, err := ioutil.ReadFile(filePath)
@@ -0,0 +1,152 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/PlatONnetwork/wagon/exec"
+	"github.com/PlatONnetwork/wagon/wasm"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
+)
+
+// WasmHostCall is an invocation of a host function by a WASM contract.
+type WasmHostCall struct {
+	Name       string            // The host function, e.g. platon_set_state
+	Args       []uint64          // The arguments, memory offsets and lengths for the most part
+	Gas        uint64            // The gas before the invocation
+	Cost       uint64            // The gas consumed by the invocation, including nested calls
+	MemorySize int               // The size of the linear memory
+	State      []WasmStateAccess // The contract state read or written by the invocation
+	CallStack  []common.Address  // The WASM contracts being executed, from the outermost one
+	Contract   *Contract
+	Depth      int
+	Err        error
+}
+
+// WasmStateAccess is a read or write of the contract state.
+type WasmStateAccess struct {
+	Address common.Address `json:"address"`
+	Key     hexutil.Bytes  `json:"key"`
+	Value   hexutil.Bytes  `json:"value"`
+	Write   bool           `json:"write"`
+}
+
+// WasmTracer is implemented by the Tracer which wants to trace the WASM
+// contracts. The wagon engine reports every host function invocation,
+// CaptureWasmHostEnd is called with the same call once it returns.
+type WasmTracer interface {
+	CaptureWasmHostStart(env *EVM, call *WasmHostCall) error
+	CaptureWasmHostEnd(env *EVM, call *WasmHostCall) error
+}
+
+// wasmTracer returns the WasmTracer of the config if tracing is enabled.
+func wasmTracer(cfg Config) (WasmTracer, bool) {
+	if !cfg.Debug {
+		return nil, false
+	}
+	tracer, ok := cfg.Tracer.(WasmTracer)
+	return tracer, ok
+}
+
+// wasmTraceStateDB records the contract state accessed by a host function,
+// the accesses of the nested calls are recorded by their own host functions.
+type wasmTraceStateDB struct {
+	StateDB
+	evm  *EVM
+	call *WasmHostCall
+}
+
+func (s *wasmTraceStateDB) GetState(addr common.Address, key []byte) []byte {
+	value := s.StateDB.GetState(addr, key)
+	if s.evm.depth == s.call.Depth {
+		s.call.State = append(s.call.State, WasmStateAccess{addr, common.CopyBytes(key), common.CopyBytes(value), false})
+	}
+	return value
+}
+
+func (s *wasmTraceStateDB) SetState(addr common.Address, key, value []byte) {
+	if s.evm.depth == s.call.Depth {
+		s.call.State = append(s.call.State, WasmStateAccess{addr, common.CopyBytes(key), common.CopyBytes(value), true})
+	}
+	s.StateDB.SetState(addr, key, value)
+}
+
+// NewTraceHostModule returns the host module whose functions report their
+// invocations to the WasmTracer, it is only used by the traced executions
+// because the functions are called through an extra reflection.
+func NewTraceHostModule() *wasm.Module {
+	m := NewHostModule()
+	for name, entry := range m.Export.Entries {
+		fn := &m.FunctionIndexSpace[entry.Index]
+		fn.Host = traceHostFunc(name, fn.Host)
+	}
+	return m
+}
+
+func traceHostFunc(name string, host reflect.Value) reflect.Value {
+	return reflect.MakeFunc(host.Type(), func(args []reflect.Value) []reflect.Value {
+		proc := args[0].Interface().(*exec.Process)
+		ctx := proc.HostCtx().(*VMContext)
+		tracer, ok := wasmTracer(ctx.config)
+		if !ok {
+			return host.Call(args)
+		}
+
+		call := &WasmHostCall{
+			Name:       name,
+			Args:       make([]uint64, 0, len(args)-1),
+			Gas:        ctx.contract.Gas,
+			MemorySize: proc.MemSize(),
+			CallStack:  append([]common.Address{}, ctx.evm.wasmCallStack...),
+			Contract:   ctx.contract,
+			Depth:      ctx.evm.depth,
+		}
+		for _, arg := range args[1:] {
+			switch arg.Kind() {
+			case reflect.Int32, reflect.Int64:
+				call.Args = append(call.Args, uint64(arg.Int()))
+			case reflect.Uint32, reflect.Uint64:
+				call.Args = append(call.Args, arg.Uint())
+			}
+		}
+		tracer.CaptureWasmHostStart(ctx.evm, call)
+
+		stateDB := ctx.evm.StateDB
+		ctx.evm.StateDB = &wasmTraceStateDB{StateDB: stateDB, evm: ctx.evm, call: call}
+		defer func() {
+			ctx.evm.StateDB = stateDB
+			if ctx.contract.Gas < call.Gas {
+				call.Cost = call.Gas - ctx.contract.Gas
+			}
+			if r := recover(); r != nil {
+				if err, ok := r.(error); ok {
+					call.Err = err
+				} else {
+					call.Err = fmt.Errorf("%v", r)
+				}
+				tracer.CaptureWasmHostEnd(ctx.evm, call)
+				panic(r)
+			}
+			tracer.CaptureWasmHostEnd(ctx.evm, call)
+		}()
+		return host.Call(args)
+	})
+}
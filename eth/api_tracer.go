@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"runtime"
 	"sync"
 	"time"
@@ -71,10 +72,12 @@ type txTraceResult struct {
 // blockTraceTask represents a single block trace task when an entire chain is
 // being traced.
 type blockTraceTask struct {
-	statedb *state.StateDB   // Intermediate state prepped for tracing
-	block   *types.Block     // Block to trace the transactions from
-	rootref common.Hash      // Trie root reference held for this task
-	results []*txTraceResult // Trace results procudes by the task
+	statedb   *state.StateDB   // Intermediate state prepped for tracing
+	traceHash common.Hash      // Snapshotdb trace block prepped for tracing
+	release   []common.Hash    // Snapshotdb trace blocks to discard once traced
+	block     *types.Block     // Block to trace the transactions from
+	rootref   common.Hash      // Trie root reference held for this task
+	results   []*txTraceResult // Trace results procudes by the task
 }
 
 // blockTraceResult represets the results of tracing a single block when an entire
@@ -88,8 +91,9 @@ type blockTraceResult struct {
 // txTraceTask represents a single transaction trace task when an entire block
 // is being traced.
 type txTraceTask struct {
-	statedb *state.StateDB // Intermediate state prepped for tracing
-	index   int            // Transaction offset in the block
+	statedb   *state.StateDB // Intermediate state prepped for tracing
+	traceHash common.Hash    // Snapshotdb trace block prepped for tracing
+	index     int            // Transaction offset in the block
 }
 
 // TraceChain returns the structured logs created during the execution of EVM
@@ -175,6 +179,12 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 			}
 		}
 	}
+	// Re-execute the ppos txs on a chain of trace blocks of the snapshotDB on
+	// top of the state of the start block
+	rootHash, err := newTraceBlock(start.Number(), start.Hash())
+	if err != nil {
+		return nil, err
+	}
 	// Execute all the transaction contained within the chain concurrently for each block
 	blocks := int(end.NumberU64() - origin)
 
@@ -194,11 +204,13 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 
 			// Fetch and execute the next block trace tasks
 			for task := range tasks {
-				signer := types.NewEIP155Signer(api.config.ChainID)
+				signer := types.LatestSignerForChainID(api.config.ChainID)
 				// Trace all the transactions contained within
 				for i, tx := range task.block.Transactions() {
-					msg, _ := tx.AsMessage(signer)
+					msg, _ := tx.AsMessage(signer, task.block.BaseFee())
 					vmctx := core.NewEVMContext(msg, task.block.Header(), api.eth.blockchain)
+					vmctx.BlockHash = task.traceHash
+					task.statedb.Prepare(tx.Hash(), task.block.Hash(), i)
 
 					res, err := api.traceTx(ctx, msg, vmctx, task.statedb, config)
 					if err != nil {
@@ -209,6 +221,9 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 					task.statedb.Finalise(true)
 					task.results[i] = &txTraceResult{Result: res}
 				}
+				for _, hash := range task.release {
+					snapshotdb.Instance().DiscardTraceBlock(hash)
+				}
 				// Stream the result back to the user or abort on teardown
 				select {
 				case results <- task:
@@ -228,11 +243,18 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 			traced uint64
 			failed error
 			proot  common.Hash
+
+			stateHash   = rootHash
+			traceHashes = []common.Hash{rootHash}
 		)
-		// Ensure everything is properly cleaned up on any exit path
+		// Ensure everything is properly cleaned up on any exit path, the
+		// trace blocks already discarded are simply not found again
 		defer func() {
 			close(tasks)
 			pend.Wait()
+			for _, hash := range traceHashes {
+				snapshotdb.Instance().DiscardTraceBlock(hash)
+			}
 
 			switch {
 			case failed != nil:
@@ -268,23 +290,61 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 				failed = fmt.Errorf("block #%d not found", number)
 				break
 			}
+			// Begin the block on a new trace block, the tracers and the fast
+			// processing below write to trace blocks of their own on top of it
+			beginHash, err := snapshotdb.Instance().NewTraceBlock(block.Number(), stateHash)
+			if err != nil {
+				failed = err
+				break
+			}
+			traceHashes = append(traceHashes, beginHash)
+			if err := core.GetReactorInstance().TraceBeginBlocker(beginHash, block.Header(), statedb); err != nil {
+				failed = err
+				break
+			}
+			// The trace blocks the block begins on are needed until it is traced
+			release := []common.Hash{beginHash, stateHash}
+
 			// Send the block over to the concurrent tracers (if not in the fast-forward phase)
 			if number > origin {
 				txs := block.Transactions()
+				taskHash, err := snapshotdb.Instance().NewTraceBlock(block.Number(), beginHash)
+				if err != nil {
+					failed = err
+					break
+				}
+				traceHashes = append(traceHashes, taskHash)
 
 				select {
-				case tasks <- &blockTraceTask{statedb: statedb.Copy(), block: block, rootref: proot, results: make([]*txTraceResult, len(txs))}:
+				case tasks <- &blockTraceTask{statedb: statedb.Copy(), traceHash: taskHash, release: append(release, taskHash), block: block, rootref: proot, results: make([]*txTraceResult, len(txs))}:
 				case <-notifier.Closed():
 					return
 				}
 				traced += uint64(len(txs))
+				release = nil
 			}
 			// Generate the next state snapshot fast without tracing
-			_, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, vm.Config{})
+			traceHash, err := snapshotdb.Instance().NewTraceBlock(block.Number(), beginHash)
 			if err != nil {
 				failed = err
 				break
 			}
+			traceHashes = append(traceHashes, traceHash)
+			if err := api.processTraceBlock(block, statedb, traceHash); err != nil {
+				failed = err
+				break
+			}
+			// Flatten the state of the block, so that the trace blocks do not
+			// pile up and the reads of the next blocks stay cheap
+			if stateHash, err = snapshotdb.Instance().FlattenTraceBlock(traceHash); err != nil {
+				failed = err
+				break
+			}
+			traceHashes = append(traceHashes, stateHash)
+			snapshotdb.Instance().DiscardTraceBlock(traceHash)
+			for _, hash := range release {
+				snapshotdb.Instance().DiscardTraceBlock(hash)
+			}
 			// Finalize the state so any modifications are written to the trie
 			root, err := statedb.Commit(true)
 			if err != nil {
@@ -412,9 +472,25 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 	if err != nil {
 		return nil, err
 	}
+	// Re-execute the ppos txs on the trace blocks of the snapshotDB, every tx
+	// is executed on a new trace block on top of the previous one, so that the
+	// concurrent tracers never see the writes of the following txs
+	traceHash, err := newTraceBlock(block.Number(), block.ParentHash())
+	if err != nil {
+		return nil, err
+	}
+	traceHashes := []common.Hash{traceHash}
+	defer func() {
+		for _, hash := range traceHashes {
+			snapshotdb.Instance().DiscardTraceBlock(hash)
+		}
+	}()
+	if err := core.GetReactorInstance().TraceBeginBlocker(traceHash, block.Header(), statedb); err != nil {
+		return nil, err
+	}
 	// Execute all the transaction contained within the block concurrently
 	var (
-		signer  = types.NewEIP155Signer(api.config.ChainID)
+		signer  = types.LatestSignerForChainID(api.config.ChainID)
 		txs     = block.Transactions()
 		results = make([]*txTraceResult, len(txs))
 
@@ -432,8 +508,10 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 
 			// Fetch and execute the next transaction trace tasks
 			for task := range jobs {
-				msg, _ := txs[task.index].AsMessage(signer)
+				msg, _ := txs[task.index].AsMessage(signer, block.BaseFee())
 				vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain)
+				vmctx.BlockHash = task.traceHash
+				task.statedb.Prepare(txs[task.index].Hash(), block.Hash(), task.index)
 
 				res, err := api.traceTx(ctx, msg, vmctx, task.statedb, config)
 				if err != nil {
@@ -448,11 +526,24 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 	var failed error
 	for i, tx := range txs {
 		// Send the trace task over for execution
-		jobs <- &txTraceTask{statedb: statedb.Copy(), index: i}
+		taskHash, err := snapshotdb.Instance().NewTraceBlock(block.Number(), traceHash)
+		if err != nil {
+			failed = err
+			break
+		}
+		traceHashes = append(traceHashes, taskHash)
+		jobs <- &txTraceTask{statedb: statedb.Copy(), traceHash: taskHash, index: i}
 
 		// Generate the next state snapshot fast without tracing
-		msg, _ := tx.AsMessage(signer)
+		if traceHash, err = snapshotdb.Instance().NewTraceBlock(block.Number(), traceHash); err != nil {
+			failed = err
+			break
+		}
+		traceHashes = append(traceHashes, traceHash)
+		msg, _ := tx.AsMessage(signer, block.BaseFee())
 		vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain)
+		vmctx.BlockHash = traceHash
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
 
 		vmenv := vm.NewEVM(vmctx, snapshotdb.Instance(), statedb, api.config, vm.Config{})
 		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas())); err != nil {
@@ -472,6 +563,37 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 	return results, nil
 }
 
+// newTraceBlock creates a trace block of the snapshotDB on top of the ppos state
+// of the parent block. Only the recent states are kept by default, the state of
+// older blocks is only kept by nodes run with --db.snapshot_archive.
+func newTraceBlock(number *big.Int, parentHash common.Hash) (common.Hash, error) {
+	traceHash, err := snapshotdb.Instance().NewTraceBlock(number, parentHash)
+	if err == snapshotdb.ErrArchiveNotAvailable {
+		return common.ZeroHash, fmt.Errorf("the ppos state of block #%d is not available, tracing historical blocks requires --db.snapshot_archive", number.Uint64()-1)
+	}
+	return traceHash, err
+}
+
+// processTraceBlock executes the txs of the block on top of the state and of
+// the trace block of the snapshotDB, like the state processor does on the chain.
+func (api *PrivateDebugAPI) processTraceBlock(block *types.Block, statedb *state.StateDB, traceHash common.Hash) error {
+	signer := types.LatestSignerForChainID(api.config.ChainID)
+	for i, tx := range block.Transactions() {
+		msg, _ := tx.AsMessage(signer, block.BaseFee())
+		vmctx := core.NewEVMContext(msg, block.Header(), api.eth.blockchain)
+		vmctx.BlockHash = traceHash
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+
+		vmenv := vm.NewEVM(vmctx, snapshotdb.Instance(), statedb, api.config, vm.Config{})
+		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas())); err != nil {
+			return fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+		}
+		// Finalize the state so any modifications are written to the trie
+		statedb.Finalise(true)
+	}
+	return core.GetReactorInstance().TraceEndBlocker(traceHash, block.Header(), statedb)
+}
+
 // computeStateDB retrieves the state database associated with a certain block.
 // If no state is locally available for the given block, a number of blocks are
 // attempted to be reexecuted to generate the desired state.
@@ -557,6 +679,7 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Ha
 	if err != nil {
 		return nil, err
 	}
+	defer snapshotdb.Instance().DiscardTraceBlock(vmctx.BlockHash)
 	// Trace the transaction and return
 	return api.traceTx(ctx, msg, vmctx, statedb, config)
 }
@@ -571,6 +694,9 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 		err    error
 	)
 	switch {
+	case config != nil && config.Tracer != nil && *config.Tracer == tracers.PPOSTracerName:
+		tracer = tracers.NewPPOSTracer()
+
 	case config != nil && config.Tracer != nil:
 		// Define a meaningful timeout of a single transaction trace
 		timeout := defaultTraceTimeout
@@ -617,6 +743,9 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 	case *tracers.Tracer:
 		return tracer.GetResult()
 
+	case *tracers.PPOSTracer:
+		return tracer.GetResult()
+
 	default:
 		panic(fmt.Sprintf("bad tracer type %T", tracer))
 	}
@@ -637,22 +766,36 @@ func (api *PrivateDebugAPI) computeTxEnv(blockHash common.Hash, txIndex int, ree
 	if err != nil {
 		return nil, vm.Context{}, nil, err
 	}
+	// Recompute the ppos state on a trace block of the snapshotDB, the block
+	// is the BlockHash of the returned context and must be discarded by the caller.
+	traceHash, err := newTraceBlock(block.Number(), block.ParentHash())
+	if err != nil {
+		return nil, vm.Context{}, nil, err
+	}
+	if err := core.GetReactorInstance().TraceBeginBlocker(traceHash, block.Header(), statedb); err != nil {
+		snapshotdb.Instance().DiscardTraceBlock(traceHash)
+		return nil, vm.Context{}, nil, err
+	}
 	// Recompute transactions up to the target index.
-	signer := types.NewEIP155Signer(api.config.ChainID)
+	signer := types.LatestSignerForChainID(api.config.ChainID)
 	for idx, tx := range block.Transactions() {
 		// Assemble the transaction call message and return if the requested offset
-		msg, _ := tx.AsMessage(signer)
+		msg, _ := tx.AsMessage(signer, block.BaseFee())
 		context := core.NewEVMContext(msg, block.Header(), api.eth.blockchain)
+		context.BlockHash = traceHash
+		statedb.Prepare(tx.Hash(), block.Hash(), idx)
 		if idx == txIndex {
 			return msg, context, statedb, nil
 		}
 		// Not yet the searched for transaction, execute on top of the current state
 		vmenv := vm.NewEVM(context, snapshotdb.Instance(), statedb, api.config, vm.Config{})
 		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(tx.Gas())); err != nil {
+			snapshotdb.Instance().DiscardTraceBlock(traceHash)
 			return nil, vm.Context{}, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
 		}
 		// Ensure any modifications are committed to the state
 		statedb.Finalise(true)
 	}
+	snapshotdb.Instance().DiscardTraceBlock(traceHash)
 	return nil, vm.Context{}, nil, fmt.Errorf("tx index %d out of range for block %x", txIndex, blockHash)
 }
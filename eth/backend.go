@@ -92,6 +92,7 @@ type Ethereum struct {
 
 	eventMux       *event.TypeMux
 	engine         consensus.Engine
+	agency         consensus.Agency
 	accountManager *accounts.Manager
 
 	bloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
@@ -99,6 +100,9 @@ type Ethereum struct {
 
 	APIBackend *EthAPIBackend
 
+	evidenceReporter *evidenceReporter // Reports the duplicate signatures to the slashing contract, nil if disabled
+	validatorMonitor *validatorMonitor // Tracks the block production of the local node, nil if disabled
+
 	miner         *miner.Miner
 	gasPrice      *big.Int
 	networkID     uint64
@@ -132,6 +136,11 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		return nil, err
 	}
 	snapshotdb.SetDBOptions(config.DatabaseCache, config.DatabaseHandles)
+	snapshotdb.SetDBArchive(config.DBSnapshotArchive)
+	snapshotdb.SetDBChainDatabase(chainDb)
+	if err := snapshotdb.SetDBBackend(config.DBSnapshotBackend); err != nil {
+		return nil, err
+	}
 
 	snapshotBaseDB, err := snapshotdb.Open(ctx.ResolvePath(snapshotdb.DBPath), config.DatabaseCache, config.DatabaseHandles, true)
 	if err != nil {
@@ -265,6 +274,9 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		return nil, err
 	}
 	snapshotdb.SetDBBlockChain(eth.blockchain)
+	if processor, ok := eth.blockchain.Processor().(*core.ParallelStateProcessor); ok {
+		processor.SetCrossCheck(config.VMParallelCheck, ctx.ResolvePath("parallelcheck"))
+	}
 
 	blockChainCache := core.NewBlockChainCache(eth.blockchain)
 
@@ -360,6 +372,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 			return nil, errors.New("Failed to recover SnapshotDB")
 		}
 
+		eth.agency = agency
 		if err := engine.Start(eth.blockchain, blockChainCache, eth.txPool, agency); err != nil {
 			log.Error("Init cbft consensus engine fail", "error", err)
 			return nil, errors.New("Failed to init cbft consensus engine")
@@ -377,6 +390,17 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	}
 	eth.APIBackend.gpo = gasprice.NewOracle(eth.APIBackend, gpoParams)
 
+	if config.EvidenceReporter != (common.Address{}) {
+		eth.evidenceReporter = newEvidenceReporter(eth, config.EvidenceReporter)
+	}
+	if config.ValidatorMonitor {
+		if chainConfig.Cbft.ValidatorMode == common.PPOS_VALIDATOR_MODE {
+			eth.validatorMonitor = newValidatorMonitor(eth, discover.PubkeyID(&ctx.NodePriKey().PublicKey), config.ValidatorMonitorWebhook)
+		} else {
+			log.Warn("Validator monitor is only available in the ppos validator mode", "mode", chainConfig.Cbft.ValidatorMode)
+		}
+	}
+
 	return eth, nil
 }
 
@@ -420,6 +444,24 @@ func (s *Ethereum) APIs() []rpc.API {
 	// Append any APIs exposed explicitly by the consensus engine
 	apis = append(apis, s.engine.APIs(s.BlockChain())...)
 
+	// Append the evidence reporter and validator monitor APIs if they are enabled
+	if s.evidenceReporter != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "platon",
+			Version:   "1.0",
+			Service:   NewPublicEvidenceReporterAPI(s.evidenceReporter),
+			Public:    true,
+		})
+	}
+	if s.validatorMonitor != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "platon",
+			Version:   "1.0",
+			Service:   NewPublicValidatorMonitorAPI(s.validatorMonitor),
+			Public:    true,
+		})
+	}
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
@@ -453,7 +495,9 @@ func (s *Ethereum) APIs() []rpc.API {
 		}, {
 			Namespace: "debug",
 			Version:   "1.0",
-			Service:   xplugin.NewPublicPPOSAPI(),
+			Service: xplugin.NewPublicPPOSAPI(func(header *types.Header) (xcom.StateDB, error) {
+				return s.blockchain.StateAt(header.Root)
+			}),
 		}, {
 			Namespace: "net",
 			Version:   "1.0",
@@ -557,6 +601,7 @@ func (s *Ethereum) BlockChain() *core.BlockChain       { return s.blockchain }
 func (s *Ethereum) TxPool() *core.TxPool               { return s.txPool }
 func (s *Ethereum) EventMux() *event.TypeMux           { return s.eventMux }
 func (s *Ethereum) Engine() consensus.Engine           { return s.engine }
+func (s *Ethereum) Agency() consensus.Agency           { return s.agency }
 func (s *Ethereum) ChainDb() ethdb.Database            { return s.chainDb }
 func (s *Ethereum) IsListening() bool                  { return true } // Always listening
 func (s *Ethereum) EthVersion() int                    { return int(s.protocolManager.SubProtocols[0].Version) }
@@ -611,6 +656,12 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 	}
 	srvr.StartWatching(s.eventMux)
 
+	if s.evidenceReporter != nil {
+		s.evidenceReporter.Start()
+	}
+	if s.validatorMonitor != nil {
+		s.validatorMonitor.Start()
+	}
 	if s.lesServer != nil {
 		s.lesServer.Start(srvr)
 	}
@@ -620,6 +671,12 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 // Stop implements node.Service, terminating all internal goroutines used by the
 // Ethereum protocol.
 func (s *Ethereum) Stop() error {
+	if s.evidenceReporter != nil {
+		s.evidenceReporter.Stop()
+	}
+	if s.validatorMonitor != nil {
+		s.validatorMonitor.Stop()
+	}
 	s.bloomIndexer.Close()
 	s.blockchain.Stop()
 	s.protocolManager.Stop()
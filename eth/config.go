@@ -22,9 +22,11 @@ import (
 
 	"github.com/AlayaNetwork/Alaya-Go/params"
 
+	"github.com/AlayaNetwork/Alaya-Go/common"
 	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
 	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/types"
 	"github.com/AlayaNetwork/Alaya-Go/core"
+	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
 	"github.com/AlayaNetwork/Alaya-Go/eth/downloader"
 	"github.com/AlayaNetwork/Alaya-Go/eth/gasprice"
 )
@@ -55,6 +57,7 @@ var DefaultConfig = Config{
 	DBGCTimeout:       time.Minute,
 	DBGCMpt:           true,
 	DBGCBlock:         10,
+	DBSnapshotBackend: snapshotdb.BackendLevelDB,
 	VMWasmType:        "wagon",
 	VmTimeoutDuration: 0, // default 0 ms for vm exec timeout
 	MinerGasPrice:     big.NewInt(params.GVon),
@@ -99,14 +102,22 @@ type Config struct {
 
 	CbftConfig types.OptionsConfig `toml:",omitempty"`
 
+	// Account reporting the duplicate signatures to the slashing contract, must be unlocked
+	EvidenceReporter common.Address `toml:",omitempty"`
+
+	// Validator monitor options
+	ValidatorMonitor        bool   `toml:",omitempty"` // Track the block production of the local node and alert on zero-production risk
+	ValidatorMonitorWebhook string `toml:",omitempty"` // URL the alerts are posted to as JSON
+
 	// Protocol options
 	NetworkId uint64 // Network ID to use for selecting peers to connect to
 	SyncMode  downloader.SyncMode
 	NoPruning bool
 
 	// Light client options
-	LightServ  int `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
-	LightPeers int `toml:",omitempty"` // Maximum number of LES client peers
+	LightServ     int  `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
+	LightPeers    int  `toml:",omitempty"` // Maximum number of LES client peers
+	LightVerifyQC bool `toml:",omitempty"` // Verify the QuorumCerts of the headers synced by the light client
 
 	// Database options
 	SkipBcVersionCheck bool `toml:"-"`
@@ -120,10 +131,13 @@ type Config struct {
 	DBGCTimeout        time.Duration
 	DBGCMpt            bool
 	DBGCBlock          int
+	DBSnapshotArchive  bool
+	DBSnapshotBackend  string
 
 	// VM options
 	VMWasmType        string
 	VmTimeoutDuration uint64
+	VMParallelCheck   uint64
 
 	// Mining-related options
 	MinerExtraData []byte `toml:",omitempty"`
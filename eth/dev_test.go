@@ -0,0 +1,130 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/core"
+	"github.com/AlayaNetwork/Alaya-Go/core/rawdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/crypto/bls"
+	"github.com/AlayaNetwork/Alaya-Go/node"
+	"github.com/AlayaNetwork/Alaya-Go/p2p"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/params"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
+)
+
+// Tests that a node started on the developer genesis seals blocks only when
+// transactions are pending.
+func TestDeveloperChain(t *testing.T) {
+	ec, ece := xcom.GetEc(xcom.DefaultUnitTestNet), xcom.GetEce()
+	window := ec.Common.NodeBlockTimeWindow
+	defer func() {
+		xcom.ResetEconomicDefaultConfig(ec)
+		xcom.ResetEconomicExtendConfig(ece)
+	}()
+
+	dir, err := ioutil.TempDir("", "alaya-dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	nodeKey, _ := crypto.GenerateKey()
+	faucetKey, _ := crypto.GenerateKey()
+	faucet := crypto.PubkeyToAddress(faucetKey.PublicKey)
+	var blsKey bls.SecretKey
+	blsKey.SetByCSPRNG()
+
+	stack, err := node.New(&node.Config{
+		DataDir:           dir,
+		UseLightweightKDF: true,
+		P2P:               p2p.Config{PrivateKey: nodeKey, NoDiscovery: true, MaxPeers: 0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshotdb.SetDBPathWithNode(stack.ResolvePath(snapshotdb.DBPath))
+
+	config := DefaultConfig
+	config.NetworkId = 1337
+	config.CbftConfig.NodePriKey = nodeKey
+	config.CbftConfig.NodeID = discover.PubkeyID(&nodeKey.PublicKey)
+	config.CbftConfig.BlsPriKey = &blsKey
+	config.Genesis = core.DeveloperGenesisBlock(0, config.CbftConfig.NodeID, *blsKey.GetPublicKey(), faucet)
+	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+		return New(ctx, &config)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer stack.Stop()
+	var ethereum *Ethereum
+	if err := stack.Service(&ethereum); err != nil {
+		t.Fatal(err)
+	}
+
+	if have := xcom.GetEc(xcom.DefaultUnitTestNet); have != config.Genesis.EconomicModel {
+		t.Fatal("the economic model of the developer genesis is not used")
+	}
+	if ec.Common.NodeBlockTimeWindow != window {
+		t.Fatal("the developer genesis changed the previous economic model")
+	}
+
+	// Empty blocks are not sealed.
+	time.Sleep(3 * time.Second)
+	if number := ethereum.BlockChain().CurrentBlock().NumberU64(); number != 0 {
+		t.Fatalf("want no block without transactions, have #%d", number)
+	}
+
+	signer := types.NewEIP155Signer(config.Genesis.Config.ChainID)
+	tx, err := types.SignTx(types.NewTransaction(0, common.Address{1}, big.NewInt(params.ATP), params.TxGas, big.NewInt(params.GVon), nil), signer, faucetKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ethereum.TxPool().AddLocal(tx); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		if sealed, _, _, _ := rawdb.ReadTransaction(ethereum.ChainDb(), tx.Hash()); sealed != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the transaction is not sealed, head #%d", ethereum.BlockChain().CurrentBlock().NumberU64())
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	state, err := ethereum.BlockChain().State()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance := state.GetBalance(common.Address{1}); balance.Cmp(big.NewInt(params.ATP)) != 0 {
+		t.Fatalf("want balance %v, have %v", params.ATP, balance)
+	}
+}
@@ -711,7 +711,14 @@ func (d *Downloader) fetchPPOSStorage(p *peerConnection, pivot *types.Header) (e
 				return errors.New("received ppos storage from incorrect kvNum")
 			}
 
-			if err := d.snapshotDB.WriteBaseDB(pposDada.KVs()); err != nil {
+			// The peer's own snapshotdb state must not end up in ours.
+			kvs := make([][2][]byte, 0, len(pposDada.kvs))
+			for _, kv := range pposDada.KVs() {
+				if snapshotdb.IsPPOSKey(kv[0]) {
+					kvs = append(kvs, kv)
+				}
+			}
+			if err := d.snapshotDB.WriteBaseDB(kvs); err != nil {
 				p.log.Error("write to base db fail", "err", err)
 				return errors.New("write to base db fail")
 			}
@@ -20,7 +20,6 @@ import (
 	"errors"
 	"math/big"
 
-	"github.com/syndtr/goleveldb/leveldb/iterator"
 
 	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
 	"github.com/AlayaNetwork/Alaya-Go/log"
@@ -183,7 +182,7 @@ func (p *FakePeer) RequestNodeData(hashes []common.Hash) error {
 }
 
 func (p *FakePeer) RequestPPOSStorage() error {
-	f := func(num *big.Int, iter iterator.Iterator) error {
+	f := func(num *big.Int, iter snapshotdb.Iterator) error {
 		var (
 			count int
 			KVNum uint64
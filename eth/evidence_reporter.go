@@ -0,0 +1,321 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/AlayaNetwork/Alaya-Go/accounts"
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	cconsensus "github.com/AlayaNetwork/Alaya-Go/common/consensus"
+	cvm "github.com/AlayaNetwork/Alaya-Go/common/vm"
+	"github.com/AlayaNetwork/Alaya-Go/consensus"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/evidence"
+	"github.com/AlayaNetwork/Alaya-Go/core"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/core/vm"
+	"github.com/AlayaNetwork/Alaya-Go/log"
+	"github.com/AlayaNetwork/Alaya-Go/params"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+	"github.com/AlayaNetwork/Alaya-Go/x/gov"
+	xplugin "github.com/AlayaNetwork/Alaya-Go/x/plugin"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
+	"github.com/AlayaNetwork/Alaya-Go/x/xutil"
+)
+
+const (
+	// Number of blocks to wait for a report transaction before it is sent again.
+	evidenceReportTimeout = 50
+
+	// Maximum number of report transactions sent for an evidence.
+	evidenceReportRetries = 3
+)
+
+// The status of the report of an evidence.
+const (
+	EvidenceReportPending   = "pending"   // The evidence is not reported yet
+	EvidenceReportSubmitted = "submitted" // The report transaction is sent
+	EvidenceReportSlashed   = "slashed"   // The node is slashed for the evidence, maybe reported by others
+	EvidenceReportExpired   = "expired"   // The evidence is older than the max evidence age
+	EvidenceReportInvalid   = "invalid"   // The evidence doesn't pass the verification
+	EvidenceReportFailed    = "failed"    // The node is not slashed after all the retries
+)
+
+// EvidenceReport is the status of the report of an evidence to the slashing contract.
+type EvidenceReport struct {
+	*evidence.EvidenceInfo
+	Status    string      `json:"status"`
+	TxHash    common.Hash `json:"txHash"`         // The last report transaction, or the slashing transaction
+	Attempts  int         `json:"attempts"`       // Number of report transactions sent
+	Submitted uint64      `json:"submittedBlock"` // The head block when the last report transaction was sent
+	Reason    string      `json:"reason,omitempty"`
+}
+
+// evidenceReporter reports the duplicate signatures detected by the consensus
+// engine to the slashing contract. Evidences are reported once per node, block
+// number and evidence type, which is the key the slashing contract uses to
+// reject duplicate reports.
+type evidenceReporter struct {
+	account common.Address
+
+	// Hooks to the node, they are replaced in tests.
+	evidences      func() cconsensus.Evidences
+	stateAt        func(header *types.Header) (xcom.StateDB, error)
+	slashed        func(ev cconsensus.Evidence, state xcom.StateDB) ([]byte, error)
+	maxEvidenceAge func(blockNumber uint64, blockHash common.Hash) (uint32, error)
+	pending        func(hash common.Hash) bool
+	submit         func(info *evidence.EvidenceInfo) (common.Hash, error)
+
+	reports map[string]*EvidenceReport
+	mu      sync.Mutex
+
+	eth  *Ethereum
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newEvidenceReporter creates an evidence reporter sending the report
+// transactions from account, the account must be unlocked.
+func newEvidenceReporter(eth *Ethereum, account common.Address) *evidenceReporter {
+	r := &evidenceReporter{
+		account: account,
+		evidences: func() cconsensus.Evidences {
+			if bft, ok := eth.engine.(consensus.Bft); ok {
+				return bft.EvidenceList()
+			}
+			return nil
+		},
+		stateAt: func(header *types.Header) (xcom.StateDB, error) {
+			return eth.blockchain.StateAt(header.Root)
+		},
+		slashed: func(ev cconsensus.Evidence, state xcom.StateDB) ([]byte, error) {
+			return xplugin.SlashInstance().CheckDuplicateSign(ev.NodeID(), ev.BlockNumber(), ev.Type(), state)
+		},
+		maxEvidenceAge: gov.GovernMaxEvidenceAge,
+		pending: func(hash common.Hash) bool {
+			return eth.txPool.Get(hash) != nil
+		},
+		reports: make(map[string]*EvidenceReport),
+		eth:     eth,
+		quit:    make(chan struct{}),
+	}
+	r.submit = r.sendReport
+	return r
+}
+
+func (r *evidenceReporter) Start() {
+	r.wg.Add(1)
+	go r.loop()
+	log.Info("Evidence reporter started", "account", r.account)
+}
+
+func (r *evidenceReporter) Stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+func (r *evidenceReporter) loop() {
+	defer r.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, 10)
+	sub := r.eth.blockchain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-headCh:
+			r.process(ev.Block.Header())
+		case <-sub.Err():
+			return
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// evidenceKey returns the key of the slashing contract to reject duplicate reports.
+func evidenceKey(ev cconsensus.Evidence) string {
+	return fmt.Sprintf("%x-%d-%d", ev.NodeID().Bytes(), ev.BlockNumber(), ev.Type())
+}
+
+// process updates the reports of the evidences on a new head block, and sends
+// the report transactions of the evidences not slashed yet.
+func (r *evidenceReporter) process(head *types.Header) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	evs := r.evidences()
+	if len(evs) == 0 {
+		return
+	}
+	state, err := r.stateAt(head)
+	if err != nil {
+		log.Warn("Failed to get the state of the evidence reporter", "number", head.Number, "hash", head.Hash(), "err", err)
+		return
+	}
+	for _, ev := range evs {
+		key := evidenceKey(ev)
+		report, ok := r.reports[key]
+		if !ok {
+			info, err := evidence.NewEvidenceInfo(ev)
+			if err != nil {
+				log.Error("Failed to encode evidence", "evidence", ev, "err", err)
+				continue
+			}
+			report = &EvidenceReport{EvidenceInfo: info, Status: EvidenceReportPending}
+			if !info.Valid {
+				report.Status, report.Reason = EvidenceReportInvalid, info.Error
+			}
+			r.reports[key] = report
+		}
+		r.update(report, ev, head, state)
+	}
+}
+
+func (r *evidenceReporter) update(report *EvidenceReport, ev cconsensus.Evidence, head *types.Header, state xcom.StateDB) {
+	switch report.Status {
+	case EvidenceReportSlashed, EvidenceReportExpired, EvidenceReportInvalid, EvidenceReportFailed:
+		return
+	}
+	number := head.Number.Uint64()
+	if ev.BlockNumber() > number {
+		return
+	}
+	if txHash, err := r.slashed(ev, state); err != nil {
+		report.Reason = err.Error()
+		return
+	} else if len(txHash) > 0 {
+		report.Status, report.TxHash, report.Reason = EvidenceReportSlashed, common.BytesToHash(txHash), ""
+		log.Info("Evidence slashed", "nodeId", ev.NodeID().TerminalString(), "blockNumber", ev.BlockNumber(), "type", ev.Type(), "txHash", report.TxHash)
+		return
+	}
+	// The report transaction is included in the next block at the earliest.
+	maxAge, err := r.maxEvidenceAge(number, head.Hash())
+	if err != nil {
+		report.Reason = err.Error()
+		return
+	}
+	if evidenceExpired(ev.BlockNumber(), number+1, maxAge) {
+		report.Status = EvidenceReportExpired
+		return
+	}
+	if report.Status == EvidenceReportSubmitted && (r.pending(report.TxHash) || number < report.Submitted+evidenceReportTimeout) {
+		return
+	}
+	if report.Attempts >= evidenceReportRetries {
+		report.Status, report.Reason = EvidenceReportFailed, fmt.Sprintf("not slashed after %d reports", report.Attempts)
+		log.Warn("Failed to report evidence", "nodeId", ev.NodeID().TerminalString(), "blockNumber", ev.BlockNumber(), "type", ev.Type(), "attempts", report.Attempts)
+		return
+	}
+	txHash, err := r.submit(report.EvidenceInfo)
+	if err != nil {
+		report.Reason = err.Error()
+		log.Warn("Failed to send evidence report", "nodeId", ev.NodeID().TerminalString(), "blockNumber", ev.BlockNumber(), "type", ev.Type(), "err", err)
+		return
+	}
+	report.Status, report.TxHash, report.Submitted, report.Reason = EvidenceReportSubmitted, txHash, number, ""
+	report.Attempts++
+	log.Info("Evidence report sent", "nodeId", ev.NodeID().TerminalString(), "blockNumber", ev.BlockNumber(), "type", ev.Type(), "txHash", txHash, "attempts", report.Attempts)
+}
+
+// evidenceExpired returns whether the evidence is too old to be reported in
+// the block, in the same way as the slashing plugin.
+func evidenceExpired(evidenceNumber, number uint64, maxAge uint32) bool {
+	blocksOfEpoch := xutil.CalcBlocksEachEpoch()
+	invalidNum := xutil.CalculateEpoch(evidenceNumber) * blocksOfEpoch
+	return invalidNum < number && number-invalidNum > blocksOfEpoch*uint64(maxAge)
+}
+
+// encodeReportDuplicateSign encodes the input of the reportDuplicateSign
+// transaction of the slashing contract.
+func encodeReportDuplicateSign(info *evidence.EvidenceInfo) ([]byte, error) {
+	return rlp.EncodeToBytes([][]byte{
+		common.MustRlpEncode(uint16(vm.TxReportDuplicateSign)),
+		common.MustRlpEncode(info.Type),
+		common.MustRlpEncode(info.Data),
+	})
+}
+
+// sendReport signs a reportDuplicateSign transaction with the account of the
+// reporter and adds it to the transaction pool.
+func (r *evidenceReporter) sendReport(info *evidence.EvidenceInfo) (common.Hash, error) {
+	data, err := encodeReportDuplicateSign(info)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	gas, err := core.IntrinsicGas(data, nil, false, nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	gas += params.SlashingGas + params.ReportDuplicateSignGas + params.DuplicateEvidencesGas
+
+	gasPrice, err := r.eth.APIBackend.SuggestPrice(context.Background())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	account := accounts.Account{Address: r.account}
+	wallet, err := r.eth.accountManager.Find(account)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx := types.NewTransaction(r.eth.txPool.Nonce(r.account), cvm.SlashingContractAddr, common.Big0, gas, gasPrice, data)
+	signed, err := wallet.SignTx(account, tx, r.eth.chainConfig.ChainID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := r.eth.txPool.AddLocal(signed); err != nil {
+		return common.Hash{}, err
+	}
+	return signed.Hash(), nil
+}
+
+// Reports returns the reports of the evidences ordered by block number.
+func (r *evidenceReporter) Reports() []*EvidenceReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reports := make([]*EvidenceReport, 0, len(r.reports))
+	for _, report := range r.reports {
+		cpy := *report
+		reports = append(reports, &cpy)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].BlockNumber == reports[j].BlockNumber {
+			return reports[i].Type < reports[j].Type
+		}
+		return reports[i].BlockNumber < reports[j].BlockNumber
+	})
+	return reports
+}
+
+// PublicEvidenceReporterAPI provides an API to access the reports of the evidences.
+type PublicEvidenceReporterAPI struct {
+	reporter *evidenceReporter
+}
+
+// NewPublicEvidenceReporterAPI creates a new evidence reporter API.
+func NewPublicEvidenceReporterAPI(reporter *evidenceReporter) *PublicEvidenceReporterAPI {
+	return &PublicEvidenceReporterAPI{reporter: reporter}
+}
+
+// EvidenceReports returns the reports of the evidences sent to the slashing contract.
+func (api *PublicEvidenceReporterAPI) EvidenceReports() []*EvidenceReport {
+	return api.reporter.Reports()
+}
@@ -0,0 +1,201 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	cconsensus "github.com/AlayaNetwork/Alaya-Go/common/consensus"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/evidence"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/crypto/bls"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
+	"github.com/AlayaNetwork/Alaya-Go/x/xutil"
+)
+
+type fakeEvidence struct {
+	Number uint64
+	Node   discover.NodeID
+	Kind   cconsensus.EvidenceType
+	Err    string
+}
+
+func (ev *fakeEvidence) Equal(other cconsensus.Evidence) bool { return false }
+func (ev *fakeEvidence) BlockNumber() uint64                  { return ev.Number }
+func (ev *fakeEvidence) Epoch() uint64                        { return 1 }
+func (ev *fakeEvidence) ViewNumber() uint64                   { return 0 }
+func (ev *fakeEvidence) Hash() []byte                         { return common.Uint64ToBytes(ev.Number) }
+func (ev *fakeEvidence) NodeID() discover.NodeID              { return ev.Node }
+func (ev *fakeEvidence) BlsPubKey() *bls.PublicKey            { return nil }
+func (ev *fakeEvidence) Type() cconsensus.EvidenceType        { return ev.Kind }
+func (ev *fakeEvidence) ValidateMsg() bool                    { return true }
+func (ev *fakeEvidence) Validate() error {
+	if ev.Err != "" {
+		return errors.New(ev.Err)
+	}
+	return nil
+}
+
+type fakeSlashing struct {
+	evidences cconsensus.Evidences
+	slashed   map[string]common.Hash
+	pending   map[common.Hash]bool
+	submits   []*evidence.EvidenceInfo
+	submitErr error
+	maxAge    uint32
+}
+
+func newTestEvidenceReporter(f *fakeSlashing) *evidenceReporter {
+	return &evidenceReporter{
+		evidences: func() cconsensus.Evidences { return f.evidences },
+		stateAt:   func(header *types.Header) (xcom.StateDB, error) { return nil, nil },
+		slashed: func(ev cconsensus.Evidence, state xcom.StateDB) ([]byte, error) {
+			if hash, ok := f.slashed[evidenceKey(ev)]; ok {
+				return hash.Bytes(), nil
+			}
+			return nil, nil
+		},
+		maxEvidenceAge: func(uint64, common.Hash) (uint32, error) { return f.maxAge, nil },
+		pending:        func(hash common.Hash) bool { return f.pending[hash] },
+		submit: func(info *evidence.EvidenceInfo) (common.Hash, error) {
+			if f.submitErr != nil {
+				return common.Hash{}, f.submitErr
+			}
+			f.submits = append(f.submits, info)
+			hash := common.BytesToHash(common.Uint64ToBytes(uint64(len(f.submits))))
+			f.pending[hash] = true
+			return hash, nil
+		},
+		reports: make(map[string]*EvidenceReport),
+	}
+}
+
+func testHead(number uint64) *types.Header {
+	return &types.Header{Number: new(big.Int).SetUint64(number)}
+}
+
+func TestEvidenceReporter(t *testing.T) {
+	xcom.GetEc(xcom.DefaultTestNet)
+
+	node := discover.NodeID{1}
+	f := &fakeSlashing{
+		evidences: cconsensus.Evidences{
+			&fakeEvidence{Number: 10, Node: node, Kind: evidence.DuplicatePrepareBlockType},
+			// Same key as the first one, only reported once.
+			&fakeEvidence{Number: 10, Node: node, Kind: evidence.DuplicatePrepareBlockType},
+			&fakeEvidence{Number: 11, Node: node, Kind: evidence.DuplicatePrepareVoteType, Err: "invalid signature"},
+			&fakeEvidence{Number: 20, Node: node, Kind: evidence.DuplicateViewChangeType},
+		},
+		slashed: make(map[string]common.Hash),
+		pending: make(map[common.Hash]bool),
+		maxAge:  1000,
+	}
+	r := newTestEvidenceReporter(f)
+
+	// The evidence of block 20 is above the head, it is not reported yet.
+	r.process(testHead(12))
+	reports := r.Reports()
+	assert.Len(t, reports, 3)
+	assert.Len(t, f.submits, 1)
+	assert.Equal(t, EvidenceReportSubmitted, reports[0].Status)
+	assert.Equal(t, 1, reports[0].Attempts)
+	assert.Equal(t, uint64(12), reports[0].Submitted)
+	assert.Equal(t, EvidenceReportInvalid, reports[1].Status)
+	assert.Equal(t, "invalid signature", reports[1].Reason)
+	assert.Equal(t, EvidenceReportPending, reports[2].Status)
+
+	// The first report is pending in the pool.
+	r.process(testHead(30))
+	reports = r.Reports()
+	assert.Len(t, f.submits, 2)
+	assert.Equal(t, uint8(evidence.DuplicateViewChangeType), reports[2].Type)
+	assert.Equal(t, EvidenceReportSubmitted, reports[2].Status)
+	assert.Equal(t, 1, reports[0].Attempts)
+
+	// The report transaction of block 10 is dropped, it is sent again after the timeout.
+	f.pending = make(map[common.Hash]bool)
+	r.process(testHead(12 + evidenceReportTimeout - 1))
+	assert.Len(t, f.submits, 2)
+	r.process(testHead(12 + evidenceReportTimeout))
+	assert.Len(t, f.submits, 3)
+	assert.Equal(t, 2, r.Reports()[0].Attempts)
+
+	// The node is slashed for the evidence of block 20.
+	slashTx := common.HexToHash("0x1234")
+	f.slashed[evidenceKey(f.evidences[3])] = slashTx
+	r.process(testHead(12 + evidenceReportTimeout))
+	reports = r.Reports()
+	assert.Equal(t, EvidenceReportSlashed, reports[2].Status)
+	assert.Equal(t, slashTx, reports[2].TxHash)
+
+	// The evidence of block 10 fails after all the retries.
+	f.pending = make(map[common.Hash]bool)
+	r.process(testHead(12 + 2*evidenceReportTimeout))
+	f.pending = make(map[common.Hash]bool)
+	r.process(testHead(12 + 3*evidenceReportTimeout))
+	reports = r.Reports()
+	assert.Len(t, f.submits, evidenceReportRetries+1)
+	assert.Equal(t, EvidenceReportFailed, reports[0].Status)
+	assert.Equal(t, evidenceReportRetries, reports[0].Attempts)
+}
+
+func TestEvidenceReporterExpired(t *testing.T) {
+	xcom.GetEc(xcom.DefaultTestNet)
+
+	blocksOfEpoch := xutil.CalcBlocksEachEpoch()
+	f := &fakeSlashing{
+		evidences: cconsensus.Evidences{
+			&fakeEvidence{Number: 1, Node: discover.NodeID{1}, Kind: evidence.DuplicatePrepareBlockType},
+		},
+		slashed:   make(map[string]common.Hash),
+		pending:   make(map[common.Hash]bool),
+		submitErr: errors.New("account locked"),
+		maxAge:    2,
+	}
+	r := newTestEvidenceReporter(f)
+
+	// Submit errors are not counted as attempts.
+	r.process(testHead(blocksOfEpoch))
+	reports := r.Reports()
+	assert.Equal(t, EvidenceReportPending, reports[0].Status)
+	assert.Equal(t, 0, reports[0].Attempts)
+	assert.Equal(t, "account locked", reports[0].Reason)
+
+	r.process(testHead(3 * blocksOfEpoch))
+	assert.Equal(t, EvidenceReportExpired, r.Reports()[0].Status)
+	assert.Len(t, f.submits, 0)
+}
+
+func TestEncodeReportDuplicateSign(t *testing.T) {
+	info, err := evidence.NewEvidenceInfo(&fakeEvidence{Number: 1, Kind: evidence.DuplicatePrepareVoteType})
+	assert.Nil(t, err)
+	assert.True(t, info.Valid)
+	assert.Equal(t, "duplicateVote", info.TypeName)
+
+	data, err := encodeReportDuplicateSign(info)
+	assert.Nil(t, err)
+	var params [][]byte
+	assert.Nil(t, rlp.DecodeBytes(data, &params))
+	assert.Len(t, params, 3)
+}
@@ -31,7 +31,9 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
 	"github.com/AlayaNetwork/Alaya-Go/ethdb"
 	"github.com/AlayaNetwork/Alaya-Go/event"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
 	"github.com/AlayaNetwork/Alaya-Go/rpc"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
 )
 
 var (
@@ -271,6 +273,48 @@ func (api *PublicFilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc
 	return rpcSub, nil
 }
 
+// PPOSEventCriteria represents a request to subscribe the PPOS events, an empty
+// field matches all the events.
+type PPOSEventCriteria struct {
+	Types     []string          `json:"types"`
+	NodeIds   []discover.NodeID `json:"nodeIds"`
+	Addresses []common.Address  `json:"addresses"`
+}
+
+// PposEvents creates a subscription that fires for the PPOS events of the
+// committed blocks matching the given criteria, i.e. the staking, delegation,
+// reward, slashing, restricting release and election events.
+func (api *PublicFilterAPI) PposEvents(ctx context.Context, crit PPOSEventCriteria) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan []*xcom.PPOSEvent)
+		eventsSub := api.events.SubscribePPOSEvents(crit, events)
+
+		for {
+			select {
+			case evs := <-events:
+				for _, ev := range evs {
+					notifier.Notify(rpcSub.ID, ev)
+				}
+			case <-rpcSub.Err():
+				eventsSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				eventsSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // FilterCriteria represents a request to create a new filter.
 // Same as ethereum.FilterQuery but with UnmarshalJSON() method.
 type FilterCriteria ethereum.FilterQuery
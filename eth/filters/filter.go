@@ -27,7 +27,9 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
 	"github.com/AlayaNetwork/Alaya-Go/ethdb"
 	"github.com/AlayaNetwork/Alaya-Go/event"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
 	"github.com/AlayaNetwork/Alaya-Go/rpc"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
 )
 
 type Backend interface {
@@ -318,6 +320,49 @@ Logs:
 	return ret
 }
 
+// filterPPOSEvents creates a slice of PPOS events matching the given criteria.
+func filterPPOSEvents(events []*xcom.PPOSEvent, crit PPOSEventCriteria) []*xcom.PPOSEvent {
+	var ret []*xcom.PPOSEvent
+	for _, ev := range events {
+		if len(crit.Types) > 0 && !includesString(crit.Types, ev.Type) {
+			continue
+		}
+		if len(crit.Addresses) > 0 && !includes(crit.Addresses, ev.Address) {
+			continue
+		}
+		if len(crit.NodeIds) > 0 {
+			// The election events match any of the elected nodes.
+			match := includesNodeId(crit.NodeIds, ev.NodeId)
+			for i := 0; i < len(ev.Nodes) && !match; i++ {
+				match = includesNodeId(crit.NodeIds, ev.Nodes[i])
+			}
+			if !match {
+				continue
+			}
+		}
+		ret = append(ret, ev)
+	}
+	return ret
+}
+
+func includesString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
+
+func includesNodeId(ids []discover.NodeID, id discover.NodeID) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
 func bloomFilter(bloom types.Bloom, addresses []common.Address, topics [][]common.Hash) bool {
 	if len(addresses) > 0 {
 		var included bool
@@ -33,6 +33,7 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/event"
 	"github.com/AlayaNetwork/Alaya-Go/log"
 	"github.com/AlayaNetwork/Alaya-Go/rpc"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
 )
 
 // Type determines the kind of filter and is used to put the filter in to
@@ -53,6 +54,8 @@ const (
 	PendingTransactionsSubscription
 	// BlocksSubscription queries hashes for blocks that are imported
 	BlocksSubscription
+	// PPOSEventsSubscription queries the PPOS events of committed blocks
+	PPOSEventsSubscription
 	// LastSubscription keeps track of the last index
 	LastIndexSubscription
 )
@@ -82,6 +85,8 @@ type subscription struct {
 	logs      chan []*types.Log
 	hashes    chan []common.Hash
 	headers   chan *types.Header
+	pposCrit  PPOSEventCriteria
+	ppos      chan []*xcom.PPOSEvent
 	installed chan struct{} // closed when the filter is installed
 	err       chan error    // closed when the filter is uninstalled
 }
@@ -99,7 +104,7 @@ type EventSystem struct {
 	logsSub       event.Subscription         // Subscription for new log event
 	rmLogsSub     event.Subscription         // Subscription for removed log event
 	chainSub      event.Subscription         // Subscription for new chain event
-	pendingLogSub *event.TypeMuxSubscription // Subscription for pending log event
+	pendingLogSub *event.TypeMuxSubscription // Subscription for pending log and PPOS event
 
 	// Channels
 	install   chan *subscription         // install filter for event notification
@@ -135,7 +140,7 @@ func NewEventSystem(mux *event.TypeMux, backend Backend, lightMode bool) *EventS
 	m.rmLogsSub = m.backend.SubscribeRemovedLogsEvent(m.rmLogsCh)
 	m.chainSub = m.backend.SubscribeChainEvent(m.chainCh)
 	// TODO(rjl493456442): use feed to subscribe pending log event
-	m.pendingLogSub = m.mux.Subscribe(core.PendingLogsEvent{})
+	m.pendingLogSub = m.mux.Subscribe(core.PendingLogsEvent{}, core.PPOSEventsEvent{})
 
 	// Make sure none of the subscriptions are empty
 	if m.txsSub == nil || m.logsSub == nil || m.rmLogsSub == nil || m.chainSub == nil ||
@@ -175,6 +180,7 @@ func (sub *Subscription) Unsubscribe() {
 			case <-sub.f.logs:
 			case <-sub.f.hashes:
 			case <-sub.f.headers:
+			case <-sub.f.ppos:
 			}
 		}
 
@@ -314,6 +320,24 @@ func (es *EventSystem) SubscribePendingTxs(hashes chan []common.Hash) *Subscript
 	return es.subscribe(sub)
 }
 
+// SubscribePPOSEvents creates a subscription that writes the PPOS events
+// matching the given criteria of the committed blocks.
+func (es *EventSystem) SubscribePPOSEvents(crit PPOSEventCriteria, events chan []*xcom.PPOSEvent) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       PPOSEventsSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		hashes:    make(chan []common.Hash),
+		headers:   make(chan *types.Header),
+		pposCrit:  crit,
+		ppos:      events,
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
 type filterIndex map[Type]map[rpc.ID]*subscription
 
 // broadcast event to filters that match criteria.
@@ -338,7 +362,8 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 			}
 		}
 	case *event.TypeMuxEvent:
-		if muxe, ok := e.Data.(core.PendingLogsEvent); ok {
+		switch muxe := e.Data.(type) {
+		case core.PendingLogsEvent:
 			for _, f := range filters[PendingLogsSubscription] {
 				if e.Time.After(f.created) {
 					if matchedLogs := filterLogs(muxe.Logs, nil, f.logsCrit.ToBlock, f.logsCrit.Addresses, f.logsCrit.Topics); len(matchedLogs) > 0 {
@@ -346,6 +371,12 @@ func (es *EventSystem) broadcast(filters filterIndex, ev interface{}) {
 					}
 				}
 			}
+		case core.PPOSEventsEvent:
+			for _, f := range filters[PPOSEventsSubscription] {
+				if matched := filterPPOSEvents(muxe.Events, f.pposCrit); len(matched) > 0 {
+					f.ppos <- matched
+				}
+			}
 		}
 	case core.NewTxsEvent:
 		hashes := make([]common.Hash, 0, len(e.Txs))
@@ -35,9 +35,10 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
 	"github.com/AlayaNetwork/Alaya-Go/ethdb"
 	"github.com/AlayaNetwork/Alaya-Go/event"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
 	"github.com/AlayaNetwork/Alaya-Go/params"
 	"github.com/AlayaNetwork/Alaya-Go/rpc"
-	_ "github.com/AlayaNetwork/Alaya-Go/x/xcom"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
 )
 
 type testBackend struct {
@@ -217,6 +218,83 @@ func TestBlockSubscription(t *testing.T) {
 	<-sub1.Err()
 }
 
+// TestPPOSEventsSubscription tests if the PPOS events posted to the event mux
+// are delivered to the subscriptions matching their criteria.
+func TestPPOSEventsSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mux        = new(event.TypeMux)
+		db         = rawdb.NewMemoryDatabase()
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		api        = NewPublicFilterAPI(backend, false)
+
+		node1, node2 = discover.NodeID{1}, discover.NodeID{2}
+		delAddr      = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		events       = []*xcom.PPOSEvent{
+			xcom.NewPPOSEvent(xcom.CreateStakingEvent, node1, 1, common.HexToAddress("0x01"), big.NewInt(100)),
+			xcom.NewPPOSEvent(xcom.DelegateEvent, node1, 1, delAddr, big.NewInt(10)),
+			xcom.NewPPOSEvent(xcom.DelegateEvent, node2, 2, delAddr, big.NewInt(20)),
+			{Type: xcom.ElectValidatorsEvent, Nodes: []discover.NodeID{node1, node2}},
+		}
+	)
+
+	testCases := []struct {
+		crit     PPOSEventCriteria
+		expected []*xcom.PPOSEvent
+	}{
+		// all events
+		{PPOSEventCriteria{}, events},
+		// the events of the type
+		{PPOSEventCriteria{Types: []string{xcom.DelegateEvent}}, events[1:3]},
+		// the events of the delegator on node2
+		{PPOSEventCriteria{NodeIds: []discover.NodeID{node2}, Addresses: []common.Address{delAddr}}, events[2:3]},
+		// the elections including node2
+		{PPOSEventCriteria{Types: []string{xcom.ElectValidatorsEvent}, NodeIds: []discover.NodeID{node2}}, events[3:]},
+		// no matching event
+		{PPOSEventCriteria{Types: []string{xcom.SlashingEvent}}, nil},
+	}
+
+	chans := make([]chan []*xcom.PPOSEvent, len(testCases))
+	subs := make([]*Subscription, len(testCases))
+	for i, tc := range testCases {
+		chans[i] = make(chan []*xcom.PPOSEvent)
+		subs[i] = api.events.SubscribePPOSEvents(tc.crit, chans[i])
+	}
+
+	go func() {
+		if err := mux.Post(core.PPOSEventsEvent{Events: events}); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	for i, tc := range testCases {
+		if tc.expected == nil {
+			continue
+		}
+		select {
+		case got := <-chans[i]:
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("sub %d received invalid events, want %v, got %v", i, tc.expected, got)
+			}
+		case <-time.After(1 * time.Second):
+			t.Fatalf("sub %d timeout", i)
+		}
+	}
+	for i, sub := range subs {
+		sub.Unsubscribe()
+		select {
+		case evs := <-chans[i]:
+			t.Errorf("sub %d received unexpected events %v", i, evs)
+		default:
+		}
+	}
+}
+
 // TestPendingTxFilter tests whether pending tx filters retrieve all pending transactions that are posted to the event mux.
 func TestPendingTxFilter(t *testing.T) {
 	t.Parallel()
@@ -73,8 +73,24 @@ func NewOracle(backend ethapi.Backend, params Config) *Oracle {
 	}
 }
 
-// SuggestPrice returns the recommended gas price.
+// SuggestPrice returns the recommended gas price, it's the recommended tip plus
+// the base fee of the latest block once the dynamic fee market is active.
 func (gpo *Oracle) SuggestPrice(ctx context.Context) (*big.Int, error) {
+	tip, err := gpo.SuggestTipCap(ctx)
+	if err != nil {
+		return tip, err
+	}
+	head, _ := gpo.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if head == nil || head.BaseFee == nil {
+		return tip, nil
+	}
+	return new(big.Int).Add(tip, head.BaseFee), nil
+}
+
+// SuggestTipCap returns the recommended tip of the dynamic fee transactions,
+// it's the lowest effective tip paid in the recent blocks at the configured
+// percentile. The tip is the whole gas price before the dynamic fee market.
+func (gpo *Oracle) SuggestTipCap(ctx context.Context) (*big.Int, error) {
 	gpo.cacheLock.RLock()
 	lastHead := gpo.lastHead
 	lastPrice := gpo.lastPrice
@@ -104,7 +120,7 @@ func (gpo *Oracle) SuggestPrice(ctx context.Context) (*big.Int, error) {
 	exp := 0
 	var blockPrices []*big.Int
 	for sent < gpo.checkBlocks && blockNum > 0 {
-		go gpo.getBlockPrices(ctx, types.NewEIP155Signer(gpo.backend.ChainConfig().ChainID), blockNum, ch)
+		go gpo.getBlockPrices(ctx, types.LatestSignerForChainID(gpo.backend.ChainConfig().ChainID), blockNum, ch)
 		sent++
 		exp++
 		blockNum--
@@ -125,7 +141,7 @@ func (gpo *Oracle) SuggestPrice(ctx context.Context) (*big.Int, error) {
 			continue
 		}
 		if blockNum > 0 && sent < gpo.maxBlocks {
-			go gpo.getBlockPrices(ctx, types.NewEIP155Signer(gpo.backend.ChainConfig().ChainID), blockNum, ch)
+			go gpo.getBlockPrices(ctx, types.LatestSignerForChainID(gpo.backend.ChainConfig().ChainID), blockNum, ch)
 			sent++
 			exp++
 			blockNum--
@@ -152,13 +168,18 @@ type getBlockPricesResult struct {
 	err   error
 }
 
-type transactionsByGasPrice []*types.Transaction
+type transactionsByEffectiveTip struct {
+	txs     []*types.Transaction
+	baseFee *big.Int
+}
 
-func (t transactionsByGasPrice) Len() int           { return len(t) }
-func (t transactionsByGasPrice) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
-func (t transactionsByGasPrice) Less(i, j int) bool { return t[i].GasPriceCmp(t[j]) < 0 }
+func (t transactionsByEffectiveTip) Len() int      { return len(t.txs) }
+func (t transactionsByEffectiveTip) Swap(i, j int) { t.txs[i], t.txs[j] = t.txs[j], t.txs[i] }
+func (t transactionsByEffectiveTip) Less(i, j int) bool {
+	return t.txs[i].EffectiveGasTipCmp(t.txs[j], t.baseFee) < 0
+}
 
-// getBlockPrices calculates the lowest transaction gas price in a given block
+// getBlockPrices calculates the lowest transaction effective tip in a given block
 // and sends it to the result channel. If the block is empty, price is nil.
 func (gpo *Oracle) getBlockPrices(ctx context.Context, signer types.Signer, blockNum uint64, ch chan getBlockPricesResult) {
 	block, err := gpo.backend.BlockByNumber(ctx, rpc.BlockNumber(blockNum))
@@ -170,12 +191,12 @@ func (gpo *Oracle) getBlockPrices(ctx context.Context, signer types.Signer, bloc
 	blockTxs := block.Transactions()
 	txs := make([]*types.Transaction, len(blockTxs))
 	copy(txs, blockTxs)
-	sort.Sort(transactionsByGasPrice(txs))
+	sort.Sort(transactionsByEffectiveTip{txs, block.BaseFee()})
 
 	for _, tx := range txs {
 		sender, err := types.Sender(signer, tx)
 		if err == nil && sender != block.Coinbase() {
-			ch <- getBlockPricesResult{tx.GasPrice(), nil}
+			ch <- getBlockPricesResult{tx.EffectiveGasTipValue(block.BaseFee()), nil}
 			return
 		}
 	}
@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/AlayaNetwork/Alaya-Go/common"
 	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
 	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/types"
 	"github.com/AlayaNetwork/Alaya-Go/core"
@@ -20,11 +21,15 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	type Config struct {
 		Genesis                  *core.Genesis       `toml:",omitempty"`
 		CbftConfig               types.OptionsConfig `toml:",omitempty"`
+		EvidenceReporter         common.Address      `toml:",omitempty"`
+		ValidatorMonitor         bool                `toml:",omitempty"`
+		ValidatorMonitorWebhook  string              `toml:",omitempty"`
 		NetworkId                uint64
 		SyncMode                 downloader.SyncMode
 		NoPruning                bool
 		LightServ                int  `toml:",omitempty"`
 		LightPeers               int  `toml:",omitempty"`
+		LightVerifyQC            bool `toml:",omitempty"`
 		SkipBcVersionCheck       bool `toml:"-"`
 		DatabaseHandles          int  `toml:"-"`
 		DatabaseCache            int
@@ -63,11 +68,15 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	var enc Config
 	enc.Genesis = c.Genesis
 	enc.CbftConfig = c.CbftConfig
+	enc.EvidenceReporter = c.EvidenceReporter
+	enc.ValidatorMonitor = c.ValidatorMonitor
+	enc.ValidatorMonitorWebhook = c.ValidatorMonitorWebhook
 	enc.NetworkId = c.NetworkId
 	enc.SyncMode = c.SyncMode
 	enc.NoPruning = c.NoPruning
 	enc.LightServ = c.LightServ
 	enc.LightPeers = c.LightPeers
+	enc.LightVerifyQC = c.LightVerifyQC
 	enc.SkipBcVersionCheck = c.SkipBcVersionCheck
 	enc.DatabaseHandles = c.DatabaseHandles
 	enc.DatabaseCache = c.DatabaseCache
@@ -110,11 +119,15 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	type Config struct {
 		Genesis                  *core.Genesis        `toml:",omitempty"`
 		CbftConfig               *types.OptionsConfig `toml:",omitempty"`
+		EvidenceReporter         *common.Address      `toml:",omitempty"`
+		ValidatorMonitor         *bool                `toml:",omitempty"`
+		ValidatorMonitorWebhook  *string              `toml:",omitempty"`
 		NetworkId                *uint64
 		SyncMode                 *downloader.SyncMode
 		NoPruning                *bool
 		LightServ                *int  `toml:",omitempty"`
 		LightPeers               *int  `toml:",omitempty"`
+		LightVerifyQC            *bool `toml:",omitempty"`
 		SkipBcVersionCheck       *bool `toml:"-"`
 		DatabaseHandles          *int  `toml:"-"`
 		DatabaseCache            *int
@@ -160,6 +173,15 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.CbftConfig != nil {
 		c.CbftConfig = *dec.CbftConfig
 	}
+	if dec.EvidenceReporter != nil {
+		c.EvidenceReporter = *dec.EvidenceReporter
+	}
+	if dec.ValidatorMonitor != nil {
+		c.ValidatorMonitor = *dec.ValidatorMonitor
+	}
+	if dec.ValidatorMonitorWebhook != nil {
+		c.ValidatorMonitorWebhook = *dec.ValidatorMonitorWebhook
+	}
 	if dec.NetworkId != nil {
 		c.NetworkId = *dec.NetworkId
 	}
@@ -175,6 +197,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.LightPeers != nil {
 		c.LightPeers = *dec.LightPeers
 	}
+	if dec.LightVerifyQC != nil {
+		c.LightVerifyQC = *dec.LightVerifyQC
+	}
 	if dec.SkipBcVersionCheck != nil {
 		c.SkipBcVersionCheck = *dec.SkipBcVersionCheck
 	}
@@ -17,7 +17,6 @@
 package eth
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -28,7 +27,6 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/syndtr/goleveldb/leveldb/iterator"
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
 	"github.com/AlayaNetwork/Alaya-Go/consensus"
@@ -488,7 +486,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 		if err := msg.Decode(&query); err != nil {
 			return errResp(ErrDecode, "%v: %v", msg, err)
 		}
-		f := func(num *big.Int, iter iterator.Iterator) error {
+		f := func(num *big.Int, iter snapshotdb.Iterator) error {
 			var psInfo PPOSInfo
 			if num == nil {
 				return errors.New("num should not be nil")
@@ -506,7 +504,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			)
 			ps.KVs = make([]downloader.PPOSStorageKV, 0)
 			for iter.Next() {
-				if bytes.Equal(iter.Key(), []byte(snapshotdb.CurrentHighestBlock)) || bytes.Equal(iter.Key(), []byte(snapshotdb.CurrentBaseNum)) || bytes.HasPrefix(iter.Key(), []byte(snapshotdb.WalKeyPrefix)) {
+				if !snapshotdb.IsPPOSKey(iter.Key()) {
 					continue
 				}
 				byteSize = byteSize + len(iter.Key()) + len(iter.Value())
@@ -364,6 +364,15 @@ func TestGetPPOSStorageMsg(t *testing.T) {
 		peer.close()
 		db.Clear()
 	}()
+	// The archive of the serving node is not part of the PPOS storage.
+	local := [][2][]byte{
+		{[]byte(snapshotdb.ArchiveBegin), []byte{1}},
+		{append([]byte(snapshotdb.ArchiveKeyPrefix), 1), []byte{1}},
+	}
+	if err := db.WriteBaseDB(local); err != nil {
+		t.Error(err)
+		return
+	}
 	if err := p2p.Send(peer.app, GetPPOSStorageMsg, []interface{}{}); err != nil {
 		t.Error(err)
 		return
@@ -396,6 +405,11 @@ func TestGetPPOSStorageMsg(t *testing.T) {
 			t.Error(err)
 			return
 		}
+		for _, kv := range data2.KVs {
+			if !snapshotdb.IsPPOSKey(kv[0]) {
+				t.Errorf("local snapshotdb key %q served", kv[0])
+			}
+		}
 		if data2.Last {
 			break
 		}
@@ -0,0 +1,240 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
+	cvm "github.com/AlayaNetwork/Alaya-Go/common/vm"
+	"github.com/AlayaNetwork/Alaya-Go/core/vm"
+	"github.com/AlayaNetwork/Alaya-Go/x/plugin"
+)
+
+// PPOSTracerName is the name of the native tracer of the PPOS precompiled contracts.
+const PPOSTracerName = "pposTracer"
+
+var pposContractNames = map[common.Address]string{
+	cvm.RestrictingContractAddr: "restricting",
+	cvm.StakingContractAddr:     "staking",
+	cvm.SlashingContractAddr:    "slashing",
+	cvm.GovContractAddr:         "gov",
+	cvm.DelegateRewardPoolAddr:  "delegateReward",
+}
+
+// PPOSTracer is a native tracer which decodes every call to the PPOS precompiled
+// contracts, and records the snapshotdb keys read and written, the balance
+// movements and the result code of the call.
+type PPOSTracer struct {
+	calls   []*pposCall
+	current *pposCall
+	env     *vm.EVM
+}
+
+type pposCall struct {
+	Contract  string          `json:"contract"`
+	From      common.Address  `json:"from"`
+	To        common.Address  `json:"to"`
+	Value     *hexutil.Big    `json:"value"`
+	Gas       hexutil.Uint64  `json:"gas"`
+	GasUsed   hexutil.Uint64  `json:"gasUsed"`
+	FuncType  uint16          `json:"funcType"`
+	FuncName  string          `json:"funcName,omitempty"`
+	Params    []interface{}   `json:"params,omitempty"`
+	Input     hexutil.Bytes   `json:"input"`
+	Output    hexutil.Bytes   `json:"output"`
+	Code      *uint32         `json:"code,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	Reads     []*pposKV       `json:"reads"`
+	Writes    []*pposKV       `json:"writes"`
+	Transfers []*pposTransfer `json:"transfers"`
+
+	readIndex  map[string]int
+	writeIndex map[string]int
+}
+
+type pposKV struct {
+	Key   hexutil.Bytes `json:"key"`
+	Value hexutil.Bytes `json:"value"`
+}
+
+type pposTransfer struct {
+	Address common.Address `json:"address"`
+	Amount  *hexutil.Big   `json:"amount"`
+}
+
+// NewPPOSTracer returns a new PPOSTracer.
+func NewPPOSTracer() *PPOSTracer {
+	return &PPOSTracer{calls: make([]*pposCall, 0)}
+}
+
+// CaptureStart implements the Tracer interface.
+func (t *PPOSTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+// CaptureState implements the Tracer interface.
+func (t *PPOSTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, rStack *vm.ReturnStack, rdata []byte, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureFault implements the Tracer interface.
+func (t *PPOSTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, rStack *vm.ReturnStack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd implements the Tracer interface.
+func (t *PPOSTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+// CapturePPOSStart implements the PPOSTracer interface, it decodes the function
+// type and the RLP arguments of the call.
+func (t *PPOSTracer) CapturePPOSStart(env *vm.EVM, p vm.PlatONPrecompiledContract, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	call := &pposCall{
+		Contract:   pposContractNames[to],
+		From:       from,
+		To:         to,
+		Value:      (*hexutil.Big)(new(big.Int).Set(value)),
+		Gas:        hexutil.Uint64(gas),
+		Input:      common.CopyBytes(input),
+		Reads:      make([]*pposKV, 0),
+		Writes:     make([]*pposKV, 0),
+		Transfers:  make([]*pposTransfer, 0),
+		readIndex:  make(map[string]int),
+		writeIndex: make(map[string]int),
+	}
+	if fnCode, fn, params, err := plugin.VerifyTxData(input, p.FnSigns()); err == nil {
+		call.FuncType = fnCode
+		call.FuncName = funcName(fn)
+		for _, param := range params {
+			call.Params = append(call.Params, param.Interface())
+		}
+	}
+	t.calls = append(t.calls, call)
+	t.current = call
+
+	if env.SnapshotDB != nil {
+		if err := env.SnapshotDB.SetTraceHook(env.BlockHash, t); err == nil {
+			t.env = env
+		}
+	}
+}
+
+// CapturePPOSBalance implements the PPOSTracer interface, the amount is negative
+// if it is subtracted from the address.
+func (t *PPOSTracer) CapturePPOSBalance(addr common.Address, amount *big.Int, add bool) {
+	if t.current == nil || amount.Sign() == 0 {
+		return
+	}
+	value := new(big.Int).Set(amount)
+	if !add {
+		value.Neg(value)
+	}
+	t.current.Transfers = append(t.current.Transfers, &pposTransfer{Address: addr, Amount: (*hexutil.Big)(value)})
+}
+
+// CapturePPOSEnd implements the PPOSTracer interface, it records the xcom result
+// code of the call.
+func (t *PPOSTracer) CapturePPOSEnd(output []byte, gasUsed uint64, err error) {
+	if t.env != nil {
+		t.env.SnapshotDB.SetTraceHook(t.env.BlockHash, nil)
+		t.env = nil
+	}
+	call := t.current
+	if call == nil {
+		return
+	}
+	t.current = nil
+
+	call.GasUsed = hexutil.Uint64(gasUsed)
+	call.Output = common.CopyBytes(output)
+	if err != nil {
+		call.Error = err.Error()
+	}
+	if bizErr, ok := err.(*common.BizError); ok {
+		call.Code = &bizErr.Code
+	} else if code, ok := resultCode(output); ok {
+		call.Code = &code
+	}
+}
+
+// CaptureRead implements the snapshotdb TraceHook interface, only the first
+// read of a key is recorded.
+func (t *PPOSTracer) CaptureRead(key, value []byte) {
+	call := t.current
+	if call == nil {
+		return
+	}
+	if _, ok := call.readIndex[string(key)]; ok {
+		return
+	}
+	call.readIndex[string(key)] = len(call.Reads)
+	call.Reads = append(call.Reads, &pposKV{Key: common.CopyBytes(key), Value: common.CopyBytes(value)})
+}
+
+// CaptureWrite implements the snapshotdb TraceHook interface, only the last
+// value written to a key is recorded.
+func (t *PPOSTracer) CaptureWrite(key, value []byte) {
+	call := t.current
+	if call == nil {
+		return
+	}
+	if i, ok := call.writeIndex[string(key)]; ok {
+		call.Writes[i].Value = common.CopyBytes(value)
+		return
+	}
+	call.writeIndex[string(key)] = len(call.Writes)
+	call.Writes = append(call.Writes, &pposKV{Key: common.CopyBytes(key), Value: common.CopyBytes(value)})
+}
+
+// GetResult returns the calls to the PPOS precompiled contracts as JSON.
+func (t *PPOSTracer) GetResult() (json.RawMessage, error) {
+	return json.Marshal(t.calls)
+}
+
+// funcName returns the name of the contract method, e.g. createStaking.
+func funcName(fn interface{}) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// resultCode parses the result code of the output, the tx functions return the
+// code directly, the call functions return the xcom.Result as JSON.
+func resultCode(output []byte) (uint32, bool) {
+	if code, err := strconv.ParseUint(string(output), 10, 32); err == nil {
+		return uint32(code), true
+	}
+	var result struct {
+		Code *uint32
+	}
+	if err := json.Unmarshal(output, &result); err != nil || result.Code == nil {
+		return 0, false
+	}
+	return *result.Code, true
+}
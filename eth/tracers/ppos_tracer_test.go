@@ -0,0 +1,111 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	cvm "github.com/AlayaNetwork/Alaya-Go/common/vm"
+	"github.com/AlayaNetwork/Alaya-Go/core/vm"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+)
+
+func TestPPOSTracer(t *testing.T) {
+	nodeId := discover.MustHexID("0x362003c50ed3a523cdede37a001803b8f0fed27cb402b3d6127a1a96661ec202318f68f4c76d9b0bfbabfd551a178d4335eaeaa9b7981a4df30dfc8c0bfe3384")
+	fnType, _ := rlp.EncodeToBytes(uint16(1105))
+	param, _ := rlp.EncodeToBytes(nodeId)
+	input, _ := rlp.EncodeToBytes([][]byte{fnType, param})
+	from := common.HexToAddress("0x01")
+
+	tracer := NewPPOSTracer()
+	tracer.CapturePPOSStart(&vm.EVM{}, &vm.StakingContract{}, from, cvm.StakingContractAddr, input, 100000, big.NewInt(0))
+	tracer.CaptureRead([]byte("k1"), []byte("v1"))
+	tracer.CaptureRead([]byte("k1"), []byte("v1"))
+	tracer.CaptureWrite([]byte("k2"), []byte("v2"))
+	tracer.CaptureWrite([]byte("k2"), []byte("v3"))
+	tracer.CapturePPOSBalance(from, big.NewInt(10), false)
+	tracer.CapturePPOSBalance(cvm.StakingContractAddr, big.NewInt(10), true)
+	tracer.CapturePPOSEnd([]byte(`{"Code":301204,"Ret":"Query candidate info failed:Candidate info is not found"}`), 6000, nil)
+
+	// the calls after the end must not be recorded
+	tracer.CaptureRead([]byte("k3"), nil)
+
+	res, err := tracer.GetResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls []struct {
+		Contract  string
+		FuncType  uint16
+		FuncName  string
+		Params    []string
+		Code      uint32
+		GasUsed   string
+		Reads     []struct{ Key, Value string }
+		Writes    []struct{ Key, Value string }
+		Transfers []struct {
+			Address common.Address
+			Amount  string
+		}
+	}
+	if err := json.Unmarshal(res, &calls); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("want 1 call, have %d", len(calls))
+	}
+	call := calls[0]
+	if call.Contract != "staking" || call.FuncType != 1105 || call.FuncName != "getCandidateInfo" {
+		t.Errorf("call not decoded: %s %d %s", call.Contract, call.FuncType, call.FuncName)
+	}
+	if len(call.Params) != 1 || call.Params[0] != nodeId.String() {
+		t.Errorf("params not decoded: %v", call.Params)
+	}
+	if call.Code != 301204 || call.GasUsed != "0x1770" {
+		t.Errorf("result not right: %d %s", call.Code, call.GasUsed)
+	}
+	if len(call.Reads) != 1 || len(call.Writes) != 1 || call.Writes[0].Value != "0x7633" {
+		t.Errorf("snapshotdb access not right: %v %v", call.Reads, call.Writes)
+	}
+	if len(call.Transfers) != 2 || call.Transfers[0].Amount != "-0xa" || call.Transfers[1].Address != cvm.StakingContractAddr {
+		t.Errorf("transfers not right: %v", call.Transfers)
+	}
+}
+
+func TestPPOSTracer_resultCode(t *testing.T) {
+	tests := []struct {
+		output []byte
+		code   uint32
+		ok     bool
+	}{
+		{[]byte("0"), 0, true},
+		{[]byte("301111"), 301111, true},
+		{[]byte(`{"Code":0,"Ret":[]}`), 0, true},
+		{[]byte(`{"Ret":[]}`), 0, false},
+		{nil, 0, false},
+	}
+	for i, test := range tests {
+		code, ok := resultCode(test.output)
+		if code != test.code || ok != test.ok {
+			t.Errorf("test %d: want %d %v, have %d %v", i, test.code, test.ok, code, ok)
+		}
+	}
+}
@@ -68,7 +68,8 @@ func pushBigInt(n *big.Int, ctx *duktape.Context) {
 
 // opWrapper provides a JavaScript wrapper around OpCode.
 type opWrapper struct {
-	op vm.OpCode
+	op   vm.OpCode
+	host string // The host function called by a WASM contract
 }
 
 // pushObject assembles a JSVM object wrapping a swappable opcode and pushes it
@@ -79,7 +80,14 @@ func (ow *opWrapper) pushObject(vm *duktape.Context) {
 	vm.PushGoFunction(func(ctx *duktape.Context) int { ctx.PushInt(int(ow.op)); return 1 })
 	vm.PutPropString(obj, "toNumber")
 
-	vm.PushGoFunction(func(ctx *duktape.Context) int { ctx.PushString(ow.op.String()); return 1 })
+	vm.PushGoFunction(func(ctx *duktape.Context) int {
+		if ow.host != "" {
+			ctx.PushString(ow.host)
+		} else {
+			ctx.PushString(ow.op.String())
+		}
+		return 1
+	})
 	vm.PutPropString(obj, "toString")
 
 	vm.PushGoFunction(func(ctx *duktape.Context) int { ctx.PushBoolean(ow.op.IsPush()); return 1 })
@@ -305,6 +313,7 @@ type Tracer struct {
 	depthValue  *uint   // Swappable depth value wrapped by a log accessor
 	errorValue  *string // Swappable error value wrapped by a log accessor
 	refundValue *uint   // Swappable refund value wrapped by a log accessor
+	hostValue   []byte  // Swappable WASM host call, JSON encoded, wrapped by a log accessor
 
 	ctx map[string]interface{} // Transaction context gathered throughout execution
 	err error                  // Error, if one has occurred
@@ -488,6 +497,17 @@ func New(code string) (*Tracer, error) {
 	})
 	tracer.vm.PutPropString(logObject, "getError")
 
+	tracer.vm.PushGoFunction(func(ctx *duktape.Context) int {
+		if tracer.hostValue != nil {
+			ctx.PushString(string(tracer.hostValue))
+			ctx.JsonDecode(-1)
+		} else {
+			ctx.PushUndefined()
+		}
+		return 1
+	})
+	tracer.vm.PutPropString(logObject, "getHostCall")
+
 	tracer.vm.PutPropString(tracer.stateObject, "log")
 
 	tracer.dbWrapper.pushObject(tracer.vm)
@@ -554,6 +574,8 @@ func (jst *Tracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost
 			return nil
 		}
 		jst.opWrapper.op = op
+		jst.opWrapper.host = ""
+		jst.hostValue = nil
 		jst.stackWrapper.stack = stack
 		jst.memoryWrapper.memory = memory
 		jst.contractWrapper.contract = contract
@@ -594,6 +616,69 @@ func (jst *Tracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost
 	return nil
 }
 
+// CaptureWasmHostStart implements the WasmTracer interface, the host function
+// call is reported to step once it returns.
+func (jst *Tracer) CaptureWasmHostStart(env *vm.EVM, call *vm.WasmHostCall) error {
+	return nil
+}
+
+// CaptureWasmHostEnd implements the WasmTracer interface to trace a host function
+// call of a WASM contract as a step, log.op is the name of the host function and
+// log.getHostCall() returns its arguments, state accesses and the call stack.
+func (jst *Tracer) CaptureWasmHostEnd(env *vm.EVM, call *vm.WasmHostCall) error {
+	if jst.err == nil {
+		if !jst.inited {
+			jst.ctx["block"] = env.BlockNumber.Uint64()
+			jst.inited = true
+		}
+		if atomic.LoadUint32(&jst.interrupt) > 0 {
+			jst.err = jst.reason
+			return nil
+		}
+		host, err := json.Marshal(&wasmHostCall{
+			Name:      call.Name,
+			Args:      call.Args,
+			State:     call.State,
+			CallStack: call.CallStack,
+		})
+		if err != nil {
+			jst.err = err
+			return nil
+		}
+		jst.opWrapper.op = 0
+		jst.opWrapper.host = call.Name
+		jst.hostValue = host
+		jst.stackWrapper.stack = &vm.Stack{}
+		jst.memoryWrapper.memory = vm.NewMemory()
+		jst.contractWrapper.contract = call.Contract
+		jst.dbWrapper.db = env.StateDB
+
+		*jst.pcValue = 0
+		*jst.gasValue = uint(call.Gas)
+		*jst.costValue = uint(call.Cost)
+		*jst.depthValue = uint(call.Depth)
+		*jst.refundValue = uint(env.StateDB.GetRefund())
+
+		jst.errorValue = nil
+		if call.Err != nil {
+			jst.errorValue = new(string)
+			*jst.errorValue = call.Err.Error()
+		}
+		if _, err := jst.call("step", "log", "db"); err != nil {
+			jst.err = wrapError("step", err)
+		}
+	}
+	return nil
+}
+
+// wasmHostCall is the host function call returned by log.getHostCall().
+type wasmHostCall struct {
+	Name      string               `json:"name"`
+	Args      []uint64             `json:"args"`
+	State     []vm.WasmStateAccess `json:"state"`
+	CallStack []common.Address     `json:"callStack"`
+}
+
 // CaptureEnd is called after the call finishes to finalize the tracing.
 func (jst *Tracer) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
 	jst.ctx["output"] = output
@@ -51,6 +51,8 @@ type dummyStatedb struct {
 
 func (*dummyStatedb) GetRefund() uint64 { return 1337 }
 
+func (*dummyStatedb) GetCurrentActiveVersion() uint32 { return 0 }
+
 func runTrace(tracer *Tracer) (json.RawMessage, error) {
 	env := vm.NewEVM(vm.Context{BlockNumber: big.NewInt(1), Ctx: context.Background()}, nil, &dummyStatedb{}, params.TestChainConfig, vm.Config{Debug: true, Tracer: tracer})
 
@@ -179,3 +181,32 @@ func TestHaltBetweenSteps(t *testing.T) {
 		t.Errorf("Expected timeout error, got %v", err)
 	}
 }
+
+func TestWasmHostCall(t *testing.T) {
+	tracer, err := New("{calls: [], step: function(log) { var host = log.getHostCall(); this.calls.push([log.op.toString(), log.getCost(), host.args.length, host.state[0].write]); }, fault: function() {}, result: function() { return this.calls; }}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := vm.NewEVM(vm.Context{BlockNumber: big.NewInt(1), Ctx: context.Background()}, nil, &dummyStatedb{}, params.TestChainConfig, vm.Config{Debug: true, Tracer: tracer})
+	contract := vm.NewContract(&account{}, &account{}, big.NewInt(0), 10000)
+
+	call := &vm.WasmHostCall{
+		Name:     "platon_set_state",
+		Args:     []uint64{1024, 3, 1040, 5},
+		Gas:      10000,
+		Cost:     200,
+		State:    []vm.WasmStateAccess{{Key: []byte("key"), Value: []byte("value"), Write: true}},
+		Contract: contract,
+		Depth:    1,
+	}
+	tracer.CaptureWasmHostStart(env, call)
+	tracer.CaptureWasmHostEnd(env, call)
+
+	ret, err := tracer.GetResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ret, []byte(`[["platon_set_state",200,4,true]]`)) {
+		t.Errorf("Expected return value to be [[\"platon_set_state\",200,4,true]], got %s", string(ret))
+	}
+}
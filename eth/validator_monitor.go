@@ -0,0 +1,303 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AlayaNetwork/Alaya-Go/core"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/log"
+	"github.com/AlayaNetwork/Alaya-Go/metrics"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	xplugin "github.com/AlayaNetwork/Alaya-Go/x/plugin"
+	"github.com/AlayaNetwork/Alaya-Go/x/slashing"
+)
+
+const (
+	// Maximum number of alerts kept for the RPC.
+	maxValidatorAlerts = 100
+
+	// Timeout of posting an alert to the webhook.
+	validatorAlertTimeout = 10 * time.Second
+)
+
+// The level of a validator alert.
+const (
+	ValidatorAlertNone     = ""
+	ValidatorAlertWarning  = "warning"  // The node has zero-production rounds in the time window of the slashing
+	ValidatorAlertCritical = "critical" // The node is slashed if it produces no block until the time window ends
+)
+
+var (
+	monitorProducedGauge   = metrics.NewRegisteredGauge("eth/monitor/validator/produced", nil)
+	monitorExpectedGauge   = metrics.NewRegisteredGauge("eth/monitor/validator/expected", nil)
+	monitorZeroRoundsGauge = metrics.NewRegisteredGauge("eth/monitor/validator/zerorounds", nil)
+	monitorLevelGauge      = metrics.NewRegisteredGauge("eth/monitor/validator/level", nil)
+	monitorAtRiskGauge     = metrics.NewRegisteredGauge("eth/monitor/round/atrisk", nil)
+	monitorAlertMeter      = metrics.NewRegisteredMeter("eth/monitor/alerts", nil)
+)
+
+// ValidatorAlert is raised when the local node is at risk of being slashed for
+// producing zero blocks.
+type ValidatorAlert struct {
+	Level                      string                  `json:"level"`
+	Message                    string                  `json:"message"`
+	Time                       time.Time               `json:"time"`
+	BlockNumber                uint64                  `json:"blockNumber"`
+	Round                      uint64                  `json:"round"`
+	ZeroProduceNumberThreshold uint16                  `json:"zeroProduceNumberThreshold"`
+	ZeroProduceCumulativeTime  uint16                  `json:"zeroProduceCumulativeTime"`
+	Status                     *slashing.ProduceStatus `json:"status"`
+}
+
+// ValidatorHealth is the block production of the validators of the latest round.
+type ValidatorHealth struct {
+	*slashing.RoundProduceStatus
+	Self  *slashing.ProduceStatus `json:"self"`
+	Level string                  `json:"level"`
+}
+
+// validatorMonitor tracks the block production of the validators on every new
+// head, and alerts when the local node is at risk of the slashing of the
+// zero-production blocks. The alerts are logged and posted to the webhook.
+type validatorMonitor struct {
+	nodeID  discover.NodeID
+	webhook string
+
+	// Hooks to the node, they are replaced in tests.
+	produceStatus func(header *types.Header) (*slashing.RoundProduceStatus, error)
+	post          func(alert *ValidatorAlert) error
+
+	latest    *ValidatorHealth
+	alerts    []*ValidatorAlert
+	lastRound uint64 // The round of the last alert
+	lastLevel string // The highest level alerted in the last round
+	mu        sync.Mutex
+
+	eth  *Ethereum
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newValidatorMonitor creates a validator monitor of the local node, the alerts
+// are posted to the webhook if it's not empty.
+func newValidatorMonitor(eth *Ethereum, nodeID discover.NodeID, webhook string) *validatorMonitor {
+	m := &validatorMonitor{
+		nodeID:  nodeID,
+		webhook: webhook,
+		produceStatus: func(header *types.Header) (*slashing.RoundProduceStatus, error) {
+			return xplugin.SlashInstance().GetProduceStatus(header.Number.Uint64(), header.Hash())
+		},
+		eth:  eth,
+		quit: make(chan struct{}),
+	}
+	m.post = m.postWebhook
+	return m
+}
+
+func (m *validatorMonitor) Start() {
+	m.wg.Add(1)
+	go m.loop()
+	log.Info("Validator monitor started", "nodeId", m.nodeID.TerminalString(), "webhook", m.webhook)
+}
+
+func (m *validatorMonitor) Stop() {
+	close(m.quit)
+	m.wg.Wait()
+}
+
+func (m *validatorMonitor) loop() {
+	defer m.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, 10)
+	sub := m.eth.blockchain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-headCh:
+			m.process(ev.Block.Header())
+		case <-sub.Err():
+			return
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// alertLevel returns the alert level of the block production of the local node.
+func alertLevel(head uint64, status *slashing.ProduceStatus, threshold uint16) string {
+	if status == nil {
+		return ValidatorAlertNone
+	}
+	// The zero-production rounds already reach the threshold, or the turn of the
+	// validator has passed without any block.
+	if status.AtRisk && (len(status.ZeroRounds) >= int(threshold) || status.Validator && head > status.TurnEnd) {
+		return ValidatorAlertCritical
+	}
+	if len(status.ZeroRounds) > 0 {
+		return ValidatorAlertWarning
+	}
+	return ValidatorAlertNone
+}
+
+func alertRank(level string) int {
+	switch level {
+	case ValidatorAlertWarning:
+		return 1
+	case ValidatorAlertCritical:
+		return 2
+	}
+	return 0
+}
+
+// process updates the block production on a new head block, and raises an
+// alert once per round and level.
+func (m *validatorMonitor) process(head *types.Header) {
+	status, err := m.produceStatus(head)
+	if err != nil {
+		log.Debug("Failed to get the block production of the validators", "number", head.Number, "hash", head.Hash(), "err", err)
+		return
+	}
+	self := status.Status(m.nodeID)
+	level := alertLevel(head.Number.Uint64(), self, status.ZeroProduceNumberThreshold)
+
+	atRisk := 0
+	for _, s := range status.Nodes {
+		if s.AtRisk {
+			atRisk++
+		}
+	}
+	monitorAtRiskGauge.Update(int64(atRisk))
+	if self != nil {
+		monitorProducedGauge.Update(int64(self.Produced))
+		monitorExpectedGauge.Update(int64(self.Expected))
+		monitorZeroRoundsGauge.Update(int64(len(self.ZeroRounds)))
+	} else {
+		monitorProducedGauge.Update(0)
+		monitorExpectedGauge.Update(0)
+		monitorZeroRoundsGauge.Update(0)
+	}
+	monitorLevelGauge.Update(int64(alertRank(level)))
+
+	m.mu.Lock()
+	m.latest = &ValidatorHealth{RoundProduceStatus: status, Self: self, Level: level}
+	if level == ValidatorAlertNone || status.Round == m.lastRound && alertRank(level) <= alertRank(m.lastLevel) {
+		m.mu.Unlock()
+		return
+	}
+	m.lastRound, m.lastLevel = status.Round, level
+	alert := &ValidatorAlert{
+		Level:                      level,
+		Time:                       time.Now(),
+		BlockNumber:                status.BlockNumber,
+		Round:                      status.Round,
+		ZeroProduceNumberThreshold: status.ZeroProduceNumberThreshold,
+		ZeroProduceCumulativeTime:  status.ZeroProduceCumulativeTime,
+		Status:                     self,
+	}
+	if level == ValidatorAlertCritical {
+		alert.Message = fmt.Sprintf("node is slashed after round %d unless it produces a block, zero-production rounds %v, threshold %d", self.SlashRound, self.ZeroRounds, status.ZeroProduceNumberThreshold)
+	} else {
+		alert.Message = fmt.Sprintf("node produced zero blocks in rounds %v, threshold %d within %d rounds", self.ZeroRounds, status.ZeroProduceNumberThreshold, status.ZeroProduceCumulativeTime)
+	}
+	m.alerts = append(m.alerts, alert)
+	if len(m.alerts) > maxValidatorAlerts {
+		m.alerts = m.alerts[len(m.alerts)-maxValidatorAlerts:]
+	}
+	m.mu.Unlock()
+
+	monitorAlertMeter.Mark(1)
+	if level == ValidatorAlertCritical {
+		log.Error("Validator at risk of zero-production slashing", "number", status.BlockNumber, "round", status.Round, "produced", self.Produced,
+			"turnEnd", self.TurnEnd, "zeroRounds", self.ZeroRounds, "slashRound", self.SlashRound, "threshold", status.ZeroProduceNumberThreshold)
+	} else {
+		log.Warn("Validator has zero-production rounds", "number", status.BlockNumber, "round", status.Round, "zeroRounds", self.ZeroRounds,
+			"slashRound", self.SlashRound, "threshold", status.ZeroProduceNumberThreshold, "cumulativeTime", status.ZeroProduceCumulativeTime)
+	}
+	if m.webhook != "" {
+		go func() {
+			if err := m.post(alert); err != nil {
+				log.Warn("Failed to post validator alert", "webhook", m.webhook, "err", err)
+			}
+		}()
+	}
+}
+
+// postWebhook posts the alert to the webhook as JSON.
+func (m *validatorMonitor) postWebhook(alert *ValidatorAlert) error {
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: validatorAlertTimeout}
+	resp, err := client.Post(m.webhook, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// Health returns the block production of the validators of the latest round.
+func (m *validatorMonitor) Health() *ValidatorHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.latest
+}
+
+// Alerts returns the latest alerts of the local node.
+func (m *validatorMonitor) Alerts() []*ValidatorAlert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*ValidatorAlert{}, m.alerts...)
+}
+
+// PublicValidatorMonitorAPI provides an API to access the block production of
+// the validators and the alerts of the local node.
+type PublicValidatorMonitorAPI struct {
+	monitor *validatorMonitor
+}
+
+// NewPublicValidatorMonitorAPI creates a new validator monitor API.
+func NewPublicValidatorMonitorAPI(monitor *validatorMonitor) *PublicValidatorMonitorAPI {
+	return &PublicValidatorMonitorAPI{monitor: monitor}
+}
+
+// ValidatorHealth returns the block production of the validators of the latest
+// round and the alert level of the local node.
+func (api *PublicValidatorMonitorAPI) ValidatorHealth() (*ValidatorHealth, error) {
+	health := api.monitor.Health()
+	if health == nil {
+		return nil, fmt.Errorf("no block production is tracked yet")
+	}
+	return health, nil
+}
+
+// ValidatorAlerts returns the latest alerts of the local node.
+func (api *PublicValidatorMonitorAPI) ValidatorAlerts() []*ValidatorAlert {
+	return api.monitor.Alerts()
+}
@@ -0,0 +1,134 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/x/slashing"
+)
+
+func TestValidatorMonitor(t *testing.T) {
+	self, other := discover.NodeID{1}, discover.NodeID{2}
+	status := &slashing.RoundProduceStatus{
+		Round:                      2,
+		Start:                      21,
+		End:                        40,
+		ZeroProduceNumberThreshold: 2,
+		ZeroProduceCumulativeTime:  4,
+		Nodes: []*slashing.ProduceStatus{
+			{NodeId: other, Validator: true, Index: 0, Expected: 10, Produced: 10, TurnEnd: 30, ZeroRounds: []uint64{}},
+			{NodeId: self, Validator: true, Index: 1, Expected: 10, TurnEnd: 40, ZeroRounds: []uint64{}},
+		},
+	}
+	m := &validatorMonitor{
+		nodeID: self,
+		produceStatus: func(header *types.Header) (*slashing.RoundProduceStatus, error) {
+			cpy := *status
+			cpy.BlockNumber = header.Number.Uint64()
+			return &cpy, nil
+		},
+	}
+
+	// No zero-production round.
+	m.process(testHead(25))
+	assert.Len(t, m.Alerts(), 0)
+	assert.Equal(t, ValidatorAlertNone, m.Health().Level)
+	assert.Equal(t, self, m.Health().Self.NodeId)
+
+	// The previous round produced no block, warned once in the round.
+	selfStatus := status.Nodes[1]
+	selfStatus.ZeroRounds, selfStatus.SlashRound, selfStatus.AtRisk = []uint64{1}, 4, true
+	m.process(testHead(26))
+	m.process(testHead(27))
+	alerts := m.Alerts()
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, ValidatorAlertWarning, alerts[0].Level)
+	assert.Equal(t, uint64(26), alerts[0].BlockNumber)
+
+	// The turn of the validator passed without any block.
+	m.process(testHead(41))
+	m.process(testHead(42))
+	alerts = m.Alerts()
+	assert.Len(t, alerts, 2)
+	assert.Equal(t, ValidatorAlertCritical, alerts[1].Level)
+	assert.Equal(t, ValidatorAlertCritical, m.Health().Level)
+
+	// Warned again in the next round.
+	status.Round = 3
+	selfStatus.TurnEnd = 60
+	m.process(testHead(45))
+	alerts = m.Alerts()
+	assert.Len(t, alerts, 3)
+	assert.Equal(t, ValidatorAlertWarning, alerts[2].Level)
+	assert.Equal(t, uint64(3), alerts[2].Round)
+
+	// The local node is not a validator.
+	status.Nodes = status.Nodes[:1]
+	m.process(testHead(46))
+	assert.Nil(t, m.Health().Self)
+	assert.Equal(t, ValidatorAlertNone, m.Health().Level)
+}
+
+func TestValidatorMonitorWebhook(t *testing.T) {
+	alerts := make(chan *ValidatorAlert, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert ValidatorAlert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		alerts <- &alert
+	}))
+	defer server.Close()
+
+	self := discover.NodeID{1}
+	m := &validatorMonitor{
+		nodeID:  self,
+		webhook: server.URL,
+		produceStatus: func(header *types.Header) (*slashing.RoundProduceStatus, error) {
+			return &slashing.RoundProduceStatus{
+				BlockNumber:                header.Number.Uint64(),
+				Round:                      1,
+				ZeroProduceNumberThreshold: 1,
+				ZeroProduceCumulativeTime:  4,
+				Nodes: []*slashing.ProduceStatus{
+					{NodeId: self, Validator: true, Expected: 10, TurnEnd: 10, ZeroRounds: []uint64{}, SlashRound: 4, AtRisk: true},
+				},
+			}, nil
+		},
+	}
+	m.post = m.postWebhook
+
+	m.process(testHead(11))
+	select {
+	case alert := <-alerts:
+		assert.Equal(t, ValidatorAlertCritical, alert.Level)
+		assert.Equal(t, uint64(11), alert.BlockNumber)
+		assert.Equal(t, self, alert.Status.NodeId)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the alert is not posted")
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package ppos provides a typed client for the PPOS system contracts: staking,
+// governance, slashing, restricting and delegation rewards.
+//
+// Transactions are validated by estimating their gas before they are signed and
+// sent, so that most rejections are reported right away. Failures reported by
+// the system contracts, either through a call, a gas estimation or a receipt,
+// are returned as the *common.BizError declared by the x/staking, x/gov,
+// x/slashing, x/restricting and x/reward packages, so they may be compared
+// against e.g. staking.ErrCanNoExist.
+package ppos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	platon "github.com/AlayaNetwork/Alaya-Go"
+	"github.com/AlayaNetwork/Alaya-Go/accounts/abi/bind"
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/vm"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+	"github.com/AlayaNetwork/Alaya-Go/rpc"
+)
+
+// ErrTxFailed is returned for a failed transaction whose receipt does not carry
+// the result logged by a system contract.
+var ErrTxFailed = errors.New("ppos transaction failed")
+
+// Backend is the chain access needed by a Client. It is implemented by
+// ethclient.Client and by the simulated backend.
+type Backend interface {
+	bind.ContractBackend
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Client sends transactions to and queries the PPOS system contracts.
+type Client struct {
+	backend Backend
+	signer  types.Signer
+}
+
+// NewClient creates a client on top of backend. Transactions are signed for
+// the chain with the given ID, see ethclient.Client.ChainID.
+func NewClient(backend Backend, chainID *big.Int) *Client {
+	return &Client{
+		backend: backend,
+		signer:  types.NewEIP155Signer(chainID),
+	}
+}
+
+// WaitMined waits for tx to be mined and returns its receipt, together with the
+// error the system contract rejected it with, if any. See TxResult.
+func (c *Client) WaitMined(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	receipt, err := bind.WaitMined(ctx, c.backend, tx)
+	if err != nil {
+		return nil, err
+	}
+	return receipt, TxResult(receipt, nil)
+}
+
+// TxResult decodes the result a system contract logged in the receipt of a PPOS
+// transaction. The value returned by WithdrewDelegation (a *big.Int) and by
+// WithdrawDelegateReward (a []reward.NodeDelegateReward) is stored into res if
+// it is not nil.
+func TxResult(receipt *types.Receipt, res interface{}) error {
+	for _, log := range receipt.Logs {
+		if !isSystemContract(log.Address) {
+			continue
+		}
+		var data [][]byte
+		if err := rlp.DecodeBytes(log.Data, &data); err != nil || len(data) == 0 {
+			return fmt.Errorf("invalid ppos result log: %x", log.Data)
+		}
+		code, err := strconv.ParseUint(string(data[0]), 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid ppos result code %q", data[0])
+		}
+		if code != uint64(common.OkCode) {
+			return bizError(uint32(code), "")
+		}
+		if res != nil && len(data) > 1 {
+			return rlp.DecodeBytes(data[1], res)
+		}
+		return nil
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return ErrTxFailed
+	}
+	return nil
+}
+
+// transact encodes a call of the given system contract function, then signs and
+// sends it as a transaction. The gas limit is estimated unless given in opts,
+// which also rejects most invalid transactions before they are sent.
+func (c *Client) transact(opts *bind.TransactOpts, fnType uint16, params ...interface{}) (*types.Transaction, error) {
+	data, to, err := encodePPOS(fnType, params...)
+	if err != nil {
+		return nil, err
+	}
+	ctx := ensureContext(opts.Context)
+
+	var nonce uint64
+	if opts.Nonce == nil {
+		nonce, err = c.backend.PendingNonceAt(ctx, opts.From)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve account nonce: %v", err)
+		}
+	} else {
+		nonce = opts.Nonce.Uint64()
+	}
+	gasPrice := opts.GasPrice
+	if gasPrice == nil {
+		gasPrice, err = c.backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to suggest gas price: %v", err)
+		}
+	}
+	gasLimit := opts.GasLimit
+	if gasLimit == 0 {
+		msg := platon.CallMsg{From: opts.From, To: &to, GasPrice: gasPrice, Data: data}
+		gasLimit, err = c.backend.EstimateGas(ctx, msg)
+		if err != nil {
+			if bizErr := asBizError(err); bizErr != nil {
+				return nil, bizErr
+			}
+			return nil, fmt.Errorf("failed to estimate gas needed: %v", err)
+		}
+	}
+	if opts.Signer == nil {
+		return nil, errors.New("no signer to authorize the transaction with")
+	}
+	rawTx := types.NewTransaction(nonce, to, new(big.Int), gasLimit, gasPrice, data)
+	signedTx, err := opts.Signer(c.signer, opts.From, rawTx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.backend.SendTransaction(ctx, signedTx); err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
+
+// call executes a read-only call of the given system contract function and
+// unmarshals the returned value into out.
+func (c *Client) call(opts *bind.CallOpts, out interface{}, fnType uint16, params ...interface{}) error {
+	if opts == nil {
+		opts = new(bind.CallOpts)
+	}
+	data, to, err := encodePPOS(fnType, params...)
+	if err != nil {
+		return err
+	}
+	var (
+		ctx    = ensureContext(opts.Context)
+		msg    = platon.CallMsg{From: opts.From, To: &to, Data: data}
+		output []byte
+	)
+	if opts.Pending {
+		pb, ok := c.backend.(bind.PendingContractCaller)
+		if !ok {
+			return bind.ErrNoPendingState
+		}
+		output, err = pb.PendingCallContract(ctx, msg)
+	} else {
+		output, err = c.backend.CallContract(ctx, msg, opts.BlockNumber)
+	}
+	if err != nil {
+		return err
+	}
+	var result struct {
+		Code uint32
+		Ret  json.RawMessage
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return fmt.Errorf("invalid ppos result %q: %v", output, err)
+	}
+	if result.Code != common.OkCode {
+		var msg string
+		json.Unmarshal(result.Ret, &msg)
+		return bizError(result.Code, msg)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(result.Ret, out)
+}
+
+// encodePPOS encodes a call of a system contract function, the destination is
+// derived from the function code. Nil pointers are encoded as empty parameters,
+// which the contracts read as optional values that are not set.
+func encodePPOS(fnType uint16, params ...interface{}) ([]byte, common.Address, error) {
+	to, ok := contractAddress(fnType)
+	if !ok {
+		return nil, common.Address{}, fmt.Errorf("unknown ppos function type %d", fnType)
+	}
+	fn, err := rlp.EncodeToBytes(fnType)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	input := [][]byte{fn}
+	for _, param := range params {
+		if v := reflect.ValueOf(param); v.Kind() == reflect.Ptr && v.IsNil() {
+			input = append(input, []byte{})
+			continue
+		}
+		enc, err := rlp.EncodeToBytes(param)
+		if err != nil {
+			return nil, common.Address{}, err
+		}
+		input = append(input, enc)
+	}
+	data, err := rlp.EncodeToBytes(input)
+	return data, to, err
+}
+
+// contractAddress returns the system contract implementing a function code.
+func contractAddress(fnType uint16) (common.Address, bool) {
+	switch fnType / 1000 {
+	case 1:
+		return vm.StakingContractAddr, true
+	case 2:
+		return vm.GovContractAddr, true
+	case 3:
+		return vm.SlashingContractAddr, true
+	case 4:
+		return vm.RestrictingContractAddr, true
+	case 5:
+		return vm.DelegateRewardPoolAddr, true
+	}
+	return common.Address{}, false
+}
+
+func isSystemContract(addr common.Address) bool {
+	switch addr {
+	case vm.StakingContractAddr, vm.GovContractAddr, vm.SlashingContractAddr, vm.RestrictingContractAddr, vm.DelegateRewardPoolAddr:
+		return true
+	}
+	return false
+}
+
+// bizError returns the business error declared with the given code, or a new
+// one carrying msg if the code is unknown.
+func bizError(code uint32, msg string) *common.BizError {
+	if err := common.LookupBizError(code); err != nil {
+		return err
+	}
+	return &common.BizError{Code: code, Msg: msg}
+}
+
+// asBizError extracts the business error from the failure of a gas estimation,
+// as returned by the simulated backend or, through the error data, by the RPC
+// API. It returns nil if err is not a business error.
+func asBizError(err error) *common.BizError {
+	var bizErr *common.BizError
+	if errors.As(err, &bizErr) {
+		return bizError(bizErr.Code, bizErr.Msg)
+	}
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) || dataErr.ErrorData() == nil {
+		return nil
+	}
+	blob, err := json.Marshal(dataErr.ErrorData())
+	if err != nil {
+		return nil
+	}
+	var data struct {
+		Code    *uint32 `json:"code"`
+		Message string  `json:"message"`
+	}
+	if err := json.Unmarshal(blob, &data); err != nil || data.Code == nil {
+		return nil
+	}
+	return bizError(*data.Code, data.Message)
+}
+
+// ensureContext is a helper method to ensure a context is not nil, even if the
+// user specified it as such.
+func ensureContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.TODO()
+	}
+	return ctx
+}
@@ -0,0 +1,319 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/accounts/abi/bind"
+	"github.com/AlayaNetwork/Alaya-Go/accounts/abi/bind/backends"
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
+	"github.com/AlayaNetwork/Alaya-Go/common/vm"
+	"github.com/AlayaNetwork/Alaya-Go/core"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/crypto/bls"
+	"github.com/AlayaNetwork/Alaya-Go/ethclient"
+	"github.com/AlayaNetwork/Alaya-Go/node"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/params"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+	"github.com/AlayaNetwork/Alaya-Go/rpc"
+	"github.com/AlayaNetwork/Alaya-Go/x/restricting"
+	"github.com/AlayaNetwork/Alaya-Go/x/reward"
+	"github.com/AlayaNetwork/Alaya-Go/x/staking"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
+)
+
+func atp(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.ATP))
+}
+
+func mustMine(t *testing.T, client *Client, sim *backends.SimulatedBackend, tx *types.Transaction, err error) *types.Receipt {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	receipt, err := client.WaitMined(context.Background(), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return receipt
+}
+
+func newStakingArgs(t *testing.T, nodeKey *ecdsa.PrivateKey, benefit common.Address) *CreateStakingArgs {
+	node.GetCryptoHandler().SetPrivateKey(nodeKey)
+	var sign common.VersionSign
+	sign.SetBytes(node.GetCryptoHandler().MustSign(params.FORKVERSION_0_14_0))
+
+	var blsKey bls.SecretKey
+	blsKey.SetByCSPRNG()
+	args := &CreateStakingArgs{
+		Type:               FreeVon,
+		BenefitAddress:     benefit,
+		NodeID:             discover.PubkeyID(&nodeKey.PublicKey),
+		ExternalID:         "external",
+		NodeName:           "node",
+		Website:            "https://www.alaya.network",
+		Details:            "details",
+		Amount:             xcom.StakeThreshold(),
+		RewardPer:          5000,
+		ProgramVersion:     params.FORKVERSION_0_14_0,
+		ProgramVersionSign: sign,
+	}
+	if err := args.BlsPubKey.UnmarshalText([]byte(common.Bytes2Hex(blsKey.GetPublicKey().Serialize()))); err != nil {
+		t.Fatal(err)
+	}
+	proof, err := blsKey.MakeSchnorrNIZKP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proofText, _ := proof.MarshalText()
+	if err := args.BlsProof.UnmarshalText(proofText); err != nil {
+		t.Fatal(err)
+	}
+	return args
+}
+
+func TestClient(t *testing.T) {
+	genesisKey, _ := crypto.GenerateKey()
+	stakerKey, _ := crypto.GenerateKey()
+	delegatorKey, _ := crypto.GenerateKey()
+	staker, delegator := bind.NewKeyedTransactor(stakerKey), bind.NewKeyedTransactor(delegatorKey)
+	alloc := core.GenesisAlloc{
+		staker.From:    {Balance: atp(100000)},
+		delegator.From: {Balance: atp(1000)},
+	}
+	sim, err := backends.NewPPOSSimulatedBackend(alloc, 100000000, &backends.PPOSConfig{
+		NodeKeys:       []*ecdsa.PrivateKey{genesisKey},
+		GenesisVersion: params.FORKVERSION_0_14_0,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close()
+	client := NewClient(sim, params.AllEthashProtocolChanges.ChainID)
+
+	// Stake a new node and check the candidate.
+	nodeKey, _ := crypto.GenerateKey()
+	nodeID := discover.PubkeyID(&nodeKey.PublicKey)
+	tx, err := client.CreateStaking(staker, newStakingArgs(t, nodeKey, staker.From))
+	receipt := mustMine(t, client, sim, tx, err)
+	can, err := client.GetCandidateInfo(nil, nodeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if can.StakingAddress != staker.From || can.NodeName != "node" || can.StakingBlockNum != receipt.BlockNumber.Uint64() {
+		t.Fatalf("candidate mismatch: %v", can)
+	}
+	if _, err := client.GetCandidateInfo(nil, discover.NodeID{0x01}); err != staking.ErrQueryCandidateInfo {
+		t.Fatalf("unknown candidate error mismatch: have %v, want %v", err, staking.ErrQueryCandidateInfo)
+	}
+
+	// Fields left nil are not modified.
+	name := "renamed"
+	tx, err = client.EditCandidate(staker, &EditCandidateArgs{NodeID: nodeID, NodeName: &name})
+	mustMine(t, client, sim, tx, err)
+	if can, _ = client.GetCandidateInfo(nil, nodeID); can.NodeName != name || can.Website != "https://www.alaya.network" || can.BenefitAddress != staker.From {
+		t.Fatalf("edited candidate mismatch: %v", can)
+	}
+
+	// Delegate to the new node.
+	tx, err = client.Delegate(delegator, FreeVon, nodeID, atp(10))
+	mustMine(t, client, sim, tx, err)
+	related, err := client.GetRelatedListByDelAddr(nil, delegator.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(related) != 1 || related[0].NodeId != nodeID || related[0].StakingBlockNum != can.StakingBlockNum {
+		t.Fatalf("related list mismatch: %v", related)
+	}
+	del, err := client.GetDelegateInfo(nil, can.StakingBlockNum, delegator.From, nodeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if del.ReleasedHes.ToInt().Cmp(atp(10)) != 0 {
+		t.Fatalf("delegation mismatch: %v", del)
+	}
+	tx, err = client.WithdrewDelegation(delegator, can.StakingBlockNum, nodeID, atp(4))
+	receipt = mustMine(t, client, sim, tx, err)
+	var income *big.Int
+	if err := TxResult(receipt, &income); err != nil || income == nil {
+		t.Fatalf("withdrawal result mismatch: %v (%v)", income, err)
+	}
+	if del, _ = client.GetDelegateInfo(nil, can.StakingBlockNum, delegator.From, nodeID); del.ReleasedHes.ToInt().Cmp(atp(6)) != 0 {
+		t.Fatalf("delegation mismatch after withdrawal: %v", del)
+	}
+
+	// Rejected transactions are reported with the error of the contract.
+	unknownKey, _ := crypto.GenerateKey()
+	for _, test := range []struct {
+		opts   *bind.TransactOpts
+		nodeID discover.NodeID
+		amount *big.Int
+		want   error
+	}{
+		{delegator, discover.PubkeyID(&unknownKey.PublicKey), atp(10), staking.ErrCanNoExist},
+		{delegator, nodeID, big.NewInt(1), staking.ErrDelegateVonTooLow},
+		{staker, nodeID, atp(10), staking.ErrAccountNoAllowToDelegate},
+		{delegator, discover.PubkeyID(&genesisKey.PublicKey), atp(10), staking.ErrCanNoAllowDelegate},
+	} {
+		if _, err := client.Delegate(test.opts, FreeVon, test.nodeID, test.amount); err != test.want {
+			t.Errorf("delegation error mismatch: have %v, want %v", err, test.want)
+		}
+	}
+	if _, err := client.GetDelegateReward(nil, staker.From, nil); err != reward.ErrDelegationNotFound {
+		t.Errorf("delegate reward error mismatch: have %v, want %v", err, reward.ErrDelegationNotFound)
+	}
+
+	// Lock funds for a new account.
+	account := common.Address{0x01}
+	plans := []restricting.RestrictingPlan{{Epoch: 1, Amount: atp(10)}, {Epoch: 2, Amount: atp(20)}}
+	tx, err = client.CreateRestrictingPlan(staker, account, plans)
+	mustMine(t, client, sim, tx, err)
+	info, err := client.GetRestrictingInfo(nil, account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Balance.ToInt().Cmp(atp(30)) != 0 || len(info.Entry) != 2 {
+		t.Fatalf("restricting info mismatch: %+v", info)
+	}
+
+	// Query the chain state.
+	verifiers, err := client.GetVerifierList(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verifiers) != 1 || verifiers[0].NodeId != discover.PubkeyID(&genesisKey.PublicKey) {
+		t.Fatalf("verifier list mismatch: %v", verifiers)
+	}
+	if candidates, err := client.GetCandidateList(nil); err != nil || len(candidates) != 2 {
+		t.Fatalf("candidate list mismatch: %v (%v)", candidates, err)
+	}
+	if version, err := client.GetActiveVersion(nil); err != nil || version != params.FORKVERSION_0_14_0 {
+		t.Fatalf("active version mismatch: have %d, want %d (%v)", version, params.FORKVERSION_0_14_0, err)
+	}
+	if value, err := client.GetGovernParamValue(nil, "staking", "stakeThreshold"); err != nil || value != xcom.StakeThreshold().String() {
+		t.Fatalf("stake threshold mismatch: have %s, want %v (%v)", value, xcom.StakeThreshold(), err)
+	}
+	if proposals, err := client.ListProposal(nil); err != nil || len(proposals) != 0 {
+		t.Fatalf("proposal list mismatch: %v (%v)", proposals, err)
+	}
+	if _, err := client.GetProposal(nil, common.Hash{0x01}); err == nil {
+		t.Fatal("expected error for unknown proposal")
+	}
+}
+
+func TestTxResult(t *testing.T) {
+	result := func(code string, res interface{}) *types.Log {
+		data := [][]byte{[]byte(code)}
+		if res != nil {
+			enc, _ := rlp.EncodeToBytes(res)
+			data = append(data, enc)
+		}
+		blob, _ := rlp.EncodeToBytes(data)
+		return &types.Log{Address: vm.StakingContractAddr, Data: blob}
+	}
+	other := &types.Log{Address: common.Address{0x01}, Data: []byte{0x01}}
+
+	receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{other, result("0", big.NewInt(7))}}
+	var income *big.Int
+	if err := TxResult(receipt, &income); err != nil || income.Int64() != 7 {
+		t.Fatalf("result mismatch: %v (%v)", income, err)
+	}
+	receipt = &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{result("301102", nil)}}
+	if err := TxResult(receipt, nil); err != staking.ErrCanNoExist {
+		t.Fatalf("error mismatch: have %v, want %v", err, staking.ErrCanNoExist)
+	}
+	receipt = &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{result("999999", nil)}}
+	if err, ok := TxResult(receipt, nil).(*common.BizError); !ok || err.Code != 999999 {
+		t.Fatalf("unknown error mismatch: %v", err)
+	}
+	receipt = &types.Receipt{Status: types.ReceiptStatusFailed}
+	if err := TxResult(receipt, nil); err != ErrTxFailed {
+		t.Fatalf("failure mismatch: have %v, want %v", err, ErrTxFailed)
+	}
+}
+
+type dataError struct {
+	data interface{}
+}
+
+func (e *dataError) Error() string          { return "inner contract exec failed" }
+func (e *dataError) ErrorData() interface{} { return e.data }
+
+func TestAsBizError(t *testing.T) {
+	rpcData := map[string]interface{}{"code": float64(301102), "message": "The candidate does not exist"}
+	for _, test := range []struct {
+		err  error
+		want *common.BizError
+	}{
+		{staking.ErrCanNoExist.Wrap("detail"), staking.ErrCanNoExist},
+		{&dataError{rpcData}, staking.ErrCanNoExist},
+		{&dataError{"0x"}, nil},
+		{&dataError{nil}, nil},
+		{errors.New("gas required exceeds allowance"), nil},
+	} {
+		if have := asBizError(test.err); have != test.want {
+			t.Errorf("business error of %v mismatch: have %v, want %v", test.err, have, test.want)
+		}
+	}
+}
+
+// economicError mirrors the error returned by platon_estimateGas when a system
+// contract rejects the call, see internal/ethapi.
+type economicError struct {
+	*common.BizError
+}
+
+func (e *economicError) Error() string  { return "inner contract exec failed" }
+func (e *economicError) ErrorCode() int { return 4 }
+func (e *economicError) ErrorData() interface{} {
+	return map[string]interface{}{"code": e.BizError.Code, "message": e.BizError.Msg}
+}
+
+// EstimateService serves a platon_estimateGas which always fails with err.
+type EstimateService struct {
+	err *common.BizError
+}
+
+func (s *EstimateService) EstimateGas(ctx context.Context, args map[string]interface{}) (hexutil.Uint64, error) {
+	return 0, &economicError{s.err}
+}
+
+func TestRPCBizError(t *testing.T) {
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("platon", &EstimateService{staking.ErrCanNoExist}); err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(ethclient.NewClient(rpc.DialInProc(server)), params.AllEthashProtocolChanges.ChainID)
+
+	key, _ := crypto.GenerateKey()
+	opts := bind.NewKeyedTransactor(key)
+	opts.Nonce, opts.GasPrice = new(big.Int), big.NewInt(1)
+	_, err := client.WithdrewStaking(opts, discover.PubkeyID(&key.PublicKey))
+	if err != staking.ErrCanNoExist {
+		t.Fatalf("error mismatch: have %v (%T), want %v", err, err, staking.ErrCanNoExist)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/AlayaNetwork/Alaya-Go/accounts/abi/bind"
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/x/gov"
+)
+
+// Function codes of the governance contract, see core/vm/gov_contract.go.
+const (
+	submitText            = uint16(2000)
+	submitVersion         = uint16(2001)
+	submitParam           = uint16(2002)
+	vote                  = uint16(2003)
+	declare               = uint16(2004)
+	submitCancel          = uint16(2005)
+	getProposal           = uint16(2100)
+	getResult             = uint16(2101)
+	listProposal          = uint16(2102)
+	getActiveVersion      = uint16(2103)
+	getGovernParamValue   = uint16(2104)
+	getAccuVerifiersCount = uint16(2105)
+	listGovernParam       = uint16(2106)
+)
+
+// SubmitText submits a text proposal on behalf of a verifier.
+func (c *Client) SubmitText(opts *bind.TransactOpts, verifier discover.NodeID, pipID string) (*types.Transaction, error) {
+	return c.transact(opts, submitText, verifier, pipID)
+}
+
+// SubmitVersion submits a proposal to upgrade the chain to newVersion, voted
+// during endVotingRounds consensus rounds.
+func (c *Client) SubmitVersion(opts *bind.TransactOpts, verifier discover.NodeID, pipID string, newVersion uint32, endVotingRounds uint64) (*types.Transaction, error) {
+	return c.transact(opts, submitVersion, verifier, pipID, newVersion, endVotingRounds)
+}
+
+// SubmitParam submits a proposal to change the value of a governable parameter.
+func (c *Client) SubmitParam(opts *bind.TransactOpts, verifier discover.NodeID, pipID, module, name, newValue string) (*types.Transaction, error) {
+	return c.transact(opts, submitParam, verifier, pipID, module, name, newValue)
+}
+
+// SubmitCancel submits a proposal to cancel the voting version or parameter
+// proposal tobeCanceled.
+func (c *Client) SubmitCancel(opts *bind.TransactOpts, verifier discover.NodeID, pipID string, endVotingRounds uint64, tobeCanceled common.Hash) (*types.Transaction, error) {
+	return c.transact(opts, submitCancel, verifier, pipID, endVotingRounds, tobeCanceled)
+}
+
+// SubmitProposal submits a proposal of any type. The proposal ID, submit block,
+// end voting block and active block are assigned by the chain and ignored.
+func (c *Client) SubmitProposal(opts *bind.TransactOpts, proposal gov.Proposal) (*types.Transaction, error) {
+	switch p := proposal.(type) {
+	case *gov.TextProposal:
+		return c.SubmitText(opts, p.Proposer, p.PIPID)
+	case *gov.VersionProposal:
+		return c.SubmitVersion(opts, p.Proposer, p.PIPID, p.NewVersion, p.EndVotingRounds)
+	case *gov.ParamProposal:
+		return c.SubmitParam(opts, p.Proposer, p.PIPID, p.Module, p.Name, p.NewValue)
+	case *gov.CancelProposal:
+		return c.SubmitCancel(opts, p.Proposer, p.PIPID, p.EndVotingRounds, p.TobeCanceled)
+	}
+	return nil, fmt.Errorf("unsupported proposal type %T", proposal)
+}
+
+// Vote votes for a proposal on behalf of a verifier, the program version and
+// its signature are those of the verifier node.
+func (c *Client) Vote(opts *bind.TransactOpts, verifier discover.NodeID, proposalID common.Hash, option gov.VoteOption, programVersion uint32, sign common.VersionSign) (*types.Transaction, error) {
+	return c.transact(opts, vote, verifier, proposalID, uint8(option), programVersion, sign)
+}
+
+// DeclareVersion declares the program version a node runs.
+func (c *Client) DeclareVersion(opts *bind.TransactOpts, activeNode discover.NodeID, programVersion uint32, sign common.VersionSign) (*types.Transaction, error) {
+	return c.transact(opts, declare, activeNode, programVersion, sign)
+}
+
+// GetProposal returns a proposal, its concrete type depends on the proposal
+// type.
+func (c *Client) GetProposal(opts *bind.CallOpts, proposalID common.Hash) (gov.Proposal, error) {
+	var raw json.RawMessage
+	if err := c.call(opts, &raw, getProposal, proposalID); err != nil {
+		return nil, err
+	}
+	return decodeProposal(raw)
+}
+
+// GetTallyResult returns the tally result of a proposal whose voting ended.
+func (c *Client) GetTallyResult(opts *bind.CallOpts, proposalID common.Hash) (*gov.TallyResult, error) {
+	result := new(gov.TallyResult)
+	if err := c.call(opts, result, getResult, proposalID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListProposal returns all the proposals, the list is empty if none was
+// submitted yet.
+func (c *Client) ListProposal(opts *bind.CallOpts) ([]gov.Proposal, error) {
+	var raws []json.RawMessage
+	if err := c.call(opts, &raws, listProposal); err != nil && err != common.NotFound {
+		return nil, err
+	}
+	proposals := make([]gov.Proposal, len(raws))
+	for i, raw := range raws {
+		p, err := decodeProposal(raw)
+		if err != nil {
+			return nil, err
+		}
+		proposals[i] = p
+	}
+	return proposals, nil
+}
+
+// GetActiveVersion returns the version the chain currently runs.
+func (c *Client) GetActiveVersion(opts *bind.CallOpts) (uint32, error) {
+	var version uint32
+	if err := c.call(opts, &version, getActiveVersion); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// GetGovernParamValue returns the current value of a governable parameter.
+func (c *Client) GetGovernParamValue(opts *bind.CallOpts, module, name string) (string, error) {
+	var value string
+	if err := c.call(opts, &value, getGovernParamValue, module, name); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// GetAccuVerifiersCount returns the number of verifiers allowed to vote for a
+// proposal and the votes cast for each option, as of the given block.
+func (c *Client) GetAccuVerifiersCount(opts *bind.CallOpts, proposalID, blockHash common.Hash) (accuVerifiers, yeas, nays, abstentions uint64, err error) {
+	var counts []uint64
+	if err := c.call(opts, &counts, getAccuVerifiersCount, proposalID, blockHash); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	if len(counts) != 4 {
+		return 0, 0, 0, 0, fmt.Errorf("invalid vote counts %v", counts)
+	}
+	return counts[0], counts[1], counts[2], counts[3], nil
+}
+
+// ListGovernParam returns the governable parameters of a module, or of all the
+// modules if module is empty.
+func (c *Client) ListGovernParam(opts *bind.CallOpts, module string) ([]*gov.GovernParam, error) {
+	var params []*gov.GovernParam
+	if err := c.call(opts, &params, listGovernParam, module); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// decodeProposal unmarshals a proposal into the type matching its proposal type.
+func decodeProposal(raw json.RawMessage) (gov.Proposal, error) {
+	var header struct {
+		ProposalType gov.ProposalType
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, err
+	}
+	var proposal gov.Proposal
+	switch header.ProposalType {
+	case gov.Text:
+		proposal = new(gov.TextProposal)
+	case gov.Version:
+		proposal = new(gov.VersionProposal)
+	case gov.Param:
+		proposal = new(gov.ParamProposal)
+	case gov.Cancel:
+		proposal = new(gov.CancelProposal)
+	default:
+		return nil, fmt.Errorf("unknown proposal type %d", header.ProposalType)
+	}
+	if err := json.Unmarshal(raw, proposal); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"github.com/AlayaNetwork/Alaya-Go/accounts/abi/bind"
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/x/restricting"
+)
+
+// Function codes of the restricting contract, see core/vm/restricting_contract.go.
+const (
+	txCreateRestrictingPlan = uint16(4000)
+	queryRestrictingInfo    = uint16(4100)
+)
+
+// CreateRestrictingPlan locks funds of the sender for account, to be released
+// according to plans.
+func (c *Client) CreateRestrictingPlan(opts *bind.TransactOpts, account common.Address, plans []restricting.RestrictingPlan) (*types.Transaction, error) {
+	return c.transact(opts, txCreateRestrictingPlan, account, plans)
+}
+
+// GetRestrictingInfo returns the restricting plans of an account.
+func (c *Client) GetRestrictingInfo(opts *bind.CallOpts, account common.Address) (*restricting.Result, error) {
+	result := new(restricting.Result)
+	if err := c.call(opts, result, queryRestrictingInfo, account); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
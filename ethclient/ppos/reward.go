@@ -0,0 +1,49 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"github.com/AlayaNetwork/Alaya-Go/accounts/abi/bind"
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/x/reward"
+)
+
+// Function codes of the delegation reward contract, see
+// core/vm/delegate_reward_contract.go.
+const (
+	txWithdrawDelegateReward = uint16(5000)
+	queryDelegateReward      = uint16(5100)
+)
+
+// WithdrawDelegateReward withdraws the rewards of all the delegations of the
+// sender. The rewards paid out are reported in the receipt as a
+// []reward.NodeDelegateReward, see TxResult.
+func (c *Client) WithdrawDelegateReward(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return c.transact(opts, txWithdrawDelegateReward)
+}
+
+// GetDelegateReward returns the pending rewards of the delegations of account
+// to the given nodes, or to all the nodes it delegated to if nodeIDs is empty.
+func (c *Client) GetDelegateReward(opts *bind.CallOpts, account common.Address, nodeIDs []discover.NodeID) ([]reward.NodeDelegateRewardPresenter, error) {
+	var rewards []reward.NodeDelegateRewardPresenter
+	if err := c.call(opts, &rewards, queryDelegateReward, account, nodeIDs); err != nil {
+		return nil, err
+	}
+	return rewards, nil
+}
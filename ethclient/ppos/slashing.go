@@ -0,0 +1,58 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"github.com/AlayaNetwork/Alaya-Go/accounts/abi/bind"
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/consensus"
+	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+
+	// Register the slashing errors, see bizError.
+	_ "github.com/AlayaNetwork/Alaya-Go/x/slashing"
+)
+
+// Function codes of the slashing contract, see core/vm/slashing_contract.go.
+const (
+	txReportDuplicateSign = uint16(3000)
+	checkDuplicateSign    = uint16(3001)
+)
+
+// ReportDuplicateSign reports the evidence of a node signing twice, data is the
+// JSON encoded evidence.
+func (c *Client) ReportDuplicateSign(opts *bind.TransactOpts, dupType consensus.EvidenceType, data string) (*types.Transaction, error) {
+	return c.transact(opts, txReportDuplicateSign, uint8(dupType), data)
+}
+
+// CheckDuplicateSign returns the hash of the transaction that reported a node
+// signing twice at blockNumber, or the zero hash if it was not reported.
+func (c *Client) CheckDuplicateSign(opts *bind.CallOpts, dupType consensus.EvidenceType, nodeID discover.NodeID, blockNumber uint64) (common.Hash, error) {
+	var txHash string
+	if err := c.call(opts, &txHash, checkDuplicateSign, uint8(dupType), nodeID, blockNumber); err != nil {
+		return common.Hash{}, err
+	}
+	if txHash == "" {
+		return common.Hash{}, nil
+	}
+	hash, err := hexutil.Decode(txHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(hash), nil
+}
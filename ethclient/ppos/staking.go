@@ -0,0 +1,204 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"math/big"
+
+	"github.com/AlayaNetwork/Alaya-Go/accounts/abi/bind"
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/crypto/bls"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/x/staking"
+)
+
+// Function codes of the staking contract, see core/vm/staking_contract.go.
+const (
+	txCreateStaking      = uint16(1000)
+	txEditorCandidate    = uint16(1001)
+	txIncreaseStaking    = uint16(1002)
+	txWithdrewCandidate  = uint16(1003)
+	txDelegate           = uint16(1004)
+	txWithdrewDelegation = uint16(1005)
+
+	queryVerifierList  = uint16(1100)
+	queryValidatorList = uint16(1101)
+	queryCandidateList = uint16(1102)
+	queryRelateList    = uint16(1103)
+	queryDelegateInfo  = uint16(1104)
+	queryCandidateInfo = uint16(1105)
+	getPackageReward   = uint16(1200)
+	getStakingReward   = uint16(1201)
+	getAvgPackTime     = uint16(1202)
+)
+
+// The sources of the von used for staking and delegation.
+const (
+	FreeVon            = uint16(0) // the free balance of the account
+	RestrictVon        = uint16(1) // the locked balance of the restricting plans
+	RestrictAndFreeVon = uint16(2) // restricting plans first, then the free balance (staking only)
+)
+
+// CreateStakingArgs are the arguments of CreateStaking.
+type CreateStakingArgs struct {
+	Type               uint16 // source of the staked von, FreeVon, RestrictVon or RestrictAndFreeVon
+	BenefitAddress     common.Address
+	NodeID             discover.NodeID
+	ExternalID         string
+	NodeName           string
+	Website            string
+	Details            string
+	Amount             *big.Int
+	RewardPer          uint16 // share of the rewards given to the delegators, in basis points
+	ProgramVersion     uint32
+	ProgramVersionSign common.VersionSign
+	BlsPubKey          bls.PublicKeyHex
+	BlsProof           bls.SchnorrProofHex
+}
+
+// EditCandidateArgs are the arguments of EditCandidate, nil fields are left
+// unchanged.
+type EditCandidateArgs struct {
+	BenefitAddress *common.Address
+	NodeID         discover.NodeID
+	RewardPer      *uint16
+	ExternalID     *string
+	NodeName       *string
+	Website        *string
+	Details        *string
+}
+
+// CreateStaking stakes a node to make it a validator candidate.
+func (c *Client) CreateStaking(opts *bind.TransactOpts, args *CreateStakingArgs) (*types.Transaction, error) {
+	return c.transact(opts, txCreateStaking, args.Type, args.BenefitAddress, args.NodeID,
+		args.ExternalID, args.NodeName, args.Website, args.Details, args.Amount, args.RewardPer,
+		args.ProgramVersion, args.ProgramVersionSign, args.BlsPubKey, args.BlsProof)
+}
+
+// EditCandidate modifies the description, the benefit address or the reward
+// ratio of a candidate.
+func (c *Client) EditCandidate(opts *bind.TransactOpts, args *EditCandidateArgs) (*types.Transaction, error) {
+	return c.transact(opts, txEditorCandidate, args.BenefitAddress, args.NodeID, args.RewardPer,
+		args.ExternalID, args.NodeName, args.Website, args.Details)
+}
+
+// IncreaseStaking adds amount to the stake of a candidate, typ is FreeVon or
+// RestrictVon.
+func (c *Client) IncreaseStaking(opts *bind.TransactOpts, nodeID discover.NodeID, typ uint16, amount *big.Int) (*types.Transaction, error) {
+	return c.transact(opts, txIncreaseStaking, nodeID, typ, amount)
+}
+
+// WithdrewStaking withdraws the stake of a candidate.
+func (c *Client) WithdrewStaking(opts *bind.TransactOpts, nodeID discover.NodeID) (*types.Transaction, error) {
+	return c.transact(opts, txWithdrewCandidate, nodeID)
+}
+
+// Delegate delegates amount to a candidate, typ is FreeVon or RestrictVon.
+func (c *Client) Delegate(opts *bind.TransactOpts, typ uint16, nodeID discover.NodeID, amount *big.Int) (*types.Transaction, error) {
+	return c.transact(opts, txDelegate, typ, nodeID, amount)
+}
+
+// WithdrewDelegation withdraws amount from the delegation made to the candidate
+// staked at stakingBlockNum. The delegation reward paid out is reported in the
+// receipt as a *big.Int, see TxResult.
+func (c *Client) WithdrewDelegation(opts *bind.TransactOpts, stakingBlockNum uint64, nodeID discover.NodeID, amount *big.Int) (*types.Transaction, error) {
+	return c.transact(opts, txWithdrewDelegation, stakingBlockNum, nodeID, amount)
+}
+
+// GetVerifierList returns the verifiers of the current epoch.
+func (c *Client) GetVerifierList(opts *bind.CallOpts) (staking.ValidatorExQueue, error) {
+	var list staking.ValidatorExQueue
+	if err := c.call(opts, &list, queryVerifierList); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetValidatorList returns the validators of the current consensus round.
+func (c *Client) GetValidatorList(opts *bind.CallOpts) (staking.ValidatorExQueue, error) {
+	var list staking.ValidatorExQueue
+	if err := c.call(opts, &list, queryValidatorList); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetCandidateList returns all the candidates.
+func (c *Client) GetCandidateList(opts *bind.CallOpts) (staking.CandidateHexQueue, error) {
+	var list staking.CandidateHexQueue
+	if err := c.call(opts, &list, queryCandidateList); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetRelatedListByDelAddr returns the candidates an account delegated to.
+func (c *Client) GetRelatedListByDelAddr(opts *bind.CallOpts, addr common.Address) (staking.DelRelatedQueue, error) {
+	var list staking.DelRelatedQueue
+	if err := c.call(opts, &list, queryRelateList, addr); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetDelegateInfo returns the delegation of an account to the candidate staked
+// at stakingBlockNum.
+func (c *Client) GetDelegateInfo(opts *bind.CallOpts, stakingBlockNum uint64, delAddr common.Address, nodeID discover.NodeID) (*staking.DelegationEx, error) {
+	del := new(staking.DelegationEx)
+	if err := c.call(opts, del, queryDelegateInfo, stakingBlockNum, delAddr, nodeID); err != nil {
+		return nil, err
+	}
+	return del, nil
+}
+
+// GetCandidateInfo returns the candidate staking a node.
+func (c *Client) GetCandidateInfo(opts *bind.CallOpts, nodeID discover.NodeID) (*staking.CandidateHex, error) {
+	can := new(staking.CandidateHex)
+	if err := c.call(opts, can, queryCandidateInfo, nodeID); err != nil {
+		return nil, err
+	}
+	return can, nil
+}
+
+// GetPackageReward returns the block reward of the current year.
+func (c *Client) GetPackageReward(opts *bind.CallOpts) (*big.Int, error) {
+	var reward hexutil.Big
+	if err := c.call(opts, &reward, getPackageReward); err != nil {
+		return nil, err
+	}
+	return reward.ToInt(), nil
+}
+
+// GetStakingReward returns the staking reward of the current epoch.
+func (c *Client) GetStakingReward(opts *bind.CallOpts) (*big.Int, error) {
+	var reward hexutil.Big
+	if err := c.call(opts, &reward, getStakingReward); err != nil {
+		return nil, err
+	}
+	return reward.ToInt(), nil
+}
+
+// GetAvgPackTime returns the average time to produce a block, in milliseconds.
+func (c *Client) GetAvgPackTime(opts *bind.CallOpts) (uint64, error) {
+	var avg uint64
+	if err := c.call(opts, &avg, getAvgPackTime); err != nil {
+		return 0, err
+	}
+	return avg, nil
+}
@@ -410,6 +410,24 @@ web3._extend({
 			name: 'getWaitSlashingNodeList',
 			call: 'debug_getWaitSlashingNodeList',
 		}),
+		new web3._extend.Method({
+			name: 'getCandidateInfo',
+			call: 'debug_getCandidateInfo',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getDelegateInfo',
+			call: 'debug_getDelegateInfo',
+			params: 4,
+			inputFormatter: [null, null, null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getVerifierList',
+			call: 'debug_getVerifierList',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'enableDBGC',
 			call: 'debug_enableDBGC',
@@ -480,8 +498,19 @@ web3._extend({
 			call: 'platon_getPrepareQC',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'feeHistory',
+			call: 'platon_feeHistory',
+			params: 3,
+			inputFormatter: [web3._extend.utils.toHex, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
 	],
 	properties: [
+		new web3._extend.Property({
+			name: 'maxPriorityFeePerGas',
+			getter: 'platon_maxPriorityFeePerGas',
+			outputFormatter: web3._extend.utils.toBigNumber
+		}),
 		new web3._extend.Property({
 			name: 'pendingTransactions',
 			getter: 'platon_pendingTransactions',
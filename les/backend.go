@@ -18,6 +18,7 @@
 package les
 
 import (
+	"errors"
 	"sync"
 	"time"
 
@@ -146,6 +147,14 @@ func New(ctx *node.ServiceContext, config *eth.Config) (*LightEthereum, error) {
 	if leth.protocolManager, err = NewProtocolManager(leth.chainConfig, light.DefaultClientIndexerConfig, true, config.NetworkId, leth.eventMux, leth.engine, leth.peers, leth.blockchain, nil, chainDb, leth.odr, leth.relay, leth.serverPool, quitSync, &leth.wg); err != nil {
 		return nil, err
 	}
+	if config.LightVerifyQC {
+		if chainConfig.Cbft == nil {
+			return nil, errors.New("QuorumCert verification requires the cbft consensus")
+		}
+		if leth.protocolManager.cbftVerifier, err = light.NewCbftVerifier(leth.odr, chainConfig.Cbft.InitialNodes); err != nil {
+			return nil, err
+		}
+	}
 	leth.ApiBackend = &LesApiBackend{leth, nil}
 	gpoParams := config.GPO
 	if gpoParams.Default == nil {
@@ -162,6 +171,8 @@ func lesTopic(genesisHash common.Hash, protocolVersion uint) discv5.Topic {
 		name = "LES"
 	case lpv2:
 		name = "LES2"
+	case lpv3:
+		name = "LES3"
 	default:
 		panic(nil)
 	}
@@ -0,0 +1,206 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package les
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	ctypes "github.com/AlayaNetwork/Alaya-Go/consensus/cbft/types"
+	"github.com/AlayaNetwork/Alaya-Go/core"
+	"github.com/AlayaNetwork/Alaya-Go/core/cbfttypes"
+	"github.com/AlayaNetwork/Alaya-Go/core/rawdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/light"
+	"github.com/AlayaNetwork/Alaya-Go/log"
+)
+
+// cbftVerifyTimeout is the time allowed for verifying the headers up to a new head.
+const cbftVerifyTimeout = time.Minute
+
+// validatorReader retrieves the consensus validators of the round which the
+// given block belongs to, it is implemented by the consensus.Agency.
+type validatorReader interface {
+	GetValidator(blockNumber uint64) (*cbfttypes.Validators, error)
+}
+
+// historyValidatorReader retrieves the consensus validators of the round which a
+// past block belongs to from the staking state of the block, it is implemented
+// by the PPOS consensus.Agency. The staking state of the past blocks is only
+// kept by the archived snapshotdb, so the les servers must run in archive mode.
+type historyValidatorReader interface {
+	GetHistoryValidator(blockHash common.Hash, blockNumber uint64) (*cbfttypes.Validators, error)
+}
+
+// readQuorumCert reads the QuorumCert of a block, which is stored in the extra
+// data of the block body.
+func (pm *ProtocolManager) readQuorumCert(hash common.Hash, number uint64) *ctypes.QuorumCert {
+	body := rawdb.ReadBody(pm.chainDb, hash, number)
+	if body == nil {
+		return nil
+	}
+	_, qc, err := ctypes.DecodeExtra(body.ExtraData)
+	if err != nil {
+		return nil
+	}
+	return qc
+}
+
+// getCbftHeaders collects the canonical headers from origin with their
+// QuorumCerts, the first header of every consensus round carries the validator
+// set of the round. The collection stops at the first header which can not be
+// proven to the light client.
+func (pm *ProtocolManager) getCbftHeaders(origin, amount uint64) []*light.CbftHeader {
+	if origin == 0 {
+		// The genesis block is not finalized by a QuorumCert
+		return nil
+	}
+	epoch := uint64(light.CbftGenesisEpoch)
+	if origin > 1 {
+		qc := pm.readQuorumCert(rawdb.ReadCanonicalHash(pm.chainDb, origin-1), origin-1)
+		if qc == nil {
+			return nil
+		}
+		epoch = qc.Epoch
+	}
+	var (
+		bytes   common.StorageSize
+		headers []*light.CbftHeader
+	)
+	for number := origin; number < origin+amount && bytes < softResponseLimit; number++ {
+		header := pm.blockchain.GetHeaderByNumber(number)
+		if header == nil {
+			break
+		}
+		qc := pm.readQuorumCert(header.Hash(), number)
+		if qc == nil {
+			break
+		}
+		item := &light.CbftHeader{Header: header, QC: qc}
+		if qc.Epoch != epoch {
+			if item.Validators = pm.cbftValidators(qc.Epoch, header); item.Validators == nil {
+				log.Debug("Missing cbft validators", "epoch", qc.Epoch, "number", number)
+				break
+			}
+			bytes += common.StorageSize(len(item.Validators) * 160)
+		}
+		epoch = qc.Epoch
+		headers = append(headers, item)
+		bytes += estHeaderRlpSize
+	}
+	return headers
+}
+
+// cbftValidators returns the validator set of the consensus round started by the
+// given header. The sets of the rounds which were not seen live are rebuilt
+// from the staking state of the header and stored.
+func (pm *ProtocolManager) cbftValidators(epoch uint64, header *types.Header) []*light.CbftValidator {
+	if list := light.ReadCbftValidators(pm.chainDb, epoch); list != nil {
+		return list
+	}
+	history, ok := pm.validators.(historyValidatorReader)
+	if !ok {
+		return nil
+	}
+	validators, err := history.GetHistoryValidator(header.Hash(), header.Number.Uint64())
+	if err != nil {
+		log.Debug("Failed to rebuild cbft validators", "epoch", epoch, "number", header.Number, "err", err)
+		return nil
+	}
+	list := light.NewCbftValidators(validators)
+	light.WriteCbftValidators(pm.chainDb, epoch, list)
+	log.Debug("Rebuilt cbft validators", "epoch", epoch, "number", header.Number, "validators", len(list))
+	return list
+}
+
+// storeCbftValidators records the validator sets of the consensus rounds up to
+// the new head block. The validator sets of the past rounds are pruned from the
+// consensus state, but the light clients need them to follow the round switches.
+// The rounds before the node started are rebuilt on demand by cbftValidators.
+func (pm *ProtocolManager) storeCbftValidators(last uint64, head *types.Block) uint64 {
+	if pm.validators == nil {
+		return head.NumberU64()
+	}
+	// Only look at the head block if we have not seen the previous ones, the
+	// validator sets of the older rounds can't be retrieved anymore.
+	from := head.NumberU64()
+	if last != 0 && last < from {
+		from = last + 1
+	}
+	for number := from; number <= head.NumberU64() && number > 0; number++ {
+		qc := pm.readQuorumCert(rawdb.ReadCanonicalHash(pm.chainDb, number), number)
+		if qc == nil || light.HasCbftValidators(pm.chainDb, qc.Epoch) {
+			continue
+		}
+		validators, err := pm.validators.GetValidator(number)
+		if err != nil {
+			log.Warn("Failed to retrieve cbft validators", "epoch", qc.Epoch, "number", number, "err", err)
+			continue
+		}
+		light.WriteCbftValidators(pm.chainDb, qc.Epoch, light.NewCbftValidators(validators))
+		log.Debug("Stored cbft validators", "epoch", qc.Epoch, "number", number, "validators", validators.Len())
+	}
+	return head.NumberU64()
+}
+
+// cbftVerifyLoop verifies the QuorumCerts of the headers inserted into the light
+// chain. The headers which are not finalized by the validators are rolled back.
+func (pm *ProtocolManager) cbftVerifyLoop() {
+	pm.wg.Add(1)
+	defer pm.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, 10)
+	headSub := pm.blockchain.SubscribeChainHeadEvent(headCh)
+	defer headSub.Unsubscribe()
+
+	for {
+		select {
+		case <-headCh:
+			pm.verifyCbftHeaders()
+		case <-pm.quitSync:
+			return
+		}
+	}
+}
+
+// verifyCbftHeaders verifies the headers up to the current head of the light chain.
+func (pm *ProtocolManager) verifyCbftHeaders() {
+	head := pm.blockchain.CurrentHeader()
+	ctx, cancel := context.WithTimeout(context.Background(), cbftVerifyTimeout)
+	defer cancel()
+
+	err := pm.cbftVerifier.Sync(ctx, head.Number.Uint64())
+	if err == nil {
+		return
+	}
+	if !errors.Is(err, light.ErrInvalidCbftHeader) {
+		log.Debug("Failed to retrieve cbft headers", "number", head.Number, "err", err)
+		return
+	}
+	verified, _ := pm.cbftVerifier.CurrentHeader()
+	log.Warn("Rolling back unverified headers", "verified", verified, "head", head.Number, "err", err)
+
+	var rollback []common.Hash
+	for number := verified + 1; number <= head.Number.Uint64(); number++ {
+		if header := pm.blockchain.GetHeaderByNumber(number); header != nil {
+			rollback = append(rollback, header.Hash())
+		}
+	}
+	pm.blockchain.Rollback(rollback)
+}
@@ -61,6 +61,7 @@ const (
 	MaxHelperTrieProofsFetch = 64  // Amount of merkle proofs to be fetched per retrieval request
 	MaxTxSend                = 64  // Amount of transactions to be send per request
 	MaxTxStatus              = 256 // Amount of transactions to queried per request
+	MaxCbftHeaderFetch       = 192 // Amount of block headers with QuorumCerts to be fetched per retrieval request
 
 	disableClientRemovePeer = false
 )
@@ -106,6 +107,9 @@ type ProtocolManager struct {
 	reqDist     *requestDistributor
 	retriever   *retrieveManager
 
+	validators   validatorReader     // nil if our node is client only
+	cbftVerifier *light.CbftVerifier // nil if the QuorumCerts are not verified
+
 	downloader *downloader.Downloader
 	fetcher    *lightFetcher
 	peers      *peerSet
@@ -174,6 +178,9 @@ func (pm *ProtocolManager) Start(maxPeers int) {
 
 	if pm.lightSync {
 		go pm.syncer()
+		if pm.cbftVerifier != nil {
+			go pm.cbftVerifyLoop()
+		}
 	} else {
 		pm.clientPool = newFreeClientPool(pm.chainDb, maxPeers, 10000, mclock.System{})
 		go func() {
@@ -325,7 +332,7 @@ func (pm *ProtocolManager) handle(p *peer) error {
 	}
 }
 
-var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsV1Msg, SendTxMsg, SendTxV2Msg, GetTxStatusMsg, GetHeaderProofsMsg, GetProofsV2Msg, GetHelperTrieProofsMsg}
+var reqList = []uint64{GetBlockHeadersMsg, GetBlockBodiesMsg, GetCodeMsg, GetReceiptsMsg, GetProofsV1Msg, SendTxMsg, SendTxV2Msg, GetTxStatusMsg, GetHeaderProofsMsg, GetProofsV2Msg, GetHelperTrieProofsMsg, GetCbftHeadersMsg}
 
 // handleMsg is invoked whenever an inbound message is received from a remote
 // peer. The remote connection is torn down upon returning any error.
@@ -1101,6 +1108,46 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 
 		p.fcServer.GotReply(resp.ReqID, resp.BV)
 
+	case GetCbftHeadersMsg:
+		p.Log().Trace("Received cbft headers request")
+		// Decode the retrieval message
+		var req struct {
+			ReqID uint64
+			Query getCbftHeadersData
+		}
+		if err := msg.Decode(&req); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		query := req.Query
+		if reject(query.Amount, MaxCbftHeaderFetch) {
+			return errResp(ErrRequestRejected, "")
+		}
+		headers := pm.getCbftHeaders(query.Origin, query.Amount)
+
+		bv, rcost := p.fcClient.RequestProcessed(costs.baseCost + query.Amount*costs.reqCost)
+		pm.server.fcCostStats.update(msg.Code, query.Amount, rcost)
+		return p.SendCbftHeaders(req.ReqID, bv, headers)
+
+	case CbftHeadersMsg:
+		if pm.odr == nil {
+			return errResp(ErrUnexpectedResponse, "")
+		}
+
+		p.Log().Trace("Received cbft headers response")
+		var resp struct {
+			ReqID, BV uint64
+			Headers   []*light.CbftHeader
+		}
+		if err := msg.Decode(&resp); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		p.fcServer.GotReply(resp.ReqID, resp.BV)
+		deliverMsg = &Msg{
+			MsgType: MsgCbftHeaders,
+			ReqID:   resp.ReqID,
+			Obj:     resp.Headers,
+		}
+
 	default:
 		p.Log().Trace("Received unknown message", "code", msg.Code)
 		return errResp(ErrInvalidMsgCode, "%v", msg.Code)
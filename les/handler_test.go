@@ -18,18 +18,25 @@ package les
 
 import (
 	"encoding/binary"
+	"errors"
+	"math/big"
 	"math/rand"
 	"testing"
 	"time"
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
+	ctypes "github.com/AlayaNetwork/Alaya-Go/consensus/cbft/types"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/utils"
 	"github.com/AlayaNetwork/Alaya-Go/core"
+	"github.com/AlayaNetwork/Alaya-Go/core/cbfttypes"
 	"github.com/AlayaNetwork/Alaya-Go/core/rawdb"
 	"github.com/AlayaNetwork/Alaya-Go/core/types"
 	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/crypto/bls"
 	"github.com/AlayaNetwork/Alaya-Go/eth/downloader"
 	"github.com/AlayaNetwork/Alaya-Go/light"
 	"github.com/AlayaNetwork/Alaya-Go/p2p"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
 	"github.com/AlayaNetwork/Alaya-Go/rlp"
 	"github.com/AlayaNetwork/Alaya-Go/trie"
 )
@@ -301,6 +308,86 @@ func testGetReceipt(t *testing.T, protocol int) {
 	}
 }
 
+// Tests that the headers can be retrieved with their QuorumCerts and the
+// validator sets of the consensus round switches.
+func TestGetCbftHeadersLes3(t *testing.T) { testGetCbftHeaders(t, false) }
+
+// Tests that the validator sets of the rounds which were not seen live are
+// rebuilt from the staking state.
+func TestGetCbftHeadersHistoryLes3(t *testing.T) { testGetCbftHeaders(t, true) }
+
+// testHistoryValidators serves the validator set of the round started by a
+// block as stored in the staking state.
+type testHistoryValidators struct {
+	number     uint64
+	validators *cbfttypes.Validators
+}
+
+func (v *testHistoryValidators) GetValidator(uint64) (*cbfttypes.Validators, error) {
+	return nil, errors.New("pruned validators")
+}
+
+func (v *testHistoryValidators) GetHistoryValidator(hash common.Hash, number uint64) (*cbfttypes.Validators, error) {
+	if number != v.number {
+		return nil, errors.New("unexpected block")
+	}
+	return v.validators, nil
+}
+
+func testGetCbftHeaders(t *testing.T, history bool) {
+	bls.Init(bls.BLS12_381)
+	server, tearDown := newServerEnv(t, 4, lpv3, nil)
+	defer tearDown()
+	bc := server.pm.blockchain.(*core.BlockChain)
+
+	// Finalize the blocks by QuorumCerts, the round switches at the third block
+	var sk bls.SecretKey
+	sk.SetByCSPRNG()
+	nodeID := discover.PubkeyID(&testBankKey.PublicKey)
+	validators := []*light.CbftValidator{{NodeID: nodeID, BlsPubKey: sk.GetPublicKey()}}
+	if history {
+		server.pm.validators = &testHistoryValidators{number: 3, validators: &cbfttypes.Validators{
+			Nodes: cbfttypes.ValidateNodeMap{nodeID: {NodeID: nodeID, BlsPubKey: sk.GetPublicKey()}},
+		}}
+	} else {
+		light.WriteCbftValidators(server.db, 2, validators)
+	}
+
+	// The blocks are committed by the consensus engine, which the test chain
+	// lacks, so write the canonical chain directly
+	headers := []*light.CbftHeader{}
+	parent := bc.Genesis().Header()
+	for i := uint64(1); i <= 4; i++ {
+		header := &types.Header{ParentHash: parent.Hash(), Number: new(big.Int).SetUint64(i), Time: new(big.Int).Add(parent.Time, big.NewInt(1))}
+		rawdb.WriteHeader(server.db, header)
+		rawdb.WriteCanonicalHash(server.db, header.Hash(), i)
+		parent = header
+
+		qc := &ctypes.QuorumCert{Epoch: 1, BlockHash: header.Hash(), BlockNumber: i, ValidatorSet: utils.NewBitArray(1)}
+		item := &light.CbftHeader{Header: header, QC: qc}
+		if i >= 3 {
+			qc.Epoch = 2
+			if i == 3 {
+				item.Validators = validators
+			}
+		}
+		extra, err := ctypes.EncodeExtra(1, qc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rawdb.WriteBody(server.db, header.Hash(), i, &types.Body{ExtraData: extra})
+		headers = append(headers, item)
+	}
+	cost := server.tPeer.GetRequestCost(GetCbftHeadersMsg, len(headers))
+	sendRequest(server.tPeer.app, GetCbftHeadersMsg, 42, cost, &getCbftHeadersData{Origin: 1, Amount: uint64(len(headers))})
+	if err := expectResponse(server.tPeer.app, CbftHeadersMsg, 42, testBufLimit, headers); err != nil {
+		t.Errorf("cbft headers mismatch: %v", err)
+	}
+	if !light.HasCbftValidators(server.pm.chainDb, 2) {
+		t.Error("the validator set of the round is not stored")
+	}
+}
+
 // Tests that trie merkle proofs can be retrieved
 func TestGetProofsLes1(t *testing.T) { testGetProofs(t, 1) }
 func TestGetProofsLes2(t *testing.T) { testGetProofs(t, 2) }
@@ -44,6 +44,8 @@ var (
 	errCHTHashMismatch     = errors.New("cht hash mismatch")
 	errCHTNumberMismatch   = errors.New("cht number mismatch")
 	errUselessNodes        = errors.New("useless nodes in merkle proof nodeset")
+	errHeaderNotLinked     = errors.New("header not linked to the previous one")
+	errQuorumCertMismatch  = errors.New("quorum cert mismatch")
 )
 
 type LesOdrRequest interface {
@@ -67,6 +69,8 @@ func LesRequest(req light.OdrRequest) LesOdrRequest {
 		return (*ChtRequest)(r)
 	case *light.BloomRequest:
 		return (*BloomRequest)(r)
+	case *light.CbftHeadersRequest:
+		return (*CbftHeadersRequest)(r)
 	default:
 		return nil
 	}
@@ -189,7 +193,7 @@ func (r *TrieRequest) GetCost(peer *peer) uint64 {
 	switch peer.version {
 	case lpv1:
 		return peer.GetRequestCost(GetProofsV1Msg, 1)
-	case lpv2:
+	case lpv2, lpv3:
 		return peer.GetRequestCost(GetProofsV2Msg, 1)
 	default:
 		panic(nil)
@@ -349,7 +353,7 @@ func (r *ChtRequest) GetCost(peer *peer) uint64 {
 	switch peer.version {
 	case lpv1:
 		return peer.GetRequestCost(GetHeaderProofsMsg, 1)
-	case lpv2:
+	case lpv2, lpv3:
 		return peer.GetRequestCost(GetHelperTrieProofsMsg, 1)
 	default:
 		panic(nil)
@@ -537,6 +541,64 @@ func (r *BloomRequest) Validate(db ethdb.Database, msg *Msg) error {
 	return nil
 }
 
+// CbftHeadersRequest is the ODR request type for headers with their QuorumCerts
+type CbftHeadersRequest light.CbftHeadersRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (r *CbftHeadersRequest) GetCost(peer *peer) uint64 {
+	return peer.GetRequestCost(GetCbftHeadersMsg, int(r.Amount))
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *CbftHeadersRequest) CanSend(peer *peer) bool {
+	peer.lock.RLock()
+	defer peer.lock.RUnlock()
+
+	if peer.version < lpv3 {
+		return false
+	}
+	return peer.headInfo.Number >= r.Origin+r.Amount-1
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *CbftHeadersRequest) Request(reqID uint64, peer *peer) error {
+	peer.Log().Debug("Requesting cbft headers", "origin", r.Origin, "amount", r.Amount)
+	return peer.RequestCbftHeaders(reqID, r.GetCost(peer), r.Origin, r.Amount)
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest). The QuorumCerts are only
+// matched with the headers here, their signatures are verified by the
+// light.CbftVerifier which follows the validator sets.
+func (r *CbftHeadersRequest) Validate(db ethdb.Database, msg *Msg) error {
+	log.Debug("Validating cbft headers", "origin", r.Origin, "amount", r.Amount)
+
+	// Ensure we have a correct message with consecutive headers
+	if msg.MsgType != MsgCbftHeaders {
+		return errInvalidMessageType
+	}
+	headers := msg.Obj.([]*light.CbftHeader)
+	if len(headers) == 0 || uint64(len(headers)) > r.Amount {
+		return errInvalidEntryCount
+	}
+	for i, h := range headers {
+		if h.Header == nil || h.QC == nil {
+			return errHeaderUnavailable
+		}
+		number := h.Header.Number.Uint64()
+		if number != r.Origin+uint64(i) || (i > 0 && h.Header.ParentHash != headers[i-1].Header.Hash()) {
+			return errHeaderNotLinked
+		}
+		if h.QC.BlockNumber != number || h.QC.BlockHash != h.Header.Hash() {
+			return errQuorumCertMismatch
+		}
+	}
+	r.Headers = headers
+	return nil
+}
+
 // readTraceDB stores the keys of database reads. We use this to check that received node
 // sets contain only the trie nodes necessary to make proofs pass.
 type readTraceDB struct {
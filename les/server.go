@@ -20,6 +20,7 @@ package les
 import (
 	"crypto/ecdsa"
 	"encoding/binary"
+	"errors"
 	"math"
 	"sync"
 
@@ -49,6 +50,11 @@ type LesServer struct {
 }
 
 func NewLesServer(eth *eth.Ethereum, config *eth.Config) (*LesServer, error) {
+	// The validator sets of the past consensus rounds served to the light
+	// clients are rebuilt from the staking state of the archived snapshotdb.
+	if !config.DBSnapshotArchive {
+		return nil, errors.New("serving light clients requires --db.snapshot_archive")
+	}
 	quitSync := make(chan struct{})
 	pm, err := NewProtocolManager(eth.BlockChain().Config(), light.DefaultServerIndexerConfig, false, config.NetworkId, eth.EventMux(), eth.Engine(), newPeerSet(), eth.BlockChain(), eth.TxPool(), eth.ChainDb(), nil, nil, nil, quitSync, new(sync.WaitGroup))
 	if err != nil {
@@ -91,6 +97,7 @@ func NewLesServer(eth *eth.Ethereum, config *eth.Config) (*LesServer, error) {
 
 	srv.chtIndexer.Start(eth.BlockChain())
 	pm.server = srv
+	pm.validators = eth.Agency()
 
 	srv.defParams = &flowcontrol.ServerParams{
 		BufLimit:    300000000,
@@ -320,9 +327,11 @@ func (pm *ProtocolManager) blockLoop() {
 	go func() {
 		var lastHead *types.Header
 		lastBroadcastBn := uint64(0)
+		lastValidatorsBn := uint64(0)
 		for {
 			select {
 			case ev := <-headCh:
+				lastValidatorsBn = pm.storeCbftValidators(lastValidatorsBn, ev.Block)
 				peers := pm.peers.AllPeers()
 				if len(peers) > 0 {
 					header := ev.Block.Header()
@@ -0,0 +1,379 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package light
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	ctypes "github.com/AlayaNetwork/Alaya-Go/consensus/cbft/types"
+	"github.com/AlayaNetwork/Alaya-Go/core/cbfttypes"
+	"github.com/AlayaNetwork/Alaya-Go/core/rawdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/crypto/bls"
+	"github.com/AlayaNetwork/Alaya-Go/ethdb"
+	"github.com/AlayaNetwork/Alaya-Go/log"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/params"
+	"github.com/AlayaNetwork/Alaya-Go/rlp"
+)
+
+const (
+	// CbftGenesisEpoch is the epoch of the consensus round started by the
+	// genesis validators.
+	CbftGenesisEpoch = 1
+
+	// CbftHeadersBatch is the number of headers retrieved by one CbftHeadersRequest.
+	CbftHeadersBatch = 192
+)
+
+var (
+	cbftValidatorsPrefix = []byte("cbftValidators-") // cbftValidatorsPrefix + epoch (uint64 big endian) -> validator set
+	cbftVerifiedHeadKey  = []byte("LastCbftVerified")
+
+	// ErrInvalidCbftHeader is returned if a header is not finalized by the
+	// validators of its consensus round.
+	ErrInvalidCbftHeader = errors.New("invalid cbft header")
+)
+
+// CbftValidator is a consensus validator as served to the light clients, the
+// index of the validator is its position in the validator set.
+type CbftValidator struct {
+	NodeID    discover.NodeID
+	BlsPubKey *bls.PublicKey
+}
+
+// CbftHeader is a header together with the QuorumCert which finalized it. The
+// validator set is only filled in for the first header of a consensus round, it
+// is the set that signs the QuorumCerts of the round.
+type CbftHeader struct {
+	Header     *types.Header
+	QC         *ctypes.QuorumCert
+	Validators []*CbftValidator
+}
+
+// NewCbftValidators converts the validators of a consensus round into the list
+// served to the light clients.
+func NewCbftValidators(vs *cbfttypes.Validators) []*CbftValidator {
+	list := make([]*CbftValidator, vs.Len())
+	for i := range list {
+		node, _ := vs.FindNodeByIndex(i)
+		list[i] = &CbftValidator{NodeID: node.NodeID, BlsPubKey: node.BlsPubKey}
+	}
+	return list
+}
+
+// cbftValidatorSet builds the validator set of a consensus round from the list
+// served to the light clients.
+func cbftValidatorSet(list []*CbftValidator) (*cbfttypes.Validators, error) {
+	vs := &cbfttypes.Validators{Nodes: make(cbfttypes.ValidateNodeMap, len(list))}
+	for i, v := range list {
+		if v == nil || v.BlsPubKey == nil {
+			return nil, fmt.Errorf("validator %d has no bls public key", i)
+		}
+		pubkey, err := v.NodeID.Pubkey()
+		if err != nil {
+			return nil, fmt.Errorf("validator %d: %v", i, err)
+		}
+		if _, ok := vs.Nodes[v.NodeID]; ok {
+			return nil, fmt.Errorf("duplicate validator %s", v.NodeID.TerminalString())
+		}
+		vs.Nodes[v.NodeID] = &cbfttypes.ValidateNode{
+			Index:     uint32(i),
+			Address:   crypto.PubkeyToNodeAddress(*pubkey),
+			PubKey:    pubkey,
+			NodeID:    v.NodeID,
+			BlsPubKey: v.BlsPubKey,
+		}
+	}
+	return vs, nil
+}
+
+func cbftValidatorsKey(epoch uint64) []byte {
+	var enc [8]byte
+	binary.BigEndian.PutUint64(enc[:], epoch)
+	return append(append([]byte{}, cbftValidatorsPrefix...), enc[:]...)
+}
+
+// ReadCbftValidators reads the validator set of the given epoch from the database.
+func ReadCbftValidators(db ethdb.Reader, epoch uint64) []*CbftValidator {
+	data, _ := db.Get(cbftValidatorsKey(epoch))
+	if len(data) == 0 {
+		return nil
+	}
+	var list []*CbftValidator
+	if err := rlp.DecodeBytes(data, &list); err != nil {
+		log.Error("Invalid cbft validator set RLP", "epoch", epoch, "err", err)
+		return nil
+	}
+	return list
+}
+
+// HasCbftValidators checks if the validator set of the given epoch is stored.
+func HasCbftValidators(db ethdb.Reader, epoch uint64) bool {
+	has, _ := db.Has(cbftValidatorsKey(epoch))
+	return has
+}
+
+// WriteCbftValidators stores the validator set of the given epoch into the database.
+func WriteCbftValidators(db ethdb.Writer, epoch uint64, list []*CbftValidator) {
+	data, err := rlp.EncodeToBytes(list)
+	if err != nil {
+		log.Crit("Failed to RLP encode cbft validator set", "err", err)
+	}
+	if err := db.Put(cbftValidatorsKey(epoch), data); err != nil {
+		log.Crit("Failed to store cbft validator set", "err", err)
+	}
+}
+
+// cbftVerifiedHead is the progress of the CbftVerifier.
+type cbftVerifiedHead struct {
+	Epoch  uint64
+	Number uint64
+	Hash   common.Hash
+}
+
+// CbftVerifier follows the CBFT consensus from the genesis validators. It
+// verifies the QuorumCert of every header against the validator set of its
+// consensus round, and accepts a new validator set at a round switch only if
+// the QuorumCert of the first header of the round is signed by the new set and
+// more than one third of the signers belong to the previous set.
+//
+// The new validator set is not proven: the QuorumCert signs the block, not the
+// set, and the set elected in the staking state is served without a proof. The
+// check at the round switch is a heuristic, the validators of the new set which
+// did not sign the first QuorumCert are trusted from the server. The light
+// client must only be served by trusted servers.
+type CbftVerifier struct {
+	odr OdrBackend
+	db  ethdb.Database
+
+	lock       sync.Mutex
+	epoch      uint64
+	validators *cbfttypes.Validators
+	number     uint64      // number of the last verified header
+	hash       common.Hash // hash of the last verified header
+}
+
+// NewCbftVerifier creates a verifier which continues from the progress stored
+// in the database, or from the genesis validators.
+func NewCbftVerifier(odr OdrBackend, nodes []params.CbftNode) (*CbftVerifier, error) {
+	v := &CbftVerifier{odr: odr, db: odr.Database()}
+
+	var head cbftVerifiedHead
+	if data, _ := v.db.Get(cbftVerifiedHeadKey); len(data) > 0 {
+		if err := rlp.DecodeBytes(data, &head); err != nil {
+			return nil, err
+		}
+	} else {
+		list := make([]*CbftValidator, len(nodes))
+		for i := range nodes {
+			list[i] = &CbftValidator{NodeID: nodes[i].Node.ID, BlsPubKey: &nodes[i].BlsPubKey}
+		}
+		WriteCbftValidators(v.db, CbftGenesisEpoch, list)
+		head.Epoch = CbftGenesisEpoch
+	}
+	validators, err := cbftValidatorSet(ReadCbftValidators(v.db, head.Epoch))
+	if err != nil {
+		return nil, err
+	}
+	if validators.Len() == 0 {
+		return nil, fmt.Errorf("no cbft validators of epoch %d", head.Epoch)
+	}
+	v.epoch, v.validators = head.Epoch, validators
+	v.number, v.hash = head.Number, head.Hash
+	return v, nil
+}
+
+// CurrentHeader returns the number and the hash of the last verified header.
+func (v *CbftVerifier) CurrentHeader() (uint64, common.Hash) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	return v.number, v.hash
+}
+
+// Epoch returns the epoch of the last verified header.
+func (v *CbftVerifier) Epoch() uint64 {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	return v.epoch
+}
+
+// Sync retrieves and verifies the headers up to the given number. The errors
+// wrapping ErrInvalidCbftHeader mean the served chain is not finalized by the
+// validators or differs from the local one, the others that the headers could
+// not be retrieved.
+func (v *CbftVerifier) Sync(ctx context.Context, number uint64) error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	defer v.writeHead()
+
+	for v.number < number {
+		amount := number - v.number
+		if amount > CbftHeadersBatch {
+			amount = CbftHeadersBatch
+		}
+		req := &CbftHeadersRequest{Origin: v.number + 1, Amount: amount}
+		if err := v.odr.Retrieve(ctx, req); err != nil {
+			return err
+		}
+		for _, h := range req.Headers {
+			if h.Header.Number.Uint64() != v.number+1 || (v.number > 0 && h.Header.ParentHash != v.hash) {
+				return fmt.Errorf("%w: number %d is not linked to the verified chain", ErrInvalidCbftHeader, h.Header.Number)
+			}
+			if local := rawdb.ReadCanonicalHash(v.db, h.Header.Number.Uint64()); local != (common.Hash{}) && local != h.Header.Hash() {
+				return fmt.Errorf("%w: local header %d(%s) differs from the served one", ErrInvalidCbftHeader, h.Header.Number, local.TerminalString())
+			}
+			if err := v.verify(h); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Verify verifies the QuorumCert of the header following the last verified
+// one, the validator set is switched if the header starts a new consensus round.
+func (v *CbftVerifier) Verify(h *CbftHeader) error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if err := v.verify(h); err != nil {
+		return err
+	}
+	v.writeHead()
+	return nil
+}
+
+func (v *CbftVerifier) verify(h *CbftHeader) error {
+	if h.Header == nil || h.QC == nil {
+		return fmt.Errorf("%w: missing header or QuorumCert", ErrInvalidCbftHeader)
+	}
+	qc := h.QC
+	if qc.BlockNumber != h.Header.Number.Uint64() || qc.BlockHash != h.Header.Hash() {
+		return fmt.Errorf("%w: QuorumCert of block %d(%s) does not match the header %d(%s)",
+			ErrInvalidCbftHeader, qc.BlockNumber, qc.BlockHash.TerminalString(), h.Header.Number, h.Header.Hash().TerminalString())
+	}
+	switch qc.Epoch {
+	case v.epoch:
+		if len(h.Validators) != 0 {
+			return fmt.Errorf("%w: unexpected validator set in epoch %d", ErrInvalidCbftHeader, qc.Epoch)
+		}
+		if err := verifyQuorumCert(v.validators, qc); err != nil {
+			return err
+		}
+
+	case v.epoch + 1:
+		if len(h.Validators) == 0 {
+			return fmt.Errorf("%w: missing validator set of epoch %d", ErrInvalidCbftHeader, qc.Epoch)
+		}
+		next, err := cbftValidatorSet(h.Validators)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidCbftHeader, err)
+		}
+		if err := verifyQuorumCert(next, qc); err != nil {
+			return err
+		}
+		if err := verifyValidatorSwitch(v.validators, next, qc); err != nil {
+			return err
+		}
+		WriteCbftValidators(v.db, qc.Epoch, h.Validators)
+		log.Info("Switched cbft validators", "epoch", qc.Epoch, "number", qc.BlockNumber, "validators", next.Len())
+		v.epoch, v.validators = qc.Epoch, next
+
+	default:
+		return fmt.Errorf("%w: unknown epoch %d, current %d", ErrInvalidCbftHeader, qc.Epoch, v.epoch)
+	}
+	v.number, v.hash = qc.BlockNumber, qc.BlockHash
+	return nil
+}
+
+func (v *CbftVerifier) writeHead() {
+	data, err := rlp.EncodeToBytes(&cbftVerifiedHead{Epoch: v.epoch, Number: v.number, Hash: v.hash})
+	if err != nil {
+		log.Crit("Failed to RLP encode cbft verified head", "err", err)
+	}
+	if err := v.db.Put(cbftVerifiedHeadKey, data); err != nil {
+		log.Crit("Failed to store cbft verified head", "err", err)
+	}
+}
+
+// cbftThreshold is the number of signatures required by a QuorumCert.
+func cbftThreshold(num int) int {
+	return num - (num-1)/3
+}
+
+// verifyQuorumCert checks that the QuorumCert is signed by enough validators of
+// the set, and verifies the aggregated BLS signature.
+func verifyQuorumCert(vs *cbfttypes.Validators, qc *ctypes.QuorumCert) error {
+	if qc.ValidatorSet == nil || int(qc.ValidatorSet.Size()) != vs.Len() {
+		return fmt.Errorf("%w: QuorumCert validator set does not match %d validators", ErrInvalidCbftHeader, vs.Len())
+	}
+	if signs, threshold := qc.Len(), cbftThreshold(vs.Len()); signs < threshold {
+		return fmt.Errorf("%w: QuorumCert has small number of signature total:%d, threshold:%d", ErrInvalidCbftHeader, signs, threshold)
+	}
+	nodes, err := vs.NodeListByBitArray(qc.ValidatorSet)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCbftHeader, err)
+	}
+	msg, err := qc.CannibalizeBytes()
+	if err != nil {
+		return err
+	}
+	pub := *nodes[0].BlsPubKey
+	for i := 1; i < len(nodes); i++ {
+		pub.Add(nodes[i].BlsPubKey)
+	}
+	var sig bls.Sign
+	if err := sig.Deserialize(qc.Signature.Bytes()); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCbftHeader, err)
+	}
+	if !sig.Verify(&pub, string(msg)) {
+		return fmt.Errorf("%w: bls verifies QuorumCert signature fail", ErrInvalidCbftHeader)
+	}
+	return nil
+}
+
+// verifyValidatorSwitch checks that more than one third of the validators
+// signing the QuorumCert of the next round were validators of the previous round
+// with the same bls public key. It doesn't prove the next set was elected, see
+// CbftVerifier.
+func verifyValidatorSwitch(prev, next *cbfttypes.Validators, qc *ctypes.QuorumCert) error {
+	signers, err := next.NodeListByBitArray(qc.ValidatorSet)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCbftHeader, err)
+	}
+	kept := 0
+	for _, node := range signers {
+		if old, err := prev.FindNodeByID(node.NodeID); err == nil && bytes.Equal(old.BlsPubKey.Serialize(), node.BlsPubKey.Serialize()) {
+			kept++
+		}
+	}
+	if faulty := (prev.Len() - 1) / 3; kept <= faulty {
+		return fmt.Errorf("%w: validator switch of epoch %d signed by %d previous validators, need more than %d",
+			ErrInvalidCbftHeader, qc.Epoch, kept, faulty)
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package light
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	ctypes "github.com/AlayaNetwork/Alaya-Go/consensus/cbft/types"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/cbft/utils"
+	"github.com/AlayaNetwork/Alaya-Go/core/rawdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/crypto/bls"
+	"github.com/AlayaNetwork/Alaya-Go/ethdb"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/params"
+)
+
+// cbftTestKey is a validator key pair of the tests.
+type cbftTestKey struct {
+	validator *CbftValidator
+	sk        *bls.SecretKey
+}
+
+func newCbftTestKeys(t *testing.T, n int) []*cbftTestKey {
+	keys := make([]*cbftTestKey, n)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sk bls.SecretKey
+		sk.SetByCSPRNG()
+		keys[i] = &cbftTestKey{
+			validator: &CbftValidator{NodeID: discover.PubkeyID(&key.PublicKey), BlsPubKey: sk.GetPublicKey()},
+			sk:        &sk,
+		}
+	}
+	return keys
+}
+
+func cbftTestValidators(keys []*cbftTestKey) []*CbftValidator {
+	list := make([]*CbftValidator, len(keys))
+	for i, key := range keys {
+		list[i] = key.validator
+	}
+	return list
+}
+
+// newCbftTestHeader creates the header after parent with a QuorumCert signed by
+// the validators at the signer indexes.
+func newCbftTestHeader(t *testing.T, parent *types.Header, epoch uint64, keys []*cbftTestKey, signers ...int) *CbftHeader {
+	header := &types.Header{Number: big.NewInt(1), Extra: make([]byte, 32)}
+	if parent != nil {
+		header.Number = new(big.Int).Add(parent.Number, big.NewInt(1))
+		header.ParentHash = parent.Hash()
+	}
+	qc := &ctypes.QuorumCert{
+		Epoch:        epoch,
+		BlockHash:    header.Hash(),
+		BlockNumber:  header.Number.Uint64(),
+		ValidatorSet: utils.NewBitArray(uint32(len(keys))),
+	}
+	msg, err := qc.CannibalizeBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var aggSig bls.Sign
+	for i, index := range signers {
+		sig := keys[index].sk.Sign(string(msg))
+		if i == 0 {
+			aggSig = *sig
+		} else {
+			aggSig.Add(sig)
+		}
+		qc.ValidatorSet.SetIndex(uint32(index), true)
+	}
+	qc.Signature.SetBytes(aggSig.Serialize())
+	return &CbftHeader{Header: header, QC: qc}
+}
+
+// cbftTestOdr serves the CbftHeadersRequest from a list of headers.
+type cbftTestOdr struct {
+	OdrBackend
+	db      ethdb.Database
+	headers []*CbftHeader
+}
+
+func (odr *cbftTestOdr) Database() ethdb.Database {
+	return odr.db
+}
+
+func (odr *cbftTestOdr) Retrieve(ctx context.Context, req OdrRequest) error {
+	if req, ok := req.(*CbftHeadersRequest); ok {
+		for n := req.Origin; n < req.Origin+req.Amount && n <= uint64(len(odr.headers)); n++ {
+			req.Headers = append(req.Headers, odr.headers[n-1])
+		}
+	}
+	return nil
+}
+
+func newCbftTestVerifier(t *testing.T, odr *cbftTestOdr, keys []*cbftTestKey) *CbftVerifier {
+	nodes := make([]params.CbftNode, len(keys))
+	for i, key := range keys {
+		nodes[i].Node.ID = key.validator.NodeID
+		nodes[i].BlsPubKey = *key.validator.BlsPubKey
+	}
+	v, err := NewCbftVerifier(odr, nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestCbftVerifier(t *testing.T) {
+	bls.Init(bls.BLS12_381)
+
+	keys := newCbftTestKeys(t, 9)
+	genesis := keys[:4]
+	odr := &cbftTestOdr{db: rawdb.NewMemoryDatabase()}
+	v := newCbftTestVerifier(t, odr, genesis)
+
+	h1 := newCbftTestHeader(t, nil, 1, genesis, 0, 1, 2)
+	if err := v.Verify(h1); err != nil {
+		t.Fatalf("failed to verify the header signed by the quorum: %v", err)
+	}
+	// The header must be signed by enough genesis validators with their own keys
+	if err := v.Verify(newCbftTestHeader(t, h1.Header, 1, genesis, 0, 1)); !errors.Is(err, ErrInvalidCbftHeader) {
+		t.Errorf("header without the quorum verified, err %v", err)
+	}
+	forged := newCbftTestHeader(t, h1.Header, 1, genesis, 0, 1, 2)
+	forged.QC.Signature = newCbftTestHeader(t, h1.Header, 1, genesis, 0, 1, 3).QC.Signature
+	if err := v.Verify(forged); !errors.Is(err, ErrInvalidCbftHeader) {
+		t.Errorf("header with a forged signature verified, err %v", err)
+	}
+	mismatch := newCbftTestHeader(t, h1.Header, 1, genesis, 0, 1, 2)
+	mismatch.Header.GasLimit = 1
+	if err := v.Verify(mismatch); !errors.Is(err, ErrInvalidCbftHeader) {
+		t.Errorf("header with the QuorumCert of another header verified, err %v", err)
+	}
+
+	// The next round replaces a validator, the switch is signed by two previous validators
+	next := []*cbftTestKey{keys[0], keys[1], keys[2], keys[4]}
+	h2 := newCbftTestHeader(t, h1.Header, 2, next, 0, 1, 3)
+	if err := v.Verify(&CbftHeader{Header: h2.Header, QC: h2.QC}); !errors.Is(err, ErrInvalidCbftHeader) {
+		t.Errorf("round switch without the validator set verified, err %v", err)
+	}
+	h2.Validators = cbftTestValidators(next)
+	if err := v.Verify(h2); err != nil {
+		t.Fatalf("failed to verify the round switch: %v", err)
+	}
+	if epoch := v.Epoch(); epoch != 2 {
+		t.Fatalf("epoch mismatch: have %d, want 2", epoch)
+	}
+	if err := v.Verify(newCbftTestHeader(t, h2.Header, 2, genesis, 0, 1, 3)); !errors.Is(err, ErrInvalidCbftHeader) {
+		t.Errorf("header signed by a replaced validator verified, err %v", err)
+	}
+
+	// A validator set which is not signed by the previous validators is rejected
+	unknown := keys[5:9]
+	h3 := newCbftTestHeader(t, h2.Header, 3, unknown, 0, 1, 2, 3)
+	h3.Validators = cbftTestValidators(unknown)
+	if err := v.Verify(h3); !errors.Is(err, ErrInvalidCbftHeader) {
+		t.Errorf("unknown validator set verified, err %v", err)
+	}
+	// The progress is kept in the database
+	v = newCbftTestVerifier(t, odr, genesis)
+	if number, hash := v.CurrentHeader(); number != 2 || hash != h2.Header.Hash() {
+		t.Errorf("verified head mismatch: have %d(%x), want 2(%x)", number, hash, h2.Header.Hash())
+	}
+	if epoch := v.Epoch(); epoch != 2 {
+		t.Errorf("epoch mismatch: have %d, want 2", epoch)
+	}
+}
+
+func TestCbftVerifierSync(t *testing.T) {
+	bls.Init(bls.BLS12_381)
+
+	keys := newCbftTestKeys(t, 5)
+	genesis, next := keys[:4], []*cbftTestKey{keys[0], keys[1], keys[2], keys[4]}
+
+	var (
+		headers []*CbftHeader
+		parent  *types.Header
+	)
+	for i := 0; i < 5; i++ {
+		var h *CbftHeader
+		switch {
+		case i < 2:
+			h = newCbftTestHeader(t, parent, 1, genesis, 0, 1, 2)
+		case i == 2:
+			h = newCbftTestHeader(t, parent, 2, next, 0, 1, 2, 3)
+			h.Validators = cbftTestValidators(next)
+		default:
+			h = newCbftTestHeader(t, parent, 2, next, 1, 2, 3)
+		}
+		headers = append(headers, h)
+		parent = h.Header
+	}
+	// Tamper the last QuorumCert
+	headers[4].QC.Signature = headers[3].QC.Signature
+
+	odr := &cbftTestOdr{db: rawdb.NewMemoryDatabase(), headers: headers}
+	v := newCbftTestVerifier(t, odr, genesis)
+	if err := v.Sync(context.Background(), 4); err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	if err := v.Sync(context.Background(), 5); !errors.Is(err, ErrInvalidCbftHeader) {
+		t.Fatalf("tampered header synced, err %v", err)
+	}
+	if number, hash := v.CurrentHeader(); number != 4 || hash != headers[3].Header.Hash() {
+		t.Errorf("verified head mismatch: have %d(%x), want 4(%x)", number, hash, headers[3].Header.Hash())
+	}
+	if validators := ReadCbftValidators(odr.db, 2); len(validators) != len(next) || validators[3].NodeID != keys[4].validator.NodeID {
+		t.Errorf("validators of the next round not stored")
+	}
+}
@@ -173,3 +173,15 @@ func (req *BloomRequest) StoreResult(db ethdb.Database) {
 		rawdb.WriteBloomBits(db, req.BitIdx, sectionIdx, sectionHead, req.BloomBits[i])
 	}
 }
+
+// CbftHeadersRequest is the ODR request type for retrieving consecutive headers
+// together with their QuorumCerts and the validator sets of the round switches
+type CbftHeadersRequest struct {
+	OdrRequest
+	Origin, Amount uint64
+	Headers        []*CbftHeader
+}
+
+// StoreResult does nothing, the headers are stored by the CbftVerifier after
+// their QuorumCerts are verified
+func (req *CbftHeadersRequest) StoreResult(db ethdb.Database) {}
@@ -34,6 +34,7 @@ import (
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
 	"github.com/AlayaNetwork/Alaya-Go/consensus"
+	"github.com/AlayaNetwork/Alaya-Go/consensus/misc"
 	"github.com/AlayaNetwork/Alaya-Go/core"
 	"github.com/AlayaNetwork/Alaya-Go/core/cbfttypes"
 	"github.com/AlayaNetwork/Alaya-Go/core/state"
@@ -451,6 +452,9 @@ func (w *worker) newWorkLoop(recommit time.Duration) {
 				if cbftEngine, ok := w.engine.(consensus.Bft); ok {
 					if status == commitStatusIdle {
 						if shouldSeal, err := cbftEngine.ShouldSeal(timestamp); err == nil {
+							if shouldSeal && "off" == w.EmptyBlock && !w.hasPendingWork(cbftEngine) {
+								shouldSeal = false
+							}
 							if shouldSeal {
 								if shouldCommit, commitBlock := w.shouldCommit(timestamp); shouldCommit {
 									log.Debug("Begin to package new block regularly")
@@ -769,7 +773,7 @@ func (w *worker) makeCurrent(parent *types.Block, header *types.Header) error {
 		return err
 	}
 	env := &environment{
-		signer:     types.NewEIP155Signer(w.config.ChainID),
+		signer:     types.LatestSignerForChainID(w.config.ChainID),
 		snapshotDB: snapshotdb.Instance(),
 		state:      state,
 		header:     header,
@@ -1009,6 +1013,11 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 		log.Error("Failed to GetReactorInstance BeginBlocker on worker", "blockNumber", header.Number, "err", err)
 		return err
 	}
+	// Set the base fee after BeginBlocker, the dynamic fee market may be
+	// activated by the governance version in this block.
+	if gov.Gte0180VersionState(w.current.state) {
+		header.BaseFee = misc.CalcBaseFee(parent.Header())
+	}
 
 	// Only set the coinbase if our consensus engine is running (avoid spurious block rewards)
 	if w.isRunning() {
@@ -1091,7 +1100,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 	startTime = time.Now()
 	var localTimeout = false
 	if len(localTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs)
+		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs, header.BaseFee)
 		if failed, timeout := w.committer.CommitTransactions(header, txs, interrupt, timestamp, blockDeadline); failed {
 			return fmt.Errorf("commit transactions error")
 		} else {
@@ -1104,7 +1113,7 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 
 	startTime = time.Now()
 	if !localTimeout && len(remoteTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, remoteTxs)
+		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, remoteTxs, header.BaseFee)
 
 		if failed, _ := w.committer.CommitTransactions(header, txs, interrupt, timestamp, blockDeadline); failed {
 			return fmt.Errorf("commit transactions error")
@@ -1201,6 +1210,23 @@ func (w *worker) makePending() (*types.Block, *state.StateDB) {
 	return nil, nil
 }
 
+// hasPendingWork reports whether transactions are pending or some sealed blocks
+// carrying transactions are not committed yet. It is used to seal blocks only on
+// demand when empty blocks are disabled, a block being committed once two
+// descendants are sealed on top of it.
+func (w *worker) hasPendingWork(cbftEngine consensus.Bft) bool {
+	if pending, _ := w.eth.TxPool().Stats(); pending > 0 {
+		return true
+	}
+	head := w.chain.CurrentBlock().NumberU64()
+	for block := cbftEngine.NextBaseBlock(); block != nil && block.NumberU64() > head; block = cbftEngine.GetBlock(block.ParentHash(), block.NumberU64()-1) {
+		if len(block.Transactions()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (w *worker) shouldCommit(timestamp time.Time) (bool, *types.Block) {
 	currentBaseBlock := w.commitWorkEnv.getCurrentBaseBlock()
 	nextBaseBlock := w.engine.NextBaseBlock()
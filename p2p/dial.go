@@ -76,6 +76,8 @@ type dialstate struct {
 	netrestrict *netutil.Netlist
 
 	lookupRunning bool
+	dnsLookups    bool // whether candidates are fetched from DNS node lists
+	dnsRunning    bool
 	dialing       map[discover.NodeID]connFlag
 	lookupBuf     []*discover.Node // current discovery lookup results
 	randomNodes   []*discover.Node // filled from Table
@@ -125,6 +127,13 @@ type discoverTask struct {
 	results []*discover.Node
 }
 
+// dnsDiscoverTask fetches dial candidates from the DNS node lists.
+// Only one dnsDiscoverTask is active at any time.
+type dnsDiscoverTask struct {
+	want    int
+	results []*discover.Node
+}
+
 // A waitExpireTask is generated if there are no other tasks
 // to keep the loop in Server.run ticking.
 type waitExpireTask struct {
@@ -260,7 +269,7 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 	// Use random nodes from the table for half of the necessary
 	// dynamic dials.
 	randomCandidates := needDynDials / 2
-	if randomCandidates > 0 {
+	if randomCandidates > 0 && s.ntab != nil {
 		n := s.ntab.ReadRandomNodes(s.randomNodes)
 		for i := 0; i < randomCandidates && i < n; i++ {
 			if addDial(dynDialedConn, s.randomNodes[i]) {
@@ -278,10 +287,15 @@ func (s *dialstate) newTasks(nRunning int, peers map[discover.NodeID]*Peer, now
 	}
 	s.lookupBuf = s.lookupBuf[:copy(s.lookupBuf, s.lookupBuf[i:])]
 	// Launch a discovery lookup if more candidates are needed.
-	if len(s.lookupBuf) < needDynDials && !s.lookupRunning {
+	if s.ntab != nil && len(s.lookupBuf) < needDynDials && !s.lookupRunning {
 		s.lookupRunning = true
 		newtasks = append(newtasks, &discoverTask{})
 	}
+	// Fetch more candidates from the DNS node lists as well.
+	if s.dnsLookups && len(s.lookupBuf) < needDynDials && !s.dnsRunning {
+		s.dnsRunning = true
+		newtasks = append(newtasks, &dnsDiscoverTask{want: needDynDials - len(s.lookupBuf)})
+	}
 
 	// Launch a timer to wait for the next node to expire if all
 	// candidates have been tried and no task is currently active.
@@ -327,6 +341,9 @@ func (s *dialstate) taskDone(t task, now time.Time) {
 	case *discoverTask:
 		s.lookupRunning = false
 		s.lookupBuf = append(s.lookupBuf, t.results...)
+	case *dnsDiscoverTask:
+		s.dnsRunning = false
+		s.lookupBuf = append(s.lookupBuf, t.results...)
 	}
 }
 
@@ -422,6 +439,27 @@ func (t *discoverTask) String() string {
 	return s
 }
 
+func (t *dnsDiscoverTask) Do(srv *Server) {
+	// The node lists are small and get iterated over and over, so
+	// throttle the task like discoverTask to keep the loop calm.
+	next := srv.lastDNSLookup.Add(lookupInterval)
+	if now := time.Now(); now.Before(next) {
+		time.Sleep(next.Sub(now))
+	}
+	srv.lastDNSLookup = time.Now()
+	for len(t.results) < t.want && srv.dnsIter.Next() {
+		t.results = append(t.results, srv.dnsIter.Node())
+	}
+}
+
+func (t *dnsDiscoverTask) String() string {
+	s := "dns discovery"
+	if len(t.results) > 0 {
+		s += fmt.Sprintf(" (%d results)", len(t.results))
+	}
+	return s
+}
+
 func (t waitExpireTask) Do(*Server) {
 	time.Sleep(t.Duration)
 }
@@ -432,6 +432,46 @@ func TestDialStateNetRestrict(t *testing.T) {
 	})
 }
 
+// This test checks that dynamic dials are launched from DNS node list results
+// when the discovery table is disabled.
+func TestDialStateDNS(t *testing.T) {
+	state := newDialState(nil, nil, nil, 5, nil, 75)
+	state.dnsLookups = true
+	runDialTest(t, dialtest{
+		init: state,
+		rounds: []round{
+			// A DNS lookup is launched for the missing dynamic peers.
+			{
+				peers: []*Peer{
+					{rw: &conn{flags: dynDialedConn, id: uintID(1)}},
+					{rw: &conn{flags: dynDialedConn, id: uintID(2)}},
+				},
+				new: []task{&dnsDiscoverTask{want: 3}},
+			},
+			// Dynamic dials are launched when it completes, and another
+			// lookup is started for the remaining slot.
+			{
+				peers: []*Peer{
+					{rw: &conn{flags: dynDialedConn, id: uintID(1)}},
+					{rw: &conn{flags: dynDialedConn, id: uintID(2)}},
+				},
+				done: []task{
+					&dnsDiscoverTask{want: 3, results: []*discover.Node{
+						{ID: uintID(2)}, // this one is already connected and not dialed.
+						{ID: uintID(3)},
+						{ID: uintID(4)},
+					}},
+				},
+				new: []task{
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(3)}},
+					&dialTask{flags: dynDialedConn, dest: &discover.Node{ID: uintID(4)}},
+					&dnsDiscoverTask{want: 1},
+				},
+			},
+		},
+	})
+}
+
 // This test checks that static dials are launched.
 func TestDialStateStaticDial(t *testing.T) {
 	wantStatic := []*discover.Node{
@@ -0,0 +1,312 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package dnsdisc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AlayaNetwork/Alaya-Go/common/mclock"
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/enr"
+)
+
+func TestClientSyncTree(t *testing.T) {
+	records, _ := testRecords(t, 40)
+	key := testKey(t)
+	tree, url := makeTestTree(t, "n", key, records, nil)
+	r := mapResolver(tree.ToTXT("n"))
+
+	c := NewClient(Config{Resolver: r})
+	stree, err := c.SyncTree(url)
+	if err != nil {
+		t.Fatal("sync error:", err)
+	}
+	if !reflect.DeepEqual(stree.Records(), tree.Records()) {
+		t.Error("wrong records in synced tree")
+	}
+	if stree.Seq() != tree.Seq() || stree.Signature() != tree.Signature() {
+		t.Error("wrong root in synced tree")
+	}
+	if !reflect.DeepEqual(stree.ToTXT("n"), tree.ToTXT("n")) {
+		t.Error("synced tree doesn't match original")
+	}
+}
+
+// In this test, syncing the tree fails because it contains a node record
+// without endpoint.
+func TestClientSyncTreeBadNode(t *testing.T) {
+	var rec enr.Record
+	rec.Set(enr.TCP(30303))
+	if err := enr.SignV4(&rec, testKey(t)); err != nil {
+		t.Fatal(err)
+	}
+	// Build the tree by hand because MakeTree refuses such records.
+	leaf, links := &enrEntry{&rec}, &branchEntry{}
+	tree := &Tree{
+		root:    &rootEntry{eroot: subdomain(leaf), lroot: subdomain(links), seq: 1},
+		entries: map[string]entry{subdomain(leaf): leaf, subdomain(links): links},
+	}
+	url, err := tree.Sign(testKey(t), "n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient(Config{Resolver: mapResolver(tree.ToTXT("n"))})
+	_, err = c.SyncTree(url)
+	if err == nil {
+		t.Fatal("expected sync error for invalid ENR entry")
+	}
+	ne, ok := err.(nameError)
+	if !ok {
+		t.Fatalf("wrong error %v", err)
+	}
+	if ee, ok := ne.err.(entryError); !ok || ee.typ != "enr" {
+		t.Fatalf("wrong error %v", err)
+	}
+}
+
+// In this test, syncing the tree fails because the root is signed by the wrong key.
+func TestClientSyncTreeBadSignature(t *testing.T) {
+	records, _ := testRecords(t, 3)
+	tree, _ := makeTestTree(t, "n", testKey(t), records, nil)
+	url := newLinkEntry("n", &testKey(t).PublicKey).String()
+
+	c := NewClient(Config{Resolver: mapResolver(tree.ToTXT("n"))})
+	_, err := c.SyncTree(url)
+	if want := (entryError{"root", errInvalidSig}); err != want {
+		t.Fatalf("wrong error %v, want %v", err, want)
+	}
+}
+
+// In this test, a tree entry doesn't match the hash under which it is published.
+func TestClientSyncTreeHashMismatch(t *testing.T) {
+	records, _ := testRecords(t, 2)
+	tree, url := makeTestTree(t, "n", testKey(t), records, nil)
+	r := mapResolver(tree.ToTXT("n"))
+	// Swap the two ENR leaves.
+	branch := tree.entries[tree.root.eroot].(*branchEntry)
+	a, b := branch.children[0]+".n", branch.children[1]+".n"
+	r[a], r[b] = r[b], r[a]
+
+	c := NewClient(Config{Resolver: r})
+	_, err := c.SyncTree(url)
+	if err == nil {
+		t.Fatal("expected sync error")
+	}
+	if ne, ok := err.(nameError); !ok || ne.err != errHashMismatch {
+		t.Fatalf("wrong error %v", err)
+	}
+}
+
+// This test checks that randomIterator finds all entries.
+func TestIterator(t *testing.T) {
+	records, nodes := testRecords(t, 30)
+	tree, url := makeTestTree(t, "n", testKey(t), records, nil)
+	r := mapResolver(tree.ToTXT("n"))
+
+	c := NewClient(Config{Resolver: r})
+	it, err := c.NewIterator(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	checkIterator(t, it, nodes)
+}
+
+// This test checks that the iterator follows links to other trees.
+func TestIteratorLinks(t *testing.T) {
+	records, nodes := testRecords(t, 40)
+	tree1, url1 := makeTestTree(t, "t1", testKey(t), records[10:], nil)
+	tree2, url2 := makeTestTree(t, "t2", testKey(t), records[:10], []string{url1})
+
+	c := NewClient(Config{Resolver: newMapResolver(tree1.ToTXT("t1"), tree2.ToTXT("t2"))})
+	it, err := c.NewIterator(url2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	checkIterator(t, it, nodes)
+}
+
+// This test checks that the iterator picks up node changes when the root is
+// rechecked.
+func TestIteratorNodeUpdates(t *testing.T) {
+	var (
+		clock    = new(mclock.Simulated)
+		key      = testKey(t)
+		resolver = newMapResolver()
+		c        = NewClient(Config{Resolver: resolver, RecheckInterval: 20 * time.Minute})
+	)
+	c.clock = clock
+	records, nodes := testRecords(t, 30)
+	tree1, url := makeTestTree(t, "n", key, records[:25], nil)
+	it, err := c.NewIterator(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+
+	// Sync the original tree.
+	resolver.add(tree1.ToTXT("n"))
+	checkIterator(t, it, nodes[:25])
+
+	// Update some nodes and ensure the iterator picks them up after the
+	// root recheck interval has passed.
+	tree2, _ := makeTestTree(t, "n", key, records, nil)
+	resolver.clear()
+	resolver.add(tree2.ToTXT("n"))
+	clock.Run(c.cfg.RecheckInterval + 1*time.Second)
+	checkIterator(t, it, nodes)
+}
+
+// This test checks that the iterator waits for a root update when the tree
+// contains no nodes, and that Close unblocks it.
+func TestIteratorEmptyTree(t *testing.T) {
+	var (
+		clock    = new(mclock.Simulated)
+		key      = testKey(t)
+		resolver = newMapResolver()
+		c        = NewClient(Config{Resolver: resolver, RecheckInterval: 20 * time.Minute})
+	)
+	c.clock = clock
+	records, nodes := testRecords(t, 5)
+	tree1, url := makeTestTree(t, "n", key, nil, nil)
+	tree2, _ := makeTestTree(t, "n", key, records, nil)
+	resolver.add(tree1.ToTXT("n"))
+
+	// Start the iterator. It will block until the tree gets some nodes.
+	it, err := c.NewIterator(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		clock.WaitForTimers(1)
+		resolver.clear()
+		resolver.add(tree2.ToTXT("n"))
+		clock.Run(c.cfg.RecheckInterval + 1*time.Second)
+	}()
+	checkIterator(t, it, nodes)
+
+	// Closing the iterator while it waits must make Next return false.
+	resolver.clear()
+	resolver.add(tree1.ToTXT("n"))
+	clock.Run(c.cfg.RecheckInterval + 1*time.Second)
+	done := make(chan bool)
+	go func() { done <- drainIterator(it) }()
+	clock.WaitForTimers(1)
+	it.Close()
+	select {
+	case more := <-done:
+		if more {
+			t.Fatal("Next returned true after Close")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close didn't unblock Next")
+	}
+}
+
+func drainIterator(it Iterator) bool {
+	for it.Next() {
+		if it.Node() == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func checkIterator(t *testing.T, it Iterator, wantNodes []*discover.Node) {
+	t.Helper()
+
+	var (
+		want     = make(map[discover.NodeID]*discover.Node)
+		maxCalls = len(wantNodes) * 20
+		calls    = 0
+	)
+	for _, n := range wantNodes {
+		want[n.ID] = n
+	}
+	for ; len(want) > 0 && calls < maxCalls; calls++ {
+		if !it.Next() {
+			t.Fatalf("Next returned false (call %d)", calls)
+		}
+		n := it.Node()
+		if wn, ok := want[n.ID]; ok && !reflect.DeepEqual(n, wn) {
+			t.Fatalf("wrong node %v, want %v", n, wn)
+		}
+		delete(want, n.ID)
+	}
+	if len(want) > 0 {
+		t.Fatalf("iterator returned %d of %d nodes in %d calls", len(wantNodes)-len(want), len(wantNodes), calls)
+	}
+}
+
+func makeTestTree(t *testing.T, domain string, key *ecdsa.PrivateKey, records []*enr.Record, links []string) (*Tree, string) {
+	t.Helper()
+	tree, err := MakeTree(1, records, links)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url, err := tree.Sign(key, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree, url
+}
+
+func testKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// mapResolver is a stub DNS resolver answering TXT queries from a map.
+type mapResolver map[string]string
+
+func newMapResolver(maps ...map[string]string) mapResolver {
+	mr := make(mapResolver)
+	for _, m := range maps {
+		mr.add(m)
+	}
+	return mr
+}
+
+func (mr mapResolver) clear() {
+	for k := range mr {
+		delete(mr, k)
+	}
+}
+
+func (mr mapResolver) add(m map[string]string) {
+	for k, v := range m {
+		mr[k] = v
+	}
+}
+
+func (mr mapResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if record, ok := mr[name]; ok {
+		return []string{record}, nil
+	}
+	return nil, errors.New("not found")
+}
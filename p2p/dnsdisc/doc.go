@@ -0,0 +1,29 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package dnsdisc implements node discovery via DNS (EIP-1459).
+//
+// A DNS node list is a merkle tree of TXT records published under a domain.
+// The root record at the domain itself points at the roots of two subtrees:
+// one holding the signed node records (ENRs) of the list, the other holding
+// links to further lists. The root is signed by the list operator, so the
+// client only needs the enrtree:// URL, which carries the operator's public
+// key, to verify everything it downloads.
+//
+// Use Client.SyncTree to fetch a whole list at once, or Client.NewIterator to
+// obtain an endless stream of random nodes from one or more lists. Trees are
+// built and signed with MakeTree and Tree.Sign.
+package dnsdisc
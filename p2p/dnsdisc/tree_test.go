@@ -0,0 +1,306 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package dnsdisc
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/crypto"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/enr"
+)
+
+func TestParseRoot(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	tree, err := MakeTree(3, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tree.Sign(key, "n"); err != nil {
+		t.Fatal(err)
+	}
+	text := tree.root.String()
+
+	e, err := parseRoot(text)
+	if err != nil {
+		t.Fatalf("can't parse root: %v", err)
+	}
+	if !reflect.DeepEqual(e, *tree.root) {
+		t.Errorf("wrong root entry %+v, want %+v", e, *tree.root)
+	}
+	if !e.verifySignature(&key.PublicKey) {
+		t.Error("root signature doesn't verify")
+	}
+
+	tests := []struct {
+		input string
+		err   error
+	}{
+		{
+			input: strings.Replace(text, " l=", " x=", 1),
+			err:   entryError{"root", errSyntax},
+		},
+		{
+			input: strings.Replace(text, " e="+e.eroot, " e=AB", 1),
+			err:   entryError{"root", errInvalidChild},
+		},
+		{
+			input: text[:strings.Index(text, "sig=")] + "sig=AAAA",
+			err:   entryError{"root", errInvalidSig},
+		},
+	}
+	for i, test := range tests {
+		if _, err := parseRoot(test.input); err != test.err {
+			t.Errorf("test %d: wrong error %q, want %q", i, err, test.err)
+		}
+	}
+}
+
+func TestParseEntry(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	records, nodes := testRecords(t, 1)
+	recordText, _ := EncodeRecord(records[0])
+	link := newLinkEntry("nodes.example.org", &key.PublicKey)
+
+	tests := []struct {
+		input string
+		e     entry
+		err   error
+	}{
+		// Subtrees:
+		{
+			input: "enrtree-branch:1,2",
+			err:   entryError{"branch", errInvalidChild},
+		},
+		{
+			input: "enrtree-branch:AAAAAAAAAAAAAAAAAA",
+			err:   entryError{"branch", errInvalidChild},
+		},
+		{
+			input: "enrtree-branch:",
+			e:     &branchEntry{},
+		},
+		{
+			input: "enrtree-branch:AAAAAAAAAAAAAAAAAAAAAAAAAA",
+			e:     &branchEntry{[]string{"AAAAAAAAAAAAAAAAAAAAAAAAAA"}},
+		},
+		{
+			input: "enrtree-branch:AAAAAAAAAAAAAAAAAAAAAAAAAA,BBBBBBBBBBBBBBBBBBBBBBBBBB",
+			e:     &branchEntry{[]string{"AAAAAAAAAAAAAAAAAAAAAAAAAA", "BBBBBBBBBBBBBBBBBBBBBBBBBB"}},
+		},
+		// Links:
+		{
+			input: link.String(),
+			e:     link,
+		},
+		{
+			input: "enrtree://nodes.example.org",
+			err:   entryError{"link", errNoPubkey},
+		},
+		{
+			input: "enrtree://AP62DT7WOTEQZGQZOU474PP3KMEGVTTE7A7NPRXKX3DUD57@nodes.example.org",
+			err:   entryError{"link", errBadPubkey},
+		},
+		// ENRs:
+		{
+			input: recordText,
+			e:     &enrEntry{records[0]},
+		},
+		{
+			input: "enr:-HW4QLZHjM4vZXkbp-5xJoHsKSbE7W39FPC8283X-y8oHcHPTnDDlIlzL5ArvDUlHZVDPgmFASrh7cWgLOLxj4wprRkHgmlkgnY0iXNlY3AyNTZrMaEC3t2jLMhDpCDX5mbSEwDn4L3iUfyXzoO8G28XvjGRkrAg=",
+			err:   entryError{"enr", errInvalidENR},
+		},
+		// Invalid:
+		{input: "", err: errUnknownEntry},
+		{input: "foo", err: errUnknownEntry},
+		{input: "enrtree", err: errUnknownEntry},
+		{input: "enrtree-x=", err: errUnknownEntry},
+	}
+	for i, test := range tests {
+		e, err := parseEntry(test.input)
+		if !reflect.DeepEqual(e, test.e) {
+			t.Errorf("test %d: wrong entry %+v, want %+v", i, e, test.e)
+		}
+		if err != test.err {
+			t.Errorf("test %d: wrong error %q, want %q", i, err, test.err)
+		}
+	}
+
+	// The node of a parsed ENR entry must match the record.
+	e, _ := parseEntry(recordText)
+	n, err := nodeFromRecord(e.(*enrEntry).record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(n, nodes[0]) {
+		t.Errorf("wrong node %v, want %v", n, nodes[0])
+	}
+}
+
+func TestMakeTree(t *testing.T) {
+	records, nodes := testRecords(t, 50)
+	key, _ := crypto.GenerateKey()
+	links := []string{
+		newLinkEntry("a.example.org", &key.PublicKey).String(),
+		newLinkEntry("b.example.org", &key.PublicKey).String(),
+	}
+	tree, err := MakeTree(2, records, links)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.Seq() != 2 {
+		t.Errorf("wrong sequence number %d", tree.Seq())
+	}
+	if !reflect.DeepEqual(tree.Links(), links) {
+		t.Errorf("wrong links %v, want %v", tree.Links(), links)
+	}
+	if !reflect.DeepEqual(tree.Records(), sortedRecords(records)) {
+		t.Error("tree records don't match input")
+	}
+	if got := tree.Nodes(); len(got) != len(nodes) {
+		t.Errorf("got %d nodes, want %d", len(got), len(nodes))
+	}
+
+	// Every entry is published as a TXT record next to the root.
+	txt := tree.ToTXT("n")
+	if len(txt) != len(tree.entries)+1 {
+		t.Errorf("got %d TXT records, want %d", len(txt), len(tree.entries)+1)
+	}
+}
+
+func TestMakeTreeUnsigned(t *testing.T) {
+	var r enr.Record
+	r.Set(enr.IP(net.IP{127, 0, 0, 1}))
+	r.Set(enr.TCP(30303))
+	if _, err := MakeTree(1, []*enr.Record{&r}, nil); err == nil {
+		t.Fatal("MakeTree accepted an unsigned record")
+	}
+}
+
+func TestTreeSignature(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+	tree, _ := MakeTree(1, nil, nil)
+	url, err := tree.Sign(key, "n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	domain, pubkey, err := ParseURL(url)
+	if err != nil {
+		t.Fatalf("can't parse signed tree URL: %v", err)
+	}
+	if domain != "n" || !reflect.DeepEqual(pubkey, &key.PublicKey) {
+		t.Fatalf("wrong URL components %q %v", domain, pubkey)
+	}
+
+	sig := tree.Signature()
+	unsigned, _ := MakeTree(1, nil, nil)
+	if err := unsigned.SetSignature(&otherKey.PublicKey, sig); err != errInvalidSig {
+		t.Errorf("SetSignature accepted signature of wrong key: %v", err)
+	}
+	if err := unsigned.SetSignature(&key.PublicKey, sig); err != nil {
+		t.Fatalf("SetSignature error: %v", err)
+	}
+	if unsigned.Signature() != sig {
+		t.Error("signature not set")
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	url := newLinkEntry("nodes.example.org", &key.PublicKey).String()
+
+	tests := []struct {
+		input  string
+		domain string
+		pubkey *ecdsa.PublicKey
+		err    bool
+	}{
+		{input: url, domain: "nodes.example.org", pubkey: &key.PublicKey},
+		{input: strings.Replace(url, "enrtree://", "http://", 1), err: true},
+		{input: "enrtree://nodes.example.org", err: true},
+	}
+	for i, test := range tests {
+		domain, pubkey, err := ParseURL(test.input)
+		if test.err != (err != nil) {
+			t.Errorf("test %d: wrong error %v", i, err)
+			continue
+		}
+		if domain != test.domain || !reflect.DeepEqual(pubkey, test.pubkey) {
+			t.Errorf("test %d: wrong result %q %v", i, domain, pubkey)
+		}
+	}
+}
+
+func TestRecordEncoding(t *testing.T) {
+	records, _ := testRecords(t, 1)
+	text, err := EncodeRecord(records[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(text, "enr:") {
+		t.Fatalf("missing prefix in %q", text)
+	}
+	dec, err := DecodeRecord(text)
+	if err != nil {
+		t.Fatalf("can't decode record: %v", err)
+	}
+	if !reflect.DeepEqual(dec, records[0]) {
+		t.Error("decoded record doesn't match original")
+	}
+	if _, err := DecodeRecord(text[4:]); err == nil {
+		t.Error("no error for record without prefix")
+	}
+	if _, err := DecodeRecord("enr:" + strings.Repeat("A", 20)); err == nil {
+		t.Error("no error for invalid record")
+	}
+}
+
+// testRecords creates n signed node records and the matching nodes.
+func testRecords(t *testing.T, n int) ([]*enr.Record, []*discover.Node) {
+	t.Helper()
+	records := make([]*enr.Record, n)
+	nodes := make([]*discover.Node, n)
+	for i := range records {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ip := net.IP{127, 0, byte(i >> 8), byte(i)}
+		var r enr.Record
+		r.Set(enr.IP(ip))
+		r.Set(enr.TCP(30303 + i))
+		r.Set(enr.UDP(30303 + i))
+		if err := enr.SignV4(&r, key); err != nil {
+			t.Fatal(fmt.Errorf("can't sign record: %v", err))
+		}
+		records[i] = &r
+		nodes[i] = discover.NewNode(discover.PubkeyID(&key.PublicKey), ip, uint16(30303+i), uint16(30303+i))
+	}
+	return records, nodes
+}
+
+func sortedRecords(records []*enr.Record) []*enr.Record {
+	sorted := append([]*enr.Record(nil), records...)
+	sortRecords(sorted)
+	return sorted
+}
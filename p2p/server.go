@@ -38,6 +38,7 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/log"
 	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
 	"github.com/AlayaNetwork/Alaya-Go/p2p/discv5"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/dnsdisc"
 	"github.com/AlayaNetwork/Alaya-Go/p2p/nat"
 	"github.com/AlayaNetwork/Alaya-Go/p2p/netutil"
 )
@@ -270,6 +271,15 @@ type Config struct {
 	// protocol.
 	BootstrapNodesV5 []*discv5.Node `toml:",omitempty"`
 
+	// DNSDiscovery lists the enrtree:// URLs of DNS node lists (EIP-1459).
+	// Nodes found in these lists are used as dial candidates, which works
+	// even if the discovery table is disabled.
+	DNSDiscovery []string `toml:",omitempty"`
+
+	// DNSResolver is used to query the DNS node lists. If nil, the
+	// system resolver is used.
+	DNSResolver dnsdisc.Resolver `toml:"-"`
+
 	// Static nodes are used as pre-configured connections which are always
 	// maintained and re-connected on disconnects.
 	StaticNodes []*discover.Node `json:"-"`
@@ -339,6 +349,9 @@ type Server struct {
 	lastLookup   time.Time
 	DiscV5       *discv5.Network
 
+	dnsIter       dnsdisc.Iterator
+	lastDNSLookup time.Time
+
 	// These are for Peers, PeerCount (and nothing else).
 	peerOp     chan peerOpFunc
 	peerOpDone chan struct{}
@@ -736,8 +749,19 @@ func (srv *Server) Start() (err error) {
 		srv.DiscV5 = ntab
 	}
 
+	// DNS node lists
+	if len(srv.DNSDiscovery) > 0 {
+		client := dnsdisc.NewClient(dnsdisc.Config{Resolver: srv.DNSResolver, Logger: srv.log})
+		it, err := client.NewIterator(srv.DNSDiscovery...)
+		if err != nil {
+			return err
+		}
+		srv.dnsIter = it
+	}
+
 	dynPeers := srv.maxDialedConns()
 	dialer := newDialState(srv.StaticNodes, srv.BootstrapNodes, srv.ntab, dynPeers, srv.NetRestrict, srv.MaxConsensusPeers)
+	dialer.dnsLookups = srv.dnsIter != nil
 
 	// handshake
 	srv.ourHandshake = &protoHandshake{Version: baseProtocolVersion, Name: srv.Name, ID: discover.PubkeyID(&srv.PrivateKey.PublicKey)}
@@ -1004,6 +1028,9 @@ running:
 	if srv.DiscV5 != nil {
 		srv.DiscV5.Close()
 	}
+	if srv.dnsIter != nil {
+		srv.dnsIter.Close()
+	}
 	// Disconnect all peers.
 	for _, p := range peers {
 		p.Disconnect(DiscQuitting)
@@ -1073,7 +1100,7 @@ func (srv *Server) maxInboundConns() int {
 }
 
 func (srv *Server) maxDialedConns() int {
-	if srv.NoDiscovery || srv.NoDial {
+	if srv.NoDial || (srv.NoDiscovery && len(srv.DNSDiscovery) == 0) {
 		return 0
 	}
 	r := srv.DialRatio
@@ -17,6 +17,7 @@
 package p2p
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"errors"
 	"net"
@@ -30,6 +31,8 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/crypto/sha3"
 	"github.com/AlayaNetwork/Alaya-Go/log"
 	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/dnsdisc"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/enr"
 )
 
 func init() {
@@ -204,6 +207,76 @@ func TestServerDial(t *testing.T) {
 	}
 }
 
+// This test checks that the server dials nodes found in a DNS node list,
+// even with the discovery table disabled.
+func TestServerDNSDiscovery(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not setup listener: %v", err)
+	}
+	defer listener.Close()
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		accepted <- conn
+	}()
+
+	// Publish the listener in a DNS node list.
+	remkey := newkey()
+	remid := discover.PubkeyID(&remkey.PublicKey)
+	tcpAddr := listener.Addr().(*net.TCPAddr)
+	var r enr.Record
+	r.Set(enr.IP(tcpAddr.IP))
+	r.Set(enr.TCP(tcpAddr.Port))
+	if err := enr.SignV4(&r, remkey); err != nil {
+		t.Fatal(err)
+	}
+	tree, err := dnsdisc.MakeTree(1, []*enr.Record{&r}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	url, err := tree.Sign(newkey(), "nodes.example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := &Server{
+		Config: Config{
+			Name:         "test",
+			MaxPeers:     10,
+			PrivateKey:   newkey(),
+			NoDiscovery:  true,
+			DNSDiscovery: []string{url},
+			DNSResolver:  mapResolver(tree.ToTXT("nodes.example.org")),
+		},
+		newTransport: func(fd net.Conn) transport { return newTestTransport(remid, fd) },
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("could not start server: %v", err)
+	}
+	defer srv.Stop()
+
+	select {
+	case conn := <-accepted:
+		conn.Close()
+	case <-time.After(5 * time.Second):
+		t.Error("server did not dial the DNS listed node within five seconds")
+	}
+}
+
+// mapResolver is a stub DNS resolver answering TXT queries from a map.
+type mapResolver map[string]string
+
+func (mr mapResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if record, ok := mr[name]; ok {
+		return []string{record}, nil
+	}
+	return nil, errors.New("not found")
+}
+
 // This test checks that tasks generated by dialstate are
 // actually executed and taskdone is called for them.
 func TestServerTaskScheduling(t *testing.T) {
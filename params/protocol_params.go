@@ -100,6 +100,18 @@ const (
 	ExtcodeHashGasEIP1884        uint64 = 700  // Cost of EXTCODEHASH after EIP 1884 (part in Istanbul)
 	SelfdestructGasEIP150        uint64 = 5000 // Cost of SELFDESTRUCT post EIP 150 (Tangerine)
 
+	// The access list gas rules (EIP-2929 and EIP-2930), enabled by the governance version 0.17.0
+	ColdAccountAccessCostEIP2929 uint64 = 2600 // Cost of the first access of an account in the transaction
+	ColdSloadCostEIP2929         uint64 = 2100 // Cost of the first access of a storage slot in the transaction
+	WarmStorageReadCostEIP2929   uint64 = 100  // Cost of reading an account or a storage slot already accessed
+	TxAccessListAddressGas       uint64 = 2400 // Per address specified in the EIP 2930 access list
+	TxAccessListStorageKeyGas    uint64 = 1900 // Per storage key specified in the EIP 2930 access list
+
+	// The dynamic fee market (EIP-1559), enabled by the governance version 0.18.0
+	BaseFeeChangeDenominator = 8          // Bounds the amount the base fee can change between blocks
+	ElasticityMultiplier     = 2          // Bounds the maximum gas limit a block may have compared to its gas target
+	InitialBaseFee           = 1000000000 // Base fee of the first block after the dynamic fee market is enabled
+
 	// EXP has a dynamic portion depending on the size of the exponent
 	ExpByteFrontier uint64 = 10 // was set to 10 in Frontier
 	ExpByteEIP158   uint64 = 50 // was raised to 50 during Eip158 (Spurious Dragon)
@@ -23,7 +23,7 @@ import (
 const (
 	//These versions are meaning the current code version.
 	VersionMajor = 0          // Major version component of the current release
-	VersionMinor = 16         // Minor version component of the current release
+	VersionMinor = 18         // Minor version component of the current release
 	VersionPatch = 0          // Patch version component of the current release
 	VersionMeta  = "unstable" // Version metadata to append to the version string
 
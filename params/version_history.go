@@ -5,4 +5,6 @@ const (
 	FORKVERSION_0_14_0 = uint32(0<<16 | 14<<8 | 0)
 	FORKVERSION_0_15_0 = uint32(0<<16 | 15<<8 | 0)
 	FORKVERSION_0_16_0 = uint32(0<<16 | 16<<8 | 0)
+	FORKVERSION_0_17_0 = uint32(0<<16 | 17<<8 | 0)
+	FORKVERSION_0_18_0 = uint32(0<<16 | 18<<8 | 0)
 )
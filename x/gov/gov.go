@@ -100,6 +100,22 @@ func Gte0160Version(version uint32) bool {
 	return version >= params.FORKVERSION_0_16_0
 }
 
+func Gte0170VersionState(state xcom.StateDB) bool {
+	return Gte0170Version(GetCurrentActiveVersion(state))
+}
+
+func Gte0170Version(version uint32) bool {
+	return version >= params.FORKVERSION_0_17_0
+}
+
+func Gte0180VersionState(state xcom.StateDB) bool {
+	return Gte0180Version(GetCurrentActiveVersion(state))
+}
+
+func Gte0180Version(version uint32) bool {
+	return version >= params.FORKVERSION_0_18_0
+}
+
 func WriteEcHash0140(state xcom.StateDB) error {
 	if data, err := xcom.EcParams0140(); nil != err {
 		return err
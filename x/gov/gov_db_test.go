@@ -1056,6 +1056,7 @@ func TestSet0140Param(t *testing.T) {
 
 	var paramItemList []*ParamItem
 
+	xcom.GetEc(xcom.DefaultUnitTestNet)
 	initParamList := queryInitParam()
 
 	var err error
@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"math/big"
 	"strconv"
-	"sync"
 
 	"github.com/AlayaNetwork/Alaya-Go/params"
 
@@ -32,18 +31,12 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
 )
 
-var (
-	initGovParam sync.Once
-)
-
-var governParam []*GovernParam
-
+// queryInitParam builds the default govern parameters from the economic model
+// in use. It is only called for the genesis and when the verifiers are
+// registered, so the parameters are not cached and always follow the model.
 func queryInitParam() []*GovernParam {
-	initGovParam.Do(func() {
-		log.Info("Init Govern parameters ...")
-		governParam = initParam()
-	})
-	return governParam
+	log.Info("Init Govern parameters ...")
+	return initParam()
 }
 
 func initParam() []*GovernParam {
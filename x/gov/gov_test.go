@@ -19,10 +19,13 @@ package gov
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math/big"
+	"os"
 	"testing"
 
 	"github.com/AlayaNetwork/Alaya-Go/common/vm"
+	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
 
 	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
 
@@ -40,6 +43,18 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/common/mock"
 )
 
+func TestMain(m *testing.M) {
+	// Without the test build tag the snapshotdb has no path of its own.
+	dir, err := ioutil.TempDir("", "gov")
+	if err != nil {
+		panic(err)
+	}
+	snapshotdb.SetDBPathWithNode(dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 var (
 	sender = common.MustBech32ToAddress("atx1pmhjxvfqeccm87kzpkkr08djgvpp5535gxm6s5")
 	nodeID = discover.MustHexID("0x362003c50ed3a523cdede37a001803b8f0fed27cb402b3d6127a1a96661ec202318f68f4c76d9b0bfbabfd551a178d4335eaeaa9b7981a4df30dfc8c0bfe3384")
@@ -18,18 +18,29 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
+
 	"github.com/AlayaNetwork/Alaya-Go/common"
 	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/rpc"
+	"github.com/AlayaNetwork/Alaya-Go/x/reward"
+	"github.com/AlayaNetwork/Alaya-Go/x/slashing"
+	"github.com/AlayaNetwork/Alaya-Go/x/staking"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
+	"github.com/AlayaNetwork/Alaya-Go/x/xutil"
 )
 
 // Provides an API interface to obtain data related to the economic model
 type PublicPPOSAPI struct {
 	snapshotDB snapshotdb.DB
+	stateAt    func(header *types.Header) (xcom.StateDB, error)
 }
 
-func NewPublicPPOSAPI() *PublicPPOSAPI {
-	return &PublicPPOSAPI{snapshotdb.Instance()}
+func NewPublicPPOSAPI(stateAt func(header *types.Header) (xcom.StateDB, error)) *PublicPPOSAPI {
+	return &PublicPPOSAPI{snapshotdb.Instance(), stateAt}
 }
 
 // Get node list of zero-out blocks
@@ -40,3 +51,79 @@ func (p *PublicPPOSAPI) GetWaitSlashingNodeList() string {
 	}
 	return fmt.Sprintf("%+v", list)
 }
+
+// Get the candidate info of the node at the given block,
+// the history block is only available when the snapshotdb archive mode is enabled
+func (p *PublicPPOSAPI) GetCandidateInfo(nodeId discover.NodeID, blockNr rpc.BlockNumber) (*staking.CandidateHex, error) {
+	header, err := p.headerByNumber(blockNr)
+	if nil != err {
+		return nil, err
+	}
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		return nil, err
+	}
+	return stk.GetCandidateCompactInfo(header.Hash(), header.Number.Uint64(), canAddr)
+}
+
+// Get the delegate info of the delegator at the given block
+func (p *PublicPPOSAPI) GetDelegateInfo(stakingBlockNum uint64, delAddr common.Address, nodeId discover.NodeID, blockNr rpc.BlockNumber) (*staking.DelegationEx, error) {
+	header, err := p.headerByNumber(blockNr)
+	if nil != err {
+		return nil, err
+	}
+	return stk.GetDelegateExCompactInfo(header.Hash(), header.Number.Uint64(), delAddr, nodeId, stakingBlockNum)
+}
+
+// Get the verifier list of the epoch which the given block belongs to
+func (p *PublicPPOSAPI) GetVerifierList(blockNr rpc.BlockNumber) (staking.ValidatorExQueue, error) {
+	header, err := p.headerByNumber(blockNr)
+	if nil != err {
+		return nil, err
+	}
+	return stk.GetVerifierList(header.Hash(), header.Number.Uint64(), QueryStartNotIrr)
+}
+
+// Get the block production of the validators of the round which the given block belongs to,
+// and the estimated risk of the slashing of the zero-production blocks
+func (p *PublicPPOSAPI) GetProduceStatus(blockNr rpc.BlockNumber) (*slashing.RoundProduceStatus, error) {
+	header, err := p.headerByNumber(blockNr)
+	if nil != err {
+		return nil, err
+	}
+	return SlashInstance().GetProduceStatus(header.Number.Uint64(), header.Hash())
+}
+
+// Project the reward of the nodes the account delegates to and the income of the account
+// in the next epochs after the given block, all the delegations of the account if nodes is empty
+func (p *PublicPPOSAPI) ProjectDelegateReward(account common.Address, nodes []discover.NodeID, epochs uint64, blockNr rpc.BlockNumber) ([]*reward.NodeDelegateRewardProjection, error) {
+	if epochs == 0 || epochs > MaxProjectionEpochs {
+		return nil, fmt.Errorf("the epochs must be in [1, %d]", MaxProjectionEpochs)
+	}
+	header, err := p.headerByNumber(blockNr)
+	if nil != err {
+		return nil, err
+	}
+	state, err := p.stateAt(header)
+	if nil != err {
+		return nil, err
+	}
+	return RewardMgrInstance().ProjectDelegateReward(header.Hash(), header, account, nodes, epochs, state)
+}
+
+func (p *PublicPPOSAPI) headerByNumber(blockNr rpc.BlockNumber) (*types.Header, error) {
+	chain := snapshotdb.GetDBBlockChain()
+	if nil == chain {
+		return nil, errors.New("the blockchain is not ready")
+	}
+	var header *types.Header
+	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
+		header = chain.CurrentHeader()
+	} else {
+		header = chain.GetHeaderByNumber(uint64(blockNr.Int64()))
+	}
+	if nil == header {
+		return nil, fmt.Errorf("block #%d not found", blockNr)
+	}
+	return header, nil
+}
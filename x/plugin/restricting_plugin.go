@@ -78,9 +78,14 @@ func (rp *RestrictingPlugin) EndBlock(blockHash common.Hash, head *types.Header,
 	if xutil.IsEndOfEpoch(head.Number.Uint64()) {
 		expect := xutil.CalculateEpoch(head.Number.Uint64())
 		rp.log.Info("begin to release restricting plan", "currentHash", blockHash, "currBlock", head.Number, "expectBlock", head.Number, "expectEpoch", expect)
-		if err := rp.releaseRestricting(expect, state); err != nil {
+		released, err := rp.releaseRestricting(expect, state)
+		if err != nil {
 			return err
 		}
+		for _, r := range released {
+			xcom.AddPPOSEvent(blockHash, head.Number.Uint64(), nil,
+				xcom.NewPPOSEvent(xcom.RestrictingReleaseEvent, discover.NodeID{}, 0, r.account, r.amount))
+		}
 		if ok, _ := xcom.IsYearEnd(blockHash, head.Number.Uint64()); ok {
 			rp.log.Info(fmt.Sprintf("release genesis restricting plan, blocknumber:%d", head.Number.Uint64()))
 			return rp.releaseGenesisRestrictingPlans(blockHash, state)
@@ -613,15 +618,25 @@ func (rp *RestrictingPlugin) storeAmount2ReleaseAmount(state xcom.StateDB, epoch
 }
 
 // releaseRestricting will release restricting plans on target epoch
-func (rp *RestrictingPlugin) releaseRestricting(epoch uint64, state xcom.StateDB) error {
+// releasedAmount is the von transferred to the account by the release of a restricting plan
+type releasedAmount struct {
+	account common.Address
+	amount  *big.Int
+}
+
+// releaseRestricting releases the restricting plans of the epoch, and returns the von
+// transferred to the accounts, the von still used by staking or delegation is not included.
+func (rp *RestrictingPlugin) releaseRestricting(epoch uint64, state xcom.StateDB) ([]releasedAmount, error) {
 
 	rp.log.Info("Call releaseRestricting begin", "epoch", epoch)
 	releaseEpochKey, numbers := rp.getReleaseEpochNumber(state, epoch)
 	if numbers == 0 {
 		rp.log.Info("Call releaseRestricting: there is no release record on curr epoch", "epoch", epoch)
-		return nil
+		return nil, nil
 	}
 
+	released := make([]releasedAmount, 0)
+
 	rp.log.Info("Call releaseRestricting: many restricting records need release", "epoch", epoch, "records", numbers)
 
 	for index := numbers; index > 0; index-- {
@@ -632,7 +647,7 @@ func (rp *RestrictingPlugin) releaseRestricting(epoch uint64, state xcom.StateDB
 			if err == restricting.ErrAccountNotFound {
 				continue
 			}
-			return err
+			return nil, err
 		}
 
 		releaseAmountKey, releaseAmount := rp.getReleaseAmount(state, epoch, account)
@@ -656,11 +671,15 @@ func (rp *RestrictingPlugin) releaseRestricting(epoch uint64, state xcom.StateDB
 			if canRelease.Cmp(releaseAmount) >= 0 {
 				rp.transferAmount(state, vm.RestrictingContractAddr, account, releaseAmount)
 				restrictInfo.CachePlanAmount.Sub(restrictInfo.CachePlanAmount, releaseAmount)
+				released = append(released, releasedAmount{account, new(big.Int).Set(releaseAmount)})
 			} else {
 				needRelease := new(big.Int).Sub(releaseAmount, canRelease)
 				rp.transferAmount(state, vm.RestrictingContractAddr, account, canRelease)
 				restrictInfo.NeedRelease.Add(restrictInfo.NeedRelease, needRelease)
 				restrictInfo.CachePlanAmount.Sub(restrictInfo.CachePlanAmount, canRelease)
+				if canRelease.Sign() > 0 {
+					released = append(released, releasedAmount{account, canRelease})
+				}
 			}
 		}
 
@@ -692,7 +711,7 @@ func (rp *RestrictingPlugin) releaseRestricting(epoch uint64, state xcom.StateDB
 
 	rp.log.Info("Call releaseRestricting finish", "epoch", epoch, "records", numbers)
 
-	return nil
+	return released, nil
 }
 
 func (rp *RestrictingPlugin) getRestrictingInfoToReturn(account common.Address, state xcom.StateDB) (*restricting.Result, *common.BizError) {
@@ -279,7 +279,7 @@ func TestRestrictingPlugin_Compose3(t *testing.T) {
 	if err := plugin.AdvanceLockedFunds(plugin.to, big.NewInt(1e18), plugin.mockDB); err != nil {
 		t.Error()
 	}
-	if err := plugin.releaseRestricting(1, plugin.mockDB); err != nil {
+	if _, err := plugin.releaseRestricting(1, plugin.mockDB); err != nil {
 		t.Error(err)
 	}
 	if err := plugin.ReturnLockFunds(plugin.to, big.NewInt(1e18), plugin.mockDB); err != nil {
@@ -311,7 +311,7 @@ func TestRestrictingPlugin_Compose2(t *testing.T) {
 	if err := plugin.AdvanceLockedFunds(to, big.NewInt(2e18), mockDB); err != nil {
 		t.Error(err)
 	}
-	if err := plugin.releaseRestricting(1, mockDB); err != nil {
+	if _, err := plugin.releaseRestricting(1, mockDB); err != nil {
 		t.Error(err)
 	}
 
@@ -375,7 +375,7 @@ func TestRestrictingPlugin_Compose(t *testing.T) {
 	assert.Equal(t, mockDB.GetBalance(vm.StakingContractAddr), big.NewInt(2e18))
 	infoAssertF(big.NewInt(2e18), []uint64{1}, big.NewInt(2e18), big.NewInt(0))
 
-	if err := plugin.releaseRestricting(1, mockDB); err != nil {
+	if _, err := plugin.releaseRestricting(1, mockDB); err != nil {
 		t.Error(err)
 	}
 	assert.Equal(t, mockDB.GetBalance(to).Uint64(), uint64(0))
@@ -466,18 +466,18 @@ func TestRestrictingInstance(t *testing.T) {
 	if err := plugin.AddRestrictingRecord(from, to, xutil.CalcBlocksEachEpoch()-10, common.ZeroHash, plans, mockDB, RestrictingTxHash); err != nil {
 		t.Error(err)
 	}
-	if err := plugin.releaseRestricting(1, mockDB); err != nil {
+	if _, err := plugin.releaseRestricting(1, mockDB); err != nil {
 		t.Error(err)
 	}
 	//	SetLatestEpoch(mockDB, 1)
 	if err := plugin.AdvanceLockedFunds(to, big.NewInt(5e18), mockDB); err != nil {
 		t.Error(err)
 	}
-	if err := plugin.releaseRestricting(2, mockDB); err != nil {
+	if _, err := plugin.releaseRestricting(2, mockDB); err != nil {
 		t.Error(err)
 	}
 	//	SetLatestEpoch(mockDB, 2)
-	if err := plugin.releaseRestricting(3, mockDB); err != nil {
+	if _, err := plugin.releaseRestricting(3, mockDB); err != nil {
 		t.Error(err)
 	}
 	//	SetLatestEpoch(mockDB, 3)
@@ -492,7 +492,7 @@ func TestRestrictingInstance(t *testing.T) {
 	assert.Equal(t, big.NewInt(9e18), mockDB.GetBalance(to))
 	assert.Equal(t, big.NewInt(1e18), mockDB.GetBalance(vm.RestrictingContractAddr))
 
-	if err := plugin.releaseRestricting(4, mockDB); err != nil {
+	if _, err := plugin.releaseRestricting(4, mockDB); err != nil {
 		t.Error(err)
 	}
 	//	SetLatestEpoch(mockDB, 4)
@@ -564,7 +564,7 @@ func TestRestrictingInstanceWithSlashing(t *testing.T) {
 		t.Error(err)
 	}
 
-	if err := plugin.releaseRestricting(1, mockDB); err != nil {
+	if _, err := plugin.releaseRestricting(1, mockDB); err != nil {
 		t.Error(err)
 	}
 	//	SetLatestEpoch(mockDB, 1)
@@ -573,12 +573,12 @@ func TestRestrictingInstanceWithSlashing(t *testing.T) {
 		t.Error(err)
 	}
 
-	if err := plugin.releaseRestricting(2, mockDB); err != nil {
+	if _, err := plugin.releaseRestricting(2, mockDB); err != nil {
 		t.Error(err)
 	}
 	//	SetLatestEpoch(mockDB, 2)
 
-	if err := plugin.releaseRestricting(3, mockDB); err != nil {
+	if _, err := plugin.releaseRestricting(3, mockDB); err != nil {
 		t.Error(err)
 	}
 	//	SetLatestEpoch(mockDB, 3)
@@ -599,7 +599,7 @@ func TestRestrictingInstanceWithSlashing(t *testing.T) {
 
 	assert.Equal(t, big.NewInt(9e18), mockDB.GetBalance(to))
 
-	if err := plugin.releaseRestricting(4, mockDB); err != nil {
+	if _, err := plugin.releaseRestricting(4, mockDB); err != nil {
 		t.Error(err)
 	}
 	//	SetLatestEpoch(mockDB, 4)
@@ -611,7 +611,7 @@ func TestRestrictingInstanceWithSlashing(t *testing.T) {
 	if mockDB.GetBalance(vm.StakingContractAddr).Cmp(big.NewInt(0)) != 0 {
 		t.Error("StakingContractAddr should compare", vm.StakingContractAddr)
 	}
-	if err := plugin.releaseRestricting(5, mockDB); err != nil {
+	if _, err := plugin.releaseRestricting(5, mockDB); err != nil {
 		t.Error(err)
 	}
 	//	SetLatestEpoch(mockDB, 5)
@@ -691,7 +691,7 @@ func TestRestrictingReturnLockFunds(t *testing.T) {
 		return
 	}
 	if err := chain.AddBlockWithSnapDB(true, nil, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
-		if err := plugin.releaseRestricting(1, chain.StateDB); err != nil {
+		if _, err := plugin.releaseRestricting(1, chain.StateDB); err != nil {
 			return err
 		}
 		return nil
@@ -780,7 +780,7 @@ func TestRestrictingForkAdvanceLockedFunds(t *testing.T) {
 		return
 	}
 	if err := chain.AddBlockWithSnapDB(true, nil, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
-		if err := plugin.releaseRestricting(1, chain.StateDB); err != nil {
+		if _, err := plugin.releaseRestricting(1, chain.StateDB); err != nil {
 			return err
 		}
 		return nil
@@ -863,7 +863,7 @@ func TestRestrictingSlashingRelease(t *testing.T) {
 		return
 	}
 	if err := chain.AddBlockWithSnapDB(true, nil, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
-		if err := plugin.releaseRestricting(1, chain.StateDB); err != nil {
+		if _, err := plugin.releaseRestricting(1, chain.StateDB); err != nil {
 			return err
 		}
 		return nil
@@ -892,7 +892,7 @@ func TestRestrictingSlashingRelease(t *testing.T) {
 		return
 	}
 	if err := chain.AddBlockWithSnapDB(true, nil, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
-		if err := plugin.releaseRestricting(2, chain.StateDB); err != nil {
+		if _, err := plugin.releaseRestricting(2, chain.StateDB); err != nil {
 			return err
 		}
 		return nil
@@ -901,7 +901,7 @@ func TestRestrictingSlashingRelease(t *testing.T) {
 		return
 	}
 	if err := chain.AddBlockWithSnapDB(true, nil, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
-		if err := plugin.releaseRestricting(3, chain.StateDB); err != nil {
+		if _, err := plugin.releaseRestricting(3, chain.StateDB); err != nil {
 			return err
 		}
 		return nil
@@ -924,7 +924,7 @@ func TestRestrictingSlashingRelease(t *testing.T) {
 	}
 
 	if err := chain.AddBlockWithSnapDB(true, nil, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
-		if err := plugin.releaseRestricting(4, chain.StateDB); err != nil {
+		if _, err := plugin.releaseRestricting(4, chain.StateDB); err != nil {
 			return err
 		}
 		return nil
@@ -933,7 +933,7 @@ func TestRestrictingSlashingRelease(t *testing.T) {
 		return
 	}
 	if err := chain.AddBlockWithSnapDB(true, nil, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
-		if err := plugin.releaseRestricting(5, chain.StateDB); err != nil {
+		if _, err := plugin.releaseRestricting(5, chain.StateDB); err != nil {
 			return err
 		}
 		return nil
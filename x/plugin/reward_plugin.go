@@ -327,6 +327,11 @@ func (rmp *RewardMgrPlugin) WithdrawDelegateReward(blockHash common.Hash, blockN
 			return nil, common.InternalError
 		}
 	}
+	for _, r := range rewards {
+		if r.Reward.Cmp(common.Big0) > 0 {
+			xcom.AddPPOSEvent(blockHash, blockNum, state, xcom.NewPPOSEvent(xcom.WithdrawDelegateRewardEvent, r.NodeID, r.StakingNum, account, r.Reward))
+		}
+	}
 	log.Debug("Call withdraw delegate reward: end", "account", account, "rewards", rewards, "blockNum", blockNum, "blockHash", blockHash, "receiveReward", receiveReward)
 
 	return rewards, nil
@@ -335,35 +340,10 @@ func (rmp *RewardMgrPlugin) WithdrawDelegateReward(blockHash common.Hash, blockN
 func (rmp *RewardMgrPlugin) GetDelegateReward(blockHash common.Hash, blockNum uint64, account common.Address, nodes []discover.NodeID, state xcom.StateDB) ([]reward.NodeDelegateRewardPresenter, error) {
 	log.Debug("Call RewardMgrPlugin: query delegate reward result begin", "account", account, "nodes", nodes, "num", blockNum)
 
-	dls, err := rmp.stakingPlugin.db.GetDelegatesInfo(blockHash, account)
+	dls, err := rmp.getDelegatesInfo(blockHash, account, nodes)
 	if err != nil {
-		log.Error("Call GetDelegateReward GetDelegatesInfo fail", "err", err, "account", account)
 		return nil, err
 	}
-	if len(dls) == 0 {
-		return nil, reward.ErrDelegationNotFound
-	}
-	if len(nodes) > 0 {
-		nodeMap := make(map[discover.NodeID]struct{})
-		for _, node := range nodes {
-			nodeMap[node] = struct{}{}
-		}
-
-		for i := 0; i < len(dls); {
-			if _, ok := nodeMap[dls[i].NodeID]; !ok {
-				dls = append(dls[:i], dls[i+1:]...)
-			} else {
-				i++
-			}
-		}
-		if len(dls) == 0 {
-			return nil, reward.ErrDelegationNotFound
-		}
-	} else {
-		if len(dls) > int(xcom.TheNumberOfDelegationsReward()) {
-			sort.Sort(staking.DelByDelegateEpoch(dls))
-		}
-	}
 
 	currentEpoch := xutil.CalculateEpoch(blockNum)
 	delegationInfoWithRewardPerList := make([]*DelegationInfoWithRewardPerList, 0)
@@ -392,6 +372,41 @@ func (rmp *RewardMgrPlugin) GetDelegateReward(blockHash common.Hash, blockNum ui
 	return rewards, nil
 }
 
+// getDelegatesInfo returns the delegations of the account on the nodes, all the
+// delegations if nodes is empty.
+func (rmp *RewardMgrPlugin) getDelegatesInfo(blockHash common.Hash, account common.Address, nodes []discover.NodeID) ([]*staking.DelegationInfo, error) {
+	dls, err := rmp.stakingPlugin.db.GetDelegatesInfo(blockHash, account)
+	if err != nil {
+		log.Error("Call GetDelegateReward GetDelegatesInfo fail", "err", err, "account", account)
+		return nil, err
+	}
+	if len(dls) == 0 {
+		return nil, reward.ErrDelegationNotFound
+	}
+	if len(nodes) > 0 {
+		nodeMap := make(map[discover.NodeID]struct{})
+		for _, node := range nodes {
+			nodeMap[node] = struct{}{}
+		}
+
+		for i := 0; i < len(dls); {
+			if _, ok := nodeMap[dls[i].NodeID]; !ok {
+				dls = append(dls[:i], dls[i+1:]...)
+			} else {
+				i++
+			}
+		}
+		if len(dls) == 0 {
+			return nil, reward.ErrDelegationNotFound
+		}
+	} else {
+		if len(dls) > int(xcom.TheNumberOfDelegationsReward()) {
+			sort.Sort(staking.DelByDelegateEpoch(dls))
+		}
+	}
+	return dls, nil
+}
+
 func (rmp *RewardMgrPlugin) CalDelegateRewardAndNodeReward(totalReward *big.Int, per uint16) (*big.Int, *big.Int) {
 	tmp := new(big.Int).Mul(totalReward, big.NewInt(int64(per)))
 	tmp.Div(tmp, big.NewInt(10000))
@@ -762,28 +777,16 @@ func (rmp *RewardMgrPlugin) CalcEpochReward(blockHash common.Hash, head *types.H
 		return nil, nil, err
 	}
 
-	epochTotalReward := new(big.Int)
 	// If the expected increase issue time is exceeded,
 	// the increase issue time will be postponed for one settlement cycle,
 	// and the remaining rewards will all be issued in the next settlement cycle
-	remainEpoch := 1
+	epochTotalReward, remainReward, remainEpoch := calcEpochTotalReward(remainReward, head.Time.Int64(), incIssuanceTime, avgPackTime, epochBlocks)
 	if head.Time.Int64() >= incIssuanceTime {
-		epochTotalReward.Add(epochTotalReward, remainReward)
-		remainReward = new(big.Int)
 		log.Info("Call CalcEpochReward, The current time has exceeded the expected additional issue time", "currBlockNumber", head.Number, "currBlockHash", blockHash,
 			"currBlockTime", head.Time.Int64(), "incIssuanceTime", incIssuanceTime, "epochTotalReward", epochTotalReward)
 	} else {
-		remainTime := incIssuanceTime - head.Time.Int64()
-
-		remainBlocks := math.Ceil(float64(remainTime) / float64(avgPackTime))
-		if remainBlocks > float64(epochBlocks) {
-			remainEpoch = int(math.Ceil(remainBlocks / float64(epochBlocks)))
-		}
-		epochTotalReward = new(big.Int).Div(remainReward, new(big.Int).SetInt64(int64(remainEpoch)))
-		// Subtract the total reward for the next cycle to calculate the remaining rewards to be issued
-		remainReward = remainReward.Sub(remainReward, epochTotalReward)
 		log.Debug("Call CalcEpochReward, Calculation of rewards for the next settlement cycle", "currBlockNumber", head.Number, "currBlockHash", blockHash,
-			"currBlockTime", head.Time.Int64(), "incIssuanceTime", incIssuanceTime, "remainTime", remainTime, "remainBlocks", remainBlocks, "epochBlocks", epochBlocks,
+			"currBlockTime", head.Time.Int64(), "incIssuanceTime", incIssuanceTime, "avgPackTime", avgPackTime, "epochBlocks", epochBlocks,
 			"remainEpoch", remainEpoch, "remainReward", remainReward, "epochTotalReward", epochTotalReward)
 	}
 	// If the last settlement cycle is left, record the increaseIssuance block height
@@ -796,13 +799,11 @@ func (rmp *RewardMgrPlugin) CalcEpochReward(blockHash common.Hash, head *types.H
 			"epochBlocks", epochBlocks, "incIssuanceNumber", incIssuanceNumber)
 	}
 	// Get the total block reward and staking reward for each settlement cycle
-	epochTotalNewBlockReward := percentageCalculation(epochTotalReward, xcom.NewBlockRewardRate())
-	epochTotalStakingReward := new(big.Int).Sub(epochTotalReward, epochTotalNewBlockReward)
+	newBlockReward, epochTotalStakingReward := splitEpochReward(epochTotalReward, epochBlocks)
 	if err := StorageRemainingReward(blockHash, rmp.db, remainReward); nil != err {
 		log.Error("Failed to execute CalcEpochReward function", "currentBlockNumber", head.Number, "currentBlockHash", blockHash.TerminalString(), "err", err)
 		return nil, nil, err
 	}
-	newBlockReward := new(big.Int).Div(epochTotalNewBlockReward, new(big.Int).SetInt64(int64(epochBlocks)))
 	if err := StorageNewBlockReward(blockHash, rmp.db, newBlockReward); nil != err {
 		log.Error("Failed to execute CalcEpochReward function", "currentBlockNumber", head.Number, "currentBlockHash", blockHash.TerminalString(), "err", err)
 		return nil, nil, err
@@ -812,11 +813,37 @@ func (rmp *RewardMgrPlugin) CalcEpochReward(blockHash common.Hash, head *types.H
 		return nil, nil, err
 	}
 	log.Debug("Call CalcEpochReward, Cycle reward", "currBlockNumber", head.Number, "currBlockHash", blockHash, "currBlockTime", head.Time.Int64(),
-		"epochTotalReward", epochTotalReward, "newBlockRewardRate", xcom.NewBlockRewardRate(), "epochTotalNewBlockReward", epochTotalNewBlockReward,
+		"epochTotalReward", epochTotalReward, "newBlockRewardRate", xcom.NewBlockRewardRate(),
 		"epochTotalStakingReward", epochTotalStakingReward, "epochBlocks", epochBlocks, "newBlockReward", newBlockReward)
 	return newBlockReward, epochTotalStakingReward, nil
 }
 
+// calcEpochTotalReward divides the remaining reward of the year into the settlement
+// cycles left before the increase issuance time. It returns the total reward of the
+// next settlement cycle, the remaining reward after it and the number of the cycles left.
+func calcEpochTotalReward(remainReward *big.Int, currTime, incIssuanceTime int64, avgPackTime, epochBlocks uint64) (*big.Int, *big.Int, int) {
+	if currTime >= incIssuanceTime {
+		return new(big.Int).Set(remainReward), new(big.Int), 1
+	}
+	remainEpoch := 1
+	remainTime := incIssuanceTime - currTime
+	remainBlocks := math.Ceil(float64(remainTime) / float64(avgPackTime))
+	if remainBlocks > float64(epochBlocks) {
+		remainEpoch = int(math.Ceil(remainBlocks / float64(epochBlocks)))
+	}
+	epochTotalReward := new(big.Int).Div(remainReward, new(big.Int).SetInt64(int64(remainEpoch)))
+	return epochTotalReward, new(big.Int).Sub(remainReward, epochTotalReward), remainEpoch
+}
+
+// splitEpochReward returns the reward of each new block and the total staking reward
+// of a settlement cycle.
+func splitEpochReward(epochTotalReward *big.Int, epochBlocks uint64) (*big.Int, *big.Int) {
+	epochTotalNewBlockReward := percentageCalculation(epochTotalReward, xcom.NewBlockRewardRate())
+	epochTotalStakingReward := new(big.Int).Sub(epochTotalReward, epochTotalNewBlockReward)
+	newBlockReward := new(big.Int).Div(epochTotalNewBlockReward, new(big.Int).SetInt64(int64(epochBlocks)))
+	return newBlockReward, epochTotalStakingReward
+}
+
 func StorageYearStartTime(hash common.Hash, snapshotDB snapshotdb.DB, blockNumber uint64, yearStartTime int64) error {
 	if blockNumber > 0 {
 		if err := snapshotDB.Put(hash, reward.YearStartBlockNumberKey, common.Uint64ToBytes(blockNumber)); nil != err {
@@ -0,0 +1,269 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package plugin
+
+import (
+	"math/big"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
+	"github.com/AlayaNetwork/Alaya-Go/common/vm"
+	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/log"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+	"github.com/AlayaNetwork/Alaya-Go/params"
+	"github.com/AlayaNetwork/Alaya-Go/x/gov"
+	"github.com/AlayaNetwork/Alaya-Go/x/reward"
+	"github.com/AlayaNetwork/Alaya-Go/x/staking"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
+	"github.com/AlayaNetwork/Alaya-Go/x/xutil"
+)
+
+// MaxProjectionEpochs is the max number of the epochs of a reward projection
+const MaxProjectionEpochs = 1000
+
+// projectedEpoch is the expected reward of an epoch after the block the projection starts from
+type projectedEpoch struct {
+	epoch uint64
+	// the number of the blocks left in the epoch
+	blocks         uint64
+	newBlockReward *big.Int
+	stakingReward  *big.Int
+	// the epoch begins after the block, the candidates need to be prepared for it
+	begin bool
+}
+
+// projectEpochRewards replays the calculation of CalcEpochReward and the increase issuance
+// for the next epochs without writing anything. The blocks are expected to be produced at
+// the average pack time of the block, and all the reward of an epoch is expected to be paid
+// out of the reward pool.
+func (rmp *RewardMgrPlugin) projectEpochRewards(blockHash common.Hash, head *types.Header, epochs uint64, state xcom.StateDB) ([]*projectedEpoch, error) {
+	newBlockReward, err := LoadNewBlockReward(blockHash, rmp.db)
+	if nil != err {
+		return nil, err
+	}
+	stakingReward, err := LoadStakingReward(blockHash, rmp.db)
+	if nil != err {
+		return nil, err
+	}
+	remainReward, err := LoadRemainingReward(blockHash, rmp.db)
+	if nil != err {
+		return nil, err
+	}
+	incIssuanceTime, err := xcom.LoadIncIssuanceTime(blockHash, rmp.db)
+	if nil != err {
+		return nil, err
+	}
+	incIssuanceNumber, err := xcom.LoadIncIssuanceNumber(blockHash, rmp.db)
+	if nil != err {
+		return nil, err
+	}
+	avgPackTime, err := xcom.LoadAvgPackTime(blockHash, rmp.db)
+	if nil != err {
+		return nil, err
+	}
+	if avgPackTime == 0 {
+		avgPackTime = xcom.Interval() * uint64(millisecond)
+	}
+	yearNumber, err := LoadChainYearNumber(blockHash, rmp.db)
+	if nil != err {
+		return nil, err
+	}
+	increaseIssuanceRatio, err := gov.GovernIncreaseIssuanceRatio(head.Number.Uint64(), blockHash)
+	if nil != err {
+		return nil, err
+	}
+
+	var (
+		blockNumber  = head.Number.Uint64()
+		epochBlocks  = xutil.CalcBlocksEachEpoch()
+		histIssuance = GetHistoryCumulativeIssue(state, yearNumber)
+		poolBalance  = new(big.Int).Set(state.GetBalance(vm.RewardManagerPoolAddr))
+		epoch        = xutil.CalculateEpoch(blockNumber)
+		list         = make([]*projectedEpoch, 0, epochs)
+	)
+	// The reward of the next epoch has been calculated at the end of the epoch
+	if xutil.IsEndOfEpoch(blockNumber) {
+		epoch++
+	}
+	for i := uint64(0); i < epochs; i, epoch = i+1, epoch+1 {
+		endNumber := (epoch - 1) * epochBlocks
+		if endNumber < blockNumber {
+			list = append(list, &projectedEpoch{epoch: epoch, blocks: epoch*epochBlocks - blockNumber, newBlockReward: newBlockReward, stakingReward: stakingReward})
+			continue
+		}
+		if endNumber > blockNumber {
+			last := list[len(list)-1]
+			paid := new(big.Int).Mul(last.newBlockReward, new(big.Int).SetUint64(last.blocks))
+			paid.Add(paid, last.stakingReward)
+			if poolBalance.Sub(poolBalance, paid).Sign() < 0 {
+				poolBalance.SetInt64(0)
+			}
+			if endNumber == incIssuanceNumber {
+				currIssuance := new(big.Int).Mul(histIssuance, big.NewInt(int64(increaseIssuanceRatio)))
+				currIssuance.Div(currIssuance, big.NewInt(10000))
+				histIssuance = new(big.Int).Add(histIssuance, currIssuance)
+				poolBalance.Add(poolBalance, percentageCalculation(currIssuance, uint64(RewardPoolIncreaseRate)))
+				remainReward = new(big.Int).Set(poolBalance)
+				incIssuanceTime += int64(xcom.AdditionalCycleTime() * uint64(minutes))
+			}
+			endTime := head.Time.Int64() + int64((endNumber-blockNumber)*avgPackTime)
+			var epochTotalReward *big.Int
+			var remainEpoch int
+			epochTotalReward, remainReward, remainEpoch = calcEpochTotalReward(remainReward, endTime, incIssuanceTime, avgPackTime, epochBlocks)
+			if remainEpoch == 1 {
+				incIssuanceNumber = endNumber + epochBlocks
+			}
+			newBlockReward, stakingReward = splitEpochReward(epochTotalReward, epochBlocks)
+		}
+		list = append(list, &projectedEpoch{epoch: epoch, blocks: epochBlocks, newBlockReward: newBlockReward, stakingReward: stakingReward, begin: true})
+	}
+	return list, nil
+}
+
+// projectNodeRewards returns the expected reward of the node in the epochs and the delegate
+// reward per of the epochs in which the delegators are rewarded. The verifiers are expected
+// to stay the same and to share the blocks and the staking reward equally.
+func (rmp *RewardMgrPlugin) projectNodeRewards(blockHash common.Hash, nodeID discover.NodeID, stakingNum uint64, verifiers *staking.ValidatorArray,
+	epochs []*projectedEpoch) ([]*reward.EpochRewardProjection, []*reward.DelegateRewardPer, error) {
+	var can *staking.CandidateMutable
+	for _, v := range verifiers.Arr {
+		if v.NodeId == nodeID && v.StakingBlockNum == stakingNum {
+			mutable, err := rmp.stakingPlugin.GetCanMutable(blockHash, v.NodeAddress)
+			if snapshotdb.NonDbNotFoundErr(err) {
+				return nil, nil, err
+			}
+			can = mutable
+			break
+		}
+	}
+
+	var (
+		projections   = make([]*reward.EpochRewardProjection, 0, len(epochs))
+		pers          = make([]*reward.DelegateRewardPer, 0, len(epochs))
+		verifierCount = big.NewInt(int64(len(verifiers.Arr)))
+	)
+	for _, e := range epochs {
+		projection := &reward.EpochRewardProjection{
+			Epoch:                e.epoch,
+			NodeReward:           (*hexutil.Big)(new(big.Int)),
+			DelegateReward:       (*hexutil.Big)(new(big.Int)),
+			DelegateTotal:        (*hexutil.Big)(new(big.Int)),
+			DelegateRewardPerATP: (*hexutil.Big)(new(big.Int)),
+			DelegatorIncome:      (*hexutil.Big)(new(big.Int)),
+		}
+		projections = append(projections, projection)
+		// The node is not a verifier, or the delegation is on a previous staking of the node
+		if can == nil || can.IsEmpty() {
+			continue
+		}
+		if e.begin {
+			prepareEpochCandidate(e.epoch, can)
+		}
+
+		totalReward := new(big.Int).Mul(e.newBlockReward, new(big.Int).SetUint64(e.blocks))
+		totalReward.Div(totalReward, verifierCount)
+		totalReward.Add(totalReward, new(big.Int).Div(e.stakingReward, verifierCount))
+		delegateReward, nodeReward := new(big.Int), totalReward
+		if can.ShouldGiveDelegateReward() {
+			delegateReward, nodeReward = rmp.CalDelegateRewardAndNodeReward(totalReward, can.RewardPer)
+		}
+		if !e.begin {
+			delegateReward.Add(delegateReward, can.CurrentEpochDelegateReward)
+		}
+		projection.RewardPer = can.RewardPer
+		projection.NodeReward = (*hexutil.Big)(nodeReward)
+		projection.DelegateReward = (*hexutil.Big)(delegateReward)
+		projection.DelegateTotal = (*hexutil.Big)(new(big.Int).Set(can.DelegateTotal))
+		if delegateReward.Sign() > 0 && can.DelegateTotal.Sign() > 0 {
+			per := reward.NewDelegateRewardPer(e.epoch, delegateReward, new(big.Int).Set(can.DelegateTotal))
+			projection.DelegateRewardPerATP = (*hexutil.Big)(per.CalDelegateReward(big.NewInt(params.ATP)))
+			pers = append(pers, per)
+		}
+	}
+	return projections, pers, nil
+}
+
+// ProjectDelegateReward projects the reward of the nodes the account delegates to and the
+// income of the account in the next epochs after the block, the pending change of the
+// delegate reward percent and the delegations in hesitation are taken into account.
+// Nothing is written to the state or the snapshotdb.
+func (rmp *RewardMgrPlugin) ProjectDelegateReward(blockHash common.Hash, head *types.Header, account common.Address, nodes []discover.NodeID,
+	epochs uint64, state xcom.StateDB) ([]*reward.NodeDelegateRewardProjection, error) {
+	log.Debug("Call RewardMgrPlugin: project delegate reward begin", "account", account, "nodes", nodes, "num", head.Number, "epochs", epochs)
+
+	dls, err := rmp.getDelegatesInfo(blockHash, account, nodes)
+	if err != nil {
+		return nil, err
+	}
+	projectedEpochs, err := rmp.projectEpochRewards(blockHash, head, epochs, state)
+	if err != nil {
+		return nil, err
+	}
+	blockNumber := head.Number.Uint64()
+	if xutil.IsEndOfEpoch(blockNumber) {
+		blockNumber++
+	}
+	verifiers, err := rmp.stakingPlugin.getVerifierList(blockHash, blockNumber, QueryStartNotIrr)
+	if err != nil {
+		return nil, err
+	}
+
+	firstEpoch, lastEpoch := projectedEpochs[0].epoch, projectedEpochs[len(projectedEpochs)-1].epoch
+	projections := make([]*reward.NodeDelegateRewardProjection, 0, len(dls))
+	for _, dl := range dls {
+		epochRewards, projectedPers, err := rmp.projectNodeRewards(blockHash, dl.NodeID, dl.StakeBlockNumber, verifiers, projectedEpochs)
+		if err != nil {
+			return nil, err
+		}
+		pers, err := rmp.GetDelegateRewardPerList(blockHash, dl.NodeID, dl.StakeBlockNumber, uint64(dl.Delegation.DelegateEpoch), firstEpoch-1)
+		if err != nil {
+			return nil, err
+		}
+		projectedPer := make(map[uint64]*reward.DelegateRewardPer, len(projectedPers))
+		for _, per := range projectedPers {
+			projectedPer[per.Epoch] = per
+		}
+
+		del := dl.Delegation
+		if del.CumulativeIncome == nil {
+			del.CumulativeIncome = new(big.Int)
+		}
+		income := new(big.Int)
+		receipts := calcDelegateIncome(lastEpoch+1, del, append(pers, projectedPers...))
+		for _, receipt := range receipts {
+			if per, ok := projectedPer[receipt.Epoch]; ok {
+				delegatorIncome := per.CalDelegateReward(receipt.Delegate)
+				epochRewards[receipt.Epoch-firstEpoch].DelegatorIncome = (*hexutil.Big)(delegatorIncome)
+				income.Add(income, delegatorIncome)
+			}
+		}
+		// The income of the epochs before is not withdrawn yet
+		pending := new(big.Int).Sub(del.CumulativeIncome, income)
+
+		projections = append(projections, &reward.NodeDelegateRewardProjection{
+			NodeID:     dl.NodeID,
+			StakingNum: dl.StakeBlockNumber,
+			Reward:     (*hexutil.Big)(pending),
+			Income:     (*hexutil.Big)(income),
+			Epochs:     epochRewards,
+		})
+	}
+	log.Debug("Call RewardMgrPlugin: project delegate reward end", "account", account, "num", head.Number, "firstEpoch", firstEpoch, "lastEpoch", lastEpoch)
+	return projections, nil
+}
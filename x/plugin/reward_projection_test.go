@@ -0,0 +1,95 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package plugin
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/mock"
+	"github.com/AlayaNetwork/Alaya-Go/common/vm"
+	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
+	"github.com/AlayaNetwork/Alaya-Go/core/types"
+	"github.com/AlayaNetwork/Alaya-Go/x/gov"
+	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
+	"github.com/AlayaNetwork/Alaya-Go/x/xutil"
+)
+
+func TestRewardMgrPlugin_ProjectEpochRewards(t *testing.T) {
+	chain := mock.NewChain()
+	defer chain.SnapDB.Clear()
+	xcom.GetEc(xcom.DefaultTestNet)
+	interval := big.NewInt(int64(xcom.Interval() * uint64(millisecond)))
+	chain.SetHeaderTimeGenerate(func(b *big.Int) *big.Int {
+		return new(big.Int).Add(b, interval)
+	})
+	snapshotdb.SetDBBlockChain(chain)
+	if _, err := gov.InitGenesisGovernParam(common.ZeroHash, chain.SnapDB, 2048); err != nil {
+		t.Fatal(err)
+	}
+
+	yearBalance := big.NewInt(1e18)
+	SetYearEndCumulativeIssue(chain.StateDB, 0, yearBalance)
+	SetYearEndBalance(chain.StateDB, 0, yearBalance)
+	chain.StateDB.AddBalance(vm.RewardManagerPoolAddr, yearBalance)
+
+	var (
+		plugin                          = &RewardMgrPlugin{db: chain.SnapDB}
+		packageReward, stakingReward    *big.Int
+		projected                       []*projectedEpoch
+		epochs                          = uint64(3)
+		newBlockRewards, stakingRewards []*big.Int
+	)
+	for i := uint64(0); i < epochs*xutil.CalcBlocksEachEpoch(); i++ {
+		if err := chain.AddBlockWithSnapDB(true, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
+			var err error
+			if header.Number.Uint64() == 1 {
+				packageReward, stakingReward, err = plugin.CalcEpochReward(hash, header, chain.StateDB)
+				newBlockRewards, stakingRewards = append(newBlockRewards, packageReward), append(stakingRewards, stakingReward)
+				return err
+			}
+			chain.StateDB.SubBalance(vm.RewardManagerPoolAddr, packageReward)
+			if xutil.IsEndOfEpoch(header.Number.Uint64()) {
+				chain.StateDB.SubBalance(vm.RewardManagerPoolAddr, stakingReward)
+				packageReward, stakingReward, err = plugin.CalcEpochReward(hash, header, chain.StateDB)
+				newBlockRewards, stakingRewards = append(newBlockRewards, packageReward), append(stakingRewards, stakingReward)
+			}
+			return err
+		}, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			var err error
+			if projected, err = plugin.projectEpochRewards(chain.CurrentHeader().Hash(), chain.CurrentHeader(), epochs, chain.StateDB); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if uint64(len(projected)) != epochs {
+		t.Fatalf("want %d projected epochs, have %d", epochs, len(projected))
+	}
+	if projected[0].epoch != 1 || projected[0].blocks != xutil.CalcBlocksEachEpoch()-1 || projected[0].begin {
+		t.Fatalf("the first epoch not right: %+v", projected[0])
+	}
+	for i, e := range projected {
+		if e.newBlockReward.Cmp(newBlockRewards[i]) != 0 || e.stakingReward.Cmp(stakingRewards[i]) != 0 {
+			t.Errorf("epoch %d: want %d %d, have %d %d", e.epoch, newBlockRewards[i], stakingRewards[i], e.newBlockReward, e.stakingReward)
+		}
+	}
+}
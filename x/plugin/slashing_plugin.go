@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"github.com/AlayaNetwork/Alaya-Go/rlp"
 	"math/big"
+	"sort"
 	"sync"
 
 	"github.com/AlayaNetwork/Alaya-Go/x/gov"
@@ -168,6 +169,7 @@ func (sp *SlashingPlugin) BeginBlock(blockHash common.Hash, header *types.Header
 					log.Error("Failed to BeginBlock, call SlashCandidates is failed", "blockNumber", header.Number.Uint64(), "blockHash", blockHash.TerminalString(), "err", err)
 					return err
 				}
+				addSlashingEvents(blockHash, header.Number.Uint64(), nil, slashQueue...)
 			}
 
 		}
@@ -489,9 +491,13 @@ func (sp *SlashingPlugin) switchEpoch(blockNumber uint64, blockHash common.Hash)
 
 // Get the consensus rate of all nodes in the previous round
 func (sp *SlashingPlugin) GetPrePackAmount(blockNumber uint64, parentHash common.Hash) (map[discover.NodeID]uint32, error) {
+	return sp.getRoundPackAmount(xutil.CalculateRound(blockNumber)-1, parentHash)
+}
+
+func (sp *SlashingPlugin) getRoundPackAmount(round uint64, blockHash common.Hash) (map[discover.NodeID]uint32, error) {
 	result := make(map[discover.NodeID]uint32)
-	prefixKey := buildPrefixByRound(xutil.CalculateRound(blockNumber) - 1)
-	iter := sp.db.Ranking(parentHash, prefixKey, 0)
+	prefixKey := buildPrefixByRound(round)
+	iter := sp.db.Ranking(blockHash, prefixKey, 0)
 
 	if err := iter.Error(); nil != err {
 		return nil, err
@@ -510,6 +516,135 @@ func (sp *SlashingPlugin) GetPrePackAmount(blockNumber uint64, parentHash common
 	return result, nil
 }
 
+// Get the block production of the validators of the round which the block belongs to,
+// and estimate the risk of the slashing of the zero-production blocks of the nodes
+func (sp *SlashingPlugin) GetProduceStatus(blockNumber uint64, blockHash common.Hash) (*slashing.RoundProduceStatus, error) {
+	zeroProduceNumberThreshold, err := gov.GovernZeroProduceNumberThreshold(blockNumber, blockHash)
+	if nil != err {
+		return nil, err
+	}
+	zeroProduceCumulativeTime, err := gov.GovernZeroProduceCumulativeTime(blockNumber, blockHash)
+	if nil != err {
+		return nil, err
+	}
+	currRoundVal, err := stk.getCurrValList(blockHash, blockNumber, QueryStartNotIrr)
+	if nil != err {
+		return nil, err
+	}
+	round := xutil.CalculateRound(blockNumber)
+	packAmount, err := sp.getRoundPackAmount(round, blockHash)
+	if nil != err {
+		return nil, err
+	}
+	waitSlashingNodeList, err := sp.getWaitSlashingNodeList(blockNumber, blockHash)
+	if nil != err {
+		return nil, err
+	}
+	zeroRounds := make(map[discover.NodeID][]uint64)
+	for _, node := range waitSlashingNodeList {
+		zeroRounds[node.NodeId] = node.zeroRounds()
+	}
+	// The previous round is recorded at the election block of the round,
+	// the zero-production rounds of a node are cleared once it produces blocks
+	if round > 1 && blockNumber < round*xutil.ConsensusSize()-xcom.ElectionDistance() {
+		preRoundVal, err := stk.getPreValList(blockHash, blockNumber, QueryStartNotIrr)
+		if nil != err {
+			return nil, err
+		}
+		prePackAmount, err := sp.getRoundPackAmount(round-1, blockHash)
+		if nil != err {
+			return nil, err
+		}
+		for _, validator := range preRoundVal.Arr {
+			if prePackAmount[validator.NodeId] > 0 || packAmount[validator.NodeId] > 0 {
+				delete(zeroRounds, validator.NodeId)
+			} else {
+				zeroRounds[validator.NodeId] = append(zeroRounds[validator.NodeId], round-1)
+			}
+		}
+	}
+
+	result := &slashing.RoundProduceStatus{
+		BlockNumber:                blockNumber,
+		Round:                      round,
+		Start:                      currRoundVal.Start,
+		End:                        currRoundVal.End,
+		ZeroProduceNumberThreshold: zeroProduceNumberThreshold,
+		ZeroProduceCumulativeTime:  zeroProduceCumulativeTime,
+		Nodes:                      make([]*slashing.ProduceStatus, 0, len(currRoundVal.Arr)),
+	}
+	expected := (currRoundVal.End - currRoundVal.Start + 1) / uint64(len(currRoundVal.Arr))
+	for i, validator := range currRoundVal.Arr {
+		status := &slashing.ProduceStatus{
+			NodeId:    validator.NodeId,
+			Validator: true,
+			Index:     uint32(i),
+			Expected:  expected,
+			Produced:  packAmount[validator.NodeId],
+			TurnEnd:   currRoundVal.Start + uint64(i+1)*expected - 1,
+		}
+		estimateZeroProduce(status, round, zeroRounds[validator.NodeId], zeroProduceNumberThreshold, zeroProduceCumulativeTime)
+		delete(zeroRounds, validator.NodeId)
+		result.Nodes = append(result.Nodes, status)
+	}
+	others := make([]*slashing.ProduceStatus, 0, len(zeroRounds))
+	for nodeId, rounds := range zeroRounds {
+		status := &slashing.ProduceStatus{NodeId: nodeId, Produced: packAmount[nodeId]}
+		estimateZeroProduce(status, round, rounds, zeroProduceNumberThreshold, zeroProduceCumulativeTime)
+		if len(status.ZeroRounds) > 0 {
+			others = append(others, status)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool {
+		return bytes.Compare(others[i].NodeId.Bytes(), others[j].NodeId.Bytes()) < 0
+	})
+	result.Nodes = append(result.Nodes, others...)
+	return result, nil
+}
+
+// estimateZeroProduce fills the zero-production rounds of the node within the time window
+// the same way as zeroProduceProcess shifts them, and whether the node will be slashed if it
+// produces no block until the time window ends, the rounds must be in ascending order
+func estimateZeroProduce(status *slashing.ProduceStatus, round uint64, rounds []uint64, zeroProduceNumberThreshold, zeroProduceCumulativeTime uint16) {
+	status.ZeroRounds = make([]uint64, 0, len(rounds))
+	for _, r := range rounds {
+		// Shifted out of the time window when the previous round is recorded
+		if r+uint64(zeroProduceCumulativeTime) <= round-1 {
+			continue
+		}
+		status.ZeroRounds = append(status.ZeroRounds, r)
+	}
+	if status.Produced > 0 {
+		status.ZeroRounds = status.ZeroRounds[:0]
+		return
+	}
+	count := len(status.ZeroRounds)
+	first := round
+	if count > 0 {
+		first = status.ZeroRounds[0]
+	}
+	status.SlashRound = first + uint64(zeroProduceCumulativeTime) - 1
+	// The round will be recorded if the validator produces no block in it
+	if status.Validator && round <= status.SlashRound {
+		count++
+	}
+	status.AtRisk = count >= int(zeroProduceNumberThreshold)
+	if count == 0 {
+		status.SlashRound = 0
+	}
+}
+
+// zeroRounds returns the zero-production rounds recorded in the count bits
+func (w *WaitSlashingNode) zeroRounds() []uint64 {
+	rounds := make([]uint64, 0)
+	for i := uint64(0); i < 64; i++ {
+		if w.CountBit&(1<<i) != 0 {
+			rounds = append(rounds, w.Round+i)
+		}
+	}
+	return rounds
+}
+
 func (sp *SlashingPlugin) DecodeEvidence(dupType consensus.EvidenceType, data string) (consensus.Evidence, error) {
 	if sp.decodeEvidence == nil {
 		return nil, common.InternalError.Wrap("decodeEvidence function is nil")
@@ -653,6 +788,7 @@ func (sp *SlashingPlugin) Slash(evidence consensus.Evidence, blockHash common.Ha
 			"nodeId", canBase.NodeId.TerminalString(), "err", err)
 		return slashing.ErrSlashingFail
 	}
+	addSlashingEvents(blockHash, blockNumber, stateDB, toCallerItem, toRewardPoolItem)
 	sp.putSlashTxHash(evidence.NodeID(), evidence.BlockNumber(), evidence.Type(), stateDB)
 	log.Info("Call Slash finished", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(),
 		"evidenceBlockNum", evidence.BlockNumber(), "nodeId", canBase.NodeId.TerminalString(), "evidenceType", evidence.Type(),
@@ -661,6 +797,15 @@ func (sp *SlashingPlugin) Slash(evidence consensus.Evidence, blockHash common.Ha
 	return nil
 }
 
+// addSlashingEvents adds an event for each slashed item, the address of the
+// event is the account receiving the slashed von. The state is nil if the
+// node is not slashed by a transaction.
+func addSlashingEvents(blockHash common.Hash, blockNumber uint64, state xcom.StateDB, queue ...*staking.SlashNodeItem) {
+	for _, item := range queue {
+		xcom.AddPPOSEvent(blockHash, blockNumber, state, xcom.NewPPOSEvent(xcom.SlashingEvent, item.NodeId, 0, item.BenefitAddr, item.Amount))
+	}
+}
+
 func (sp *SlashingPlugin) CheckDuplicateSign(nodeId discover.NodeID, blockNumber uint64, dupType consensus.EvidenceType, stateDB xcom.StateDB) ([]byte, error) {
 	if value := sp.getSlashTxHash(nodeId, blockNumber, dupType, stateDB); len(value) > 0 {
 		return value, nil
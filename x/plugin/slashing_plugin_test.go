@@ -47,6 +47,7 @@ import (
 	"github.com/AlayaNetwork/Alaya-Go/crypto"
 	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
 	"github.com/AlayaNetwork/Alaya-Go/x/staking"
+	"github.com/AlayaNetwork/Alaya-Go/x/slashing"
 	"github.com/AlayaNetwork/Alaya-Go/x/xcom"
 	"github.com/AlayaNetwork/Alaya-Go/x/xutil"
 )
@@ -881,3 +882,43 @@ func TestSlashingPlugin_ZeroProduceProcess(t *testing.T) {
 		}
 	}
 }
+
+func TestSlashingPlugin_EstimateZeroProduce(t *testing.T) {
+	node := &WaitSlashingNode{NodeId: nodeIdArr[0], Round: 3, CountBit: 5}
+	assert.Equal(t, []uint64{3, 5}, node.zeroRounds())
+
+	testCases := []struct {
+		validator  bool
+		produced   uint32
+		round      uint64
+		rounds     []uint64
+		zeroRounds []uint64
+		slashRound uint64
+		atRisk     bool
+	}{
+		// No zero-production round, the current round is counted if the validator produces no block
+		{validator: true, round: 7, zeroRounds: []uint64{}, slashRound: 10, atRisk: false},
+		{validator: true, round: 7, rounds: []uint64{5}, zeroRounds: []uint64{5}, slashRound: 8, atRisk: true},
+		// Cleared once the validator produces blocks
+		{validator: true, produced: 1, round: 7, rounds: []uint64{5, 6}, zeroRounds: []uint64{}},
+		// The round 3 is shifted out of the time window when the round 7 is recorded
+		{validator: true, round: 8, rounds: []uint64{3, 5}, zeroRounds: []uint64{5}, slashRound: 8, atRisk: true},
+		// The current round is out of the time window, which ends when the round 6 is recorded
+		{validator: true, round: 7, rounds: []uint64{3}, zeroRounds: []uint64{3}, slashRound: 6, atRisk: false},
+		{validator: true, round: 7, rounds: []uint64{3, 5}, zeroRounds: []uint64{3, 5}, slashRound: 6, atRisk: true},
+		// Not a validator of the round
+		{round: 7, rounds: []uint64{5}, zeroRounds: []uint64{5}, slashRound: 8, atRisk: false},
+		{round: 7, rounds: []uint64{5, 6}, zeroRounds: []uint64{5, 6}, slashRound: 8, atRisk: true},
+		{round: 7},
+	}
+	for i, c := range testCases {
+		status := &slashing.ProduceStatus{Validator: c.validator, Produced: c.produced}
+		estimateZeroProduce(status, c.round, c.rounds, 2, 4)
+		if c.zeroRounds == nil {
+			c.zeroRounds = []uint64{}
+		}
+		assert.Equal(t, c.zeroRounds, status.ZeroRounds, "case %d", i)
+		assert.Equal(t, c.slashRound, status.SlashRound, "case %d", i)
+		assert.Equal(t, c.atRisk, status.AtRisk, "case %d", i)
+	}
+}
@@ -122,17 +122,7 @@ func (sk *StakingPlugin) BeginBlock(blockHash common.Hash, header *types.Header,
 			if canOld.IsInvalid() {
 				continue
 			}
-			var changed bool
-			changed = lazyCalcNodeTotalDelegateAmount(xutil.CalculateEpoch(blockNumber), canOld)
-			if canOld.RewardPer != canOld.NextRewardPer {
-				canOld.RewardPer = canOld.NextRewardPer
-				changed = true
-			}
-			if canOld.CurrentEpochDelegateReward.Cmp(common.Big0) > 0 {
-				canOld.CleanCurrentEpochDelegateReward()
-				changed = true
-			}
-			if changed {
+			if changed := prepareEpochCandidate(xutil.CalculateEpoch(blockNumber), canOld); changed {
 				if err = sk.db.SetCanMutableStore(blockHash, v.NodeAddress, canOld); err != nil {
 					log.Error("Failed to editCandidate on stakingPlugin BeginBlock", "nodeAddress", v.NodeAddress.String(),
 						"blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "err", err)
@@ -495,6 +485,8 @@ func (sk *StakingPlugin) IncreaseStaking(state xcom.StateDB, blockHash common.Ha
 		return err
 	}
 
+	xcom.AddPPOSEvent(blockHash, blockNumber.Uint64(), state,
+		xcom.NewPPOSEvent(xcom.IncreaseStakingEvent, can.NodeId, can.StakingBlockNum, can.StakingAddress, amount))
 	return nil
 }
 
@@ -505,6 +497,10 @@ func (sk *StakingPlugin) WithdrewStaking(state xcom.StateDB, blockHash common.Ha
 
 	lazyCalcStakeAmount(epoch, can.CandidateMutable)
 
+	amount := new(big.Int).Add(can.Released, can.ReleasedHes)
+	amount.Add(amount, can.RestrictingPlan)
+	amount.Add(amount, can.RestrictingPlanHes)
+
 	if err := sk.db.DelCanPowerStore(blockHash, can); nil != err {
 		log.Error("Failed to WithdrewStaking on stakingPlugin: Delete Candidate old power is failed",
 			"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
@@ -541,6 +537,8 @@ func (sk *StakingPlugin) WithdrewStaking(state xcom.StateDB, blockHash common.Ha
 		return err
 	}
 
+	xcom.AddPPOSEvent(blockHash, blockNumber.Uint64(), state,
+		xcom.NewPPOSEvent(xcom.WithdrewStakingEvent, can.NodeId, can.StakingBlockNum, can.StakingAddress, amount))
 	return nil
 }
 
@@ -945,6 +943,9 @@ func (sk *StakingPlugin) Delegate(state xcom.StateDB, blockHash common.Hash, blo
 			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
 		return err
 	}
+
+	xcom.AddPPOSEvent(blockHash, blockNumber.Uint64(), state,
+		xcom.NewPPOSEvent(xcom.DelegateEvent, can.NodeId, can.StakingBlockNum, delAddr, amount))
 	return nil
 }
 
@@ -1114,6 +1115,9 @@ func (sk *StakingPlugin) WithdrewDelegation(state xcom.StateDB, blockHash common
 			return nil, err
 		}
 	}
+
+	xcom.AddPPOSEvent(blockHash, blockNumber.Uint64(), state,
+		xcom.NewPPOSEvent(xcom.WithdrewDelegationEvent, nodeId, stakingBlockNum, delAddr, realSub))
 	return issueIncome, nil
 }
 
@@ -1274,9 +1278,26 @@ func (sk *StakingPlugin) ElectNextVerifierList(blockHash common.Hash, blockNumbe
 	log.Debug("Call ElectNextVerifierList  Next verifiers", "blockNumber", blockNumber, "blockHash", blockHash.Hex(),
 		"list length", len(queue), "list", newVerifierArr)
 
+	addElectionEvent(xcom.ElectVerifiersEvent, blockHash, blockNumber, queue)
 	return nil
 }
 
+// addElectionEvent adds an event of the elected verifiers of the next epoch or
+// validators of the next round, the amount is the total shares of the nodes.
+func addElectionEvent(typ string, blockHash common.Hash, blockNumber uint64, queue staking.ValidatorQueue) {
+	ev := xcom.NewPPOSEvent(typ, discover.NodeID{}, 0, common.Address{}, nil)
+	total := new(big.Int)
+	ev.Nodes = make([]discover.NodeID, len(queue))
+	for i, v := range queue {
+		ev.Nodes[i] = v.NodeId
+		if v.Shares != nil {
+			total.Add(total, v.Shares)
+		}
+	}
+	ev.Amount = (*hexutil.Big)(total)
+	xcom.AddPPOSEvent(blockHash, blockNumber, nil, ev)
+}
+
 func (sk *StakingPlugin) GetVerifierCandidateInfo(blockHash common.Hash, blockNumber uint64) ([]*staking.Candidate, error) {
 	verifierList, err := sk.getVerifierList(blockHash, blockNumber, false)
 	if nil != err {
@@ -2068,6 +2089,7 @@ func (sk *StakingPlugin) Election(blockHash common.Hash, header *types.Header, s
 	log.Debug("Call Election Next validators", "blockNumber", header.Number.Uint64(), "blockHash", blockHash.Hex(),
 		"list length", len(next.Arr), "list", next)
 
+	addElectionEvent(xcom.ElectValidatorsEvent, blockHash, blockNumber, nextQueue)
 	return nil
 }
 
@@ -2692,6 +2714,18 @@ func (sk *StakingPlugin) GetValidator(blockNumber uint64) (*cbfttypes.Validators
 	return nil, fmt.Errorf("Not Found Validators by blockNumber: %d", blockNumber)
 }
 
+// GetHistoryValidator returns the consensus validators of the round which the
+// block belongs to, read from the staking state of the block. The state of the
+// past blocks is kept by the archive of the snapshotdb, without it only the
+// rounds still indexed by the current state are found.
+func (sk *StakingPlugin) GetHistoryValidator(blockHash common.Hash, blockNumber uint64) (*cbfttypes.Validators, error) {
+	valArr, err := sk.getCurrValList(blockHash, blockNumber, QueryStartNotIrr)
+	if nil != err {
+		return nil, err
+	}
+	return buildCbftValidators(valArr.Start, valArr.Arr), nil
+}
+
 // NOTE: Verify that it is the validator of the current Epoch
 func (sk *StakingPlugin) IsCandidateNode(nodeID discover.NodeID) bool {
 
@@ -2778,6 +2812,21 @@ func lazyCalcStakeAmount(epoch uint64, can *staking.CandidateMutable) {
 
 }
 
+// prepareEpochCandidate adjusts the candidate of a verifier at the begin of the epoch,
+// the delegations in hesitation become effective and the new rewardPer takes effect.
+func prepareEpochCandidate(epoch uint64, can *staking.CandidateMutable) bool {
+	changed := lazyCalcNodeTotalDelegateAmount(epoch, can)
+	if can.RewardPer != can.NextRewardPer {
+		can.RewardPer = can.NextRewardPer
+		changed = true
+	}
+	if can.CurrentEpochDelegateReward.Cmp(common.Big0) > 0 {
+		can.CleanCurrentEpochDelegateReward()
+		changed = true
+	}
+	return changed
+}
+
 // The total delegate amount of the compute node
 func lazyCalcNodeTotalDelegateAmount(epoch uint64, can *staking.CandidateMutable) bool {
 	if can.IsEmpty() {
@@ -1639,6 +1639,17 @@ func TestStakingPlugin_Delegate(t *testing.T) {
 		return
 	}
 
+	// The delegation is added to the events of the block
+	events := sndb.Events(blockHash2)
+	if assert.Len(t, events, 1) {
+		ev := events[0].(*xcom.PPOSEvent)
+		assert.Equal(t, xcom.DelegateEvent, ev.Type)
+		assert.Equal(t, blockNumber2.Uint64(), ev.BlockNumber)
+		assert.Equal(t, can.NodeId, ev.NodeId)
+		assert.Equal(t, addrArr[index+1], ev.Address)
+		assert.Equal(t, del.ReleasedHes, ev.Amount.ToInt())
+	}
+
 	if err := sndb.Commit(blockHash2); nil != err {
 		t.Error("Commit 2 err", err)
 		return
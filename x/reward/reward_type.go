@@ -123,3 +123,30 @@ type DelegateRewardReceipt struct {
 	Delegate *big.Int
 	Epoch    uint64
 }
+
+// EpochRewardProjection is the projected reward of a node and the delegator in an epoch
+type EpochRewardProjection struct {
+	Epoch uint64 `json:"epoch"`
+	// the delegate reward amount percent of the node in the epoch
+	RewardPer uint16 `json:"rewardPer"`
+	// the expected reward of the node, the block reward and the staking reward not given to the delegators
+	NodeReward *hexutil.Big `json:"nodeReward"`
+	// the expected reward of all the delegators of the node
+	DelegateReward *hexutil.Big `json:"delegateReward"`
+	// the effective delegate amount of the node
+	DelegateTotal *hexutil.Big `json:"delegateTotal"`
+	// the expected delegate reward of 1 ATP effective delegation
+	DelegateRewardPerATP *hexutil.Big `json:"delegateRewardPerATP"`
+	// the expected income of the delegator
+	DelegatorIncome *hexutil.Big `json:"delegatorIncome"`
+}
+
+type NodeDelegateRewardProjection struct {
+	NodeID     discover.NodeID `json:"nodeID"`
+	StakingNum uint64          `json:"stakingNum"`
+	// the reward not withdrawn yet, see NodeDelegateRewardPresenter
+	Reward *hexutil.Big `json:"reward"`
+	// the projected income of the delegator in all the epochs
+	Income *hexutil.Big             `json:"income"`
+	Epochs []*EpochRewardProjection `json:"epochs"`
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package slashing
+
+import "github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+
+// The block production of a node in a consensus round, and the estimated risk
+// of the slashing of the zero-production blocks
+type ProduceStatus struct {
+	NodeId discover.NodeID `json:"nodeId"`
+	// Whether the node is a validator of the round
+	Validator bool `json:"validator"`
+	// The proposer index of the validator in the round
+	Index uint32 `json:"index"`
+	// The number of the blocks the validator is expected to produce in the round
+	Expected uint64 `json:"expected"`
+	// The number of the blocks produced in the round so far
+	Produced uint32 `json:"produced"`
+	// The last block of the turn of the validator if there is no view change in the round
+	TurnEnd uint64 `json:"turnEnd"`
+	// The zero-production rounds within the time window of the slashing, the previous round is
+	// included before it is recorded at the election block of the round
+	ZeroRounds []uint64 `json:"zeroRounds"`
+	// The round the time window ends at, the node is slashed at the election block of the next
+	// round if the number of the zero-production rounds reaches the threshold
+	SlashRound uint64 `json:"slashRound"`
+	// The number of the zero-production rounds reaches the threshold in the time window
+	// if the node produces no block until the time window ends
+	AtRisk bool `json:"atRisk"`
+}
+
+// The block production of the validators of a consensus round
+type RoundProduceStatus struct {
+	BlockNumber uint64 `json:"blockNumber"`
+	Round       uint64 `json:"round"`
+	// The first and the last block of the round
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
+	// The governance parameters of the slashing of the zero-production blocks
+	ZeroProduceNumberThreshold uint16 `json:"zeroProduceNumberThreshold"`
+	ZeroProduceCumulativeTime  uint16 `json:"zeroProduceCumulativeTime"`
+	// The validators of the round ordered by the proposer index, followed by the other nodes with zero-production rounds
+	Nodes []*ProduceStatus `json:"nodes"`
+}
+
+// Status returns the block production of the node, nil if it isn't a validator and has no zero-production round
+func (r *RoundProduceStatus) Status(nodeId discover.NodeID) *ProduceStatus {
+	for _, s := range r.Nodes {
+		if s.NodeId == nodeId {
+			return s
+		}
+	}
+	return nil
+}
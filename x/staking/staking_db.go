@@ -20,7 +20,6 @@ package staking
 import (
 	"fmt"
 
-	"github.com/syndtr/goleveldb/leveldb/iterator"
 
 	"github.com/AlayaNetwork/Alaya-Go/common"
 	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
@@ -64,7 +63,7 @@ func (db *StakingDB) del(blockHash common.Hash, key []byte) error {
 	return db.db.Del(blockHash, key)
 }
 
-func (db *StakingDB) ranking(blockHash common.Hash, prefix []byte, ranges int) iterator.Iterator {
+func (db *StakingDB) ranking(blockHash common.Hash, prefix []byte, ranges int) snapshotdb.Iterator {
 	return db.db.Ranking(blockHash, prefix, ranges)
 }
 func (db *StakingDB) Del(blockHash common.Hash, key []byte) error {
@@ -686,11 +685,11 @@ func (db *StakingDB) DelRoundValListByBlockHash(blockHash common.Hash, start, en
 
 // iterator ...
 
-func (db *StakingDB) IteratorCandidatePowerByBlockHash(blockHash common.Hash, ranges int) iterator.Iterator {
+func (db *StakingDB) IteratorCandidatePowerByBlockHash(blockHash common.Hash, ranges int) snapshotdb.Iterator {
 	return db.ranking(blockHash, CanPowerKeyPrefix, ranges)
 }
 
-func (db *StakingDB) IteratorDelegateByBlockHashWithAddr(blockHash common.Hash, addr common.Address, ranges int) iterator.Iterator {
+func (db *StakingDB) IteratorDelegateByBlockHashWithAddr(blockHash common.Hash, addr common.Address, ranges int) snapshotdb.Iterator {
 	prefix := append(DelegateKeyPrefix, addr.Bytes()...)
 	return db.ranking(blockHash, prefix, ranges)
 }
@@ -207,7 +207,10 @@ func GetEce() *EconomicModelExtend {
 	return ece
 }
 
+// ResetEconomicDefaultConfig replaces the global EconomicModel, GetEc returns
+// it from now on instead of the default one of its net.
 func ResetEconomicDefaultConfig(newEc *EconomicModel) {
+	modelOnce.Do(func() {})
 	ec = newEc
 }
 
@@ -216,12 +219,25 @@ func ResetEconomicExtendConfig(newEc *EconomicModelExtend) {
 }
 
 const (
-	DefaultAlayaNet    = iota // PlatON default Alaya net flag
-	DefaultTestNet            // PlatON default test net flag
-	DefaultUnitTestNet        // PlatON default unit test
+	DefaultAlayaNet     = iota // PlatON default Alaya net flag
+	DefaultTestNet             // PlatON default test net flag
+	DefaultUnitTestNet         // PlatON default unit test
+	DefaultDeveloperNet        // PlatON single node developer chain
 )
 
+// getDefaultEMConfig sets the global EconomicModel to the default one of the net.
 func getDefaultEMConfig(netId int8) *EconomicModel {
+	model, extend := NewEconomicModel(netId)
+	if model == nil {
+		return nil
+	}
+	ec, ece = model, extend
+	return ec
+}
+
+// NewEconomicModel returns a new copy of the default EconomicModel of the net,
+// the global one is left unchanged. It returns nil if the net is not supported.
+func NewEconomicModel(netId int8) (model *EconomicModel, extend *EconomicModelExtend) {
 	var (
 		ok                bool
 		cdfundBalance     *big.Int
@@ -229,17 +245,17 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 	)
 
 	if cdfundBalance, ok = new(big.Int).SetString("500000000000000000000000", 10); !ok {
-		return nil
+		return nil, nil
 	}
 	if platonFundBalance, ok = new(big.Int).SetString("2500000000000000000000000", 10); !ok {
-		return nil
+		return nil, nil
 	}
 
 	oneAtp, _ := new(big.Int).SetString("1000000000000000000", 10)
 
 	switch netId {
 	case DefaultAlayaNet:
-		ec = &EconomicModel{
+		model = &EconomicModel{
 			Common: commonConfig{
 				MaxEpochMinutes:     uint64(360), // 6 hours
 				NodeBlockTimeWindow: uint64(20),  // 20 seconds
@@ -289,7 +305,7 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 				CDFBalance:        new(big.Int).Set(cdfundBalance),
 			},
 		}
-		ece = &EconomicModelExtend{
+		extend = &EconomicModelExtend{
 			Reward: rewardConfigExtend{
 				TheNumberOfDelegationsReward: 20,
 			},
@@ -299,7 +315,7 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 		}
 
 	case DefaultTestNet:
-		ec = &EconomicModel{
+		model = &EconomicModel{
 			Common: commonConfig{
 				MaxEpochMinutes:     uint64(360), // 6 hours
 				NodeBlockTimeWindow: uint64(20),  // 20 seconds
@@ -349,7 +365,7 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 				CDFBalance:        new(big.Int).Set(cdfundBalance),
 			},
 		}
-		ece = &EconomicModelExtend{
+		extend = &EconomicModelExtend{
 			Reward: rewardConfigExtend{
 				TheNumberOfDelegationsReward: 20,
 			},
@@ -358,7 +374,7 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 			},
 		}
 	case DefaultUnitTestNet:
-		ec = &EconomicModel{
+		model = &EconomicModel{
 			Common: commonConfig{
 				MaxEpochMinutes:     uint64(6),  // 6 minutes
 				NodeBlockTimeWindow: uint64(10), // 10 seconds
@@ -408,7 +424,7 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 				CDFBalance:        new(big.Int).Set(new(big.Int).Mul(cdfundBalance, new(big.Int).SetUint64(1000))),
 			},
 		}
-		ece = &EconomicModelExtend{
+		extend = &EconomicModelExtend{
 			Reward: rewardConfigExtend{
 				TheNumberOfDelegationsReward: 2,
 			},
@@ -416,12 +432,72 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 				MinimumRelease: new(big.Int).SetInt64(1),
 			},
 		}
+	case DefaultDeveloperNet:
+		// One second blocks, 40 seconds consensus rounds and epochs of four
+		// rounds, proposals are voted within an epoch.
+		model = &EconomicModel{
+			Common: commonConfig{
+				MaxEpochMinutes:     uint64(3),  // 3 minutes
+				NodeBlockTimeWindow: uint64(10), // 10 seconds
+				PerRoundBlocks:      uint64(10),
+				MaxConsensusVals:    uint64(4),
+				AdditionalCycleTime: uint64(12),
+			},
+			Staking: stakingConfig{
+				StakeThreshold:          new(big.Int).Set(StakeLowerLimit),
+				OperatingThreshold:      new(big.Int).Set(DelegateLowerLimit),
+				MaxValidators:           uint64(25),
+				UnStakeFreezeDuration:   uint64(2),
+				RewardPerMaxChangeRange: uint16(500),
+				RewardPerChangeInterval: uint16(2),
+			},
+			Slashing: slashingConfig{
+				SlashFractionDuplicateSign: uint32(10),
+				DuplicateSignReportReward:  uint32(50),
+				MaxEvidenceAge:             uint32(1),
+				SlashBlocksReward:          uint32(0),
+				ZeroProduceCumulativeTime:  uint16(3),
+				ZeroProduceNumberThreshold: uint16(2),
+				ZeroProduceFreezeDuration:  uint64(1),
+			},
+			Gov: governanceConfig{
+				VersionProposalVoteDurationSeconds: uint64(160),
+				VersionProposalSupportRate:         6670,
+				TextProposalVoteDurationSeconds:    uint64(160),
+				TextProposalVoteRate:               5000,
+				TextProposalSupportRate:            6670,
+				CancelProposalVoteRate:             5000,
+				CancelProposalSupportRate:          6670,
+				ParamProposalVoteDurationSeconds:   uint64(160),
+				ParamProposalVoteRate:              5000,
+				ParamProposalSupportRate:           6670,
+			},
+			Reward: rewardConfig{
+				NewBlockRate:          50,
+				PlatONFoundationYear:  10,
+				IncreaseIssuanceRatio: 250,
+			},
+			InnerAcc: innerAccount{
+				PlatONFundAccount: common.HexToAddress("0x493301712671Ada506ba6Ca7891F436D29185821"),
+				PlatONFundBalance: new(big.Int).Set(platonFundBalance),
+				CDFAccount:        common.HexToAddress("0xC1f330B214668beAc2E6418Dd651B09C759a4Bf5"),
+				CDFBalance:        new(big.Int).Set(cdfundBalance),
+			},
+		}
+		extend = &EconomicModelExtend{
+			Reward: rewardConfigExtend{
+				TheNumberOfDelegationsReward: 20,
+			},
+			Restricting: restrictingConfigExtend{
+				MinimumRelease: new(big.Int).SetInt64(1),
+			},
+		}
 	default:
 		log.Error("not support chainID", "netId", netId)
-		return nil
+		return nil, nil
 	}
 
-	return ec
+	return model, extend
 }
 
 func CheckStakeThreshold(threshold *big.Int) error {
@@ -33,6 +33,14 @@ func TestGetDefaultEMConfig(t *testing.T) {
 			t.Error(err)
 		}
 	})
+	t.Run("DefaultDeveloperNet", func(t *testing.T) {
+		if getDefaultEMConfig(DefaultDeveloperNet) == nil {
+			t.Error("DefaultDeveloperNet can't be nil config")
+		}
+		if err := CheckEconomicModel(); nil != err {
+			t.Error(err)
+		}
+	})
 	t.Run("DefaultUnitTestNet", func(t *testing.T) {
 		if getDefaultEMConfig(DefaultUnitTestNet) == nil {
 			t.Error("DefaultUnitTestNet can't be nil config")
@@ -0,0 +1,90 @@
+// Copyright 2021 The Alaya Network Authors
+// This file is part of the Alaya-Go library.
+//
+// The Alaya-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The Alaya-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the Alaya-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package xcom
+
+import (
+	"math/big"
+
+	"github.com/AlayaNetwork/Alaya-Go/common"
+	"github.com/AlayaNetwork/Alaya-Go/common/hexutil"
+	"github.com/AlayaNetwork/Alaya-Go/core/snapshotdb"
+	"github.com/AlayaNetwork/Alaya-Go/log"
+	"github.com/AlayaNetwork/Alaya-Go/p2p/discover"
+)
+
+// The types of the PPOS events
+const (
+	CreateStakingEvent          = "createStaking"
+	EditCandidateEvent          = "editCandidate"
+	IncreaseStakingEvent        = "increaseStaking"
+	WithdrewStakingEvent        = "withdrewStaking"
+	DelegateEvent               = "delegate"
+	WithdrewDelegationEvent     = "withdrewDelegation"
+	WithdrawDelegateRewardEvent = "withdrawDelegateReward"
+	SlashingEvent               = "slashing"
+	RestrictingReleaseEvent     = "restrictingRelease"
+	ElectVerifiersEvent         = "electVerifiers"
+	ElectValidatorsEvent        = "electValidators"
+)
+
+// PPOSEvent is a change of the PPOS state made by a transaction or by the
+// plugins at the beginning or the end of a block. The events of a block are
+// published when the block is committed, the ones of the reverted transactions
+// are dropped.
+type PPOSEvent struct {
+	Type        string      `json:"type"`
+	BlockNumber uint64      `json:"blockNumber"`
+	BlockHash   common.Hash `json:"blockHash"`
+	// The transaction emitting the event, zero if it's emitted by the plugins
+	TxHash  common.Hash `json:"txHash"`
+	TxIndex uint32      `json:"txIndex"`
+	// The candidate of the staking, delegation, reward or slashing event
+	NodeId          discover.NodeID `json:"nodeId"`
+	StakingBlockNum uint64          `json:"stakingBlockNum"`
+	// The account of the event, the staking address of the candidate events
+	Address common.Address `json:"address"`
+	Amount  *hexutil.Big   `json:"amount"`
+	// The elected nodes of the election events
+	Nodes []discover.NodeID `json:"nodes,omitempty"`
+}
+
+// NewPPOSEvent creates an event of the given type and amount, the amount may be nil.
+func NewPPOSEvent(typ string, nodeId discover.NodeID, stakingBlockNum uint64, addr common.Address, amount *big.Int) *PPOSEvent {
+	ev := &PPOSEvent{
+		Type:            typ,
+		NodeId:          nodeId,
+		StakingBlockNum: stakingBlockNum,
+		Address:         addr,
+		Amount:          (*hexutil.Big)(new(big.Int)),
+	}
+	if amount != nil {
+		ev.Amount = (*hexutil.Big)(new(big.Int).Set(amount))
+	}
+	return ev
+}
+
+// AddPPOSEvent adds the event to the block in the snapshot db, the transaction
+// of the event is taken from the state, which may be nil out of transactions.
+func AddPPOSEvent(blockHash common.Hash, blockNumber uint64, state StateDB, ev *PPOSEvent) {
+	ev.BlockNumber = blockNumber
+	if state != nil {
+		ev.TxHash, ev.TxIndex = state.TxHash(), state.TxIdx()
+	}
+	if err := snapshotdb.Instance().AddEvent(blockHash, ev); err != nil {
+		log.Warn("Failed to add PPOS event", "type", ev.Type, "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "err", err)
+	}
+}